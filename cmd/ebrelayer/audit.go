@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// auditVerifyCmd : Verifies the integrity of an audit log written with --audit-log
+func auditVerifyCmd() *cobra.Command {
+	auditVerifyCmd := &cobra.Command{
+		Use:     "audit-verify [log]",
+		Short:   "Check the sequence and hash chain of an audit log written by a relayer run with --" + FlagAuditLog,
+		Args:    cobra.ExactArgs(1),
+		Example: "ebrelayer audit-verify ./audit.log",
+		RunE:    RunAuditVerifyCmd,
+	}
+	return auditVerifyCmd
+}
+
+// RunAuditVerifyCmd : executes the auditVerifyCmd
+func RunAuditVerifyCmd(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return errors.Errorf("invalid [log]: %v", err)
+	}
+	defer file.Close()
+
+	entries, err := txs.VerifyAuditLog(file)
+	if err != nil {
+		return err
+	}
+	chained := len(entries) > 0 && len(entries[len(entries)-1].Hash) != 0
+	fmt.Fprintf(cmd.OutOrStdout(), "Verified %d audit log entries, hash-chained: %v\n", len(entries), chained)
+	return nil
+}
@@ -2,19 +2,192 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	tmLog "github.com/tendermint/tendermint/libs/log"
 
 	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract"
 	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/relayer"
 	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+	"github.com/mochi-lab/eth-one-bridge/hmyclient"
+)
+
+const (
+	// FlagEthereumKeyEnv names the environment variable holding the validator's Ethereum private key
+	FlagEthereumKeyEnv = "ethereum-key-env"
+	// FlagHarmonyKeyEnv names the environment variable holding the validator's Harmony private key
+	FlagHarmonyKeyEnv = "harmony-key-env"
+	// FlagKeyringService is the OS keyring service validator keys are loaded from
+	FlagKeyringService = "keyring-service"
+	// FlagEthereumKeyringAccount is the OS keyring account holding the validator's Ethereum private key
+	FlagEthereumKeyringAccount = "ethereum-keyring-account"
+	// FlagHarmonyKeyringAccount is the OS keyring account holding the validator's Harmony private key
+	FlagHarmonyKeyringAccount = "harmony-keyring-account"
+	// FlagCheckpointFile is the file persisting the last processed block of each chain
+	FlagCheckpointFile = "checkpoint-file"
+	// FlagEthereumStartBlock is the Ethereum block to start processing from without a later checkpoint
+	FlagEthereumStartBlock = "ethereum-start-block"
+	// FlagHarmonyStartBlock is the Harmony block to start processing from without a later checkpoint
+	FlagHarmonyStartBlock = "harmony-start-block"
+	// FlagSeenFile is the file persisting relayed events, so they are not relayed again after a restart
+	FlagSeenFile = "seen-file"
+	// FlagEthereumConfirmations is the number of blocks an Ethereum event must be buried under before it is relayed
+	FlagEthereumConfirmations = "ethereum-confirmations"
+	// FlagHarmonyConfirmations is the number of blocks a Harmony event must be buried under before it is relayed
+	FlagHarmonyConfirmations = "harmony-confirmations"
+	// FlagEthereumMaxReorgDepth, if set, halts claim signing on an Ethereum reorg deeper than this many blocks
+	FlagEthereumMaxReorgDepth = "ethereum-max-reorg-depth"
+	// FlagHarmonyMaxReorgDepth, if set, halts claim signing on a Harmony reorg deeper than this many blocks
+	FlagHarmonyMaxReorgDepth = "harmony-max-reorg-depth"
+	// FlagEthereumMaxEventBlocks, if set, skips Ethereum events more than this many blocks behind the head
+	FlagEthereumMaxEventBlocks = "ethereum-max-event-blocks"
+	// FlagHarmonyMaxEventBlocks, if set, skips Harmony events more than this many blocks behind the head
+	FlagHarmonyMaxEventBlocks = "harmony-max-event-blocks"
+	// FlagMaxEventAge, if set, skips events whose block is older than this relative to the head block
+	FlagMaxEventAge = "max-event-age"
+	// FlagSigningHaltFile persists a halt of claim signing until an operator removes the file
+	FlagSigningHaltFile = "signing-halt-file"
+	// FlagMetricsAddr is the address serving Prometheus metrics at /metrics
+	FlagMetricsAddr = "metrics-addr"
+	// FlagEthereumClaimChainID is the chain ID bound into claims verified on Ethereum
+	FlagEthereumClaimChainID = "ethereum-claim-chain-id"
+	// FlagHarmonyClaimChainID is the chain ID bound into claims verified on Harmony
+	FlagHarmonyClaimChainID = "harmony-claim-chain-id"
+	// FlagEthereumClaimTxHash binds the source transaction hash into claims verified on Ethereum
+	FlagEthereumClaimTxHash = "ethereum-claim-tx-hash"
+	// FlagHarmonyClaimTxHash binds the source transaction hash into claims verified on Harmony
+	FlagHarmonyClaimTxHash = "harmony-claim-tx-hash"
+	// FlagEthereumClaimTokenAmountHash hashes the token and amount together in claims verified on Ethereum
+	FlagEthereumClaimTokenAmountHash = "ethereum-claim-token-amount-hash"
+	// FlagHarmonyClaimTokenAmountHash hashes the token and amount together in claims verified on Harmony
+	FlagHarmonyClaimTokenAmountHash = "harmony-claim-token-amount-hash"
+	// FlagEthereumChainID is the chain ID Ethereum transactions are signed for
+	FlagEthereumChainID = "ethereum-chain-id"
+	// FlagHarmonyChainID is the chain ID Harmony transactions are signed for
+	FlagHarmonyChainID = "harmony-chain-id"
+	// FlagHarmonyShardID is the Harmony shard transactions are sent to
+	FlagHarmonyShardID = "harmony-shard-id"
+	// FlagSignedClaimsFile is the file persisting signed unlock IDs, so none is ever signed twice
+	FlagSignedClaimsFile = "signed-claims-file"
+	// FlagSignatureCacheFile is the file persisting unconfirmed claim signatures, so re-observed claims reuse them
+	FlagSignatureCacheFile = "signature-cache-file"
+	// FlagSubmissionQueueFile is the file persisting submitted claims until their transactions are mined
+	FlagSubmissionQueueFile = "submission-queue-file"
+	// FlagHaltOnSignatureReuse halts claim signing when one signature is seen for two unlock IDs
+	FlagHaltOnSignatureReuse = "halt-on-signature-reuse"
+	// FlagReconnectBaseDelay is the wait before the first resubscription after a subscription drops
+	FlagReconnectBaseDelay = "reconnect-base-delay"
+	// FlagReconnectMaxDelay caps the wait between resubscription attempts
+	FlagReconnectMaxDelay = "reconnect-max-delay"
+	// FlagPollInterval, if set, polls for events at this interval instead of subscribing to them
+	FlagPollInterval = "poll-interval"
+	// FlagPollMaxBlockRange is the most blocks queried per poll
+	FlagPollMaxBlockRange = "poll-max-block-range"
+	// FlagWorkers is the number of events processed concurrently on each chain
+	FlagWorkers = "workers"
+	// FlagDryRun logs the claim hash computed for each event without signing or submitting anything
+	FlagDryRun = "dry-run"
+	// FlagClaimExport writes each signed claim as JSON for an external submitter instead of submitting it
+	FlagClaimExport = "claim-export"
+	// FlagAuditLog is the append-only file recording every claim signed, before it is exported or submitted
+	FlagAuditLog = "audit-log"
+	// FlagAuditLogChained hash-chains the audit log's entries so tampering with them is detectable
+	FlagAuditLogChained = "audit-log-chained"
+	// FlagTokenDecimals rescales claim amounts of a token bridged with different decimals on each chain
+	FlagTokenDecimals = "token-decimals"
+	// FlagTokenAmountScale scales a token's claim amounts by a fixed factor before they are hashed
+	FlagTokenAmountScale = "token-amount-scale"
+	// FlagEthToHmyToken maps a token locked on Ethereum to the Harmony token unlocked for it
+	FlagEthToHmyToken = "eth-to-hmy-token"
+	// FlagHmyToEthToken maps a token locked on Harmony to the Ethereum token unlocked for it
+	FlagHmyToEthToken = "hmy-to-eth-token"
+	// FlagTokenSymbol registers a token symbol resolving to the token's address on each chain
+	FlagTokenSymbol = "token-symbol"
+	// FlagTokenAllowlist restricts signing to claims for the listed tokens
+	FlagTokenAllowlist = "token-allowlist"
+	// FlagTokenDenylist refuses to sign claims for the listed tokens
+	FlagTokenDenylist = "token-denylist"
+	// FlagMaxClaimAmount is the largest claim amount signed automatically for tokens without their own limit
+	FlagMaxClaimAmount = "max-claim-amount"
+	// FlagTokenMaxAmount is the largest claim amount signed automatically for a token
+	FlagTokenMaxAmount = "token-max-amount"
+	// FlagSubmitRate is the most claim submissions per second to each chain
+	FlagSubmitRate = "submit-rate"
+	// FlagSubmitBurst is the most claim submissions sent at once to each chain before FlagSubmitRate applies
+	FlagSubmitBurst = "submit-burst"
+	// FlagSkipZeroAmount skips claims for a zero amount
+	FlagSkipZeroAmount = "skip-zero-amount"
+	// FlagSkipSelfTransfer skips claims whose sender is their recipient
+	FlagSkipSelfTransfer = "skip-self-transfer"
+	// FlagSigningScheme is how claim messages are prefixed for verifying contracts without their own scheme
+	FlagSigningScheme = "signing-scheme"
+	// FlagContractSigningScheme is how claim messages are prefixed for a verifying contract
+	FlagContractSigningScheme = "contract-signing-scheme"
+	// FlagContractClaimEncoding is how claim messages are laid out before hashing for a verifying contract
+	FlagContractClaimEncoding = "contract-claim-encoding"
+	// FlagHealthAddr is the listen address of the health check endpoint
+	FlagHealthAddr = "health-addr"
+	// FlagAdminAddr is the listen address of the admin endpoints pausing and resuming claim signing
+	FlagAdminAddr = "admin-addr"
+	// FlagHealthMaxLag is the most blocks a chain may lag behind its head before the health check fails
+	FlagHealthMaxLag = "health-max-lag"
+	// FlagTxWatchTimeout, if set, watches Ethereum claim transactions until mined for at most this long
+	FlagTxWatchTimeout = "tx-watch-timeout"
+	// FlagTxMaxRebroadcasts is how many times a watched transaction dropped from the mempool is re-sent
+	FlagTxMaxRebroadcasts = "tx-max-rebroadcasts"
+	// FlagTxGasBump is the percentage a re-broadcast transaction's gas price is raised by
+	FlagTxGasBump = "tx-gas-bump"
+	// FlagTxSpeedUpAfter is how long a watched transaction may be pending before it is sped up
+	FlagTxSpeedUpAfter = "tx-speed-up-after"
+	// FlagGasPriceMultiplier scales the gas prices nodes suggest for claim transactions
+	FlagGasPriceMultiplier = "gas-price-multiplier"
+	// FlagEthereumUnlockEvent watches another version of the Ethereum UnlockClaim event, read from a file
+	FlagEthereumUnlockEvent = "ethereum-unlock-event"
+	// FlagHarmonyUnlockEvent watches another version of the Harmony UnlockClaim event, read from a file
+	FlagHarmonyUnlockEvent = "harmony-unlock-event"
+	// FlagEthereumFallbackProvider is an Ethereum websocket URL failed over to while the provider is down
+	FlagEthereumFallbackProvider = "ethereum-fallback-provider"
+	// FlagHarmonyFallbackProvider is a Harmony websocket URL failed over to while the provider is down
+	FlagHarmonyFallbackProvider = "harmony-fallback-provider"
+	// FlagEthereumCheckValidator is whether startup checks the Ethereum Valset registers the Ethereum key
+	FlagEthereumCheckValidator = "ethereum-check-validator"
+	// FlagHarmonyCheckValidator is whether startup checks the Harmony Valset registers the Harmony key
+	FlagHarmonyCheckValidator = "harmony-check-validator"
+	// FlagEthereumBridgeContract is an Ethereum bridge contract of another bridge instance to watch
+	FlagEthereumBridgeContract = "ethereum-bridge-contract"
+	// FlagHarmonyBridgeContract is a Harmony bridge contract of another bridge instance to watch
+	FlagHarmonyBridgeContract = "harmony-bridge-contract"
+	// FlagBridgeToken maps a token locked in a given BridgeBank to the token unlocked for it
+	FlagBridgeToken = "bridge-token"
+	// FlagEthereumMinGasBalance is the least ETH the Ethereum account may hold before alerting
+	FlagEthereumMinGasBalance = "ethereum-min-gas-balance"
+	// FlagHarmonyMinGasBalance is the least ONE the Harmony account may hold before alerting
+	FlagHarmonyMinGasBalance = "harmony-min-gas-balance"
+	// FlagPauseOnLowGasBalance pauses claim submission to a chain while its gas balance is low
+	FlagPauseOnLowGasBalance = "pause-on-low-gas-balance"
+	// FlagBreakerReverts is the number of consecutive reverted claims halting submission to a chain
+	FlagBreakerReverts = "breaker-reverts"
+	// FlagBreakerCooldown is how long submission stays halted before a single claim probes again
+	FlagBreakerCooldown = "breaker-cooldown"
+	// FlagEthereumRegistryOrigin is the deployment the Ethereum BridgeRegistry address is checked against
+	FlagEthereumRegistryOrigin = "ethereum-registry-origin"
+	// FlagHarmonyRegistryOrigin is the deployment the Harmony BridgeRegistry address is checked against
+	FlagHarmonyRegistryOrigin = "harmony-registry-origin"
 )
 
 func init() {
@@ -22,6 +195,10 @@ func init() {
 	rootCmd.AddCommand(
 		initRelayerCmd(),
 		generateBindingsCmd(),
+		verifyCmd(),
+		submitCmd(),
+		replayCmd(),
+		auditVerifyCmd(),
 	)
 }
 
@@ -42,6 +219,184 @@ func initRelayerCmd() *cobra.Command {
 		RunE:    RunInitRelayerCmd,
 	}
 
+	initRelayerCmd.Flags().String(FlagEthereumKeyEnv, txs.EthereumPrivateKeyEnv,
+		"environment variable holding the validator's Ethereum private key")
+	initRelayerCmd.Flags().String(FlagHarmonyKeyEnv, txs.HarmonyPrivateKeyEnv,
+		"environment variable holding the validator's Harmony private key")
+	initRelayerCmd.Flags().String(FlagKeyringService, txs.DefaultKeyringService,
+		"OS keyring service validator keys are loaded from")
+	initRelayerCmd.Flags().String(FlagEthereumKeyringAccount, "",
+		"OS keyring account to load the validator's Ethereum private key from, instead of the environment")
+	initRelayerCmd.Flags().String(FlagHarmonyKeyringAccount, "",
+		"OS keyring account to load the validator's Harmony private key from, instead of the environment")
+	initRelayerCmd.Flags().String(FlagCheckpointFile, "",
+		"file persisting the last processed block of each chain, so missed events are replayed on restart")
+	initRelayerCmd.Flags().Uint64(FlagEthereumStartBlock, 0,
+		"Ethereum block to replay events from when no later checkpoint exists")
+	initRelayerCmd.Flags().Uint64(FlagHarmonyStartBlock, 0,
+		"Harmony block to replay events from when no later checkpoint exists")
+	initRelayerCmd.Flags().String(FlagSeenFile, "",
+		"file persisting relayed events, so they are not relayed again after a restart")
+	initRelayerCmd.Flags().Uint64(FlagEthereumConfirmations, 0,
+		"number of blocks an Ethereum event must be buried under before it is relayed")
+	initRelayerCmd.Flags().Uint64(FlagHarmonyConfirmations, 0,
+		"number of blocks a Harmony event must be buried under before it is relayed")
+	initRelayerCmd.Flags().Uint64(FlagEthereumMaxReorgDepth, 0,
+		"halt claim signing if an Ethereum reorg replaces a processed block more than this many blocks deep; 0 disables the check")
+	initRelayerCmd.Flags().Uint64(FlagHarmonyMaxReorgDepth, 0,
+		"halt claim signing if a Harmony reorg replaces a processed block more than this many blocks deep; 0 disables the check")
+	initRelayerCmd.Flags().Uint64(FlagEthereumMaxEventBlocks, 0,
+		"skip Ethereum events more than this many blocks behind the head, logging them for manual review; 0 disables the limit")
+	initRelayerCmd.Flags().Uint64(FlagHarmonyMaxEventBlocks, 0,
+		"skip Harmony events more than this many blocks behind the head, logging them for manual review; 0 disables the limit")
+	initRelayerCmd.Flags().Duration(FlagMaxEventAge, 0,
+		"skip events whose block is older than this relative to the head block, logging them for manual review; 0 disables the limit")
+	initRelayerCmd.Flags().String(FlagSigningHaltFile, "",
+		"file recording a halt of claim signing, so it persists across restarts until an operator removes the file")
+	initRelayerCmd.Flags().String(FlagMetricsAddr, "",
+		"address serving Prometheus metrics at /metrics, such as :9090; disabled if empty")
+	initRelayerCmd.Flags().Uint64(FlagEthereumClaimChainID, 0,
+		"chain ID bound into claims verified on Ethereum, preventing cross-chain replay; 0 keeps the legacy claim layout")
+	initRelayerCmd.Flags().Uint64(FlagHarmonyClaimChainID, 0,
+		"chain ID bound into claims verified on Harmony, preventing cross-chain replay; 0 keeps the legacy claim layout")
+	initRelayerCmd.Flags().Bool(FlagEthereumClaimTxHash, false,
+		"append the hash of the transaction emitting each claim to claims verified on Ethereum, after any chain ID")
+	initRelayerCmd.Flags().Bool(FlagHarmonyClaimTxHash, false,
+		"append the hash of the transaction emitting each claim to claims verified on Harmony, after any chain ID")
+	initRelayerCmd.Flags().Bool(FlagEthereumClaimTokenAmountHash, false,
+		"pack the token and amount of claims verified on Ethereum as keccak256(abi.encode(token, amount)) rather than inline")
+	initRelayerCmd.Flags().Bool(FlagHarmonyClaimTokenAmountHash, false,
+		"pack the token and amount of claims verified on Harmony as keccak256(abi.encode(token, amount)) rather than inline")
+	initRelayerCmd.Flags().Uint64(FlagEthereumChainID, 0,
+		"chain ID Ethereum transactions are signed for per EIP-155; 0 uses the chain ID the node reports")
+	initRelayerCmd.Flags().Uint64(FlagHarmonyChainID, txs.DefaultHarmonyChainID,
+		"chain ID Harmony transactions are signed for")
+	initRelayerCmd.Flags().Uint32(FlagHarmonyShardID, txs.DefaultHarmonyShardID,
+		"Harmony shard the bridge contracts are deployed on, and transactions are sent to")
+	initRelayerCmd.Flags().String(FlagSignedClaimsFile, "",
+		"file persisting signed unlock IDs, so none is ever signed twice, even across restarts; with "+
+			FlagSignatureCacheFile+", claims retried after a restart reuse their first signature")
+	initRelayerCmd.Flags().String(FlagSignatureCacheFile, "",
+		"file persisting the signatures of claims not yet confirmed, so claims re-observed after a restart "+
+			"reuse their first signature; signatures are appended and evicted once their claim is mined")
+	initRelayerCmd.Flags().String(FlagSubmissionQueueFile, "",
+		"file persisting oracle and unlock claims until their transactions are mined, so claims a crash interrupts are re-submitted on restart")
+	initRelayerCmd.Flags().Bool(FlagHaltOnSignatureReuse, true,
+		"halt claim signing, as well as alerting, when one signature is seen for two unlock IDs")
+	initRelayerCmd.Flags().Duration(FlagReconnectBaseDelay, relayer.DefaultReconnectBackoff.BaseDelay,
+		"wait before the first resubscription after a subscription drops, doubled on each failed attempt")
+	initRelayerCmd.Flags().Duration(FlagReconnectMaxDelay, relayer.DefaultReconnectBackoff.MaxDelay,
+		"maximum wait between resubscription attempts")
+	initRelayerCmd.Flags().Duration(FlagPollInterval, 0,
+		"poll for events at this interval instead of subscribing, for providers without eth_subscribe support")
+	initRelayerCmd.Flags().Uint64(FlagPollMaxBlockRange, relayer.DefaultMaxBlockRange,
+		"maximum blocks queried per poll, to stay within provider limits")
+	initRelayerCmd.Flags().Int(FlagWorkers, relayer.DefaultWorkers,
+		"number of events processed concurrently on each chain; claims are still sent in nonce order per signer")
+	initRelayerCmd.Flags().Bool(FlagDryRun, false,
+		"log the claim hash and packed components computed for each event, without signing or submitting")
+	initRelayerCmd.Flags().String(FlagClaimExport, "",
+		"write each signed claim as a JSON record for an external submitter instead of submitting it: "+
+			"- for stdout, a directory for one file per claim, or a file appended to")
+	initRelayerCmd.Flags().String(FlagAuditLog, "",
+		"file every signed claim is appended to as a JSON line, synced before the claim is exported or submitted; "+
+			"disabled if empty")
+	initRelayerCmd.Flags().Bool(FlagAuditLogChained, true,
+		"hash-chain the --"+FlagAuditLog+" entries, so that ebrelayer audit-verify detects any edited or removed entry")
+	initRelayerCmd.Flags().StringSlice(FlagTokenDecimals, nil,
+		"token decimals as address=source:dest[:symbol], rescaling its claim amounts from source to dest decimals "+
+			"and logging them in whole units of symbol; may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagTokenAmountScale, nil,
+		"a fixed factor a token's claim amounts are hashed at, after any --"+FlagTokenDecimals+" rescaling, as "+
+			"address=*factor or address=/factor such as 0x...=*1e12; division must be exact; may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagEthToHmyToken, nil,
+		"an Ethereum token's Harmony counterpart as ethToken=hmyToken, or a --"+FlagTokenSymbol+" symbol; "+
+			"if any is set, locks of unmapped tokens aren't relayed")
+	initRelayerCmd.Flags().StringSlice(FlagHmyToEthToken, nil,
+		"a Harmony token's Ethereum counterpart as hmyToken=ethToken, or a --"+FlagTokenSymbol+" symbol; "+
+			"if any is set, locks of unmapped tokens aren't relayed")
+	initRelayerCmd.Flags().StringSlice(FlagTokenSymbol, nil,
+		"a token symbol as SYMBOL=ethToken:hmyToken, for token mappings to name the token by; may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagTokenAllowlist, nil,
+		"only sign claims for these token addresses")
+	initRelayerCmd.Flags().StringSlice(FlagTokenDenylist, nil,
+		"never sign claims for these token addresses")
+	initRelayerCmd.Flags().String(FlagMaxClaimAmount, "",
+		"refuse to sign claims above this amount, flagging them for manual review")
+	initRelayerCmd.Flags().StringSlice(FlagTokenMaxAmount, nil,
+		"a token's claim amount limit as address=amount, overriding --"+FlagMaxClaimAmount+"; may be repeated")
+	initRelayerCmd.Flags().Float64(FlagSubmitRate, 0,
+		"claim submissions per second to each chain, queueing any beyond it; 0 disables the limit")
+	initRelayerCmd.Flags().Int(FlagSubmitBurst, 1,
+		"claim submissions sent at once to each chain before --"+FlagSubmitRate+" applies")
+	initRelayerCmd.Flags().Bool(FlagSkipZeroAmount, true,
+		"skip claims for a zero amount, which are almost always malformed or spam")
+	initRelayerCmd.Flags().Bool(FlagSkipSelfTransfer, true,
+		"skip claims whose sender on the source chain is their recipient")
+	initRelayerCmd.Flags().String(FlagSigningScheme, txs.EthSignedMessage.String(),
+		"signing scheme of Oracle contracts without their own: eth-signed-message, raw (the unprefixed claim hash, "+
+			"for contracts calling ecrecover on it directly) or intended-validator")
+	initRelayerCmd.Flags().StringSlice(FlagContractSigningScheme, nil,
+		"an Oracle contract's signing scheme as address=scheme, or address=eip712:name:version:chainID; may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagContractClaimEncoding, nil,
+		"an Oracle contract's claim message encoding as address=packed or address=abi; may be repeated")
+	initRelayerCmd.Flags().String(FlagHealthAddr, "",
+		"address to serve the health check on at "+relayer.HealthPath+", such as :8081; disabled if empty")
+	initRelayerCmd.Flags().String(FlagAdminAddr, "",
+		"loopback address to serve POST "+relayer.PausePath+" and "+relayer.ResumePath+" on, pausing and resuming claim "+
+			"signing, such as 127.0.0.1:8082; unauthenticated, disabled if empty")
+	initRelayerCmd.Flags().Uint64(FlagHealthMaxLag, 0,
+		"blocks a chain may lag behind its head before the health check responds 503; 0 disables the lag check")
+	initRelayerCmd.Flags().Duration(FlagTxWatchTimeout, 0,
+		"watch each Ethereum claim transaction until mined for at most this long, re-broadcasting it if dropped; 0 disables watching")
+	initRelayerCmd.Flags().Int(FlagTxMaxRebroadcasts, txs.DefaultMaxRebroadcasts,
+		"times a watched transaction dropped from the mempool or sped up is re-broadcast with a bumped gas price")
+	initRelayerCmd.Flags().Float64(FlagTxGasBump, txs.DefaultGasBump*100,
+		"percentage a re-broadcast transaction's gas price is raised by, at least the 10% nodes require to replace it")
+	initRelayerCmd.Flags().Duration(FlagTxSpeedUpAfter, 0,
+		"re-broadcast a watched transaction still pending this long with the same nonce and a bumped gas price; 0 disables it")
+	initRelayerCmd.Flags().Float64(FlagGasPriceMultiplier, 1,
+		"multiplier applied to the gas prices nodes suggest for claim transactions, to outbid congestion")
+	initRelayerCmd.Flags().StringSlice(FlagEthereumUnlockEvent, nil,
+		"JSON file describing another version of the Ethereum UnlockClaim event to watch alongside the bundled ABI's, "+
+			"such as during a contract upgrade; may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagHarmonyUnlockEvent, nil,
+		"JSON file describing another version of the Harmony UnlockClaim event to watch alongside the bundled ABI's; "+
+			"may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagEthereumFallbackProvider, nil,
+		"an Ethereum websocket URL to fail over to while the provider is down, in order of preference; may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagHarmonyFallbackProvider, nil,
+		"a Harmony websocket URL to fail over to while the provider is down, in order of preference; may be repeated")
+	initRelayerCmd.Flags().Bool(FlagEthereumCheckValidator, true,
+		"fail at startup unless the Ethereum Valset registers the Ethereum key as a validator")
+	initRelayerCmd.Flags().Bool(FlagHarmonyCheckValidator, true,
+		"fail at startup unless the Harmony Valset registers the Harmony key as a validator")
+	initRelayerCmd.Flags().StringSlice(FlagEthereumBridgeContract, nil,
+		"an Ethereum bridge contract to watch besides the registry's, as address=lock|unlock[@registry], where registry "+
+			"is the BridgeRegistry its claims are submitted to; may be repeated")
+	initRelayerCmd.Flags().StringSlice(FlagHarmonyBridgeContract, nil,
+		"a Harmony bridge contract to watch besides the registry's, as address=lock|unlock[@registry], where registry "+
+			"is the BridgeRegistry its claims are submitted to; may be repeated")
+	initRelayerCmd.Flags().String(FlagEthereumMinGasBalance, "",
+		"ETH the Ethereum account must hold, such as 0.5, checked before each claim is submitted; disabled if empty")
+	initRelayerCmd.Flags().String(FlagHarmonyMinGasBalance, "",
+		"ONE the Harmony account must hold, such as 10, checked before each claim is submitted; disabled if empty")
+	initRelayerCmd.Flags().Bool(FlagPauseOnLowGasBalance, false,
+		"refuse to submit claims to a chain while its gas balance is below the minimum, rather than only alerting")
+	initRelayerCmd.Flags().Int(FlagBreakerReverts, 0,
+		"consecutive claims reverting on a chain before submission to it halts with a critical alert; 0 disables")
+	initRelayerCmd.Flags().Duration(FlagBreakerCooldown, txs.DefaultBreakerCooldown,
+		"how long submission stays halted by --"+FlagBreakerReverts+" before one claim is sent to probe the contracts")
+	initRelayerCmd.Flags().String(FlagEthereumRegistryOrigin, "",
+		"fail at startup unless the Ethereum BridgeRegistry address is the one deployed by deployer:nonce with CREATE, "+
+			"or deployer:salt:initCodeHash with CREATE2; unchecked if empty")
+	initRelayerCmd.Flags().String(FlagHarmonyRegistryOrigin, "",
+		"fail at startup unless the Harmony BridgeRegistry address is the one deployed by deployer:nonce with CREATE, "+
+			"or deployer:salt:initCodeHash with CREATE2; unchecked if empty")
+	initRelayerCmd.Flags().StringSlice(FlagBridgeToken, nil,
+		"a token's counterpart for locks in one BridgeBank as bridgeBank:source=dest, overriding the "+
+			"eth-to-hmy-token and hmy-to-eth-token mappings for that BridgeBank; may be repeated")
+
 	return initRelayerCmd
 }
 
@@ -60,15 +415,59 @@ func generateBindingsCmd() *cobra.Command {
 
 // RunInitRelayerCmd executes initRelayerCmd
 func RunInitRelayerCmd(cmd *cobra.Command, args []string) error {
-	// Load the validator's Ethereum private key from environment variables
-	ethereumPrivateKey, err := txs.LoadEthereumPrivateKey()
+	ethereumKeyEnv, err := cmd.Flags().GetString(FlagEthereumKeyEnv)
+	if err != nil {
+		return err
+	}
+
+	harmonyKeyEnv, err := cmd.Flags().GetString(FlagHarmonyKeyEnv)
 	if err != nil {
-		return errors.Errorf("invalid [ETHEREUM_PRIVATE_KEY] environment variable")
+		return err
 	}
 
-	harmonyPrivateKey, err := txs.LoadHarmonyPrivateKey()
+	keyringService, err := cmd.Flags().GetString(FlagKeyringService)
+	if err != nil {
+		return err
+	}
+	ethereumKeyringAccount, err := cmd.Flags().GetString(FlagEthereumKeyringAccount)
+	if err != nil {
+		return err
+	}
+	harmonyKeyringAccount, err := cmd.Flags().GetString(FlagHarmonyKeyringAccount)
 	if err != nil {
-		return errors.Errorf("invalid [HARMONY_PRIVATE_KEY] environment variable")
+		return err
+	}
+
+	// Load the validator's Ethereum private keys from the OS keyring, or else from environment
+	// variables, more than one while rotating keys
+	var ethereumPrivateKeys []*ecdsa.PrivateKey
+	if len(ethereumKeyringAccount) != 0 {
+		key, err := txs.LoadKeyFromKeyring(keyringService, ethereumKeyringAccount)
+		if err != nil {
+			return errors.Errorf("invalid [%s]: %v", FlagEthereumKeyringAccount, err)
+		}
+		ethereumPrivateKeys = []*ecdsa.PrivateKey{key}
+	} else if ethereumPrivateKeys, err = txs.LoadPrivateKeysFromEnv(ethereumKeyEnv); err != nil {
+		return errors.Errorf("invalid [%s] environment variable", ethereumKeyEnv)
+	}
+
+	var harmonyPrivateKey *ecdsa.PrivateKey
+	if len(harmonyKeyringAccount) != 0 {
+		if harmonyPrivateKey, err = txs.LoadKeyFromKeyring(keyringService, harmonyKeyringAccount); err != nil {
+			return errors.Errorf("invalid [%s]: %v", FlagHarmonyKeyringAccount, err)
+		}
+	} else if harmonyPrivateKey, err = txs.LoadPrivateKeyFromEnv(harmonyKeyEnv); err != nil {
+		return errors.Errorf("invalid [%s] environment variable", harmonyKeyEnv)
+	}
+
+	// Fail fast on a misconfigured key or broken claim hashing, before any real event
+	for _, key := range ethereumPrivateKeys {
+		if err := txs.SelfTest(txs.NewKeySigner(key)); err != nil {
+			return errors.Errorf("key [%s]: %v", ethereumKeyEnv, err)
+		}
+	}
+	if err := txs.SelfTest(txs.NewKeySigner(harmonyPrivateKey)); err != nil {
+		return errors.Errorf("key [%s]: %v", harmonyKeyEnv, err)
 	}
 
 	if !relayer.IsWebsocketURL(args[0]) {
@@ -76,8 +475,8 @@ func RunInitRelayerCmd(cmd *cobra.Command, args []string) error {
 	}
 	ethereumProvider := args[0]
 
-	if !common.IsHexAddress(args[1]) {
-		return errors.Errorf("invalid [bridge-registry-contract-address]: %s", args[1])
+	if err := txs.ValidateAddressChecksum(args[1], false); err != nil {
+		return errors.Errorf("invalid [bridge-registry-contract-address]: %v", err)
 	}
 	ethereumBridgeRegistry := common.HexToAddress(args[1])
 
@@ -86,17 +485,60 @@ func RunInitRelayerCmd(cmd *cobra.Command, args []string) error {
 	}
 	harmonyProvider := args[2]
 
-	if !common.IsHexAddress(args[3]) {
-		return errors.Errorf("invalid [bridge-registry-contract-address]: %s", args[3])
+	if err := txs.ValidateAddressChecksum(args[3], false); err != nil {
+		return errors.Errorf("invalid [bridge-registry-contract-address]: %v", err)
 	}
 	harmonyBridgeRegistry := common.HexToAddress(args[3])
 
+	// Refuse registry addresses other than the expected deployments, before trusting the
+	// contracts they name
+	if err := checkRegistryOrigin(cmd, FlagEthereumRegistryOrigin, ethereumBridgeRegistry); err != nil {
+		return err
+	}
+	if err := checkRegistryOrigin(cmd, FlagHarmonyRegistryOrigin, harmonyBridgeRegistry); err != nil {
+		return err
+	}
+
+	ethereumPrivateKey, err := selectEthereumKey(ethereumPrivateKeys, ethereumProvider, ethereumBridgeRegistry)
+	if err != nil {
+		return err
+	}
+
+	// Fail fast on a key the contracts don't register as a validator, before any claim is rejected
+	checkEthereumValidator, err := cmd.Flags().GetBool(FlagEthereumCheckValidator)
+	if err != nil {
+		return err
+	}
+	if checkEthereumValidator {
+		if err := checkEthereumValidatorSet(ethereumPrivateKey, ethereumProvider, ethereumBridgeRegistry); err != nil {
+			return errors.Errorf("key [%s]: %v", ethereumKeyEnv, err)
+		}
+	}
+	checkHarmonyValidator, err := cmd.Flags().GetBool(FlagHarmonyCheckValidator)
+	if err != nil {
+		return err
+	}
+	if checkHarmonyValidator {
+		if err := checkHarmonyValidatorSet(harmonyPrivateKey, harmonyProvider, harmonyBridgeRegistry); err != nil {
+			return errors.Errorf("key [%s]: %v", harmonyKeyEnv, err)
+		}
+	}
+
 	if len(strings.Trim(args[4], "")) == 0 {
 		return errors.Errorf("invalid [validator-moniker]: %s", args[4])
 	}
 
 	validatorMoniker := args[4]
 
+	ethereumEndpoints, err := fallbackEndpoints(cmd, FlagEthereumFallbackProvider, ethereumProvider)
+	if err != nil {
+		return err
+	}
+	harmonyEndpoints, err := fallbackEndpoints(cmd, FlagHarmonyFallbackProvider, harmonyProvider)
+	if err != nil {
+		return err
+	}
+
 	// Universal logger
 	logger := tmLog.NewTMLogger(tmLog.NewSyncWriter(os.Stdout))
 
@@ -115,15 +557,519 @@ func RunInitRelayerCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	go harmonySub.Start()
-	go ethereumSub.Start()
+	checkpointFile, err := cmd.Flags().GetString(FlagCheckpointFile)
+	if err != nil {
+		return err
+	}
+	if len(checkpointFile) != 0 {
+		checkpoints := relayer.NewFileCheckpointStore(checkpointFile)
+		ethereumSub.Checkpoints = checkpoints
+		harmonySub.Checkpoints = checkpoints
+	}
+
+	if ethereumSub.StartBlock, err = cmd.Flags().GetUint64(FlagEthereumStartBlock); err != nil {
+		return err
+	}
+	if harmonySub.StartBlock, err = cmd.Flags().GetUint64(FlagHarmonyStartBlock); err != nil {
+		return err
+	}
+
+	seenFile, err := cmd.Flags().GetString(FlagSeenFile)
+	if err != nil {
+		return err
+	}
+	var seenStore types.KeyStore
+	if len(seenFile) != 0 {
+		fileSeenStore, err := types.NewFileKeyStore(seenFile)
+		if err != nil {
+			return err
+		}
+		defer fileSeenStore.Close()
+		seenStore = fileSeenStore
+	}
+	ethereumSub.Seen = relayer.NewSeenSet(relayer.DefaultSeenCapacity, seenStore)
+	harmonySub.Seen = relayer.NewSeenSet(relayer.DefaultSeenCapacity, seenStore)
+
+	if ethereumSub.ConfirmationDepth, err = cmd.Flags().GetUint64(FlagEthereumConfirmations); err != nil {
+		return err
+	}
+	if harmonySub.ConfirmationDepth, err = cmd.Flags().GetUint64(FlagHarmonyConfirmations); err != nil {
+		return err
+	}
+	if ethereumSub.MaxReorgDepth, err = cmd.Flags().GetUint64(FlagEthereumMaxReorgDepth); err != nil {
+		return err
+	}
+	if harmonySub.MaxReorgDepth, err = cmd.Flags().GetUint64(FlagHarmonyMaxReorgDepth); err != nil {
+		return err
+	}
+	if ethereumSub.MaxEventBlocks, err = cmd.Flags().GetUint64(FlagEthereumMaxEventBlocks); err != nil {
+		return err
+	}
+	if ethereumSub.MaxEventBlocks != 0 && ethereumSub.MaxEventBlocks < ethereumSub.ConfirmationDepth {
+		return errors.Errorf("invalid [%s]: less than [%s]", FlagEthereumMaxEventBlocks, FlagEthereumConfirmations)
+	}
+	if harmonySub.MaxEventBlocks, err = cmd.Flags().GetUint64(FlagHarmonyMaxEventBlocks); err != nil {
+		return err
+	}
+	if harmonySub.MaxEventBlocks != 0 && harmonySub.MaxEventBlocks < harmonySub.ConfirmationDepth {
+		return errors.Errorf("invalid [%s]: less than [%s]", FlagHarmonyMaxEventBlocks, FlagHarmonyConfirmations)
+	}
+	maxEventAge, err := cmd.Flags().GetDuration(FlagMaxEventAge)
+	if err != nil {
+		return err
+	}
+	ethereumSub.MaxEventAge = maxEventAge
+	harmonySub.MaxEventAge = maxEventAge
+	if txs.SigningHaltFile, err = cmd.Flags().GetString(FlagSigningHaltFile); err != nil {
+		return err
+	}
+	if reason, halted := txs.SigningHalted(); halted {
+		logger.Error("Claim signing is halted until the halt file is removed", "haltFile", txs.SigningHaltFile,
+			"reason", reason)
+	}
+
+	metricsAddr, err := cmd.Flags().GetString(FlagMetricsAddr)
+	if err != nil {
+		return err
+	}
+	// Metrics, health checks and admin endpoints share one mux per listen address
+	muxes := make(map[string]*http.ServeMux)
+	if len(metricsAddr) != 0 {
+		if err := registerMetrics(muxFor(muxes, metricsAddr)); err != nil {
+			return err
+		}
+	}
+
+	pauseOnLowGasBalance, err := cmd.Flags().GetBool(FlagPauseOnLowGasBalance)
+	if err != nil {
+		return err
+	}
+	if txs.EthGasBalance, err = newGasBalanceGuard(cmd, FlagEthereumMinGasBalance, relayer.EthereumChain,
+		pauseOnLowGasBalance); err != nil {
+		return err
+	}
+	if txs.HmyGasBalance, err = newGasBalanceGuard(cmd, FlagHarmonyMinGasBalance, relayer.HarmonyChain,
+		pauseOnLowGasBalance); err != nil {
+		return err
+	}
+
+	breakerReverts, err := cmd.Flags().GetInt(FlagBreakerReverts)
+	if err != nil {
+		return err
+	}
+	breakerCooldown, err := cmd.Flags().GetDuration(FlagBreakerCooldown)
+	if err != nil {
+		return err
+	}
+	if breakerReverts < 0 {
+		return errors.Errorf("invalid [%s]: %d is negative", FlagBreakerReverts, breakerReverts)
+	}
+	if breakerReverts > 0 {
+		txs.EthSubmitBreaker = txs.NewCircuitBreaker(relayer.EthereumChain, breakerReverts, breakerCooldown)
+		txs.HmySubmitBreaker = txs.NewCircuitBreaker(relayer.HarmonyChain, breakerReverts, breakerCooldown)
+	}
+
+	healthAddr, err := cmd.Flags().GetString(FlagHealthAddr)
+	if err != nil {
+		return err
+	}
+	healthMaxLag, err := cmd.Flags().GetUint64(FlagHealthMaxLag)
+	if err != nil {
+		return err
+	}
+	if len(healthAddr) != 0 {
+		progress := relayer.NewProgress()
+		ethereumSub.Progress = progress
+		harmonySub.Progress = progress
+
+		healthCheck, closeHealthCheck, err := newHealthCheck(progress, healthMaxLag, ethereumProvider,
+			harmonyProvider, ethereumPrivateKey, harmonyPrivateKey, logger)
+		if err != nil {
+			return err
+		}
+		defer closeHealthCheck()
+		healthCheck.SetEndpoints(relayer.EthereumChain, ethereumEndpoints)
+		healthCheck.SetEndpoints(relayer.HarmonyChain, harmonyEndpoints)
+		healthCheck.Register(muxFor(muxes, healthAddr))
+	}
+	adminAddr, err := cmd.Flags().GetString(FlagAdminAddr)
+	if err != nil {
+		return err
+	}
+	if len(adminAddr) != 0 {
+		relayer.Admin{}.Register(muxFor(muxes, adminAddr))
+	}
+	serveMuxes(muxes, logger)
+
+	ethereumClaimChainID, err := cmd.Flags().GetUint64(FlagEthereumClaimChainID)
+	if err != nil {
+		return err
+	}
+	if ethereumClaimChainID != 0 {
+		txs.EthClaimChainID = new(big.Int).SetUint64(ethereumClaimChainID)
+	}
+
+	harmonyClaimChainID, err := cmd.Flags().GetUint64(FlagHarmonyClaimChainID)
+	if err != nil {
+		return err
+	}
+	if harmonyClaimChainID != 0 {
+		txs.HmyClaimChainID = new(big.Int).SetUint64(harmonyClaimChainID)
+	}
+
+	if txs.EthClaimTxHash, err = cmd.Flags().GetBool(FlagEthereumClaimTxHash); err != nil {
+		return err
+	}
+	if txs.HmyClaimTxHash, err = cmd.Flags().GetBool(FlagHarmonyClaimTxHash); err != nil {
+		return err
+	}
+	if txs.EthClaimTokenAmountHash, err = cmd.Flags().GetBool(FlagEthereumClaimTokenAmountHash); err != nil {
+		return err
+	}
+	if txs.HmyClaimTokenAmountHash, err = cmd.Flags().GetBool(FlagHarmonyClaimTokenAmountHash); err != nil {
+		return err
+	}
+
+	ethereumChainID, err := cmd.Flags().GetUint64(FlagEthereumChainID)
+	if err != nil {
+		return err
+	}
+	if ethereumChainID != 0 {
+		txs.EthChainID = new(big.Int).SetUint64(ethereumChainID)
+	}
+
+	harmonyChainID, err := cmd.Flags().GetUint64(FlagHarmonyChainID)
+	if err != nil {
+		return err
+	}
+	txs.HmyChainID = new(big.Int).SetUint64(harmonyChainID)
+	if txs.HmyShardID, err = cmd.Flags().GetUint32(FlagHarmonyShardID); err != nil {
+		return err
+	}
+
+	// Fail fast on an endpoint serving another network than the chain IDs transactions are signed for
+	if err := checkChainIDs(ethereumProvider, harmonyProvider); err != nil {
+		return err
+	}
+
+	signedClaimsFile, err := cmd.Flags().GetString(FlagSignedClaimsFile)
+	if err != nil {
+		return err
+	}
+	var signedClaimsStore types.KeyStore
+	if len(signedClaimsFile) != 0 {
+		fileSignedClaimsStore, err := types.NewFileKeyStore(signedClaimsFile)
+		if err != nil {
+			return err
+		}
+		defer fileSignedClaimsStore.Close()
+		signedClaimsStore = fileSignedClaimsStore
+	}
+	txs.ClaimTracker = txs.NewSignedClaimTracker(signedClaimsStore)
+
+	signatureCacheFile, err := cmd.Flags().GetString(FlagSignatureCacheFile)
+	if err != nil {
+		return err
+	}
+	if txs.SignatureCache, err = txs.NewClaimSignatureCache(signatureCacheFile); err != nil {
+		return errors.Errorf("invalid [%s]: %v", FlagSignatureCacheFile, err)
+	}
+	defer txs.SignatureCache.Close()
+	submissionQueueFile, err := cmd.Flags().GetString(FlagSubmissionQueueFile)
+	if err != nil {
+		return err
+	}
+	if len(submissionQueueFile) != 0 {
+		if txs.SubmissionQueue, err = txs.NewClaimSubmissionQueue(submissionQueueFile); err != nil {
+			return errors.Errorf("invalid [%s]: %v", FlagSubmissionQueueFile, err)
+		}
+	}
+
+	haltOnSignatureReuse, err := cmd.Flags().GetBool(FlagHaltOnSignatureReuse)
+	if err != nil {
+		return err
+	}
+	txs.SignatureMonitor = txs.NewSignatureSanityMonitor(haltOnSignatureReuse)
+
+	reconnectBackoff := relayer.DefaultReconnectBackoff
+	if reconnectBackoff.BaseDelay, err = cmd.Flags().GetDuration(FlagReconnectBaseDelay); err != nil {
+		return err
+	}
+	if reconnectBackoff.MaxDelay, err = cmd.Flags().GetDuration(FlagReconnectMaxDelay); err != nil {
+		return err
+	}
+	ethereumSub.ReconnectBackoff = reconnectBackoff
+	harmonySub.ReconnectBackoff = reconnectBackoff
+
+	pollInterval, err := cmd.Flags().GetDuration(FlagPollInterval)
+	if err != nil {
+		return err
+	}
+	pollMaxBlockRange, err := cmd.Flags().GetUint64(FlagPollMaxBlockRange)
+	if err != nil {
+		return err
+	}
+	ethereumSub.PollInterval, ethereumSub.MaxBlockRange = pollInterval, pollMaxBlockRange
+	harmonySub.PollInterval, harmonySub.MaxBlockRange = pollInterval, pollMaxBlockRange
+
+	workers, err := cmd.Flags().GetInt(FlagWorkers)
+	if err != nil {
+		return err
+	}
+	ethereumSub.Workers, harmonySub.Workers = workers, workers
+
+	if ethereumSub.Contracts, err = bridgeContracts(cmd, FlagEthereumBridgeContract); err != nil {
+		return err
+	}
+	if harmonySub.Contracts, err = bridgeContracts(cmd, FlagHarmonyBridgeContract); err != nil {
+		return err
+	}
 
-	// Exit signal enables graceful shutdown
+	if txs.DryRun, err = cmd.Flags().GetBool(FlagDryRun); err != nil {
+		return err
+	}
+	claimExport, err := cmd.Flags().GetString(FlagClaimExport)
+	if err != nil {
+		return err
+	}
+	if len(claimExport) != 0 {
+		if txs.ClaimExport, err = txs.NewClaimExporter(claimExport); err != nil {
+			return errors.Errorf("invalid [%s]: %v", FlagClaimExport, err)
+		}
+		defer txs.ClaimExport.Close()
+	}
+	auditLog, err := cmd.Flags().GetString(FlagAuditLog)
+	if err != nil {
+		return err
+	}
+	auditLogChained, err := cmd.Flags().GetBool(FlagAuditLogChained)
+	if err != nil {
+		return err
+	}
+	if len(auditLog) != 0 {
+		if txs.ClaimAudit, err = txs.NewAuditLog(auditLog, auditLogChained); err != nil {
+			return errors.Errorf("invalid [%s]: %v", FlagAuditLog, err)
+		}
+		defer txs.ClaimAudit.Close()
+	}
+
+	tokenDecimals, err := cmd.Flags().GetStringSlice(FlagTokenDecimals)
+	if err != nil {
+		return err
+	}
+	if len(tokenDecimals) != 0 {
+		txs.Tokens = txs.NewTokenRegistry()
+		for _, value := range tokenDecimals {
+			token, decimals, err := txs.ParseTokenDecimals(value)
+			if err != nil {
+				return err
+			}
+			txs.Tokens.Register(token, decimals.Source, decimals.Dest, decimals.Symbol)
+		}
+	}
+
+	amountScales, err := cmd.Flags().GetStringSlice(FlagTokenAmountScale)
+	if err != nil {
+		return err
+	}
+	if len(amountScales) != 0 {
+		txs.AmountScales = txs.NewAmountScaleRegistry()
+		for _, value := range amountScales {
+			token, scale, err := txs.ParseAmountScale(value)
+			if err != nil {
+				return errors.Errorf("invalid [%s]: %v", FlagTokenAmountScale, err)
+			}
+			txs.AmountScales.Register(token, scale)
+		}
+	}
+
+	tokenSymbols, err := cmd.Flags().GetStringSlice(FlagTokenSymbol)
+	if err != nil {
+		return err
+	}
+	if len(tokenSymbols) != 0 {
+		txs.TokenSymbols = txs.NewTokenSymbolRegistry()
+		for _, value := range tokenSymbols {
+			symbol, tokens, err := txs.ParseTokenSymbol(value)
+			if err != nil {
+				return errors.Errorf("invalid [%s]: %v", FlagTokenSymbol, err)
+			}
+			if err := txs.TokenSymbols.Register(symbol, tokens); err != nil {
+				return errors.Errorf("invalid [%s]: %v", FlagTokenSymbol, err)
+			}
+		}
+	}
+
+	if txs.EthToHmyTokens, err = newTokenMapping(cmd, FlagEthToHmyToken, true); err != nil {
+		return err
+	}
+	if txs.HmyToEthTokens, err = newTokenMapping(cmd, FlagHmyToEthToken, false); err != nil {
+		return err
+	}
+	bridgeTokens, err := cmd.Flags().GetStringSlice(FlagBridgeToken)
+	if err != nil {
+		return err
+	}
+	if len(bridgeTokens) != 0 {
+		txs.BridgeBankTokens = make(map[common.Address]*txs.TokenMapping)
+		for _, value := range bridgeTokens {
+			bridgeBank, source, dest, err := txs.ParseBridgeBankTokenMapping(value)
+			if err != nil {
+				return errors.Errorf("invalid [%s]: %v", FlagBridgeToken, err)
+			}
+			if txs.BridgeBankTokens[bridgeBank] == nil {
+				txs.BridgeBankTokens[bridgeBank] = txs.NewTokenMapping()
+			}
+			txs.BridgeBankTokens[bridgeBank].Register(source, dest)
+		}
+	}
+
+	tokenAllowlist, err := cmd.Flags().GetStringSlice(FlagTokenAllowlist)
+	if err != nil {
+		return err
+	}
+	tokenDenylist, err := cmd.Flags().GetStringSlice(FlagTokenDenylist)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(tokenAllowlist) != 0 && len(tokenDenylist) != 0:
+		return errors.Errorf("only one of --%s and --%s may be set", FlagTokenAllowlist, FlagTokenDenylist)
+	case len(tokenAllowlist) != 0:
+		if txs.ClaimTokenFilter, err = newTokenFilter(txs.AllowlistMode, tokenAllowlist); err != nil {
+			return err
+		}
+	case len(tokenDenylist) != 0:
+		if txs.ClaimTokenFilter, err = newTokenFilter(txs.DenylistMode, tokenDenylist); err != nil {
+			return err
+		}
+	}
+
+	maxClaimAmount, err := cmd.Flags().GetString(FlagMaxClaimAmount)
+	if err != nil {
+		return err
+	}
+	tokenMaxAmounts, err := cmd.Flags().GetStringSlice(FlagTokenMaxAmount)
+	if err != nil {
+		return err
+	}
+	if len(maxClaimAmount) != 0 || len(tokenMaxAmounts) != 0 {
+		if txs.ClaimAmountLimits, err = newAmountLimits(maxClaimAmount, tokenMaxAmounts); err != nil {
+			return err
+		}
+	}
+
+	submitRate, err := cmd.Flags().GetFloat64(FlagSubmitRate)
+	if err != nil {
+		return err
+	}
+	submitBurst, err := cmd.Flags().GetInt(FlagSubmitBurst)
+	if err != nil {
+		return err
+	}
+	if submitRate < 0 {
+		return errors.Errorf("invalid [%s]: %v", FlagSubmitRate, submitRate)
+	}
+	if submitRate > 0 {
+		txs.EthSubmitLimiter = txs.NewRateLimiter(submitRate, submitBurst)
+		txs.HmySubmitLimiter = txs.NewRateLimiter(submitRate, submitBurst)
+	}
+
+	if txs.ClaimSanityChecks.SkipZeroAmount, err = cmd.Flags().GetBool(FlagSkipZeroAmount); err != nil {
+		return err
+	}
+	if txs.ClaimSanityChecks.SkipSelfTransfer, err = cmd.Flags().GetBool(FlagSkipSelfTransfer); err != nil {
+		return err
+	}
+
+	signingScheme, err := cmd.Flags().GetString(FlagSigningScheme)
+	if err != nil {
+		return err
+	}
+	contractSigningSchemes, err := cmd.Flags().GetStringSlice(FlagContractSigningScheme)
+	if err != nil {
+		return err
+	}
+	contractClaimEncodings, err := cmd.Flags().GetStringSlice(FlagContractClaimEncoding)
+	if err != nil {
+		return err
+	}
+	if txs.SigningSchemes, err = newSigningSchemes(signingScheme, contractSigningSchemes,
+		contractClaimEncodings); err != nil {
+		return err
+	}
+
+	txWatchTimeout, err := cmd.Flags().GetDuration(FlagTxWatchTimeout)
+	if err != nil {
+		return err
+	}
+	txMaxRebroadcasts, err := cmd.Flags().GetInt(FlagTxMaxRebroadcasts)
+	if err != nil {
+		return err
+	}
+	txGasBump, err := cmd.Flags().GetFloat64(FlagTxGasBump)
+	if err != nil {
+		return err
+	}
+	if txGasBump < txs.MinGasBump*100 {
+		return errors.Errorf("invalid [%s]: %v%% is below the %v%% nodes require to replace a transaction",
+			FlagTxGasBump, txGasBump, txs.MinGasBump*100)
+	}
+	txSpeedUpAfter, err := cmd.Flags().GetDuration(FlagTxSpeedUpAfter)
+	if err != nil {
+		return err
+	}
+	if txWatchTimeout > 0 {
+		txs.EthTxWatch = &txs.BroadcastPolicy{Timeout: txWatchTimeout, MaxRebroadcasts: txMaxRebroadcasts,
+			GasBump: txGasBump / 100, SpeedUpAfter: txSpeedUpAfter}
+	}
+
+	if txs.EthGasStrategy, txs.HmyGasStrategy, err = gasStrategies(cmd); err != nil {
+		return err
+	}
+
+	if txs.EthUnlockEvents, err = unlockEventVersions(cmd, FlagEthereumUnlockEvent, txs.NewEthUnlockEventRegistry); err != nil {
+		return err
+	}
+	if txs.HmyUnlockEvents, err = unlockEventVersions(cmd, FlagHarmonyUnlockEvent, txs.NewHmyUnlockEventRegistry); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Re-submit the claims a previous run signed but didn't see mined, unless already processed
+	if resubmitted, err := txs.RecoverEthSubmissions(ctx, ethereumProvider, ethereumPrivateKey); err != nil {
+		logger.Error("Recovering queued Ethereum claims failed", "resubmitted", resubmitted, "err", err)
+	}
+	if resubmitted, err := txs.RecoverHmySubmissions(ctx, harmonyProvider, harmonyPrivateKey); err != nil {
+		logger.Error("Recovering queued Harmony claims failed", "resubmitted", resubmitted, "err", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, run := range []func(context.Context) error{harmonySub.Run, ethereumSub.Run} {
+		wg.Add(1)
+		go func(run func(context.Context) error) {
+			defer wg.Done()
+			if err := run(ctx); err != nil {
+				errs <- err
+			}
+		}(run)
+	}
+
+	// Exit signal enables graceful shutdown, letting in-flight events complete and checkpoint
 	exitSignal := make(chan os.Signal, 1)
 	signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
-	<-exitSignal
+	select {
+	case <-exitSignal:
+		logger.Info("Shutting down, draining in-flight events...")
+	case err = <-errs:
+	}
 
-	return nil
+	cancel()
+	wg.Wait()
+	return err
 }
 
 // RunGenerateBindingsCmd : executes the generateBindingsCmd
@@ -164,3 +1110,384 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// registerMetrics registers the claim metrics and mounts them on mux at /metrics
+func registerMetrics(mux *http.ServeMux) error {
+	registry := prometheus.NewRegistry()
+	metrics, err := txs.NewMetrics(registry)
+	if err != nil {
+		return err
+	}
+	txs.SetMetrics(metrics)
+
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return nil
+}
+
+// fallbackEndpoints registers provider's fallback URLs given by flag in txs.RPCEndpoints,
+// returning provider's Endpoints, or nil if it has no fallbacks
+func fallbackEndpoints(cmd *cobra.Command, flag, provider string) (*txs.Endpoints, error) {
+	fallbacks, err := cmd.Flags().GetStringSlice(flag)
+	if err != nil {
+		return nil, err
+	}
+	if len(fallbacks) == 0 {
+		return nil, nil
+	}
+	for _, fallback := range fallbacks {
+		if !relayer.IsWebsocketURL(fallback) {
+			return nil, errors.Errorf("invalid [%s]: %s", flag, fallback)
+		}
+	}
+
+	endpoints := txs.NewEndpoints(append([]string{provider}, fallbacks...)...)
+	if txs.RPCEndpoints == nil {
+		txs.RPCEndpoints = txs.NewEndpointRegistry()
+	}
+	txs.RPCEndpoints.Register(endpoints)
+	return endpoints, nil
+}
+
+// newHealthCheck builds a HealthCheck over its own connections to both chains, returning a
+// function closing them
+func newHealthCheck(progress *relayer.Progress, maxLag uint64, ethereumProvider, harmonyProvider string,
+	ethereumPrivateKey, harmonyPrivateKey *ecdsa.PrivateKey, logger tmLog.Logger) (*relayer.HealthCheck, func(), error) {
+	ethereumValidator, err := txs.LoadSender(ethereumPrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	harmonyValidator, err := txs.LoadSender(harmonyPrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ethereumClient, err := relayer.EthSetupWebsocketClient(context.Background(), ethereumProvider,
+		relayer.DefaultReconnectBackoff, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	harmonyClient, err := relayer.HmySetupWebsocketClient(context.Background(), harmonyProvider,
+		relayer.DefaultReconnectBackoff, logger)
+	if err != nil {
+		ethereumClient.Close()
+		return nil, nil, err
+	}
+
+	healthCheck := relayer.NewHealthCheck(progress, maxLag)
+	healthCheck.AddChain(relayer.EthereumChain, relayer.NewEthCanonicalChain(ethereumClient), ethereumValidator)
+	healthCheck.AddChain(relayer.HarmonyChain, harmonyClient, harmonyValidator)
+	healthCheck.SetGasBalance(relayer.EthereumChain, ethereumClient, txs.EthGasBalance)
+	healthCheck.SetGasBalance(relayer.HarmonyChain, harmonyClient, txs.HmyGasBalance)
+	return healthCheck, func() {
+		ethereumClient.Close()
+		harmonyClient.Close()
+	}, nil
+}
+
+// muxFor returns the mux serving addr, creating it if needed
+func muxFor(muxes map[string]*http.ServeMux, addr string) *http.ServeMux {
+	mux, ok := muxes[addr]
+	if !ok {
+		mux = http.NewServeMux()
+		muxes[addr] = mux
+	}
+	return mux
+}
+
+// serveMuxes serves each mux on its address in the background
+func serveMuxes(muxes map[string]*http.ServeMux, logger tmLog.Logger) {
+	for addr, mux := range muxes {
+		go func(addr string, mux *http.ServeMux) {
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				logger.Error("HTTP server on "+addr+" stopped: ", err.Error())
+			}
+		}(addr, mux)
+	}
+}
+
+// newTokenMapping builds a TokenMapping from the source=dest values of flag, or nil if it has none.
+// A value without a "=" is a symbol of txs.TokenSymbols, mapping its Ethereum token to its Harmony
+// token if ethToHmy is set, or the reverse otherwise.
+func newTokenMapping(cmd *cobra.Command, flag string, ethToHmy bool) (*txs.TokenMapping, error) {
+	values, err := cmd.Flags().GetStringSlice(flag)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	mapping := txs.NewTokenMapping()
+	for _, value := range values {
+		if !strings.Contains(value, "=") {
+			if txs.TokenSymbols == nil {
+				return nil, errors.Errorf("invalid [%s]: %s is not source=dest, and no --%s is set", flag, value,
+					FlagTokenSymbol)
+			}
+			if err := txs.TokenSymbols.RegisterMapping(mapping, value, ethToHmy); err != nil {
+				return nil, errors.Errorf("invalid [%s]: %v", flag, err)
+			}
+			continue
+		}
+		source, dest, err := txs.ParseTokenMapping(value)
+		if err != nil {
+			return nil, errors.Errorf("invalid [%s]: %v", flag, err)
+		}
+		mapping.Register(source, dest)
+	}
+	return mapping, nil
+}
+
+// nativeTokenDecimals are the decimals of both chains' native tokens, ETH and ONE
+const nativeTokenDecimals = 18
+
+// gasStrategies returns the Ethereum and Harmony gas strategies of the gas price multiplier flag
+func gasStrategies(cmd *cobra.Command) (txs.GasStrategy, txs.GasStrategy, error) {
+	ethStrategy, hmyStrategy := txs.Legacy(nil), txs.Legacy(nil)
+
+	multiplier, err := cmd.Flags().GetFloat64(FlagGasPriceMultiplier)
+	if err != nil {
+		return ethStrategy, hmyStrategy, err
+	}
+	if multiplier <= 0 {
+		return ethStrategy, hmyStrategy, errors.Errorf("invalid [%s]: %v is not positive", FlagGasPriceMultiplier, multiplier)
+	}
+	ethStrategy.Multiplier, hmyStrategy.Multiplier = multiplier, multiplier
+	return ethStrategy, hmyStrategy, nil
+}
+
+// newGasBalanceGuard builds a GasBalanceGuard for chain from the minimum balance of flag, given in
+// whole native tokens, or nil if it is empty
+func newGasBalanceGuard(cmd *cobra.Command, flag, chain string, pause bool) (*txs.GasBalanceGuard, error) {
+	value, err := cmd.Flags().GetString(flag)
+	if err != nil || len(value) == 0 {
+		return nil, err
+	}
+	minimum, err := txs.ParseTokenAmount(value, nativeTokenDecimals)
+	if err == nil && minimum.Sign() < 0 {
+		err = errors.Errorf("negative balance %s", value)
+	}
+	if err != nil {
+		return nil, errors.Errorf("invalid [%s]: %v", flag, err)
+	}
+	return txs.NewGasBalanceGuard(chain, minimum, pause), nil
+}
+
+// bridgeContracts parses the address=direction[@registry] values of flag
+func bridgeContracts(cmd *cobra.Command, flag string) ([]relayer.BridgeContract, error) {
+	values, err := cmd.Flags().GetStringSlice(flag)
+	if err != nil {
+		return nil, err
+	}
+	var contracts []relayer.BridgeContract
+	for _, value := range values {
+		contract, err := relayer.ParseBridgeContract(value)
+		if err != nil {
+			return nil, errors.Errorf("invalid [%s]: %v", flag, err)
+		}
+		contracts = append(contracts, contract)
+	}
+	return contracts, nil
+}
+
+// unlockEventVersions registers the UnlockClaim event versions in the files flag names with a
+// registry from newRegistry, returning nil if it names none
+func unlockEventVersions(cmd *cobra.Command, flag string,
+	newRegistry func() (*txs.UnlockEventRegistry, error)) (*txs.UnlockEventRegistry, error) {
+	paths, err := cmd.Flags().GetStringSlice(flag)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	registry, err := newRegistry()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		version, err := txs.LoadUnlockEventVersion(path)
+		if err != nil {
+			return nil, errors.Errorf("invalid [%s]: %v", flag, err)
+		}
+		if err := registry.Register(version); err != nil {
+			return nil, errors.Errorf("invalid [%s]: %v", flag, err)
+		}
+	}
+	return registry, nil
+}
+
+// newTokenFilter builds a TokenFilter from hex token addresses
+func newTokenFilter(mode txs.TokenFilterMode, addresses []string) (*txs.TokenFilter, error) {
+	tokens := make([]common.Address, len(addresses))
+	for i, address := range addresses {
+		if err := txs.ValidateAddressChecksum(address, false); err != nil {
+			return nil, errors.Errorf("invalid token address in %s: %v", mode, err)
+		}
+		tokens[i] = common.HexToAddress(address)
+	}
+	return txs.NewTokenFilter(mode, tokens...), nil
+}
+
+// newAmountLimits builds AmountLimits from a default limit, which may be empty, and address=amount
+// token limits
+func newAmountLimits(defaultMax string, tokenMaxAmounts []string) (*txs.AmountLimits, error) {
+	var fallback *big.Int
+	if len(defaultMax) != 0 {
+		var ok bool
+		if fallback, ok = new(big.Int).SetString(defaultMax, 10); !ok {
+			return nil, errors.Errorf("invalid [%s]: %s", FlagMaxClaimAmount, defaultMax)
+		}
+	}
+
+	limits := txs.NewAmountLimits(fallback)
+	for _, value := range tokenMaxAmounts {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+			return nil, errors.Errorf("invalid [%s]: %s", FlagTokenMaxAmount, value)
+		}
+		if err := txs.ValidateAddressChecksum(parts[0], false); err != nil {
+			return nil, errors.Errorf("invalid [%s]: %v", FlagTokenMaxAmount, err)
+		}
+		max, ok := new(big.Int).SetString(parts[1], 10)
+		if !ok {
+			return nil, errors.Errorf("invalid [%s]: %s", FlagTokenMaxAmount, value)
+		}
+		limits.SetMax(common.HexToAddress(parts[0]), max)
+	}
+	return limits, nil
+}
+
+// newSigningSchemes builds the signing scheme registry from the default scheme, the
+// address=scheme values of contracts with their own, and the address=mode claim encodings of
+// contracts not using the packed layout
+func newSigningSchemes(defaultScheme string, contractSchemes, contractEncodings []string) (*txs.SigningSchemeRegistry, error) {
+	scheme, err := txs.ParseSigningScheme(defaultScheme)
+	if err != nil {
+		return nil, errors.Errorf("invalid [%s]: %v", FlagSigningScheme, err)
+	}
+	if scheme == txs.EIP712 {
+		return nil, errors.Errorf("invalid [%s]: eip712 needs a domain, set it per contract with --%s",
+			FlagSigningScheme, FlagContractSigningScheme)
+	}
+
+	schemes := txs.NewSigningSchemeRegistry(txs.SigningConfig{Scheme: scheme})
+	for _, value := range contractSchemes {
+		contract, config, err := txs.ParseSigningConfig(value)
+		if err != nil {
+			return nil, errors.Errorf("invalid [%s]: %v", FlagContractSigningScheme, err)
+		}
+		schemes.Register(contract, config)
+	}
+	for _, value := range contractEncodings {
+		contract, mode, err := txs.ParseClaimEncoding(value)
+		if err != nil {
+			return nil, errors.Errorf("invalid [%s]: %v", FlagContractClaimEncoding, err)
+		}
+		schemes.SetEncoding(contract, mode)
+	}
+	return schemes, nil
+}
+
+// selectEthereumKey returns the only key of keys or, while rotating keys, the key the Ethereum
+// Valset currently registers as an active validator
+func selectEthereumKey(keys []*ecdsa.PrivateKey, provider string, registry common.Address) (*ecdsa.PrivateKey, error) {
+	if len(keys) == 1 {
+		return keys[0], nil
+	}
+
+	client, err := ethclient.Dial(provider)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	signers := txs.NewKeySignerSet(keys)
+	validator, err := txs.EthActiveValidator(keys[0], client, registry, signers.Addresses())
+	if err != nil {
+		return nil, err
+	}
+	signer, err := signers.SelectSigner(validator)
+	if err != nil {
+		return nil, err
+	}
+	return signer.(*txs.KeySigner).PrivateKey(), nil
+}
+
+// checkRegistryOrigin checks registry is the address deployed by the origin of flag, if set
+func checkRegistryOrigin(cmd *cobra.Command, flag string, registry common.Address) error {
+	value, err := cmd.Flags().GetString(flag)
+	if err != nil || len(value) == 0 {
+		return err
+	}
+	origin, err := txs.ParseContractOrigin(value)
+	if err != nil {
+		return errors.Errorf("invalid [%s]: %v", flag, err)
+	}
+	if err := origin.Verify(registry); err != nil {
+		return errors.Errorf("[%s]: %v", flag, err)
+	}
+	return nil
+}
+
+// checkEthereumValidatorSet checks the Ethereum Valset registers key as a validator
+func checkEthereumValidatorSet(key *ecdsa.PrivateKey, provider string, registry common.Address) error {
+	client, err := ethclient.Dial(provider)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	valsetAddress, err := txs.EthGetAddressFromBridgeRegistry(key, client, registry, txs.Valset)
+	if err != nil {
+		return err
+	}
+	check, err := txs.NewEthValidatorCheck(client, valsetAddress)
+	if err != nil {
+		return err
+	}
+	validator, err := txs.LoadSender(key)
+	if err != nil {
+		return err
+	}
+	return check.Check(context.Background(), validator)
+}
+
+// checkChainIDs checks the Ethereum and Harmony nodes serve the networks of txs.EthChainID and
+// txs.HmyChainID
+func checkChainIDs(ethereumProvider, harmonyProvider string) error {
+	ethereumClient, err := ethclient.Dial(ethereumProvider)
+	if err != nil {
+		return err
+	}
+	defer ethereumClient.Close()
+	if err := txs.CheckChainID(context.Background(), relayer.EthereumChain, ethereumClient, txs.EthChainID); err != nil {
+		return err
+	}
+
+	harmonyClient, err := hmyclient.Dial(harmonyProvider)
+	if err != nil {
+		return err
+	}
+	defer harmonyClient.Close()
+	return txs.CheckChainID(context.Background(), relayer.HarmonyChain, harmonyClient, txs.HmyChainID)
+}
+
+// checkHarmonyValidatorSet checks the Harmony Valset registers key as a validator
+func checkHarmonyValidatorSet(key *ecdsa.PrivateKey, provider string, registry common.Address) error {
+	client, err := hmyclient.Dial(provider)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	valsetAddress, err := txs.HmyGetAddressFromBridgeRegistry(key, client, registry, txs.Valset)
+	if err != nil {
+		return err
+	}
+	check, err := txs.NewHmyValidatorCheck(client, valsetAddress)
+	if err != nil {
+		return err
+	}
+	validator, err := txs.LoadSender(key)
+	if err != nil {
+		return err
+	}
+	return check.Check(context.Background(), validator)
+}
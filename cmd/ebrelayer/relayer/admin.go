@@ -0,0 +1,48 @@
+package relayer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+const (
+	// PausePath is the path an Admin pauses claim signing at
+	PausePath = "/pause"
+	// ResumePath is the path an Admin resumes claim signing at
+	ResumePath = "/resume"
+)
+
+// PauseState is the JSON body served by an Admin
+type PauseState struct {
+	Paused bool `json:"paused"`
+	// Changed is set if the request paused or resumed signing, rather than finding it so already
+	Changed bool `json:"changed"`
+}
+
+// Admin serves operator controls: POST PausePath pauses claim signing and submission with
+// txs.PauseSigning, holding events rather than dropping them, and POST ResumePath resumes it. It
+// doesn't authenticate requests, so should only listen on a loopback or otherwise private address.
+type Admin struct{}
+
+// Register mounts the Admin on mux at PausePath and ResumePath
+func (a Admin) Register(mux *http.ServeMux) {
+	mux.HandleFunc(PausePath, a.handle(txs.PauseSigning))
+	mux.HandleFunc(ResumePath, a.handle(txs.ResumeSigning))
+}
+
+// handle returns a handler applying toggle to POST requests and reporting the resulting state
+func (Admin) handle(toggle func() bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		changed := toggle()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(PauseState{Paused: txs.SigningPaused(), Changed: changed})
+	}
+}
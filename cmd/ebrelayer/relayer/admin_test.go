@@ -0,0 +1,87 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// postAdmin sends a request to the Admin mounted on mux, returning its status code and state
+func postAdmin(t *testing.T, mux *http.ServeMux, method, path string) (int, PauseState) {
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+	var state PauseState
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+	}
+	return rec.Code, state
+}
+
+func TestAdminPauseHoldsSigning(t *testing.T) {
+	defer txs.ResumeSigning()
+	mux := http.NewServeMux()
+	Admin{}.Register(mux)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code, _ := postAdmin(t, mux, http.MethodGet, PausePath); code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET %s = %d, want 405", PausePath, code)
+	}
+	if code, state := postAdmin(t, mux, http.MethodPost, PausePath); code != http.StatusOK || !state.Paused || !state.Changed {
+		t.Fatalf("POST %s = %d, %+v, want paused", PausePath, code, state)
+	}
+
+	// As an event handler does: wait for signing to resume, then sign the claim
+	signed := make(chan error, 1)
+	go func() {
+		if err := txs.WaitSigningResumed(context.Background()); err != nil {
+			signed <- err
+			return
+		}
+		_, err := txs.SignClaim(txs.PrefixMsg(crypto.Keccak256([]byte("claim"))), key)
+		signed <- err
+	}()
+	select {
+	case err := <-signed:
+		t.Fatalf("claim signed while paused: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if code, state := postAdmin(t, mux, http.MethodPost, ResumePath); code != http.StatusOK || state.Paused || !state.Changed {
+		t.Fatalf("POST %s = %d, %+v, want resumed", ResumePath, code, state)
+	}
+	select {
+	case err := <-signed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("held claim wasn't signed after resuming")
+	}
+	if _, state := postAdmin(t, mux, http.MethodPost, ResumePath); state.Changed {
+		t.Fatal("resuming signing that wasn't paused reported a change")
+	}
+}
+
+func TestWaitSigningResumedCancelled(t *testing.T) {
+	defer txs.ResumeSigning()
+	txs.PauseSigning()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := txs.WaitSigningResumed(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("WaitSigningResumed while paused = %v, want the context's error", err)
+	}
+}
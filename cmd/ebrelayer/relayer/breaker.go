@@ -0,0 +1,22 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// submitWhenAllowed runs submit once breaker may let a claim through, waiting again whenever
+// submit is refused with ErrCircuitOpen as another claim took the probe. Claims are held while
+// the breaker is open rather than failed, as while signing is paused.
+func submitWhenAllowed(ctx context.Context, breaker *txs.CircuitBreaker, submit func() error) error {
+	for {
+		if err := breaker.Wait(ctx); err != nil {
+			return err
+		}
+		if err := submit(); !errors.Is(err, txs.ErrCircuitOpen) {
+			return err
+		}
+	}
+}
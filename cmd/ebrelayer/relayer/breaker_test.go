@@ -0,0 +1,42 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+func TestSubmitWhenAllowedHoldsRefusedClaims(t *testing.T) {
+	breaker := txs.NewCircuitBreaker(EthereumChain, 1, 10*time.Millisecond)
+	breaker.Record(txs.ErrTxReverted)
+
+	calls := 0
+	err := submitWhenAllowed(context.Background(), breaker, func() error {
+		calls++
+		if err := breaker.Allow(); err != nil {
+			return err
+		}
+		breaker.Record(nil)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("submitWhenAllowed = %v, want the claim held until the breaker let it through", err)
+	}
+	if calls != 1 {
+		t.Fatalf("submitted %d times, want once, after the cooldown", calls)
+	}
+	if breaker.Open() {
+		t.Fatal("breaker still open after the held claim was mined")
+	}
+}
+
+func TestSubmitWhenAllowedReturnsOtherErrors(t *testing.T) {
+	failed := errors.New("insufficient funds")
+	err := submitWhenAllowed(context.Background(), nil, func() error { return failed })
+	if err != failed {
+		t.Fatalf("submitWhenAllowed = %v, want %v", err, failed)
+	}
+}
@@ -0,0 +1,140 @@
+package relayer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+const (
+	// EthereumChain is the checkpoint key of the Ethereum chain
+	EthereumChain = "ethereum"
+	// HarmonyChain is the checkpoint key of the Harmony chain
+	HarmonyChain = "harmony"
+)
+
+// CheckpointStore persists the last block processed on each chain, so the relayer resumes where
+// it stopped instead of rescanning or skipping events after a restart
+type CheckpointStore interface {
+	// Save records block as the last processed block on chain
+	Save(chain string, block uint64) error
+	// Load returns the last processed block on chain, or 0 if none was saved
+	Load(chain string) (uint64, error)
+}
+
+// StartBlock returns the block to resume processing chain from: the later of its checkpoint and
+// the configured start block. The checkpointed block itself is replayed, as it may have been
+// only partially processed.
+func StartBlock(store CheckpointStore, chain string, configuredStart uint64) (uint64, error) {
+	checkpoint, err := store.Load(chain)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint > configuredStart {
+		return checkpoint, nil
+	}
+	return configuredStart, nil
+}
+
+// FileCheckpointStore is a CheckpointStore persisting every chain's checkpoint in one JSON file
+type FileCheckpointStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCheckpointStore initializes a new FileCheckpointStore at path. The file is created on
+// the first Save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Save implements CheckpointStore. The file is replaced atomically, so a crash mid-write leaves
+// the previous checkpoints intact.
+func (s *FileCheckpointStore) Save(chain string, block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[chain] = block
+
+	data, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+	return types.WriteFileAtomic(s.path, data)
+}
+
+// Load implements CheckpointStore
+func (s *FileCheckpointStore) Load(chain string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+	return checkpoints[chain], nil
+}
+
+// read loads all checkpoints, treating a missing file as empty
+func (s *FileCheckpointStore) read() (map[string]uint64, error) {
+	checkpoints := make(map[string]uint64)
+
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+// EventCheckpoints saves a chain's contract checkpoints as its events complete in order. Once an
+// event fails, neither its contract's checkpoint nor the chain's advances again for the rest of
+// the run, so a later event completing never checkpoints past the failed one, which is replayed
+// from the checkpoint on the next run. Its methods are called one at a time, as a WorkerPool's
+// done callbacks are.
+type EventCheckpoints struct {
+	store  CheckpointStore
+	chain  string
+	failed map[common.Address]uint64
+}
+
+// NewEventCheckpoints initializes a new EventCheckpoints saving chain's checkpoints into store
+func NewEventCheckpoints(store CheckpointStore, chain string) *EventCheckpoints {
+	return &EventCheckpoints{store: store, chain: chain, failed: make(map[common.Address]uint64)}
+}
+
+// Done records the outcome of contract's event in block, checkpointing block unless the event
+// or an earlier one failed. It returns the error saving the checkpoint, if any.
+func (c *EventCheckpoints) Done(contract common.Address, block uint64, eventErr error) error {
+	if eventErr != nil {
+		if _, ok := c.failed[contract]; !ok {
+			c.failed[contract] = block
+		}
+		return nil
+	}
+	if _, ok := c.failed[contract]; ok {
+		return nil
+	}
+	if err := c.store.Save(ContractCheckpointKey(c.chain, contract), block); err != nil {
+		return err
+	}
+	if len(c.failed) > 0 {
+		return nil
+	}
+	return c.store.Save(c.chain, block)
+}
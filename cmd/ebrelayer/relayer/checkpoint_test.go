@@ -0,0 +1,169 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// memoryCheckpoints is a CheckpointStore held in memory
+type memoryCheckpoints struct {
+	mu     sync.Mutex
+	blocks map[string]uint64
+}
+
+func newMemoryCheckpoints() *memoryCheckpoints {
+	return &memoryCheckpoints{blocks: make(map[string]uint64)}
+}
+
+func (m *memoryCheckpoints) Save(chain string, block uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.blocks[chain] = block
+	return nil
+}
+
+func (m *memoryCheckpoints) Load(chain string) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.blocks[chain], nil
+}
+
+// testDir creates a temporary directory, returning it and the function removing it
+func testDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestStartBlockMissingCheckpointFile(t *testing.T) {
+	dir, cleanup := testDir(t)
+	defer cleanup()
+	store := NewFileCheckpointStore(filepath.Join(dir, "checkpoints.json"))
+	start, err := StartBlock(store, EthereumChain, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 100 {
+		t.Fatalf("StartBlock = %d, want the configured start 100", start)
+	}
+}
+
+func TestStartBlockResumesAfterRestart(t *testing.T) {
+	dir, cleanup := testDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "checkpoints.json")
+	if err := NewFileCheckpointStore(path).Save(EthereumChain, 250); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store over the same file stands in for the restarted relayer
+	restarted := NewFileCheckpointStore(path)
+	for _, tt := range []struct {
+		configured, want uint64
+	}{
+		{100, 250},
+		{300, 300},
+	} {
+		start, err := StartBlock(restarted, EthereumChain, tt.configured)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if start != tt.want {
+			t.Fatalf("StartBlock(configured %d) = %d, want %d", tt.configured, start, tt.want)
+		}
+	}
+	if start, _ := StartBlock(restarted, HarmonyChain, 7); start != 7 {
+		t.Fatalf("StartBlock of an unsaved chain = %d, want 7", start)
+	}
+}
+
+func TestEventCheckpointsHoldAtFirstFailure(t *testing.T) {
+	contract, other := common.HexToAddress("0xa"), common.HexToAddress("0xb")
+	routes := ContractRoutes{contract: {Address: contract}, other: {Address: other}}
+	store := newMemoryCheckpoints()
+	checkpoints := NewEventCheckpoints(store, EthereumChain)
+
+	events := []struct {
+		contract common.Address
+		block    uint64
+		err      error
+	}{
+		{contract, 10, nil},
+		{contract, 11, errors.New("submission reverted")},
+		{contract, 12, nil},
+		{other, 13, nil},
+	}
+	for _, event := range events {
+		if err := checkpoints.Done(event.contract, event.block, event.err); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if block, _ := store.Load(ContractCheckpointKey(EthereumChain, contract)); block != 10 {
+		t.Fatalf("failed contract checkpointed at %d, want it held at 10", block)
+	}
+	if block, _ := store.Load(ContractCheckpointKey(EthereumChain, other)); block != 13 {
+		t.Fatalf("other contract checkpointed at %d, want 13", block)
+	}
+	if block, _ := store.Load(EthereumChain); block != 10 {
+		t.Fatalf("chain checkpointed at %d, want it held at 10", block)
+	}
+
+	start, err := ContractsStartBlock(store, EthereumChain, routes, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start > 11 {
+		t.Fatalf("next run starts at block %d, past the failed event in block 11", start)
+	}
+}
+
+// TestEventCheckpointsCancelMidBatch cancels a run while later events have already completed,
+// as a shutdown with several workers does, and checks the checkpoint stays before the abandoned ones
+func TestEventCheckpointsCancelMidBatch(t *testing.T) {
+	contract := common.HexToAddress("0xa")
+	store := newMemoryCheckpoints()
+	checkpoints := NewEventCheckpoints(store, EthereumChain)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool := NewWorkerPool(4)
+	for block := uint64(1); block <= 8; block++ {
+		block := block
+		var err error
+		pool.Go(func() {
+			if block%4 == 3 {
+				// Mid-confirmation when the relayer shuts down
+				cancel()
+				<-ctx.Done()
+				err = ctx.Err()
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}, func() {
+			if saveErr := checkpoints.Done(contract, block, err); saveErr != nil {
+				t.Error(saveErr)
+			}
+		})
+	}
+	pool.Wait()
+
+	if block, _ := store.Load(ContractCheckpointKey(EthereumChain, contract)); block != 2 {
+		t.Fatalf("checkpoint = %d, want 2, before the first abandoned event", block)
+	}
+	if block, _ := store.Load(EthereumChain); block != 2 {
+		t.Fatalf("chain checkpoint = %d, want 2", block)
+	}
+}
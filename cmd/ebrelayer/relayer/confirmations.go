@@ -0,0 +1,195 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/mochi-lab/eth-one-bridge/hmyclient"
+)
+
+// DefaultConfirmationPollInterval is how often the chain head is polled while waiting for confirmations
+const DefaultConfirmationPollInterval = 5 * time.Second
+
+// ErrEventReorged is returned when an event's block is no longer on the canonical chain
+var ErrEventReorged = errors.New("event block was reorged out of the canonical chain")
+
+// ErrEventVanished is returned when an event's transaction is no longer included at the block it
+// was witnessed in
+var ErrEventVanished = errors.New("event transaction is no longer in the canonical chain")
+
+// CanonicalChain queries a chain's head and the hashes of its canonical blocks
+type CanonicalChain interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error)
+}
+
+// ethCanonicalChain adapts an ethclient.Client to CanonicalChain
+type ethCanonicalChain struct {
+	client *ethclient.Client
+}
+
+// NewEthCanonicalChain returns a CanonicalChain backed by an Ethereum client
+func NewEthCanonicalChain(client *ethclient.Client) CanonicalChain {
+	return ethCanonicalChain{client}
+}
+
+// BlockNumber implements CanonicalChain
+func (c ethCanonicalChain) BlockNumber(ctx context.Context) (uint64, error) {
+	header, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// BlockHashByNumber implements CanonicalChain
+func (c ethCanonicalChain) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	header, err := c.client.HeaderByNumber(ctx, number)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// TxLocator finds the block including a transaction
+type TxLocator interface {
+	// TransactionBlock returns the number and hash of the block including txHash, or
+	// ethereum.NotFound if no canonical block includes it
+	TransactionBlock(ctx context.Context, txHash common.Hash) (uint64, common.Hash, error)
+}
+
+// ethTxLocator adapts an ethclient.Client to TxLocator
+type ethTxLocator struct {
+	client *ethclient.Client
+}
+
+// NewEthTxLocator returns a TxLocator backed by an Ethereum client's transaction receipts
+func NewEthTxLocator(client *ethclient.Client) TxLocator {
+	return ethTxLocator{client}
+}
+
+// TransactionBlock implements TxLocator
+func (l ethTxLocator) TransactionBlock(ctx context.Context, txHash common.Hash) (uint64, common.Hash, error) {
+	receipt, err := l.client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	return receipt.BlockNumber.Uint64(), receipt.BlockHash, nil
+}
+
+// hmyTxLocator adapts a hmyclient.Client to TxLocator
+type hmyTxLocator struct {
+	client *hmyclient.Client
+}
+
+// NewHmyTxLocator returns a TxLocator backed by a Harmony client's transaction receipts
+func NewHmyTxLocator(client *hmyclient.Client) TxLocator {
+	return hmyTxLocator{client}
+}
+
+// TransactionBlock implements TxLocator
+func (l hmyTxLocator) TransactionBlock(ctx context.Context, txHash common.Hash) (uint64, common.Hash, error) {
+	receipt, err := l.client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	return receipt.BlockNumber.Uint64(), receipt.BlockHash, nil
+}
+
+// Confirmations holds an event back from claim generation until it is buried under Depth blocks.
+// If Txs is set, Revalidate re-checks the event just before its claim is submitted.
+type Confirmations struct {
+	Chain        CanonicalChain
+	Txs          TxLocator
+	Depth        uint64
+	PollInterval time.Duration
+}
+
+// NewConfirmations initializes a new Confirmations polling at DefaultConfirmationPollInterval
+func NewConfirmations(chain CanonicalChain, depth uint64) Confirmations {
+	return Confirmations{Chain: chain, Depth: depth, PollInterval: DefaultConfirmationPollInterval}
+}
+
+// Wait blocks until the block blockNumber has Depth confirmations, then checks that its hash still
+// matches blockHash. It returns ErrEventReorged if the block was replaced, or ctx's error if ctx
+// is done first. A zero Depth returns immediately.
+func (c Confirmations) Wait(ctx context.Context, blockNumber uint64, blockHash common.Hash) error {
+	if c.Depth == 0 {
+		return nil
+	}
+
+	for {
+		confirmed, err := c.Check(ctx, blockNumber, blockHash)
+		if err != nil || confirmed {
+			return err
+		}
+
+		timer := time.NewTimer(c.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Check reports whether the block blockNumber has Depth confirmations, returning ErrEventReorged
+// if it has but its hash no longer matches blockHash
+func (c Confirmations) Check(ctx context.Context, blockNumber uint64, blockHash common.Hash) (bool, error) {
+	head, err := c.Chain.BlockNumber(ctx)
+	if err != nil {
+		return false, err
+	}
+	if head < blockNumber || head-blockNumber < c.Depth {
+		return false, nil
+	}
+
+	canonicalHash, err := c.Chain.BlockHashByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return false, err
+	}
+	if canonicalHash != blockHash {
+		return false, ErrEventReorged
+	}
+	return true, nil
+}
+
+// Revalidate checks, just before a signed claim is submitted, that the event's transaction txHash
+// is still included in the canonical block blockNumber with hash blockHash, returning
+// ErrEventVanished otherwise. It closes the window between Wait and submission in which a deep
+// reorg could drop the event. It returns nil if Txs is unset.
+func (c Confirmations) Revalidate(ctx context.Context, txHash common.Hash, blockNumber uint64,
+	blockHash common.Hash) error {
+	if c.Txs == nil {
+		return nil
+	}
+
+	number, hash, err := c.Txs.TransactionBlock(ctx, txHash)
+	if err == ethereum.NotFound {
+		return fmt.Errorf("%w: %s not found", ErrEventVanished, txHash.Hex())
+	}
+	if err != nil {
+		return err
+	}
+	if number != blockNumber || hash != blockHash {
+		return fmt.Errorf("%w: %s moved from block %d (%s) to %d (%s)", ErrEventVanished, txHash.Hex(),
+			blockNumber, blockHash.Hex(), number, hash.Hex())
+	}
+
+	canonicalHash, err := c.Chain.BlockHashByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return err
+	}
+	if canonicalHash != blockHash {
+		return fmt.Errorf("%w: block %d is now %s", ErrEventVanished, blockNumber, canonicalHash.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// testBlock is a block of a testChain, and the transactions it includes
+type testBlock struct {
+	hash common.Hash
+	txs  []common.Hash
+}
+
+// testChain is a CanonicalChain and TxLocator whose blocks tests replace to simulate reorgs
+type testChain struct {
+	mu     sync.Mutex
+	blocks map[uint64]testBlock
+	head   uint64
+}
+
+func newTestChain() *testChain {
+	return &testChain{blocks: make(map[uint64]testBlock)}
+}
+
+// set makes block the canonical block number, moving the head up to it if it is lower
+func (c *testChain) set(number uint64, block testBlock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.blocks[number] = block
+	if number > c.head {
+		c.head = number
+	}
+}
+
+func (c *testChain) BlockNumber(context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.head, nil
+}
+
+func (c *testChain) BlockHashByNumber(_ context.Context, number *big.Int) (common.Hash, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	block, ok := c.blocks[number.Uint64()]
+	if !ok {
+		return common.Hash{}, ethereum.NotFound
+	}
+	return block.hash, nil
+}
+
+func (c *testChain) TransactionBlock(_ context.Context, txHash common.Hash) (uint64, common.Hash, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for number, block := range c.blocks {
+		for _, tx := range block.txs {
+			if tx == txHash {
+				return number, block.hash, nil
+			}
+		}
+	}
+	return 0, common.Hash{}, ethereum.NotFound
+}
+
+// testConfirmations returns Confirmations of depth over chain, polling every millisecond
+func testConfirmations(chain *testChain, depth uint64) Confirmations {
+	confirmations := NewConfirmations(chain, depth)
+	confirmations.Txs = chain
+	confirmations.PollInterval = time.Millisecond
+	return confirmations
+}
+
+var (
+	eventTx    = common.HexToHash("0xe1")
+	eventBlock = testBlock{hash: common.HexToHash("0xb10"), txs: []common.Hash{eventTx}}
+)
+
+func TestConfirmationsWaitForDepth(t *testing.T) {
+	chain := newTestChain()
+	chain.set(10, eventBlock)
+	confirmations := testConfirmations(chain, 3)
+
+	if confirmed, err := confirmations.Check(context.Background(), 10, eventBlock.hash); err != nil || confirmed {
+		t.Fatalf("Check = %v, %v, want the event unconfirmed at the head", confirmed, err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- confirmations.Wait(context.Background(), 10, eventBlock.hash) }()
+	for number := uint64(11); number <= 13; number++ {
+		select {
+		case err := <-done:
+			t.Fatalf("Wait returned %v with the head at %d, before 3 confirmations", err, number-1)
+		case <-time.After(5 * time.Millisecond):
+		}
+		chain.set(number, testBlock{hash: common.BigToHash(new(big.Int).SetUint64(number))})
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Wait = %v, want the event confirmed", err)
+	}
+}
+
+func TestConfirmationsReorgDropsUnconfirmedEvent(t *testing.T) {
+	chain := newTestChain()
+	chain.set(10, eventBlock)
+	confirmations := testConfirmations(chain, 2)
+
+	done := make(chan error, 1)
+	go func() { done <- confirmations.Wait(context.Background(), 10, eventBlock.hash) }()
+
+	// The event's block is replaced by one without it before it is confirmed
+	chain.set(10, testBlock{hash: common.HexToHash("0xc10")})
+	chain.set(11, testBlock{hash: common.HexToHash("0xc11")})
+	chain.set(12, testBlock{hash: common.HexToHash("0xc12")})
+	if err := <-done; err != ErrEventReorged {
+		t.Fatalf("Wait = %v, want ErrEventReorged", err)
+	}
+}
+
+func TestConfirmationsWaitZeroDepth(t *testing.T) {
+	// A zero depth never queries the chain
+	if err := NewConfirmations(nil, 0).Wait(context.Background(), 10, eventBlock.hash); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfirmationsWaitContextDone(t *testing.T) {
+	chain := newTestChain()
+	chain.set(10, eventBlock)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := testConfirmations(chain, 5).Wait(ctx, 10, eventBlock.hash); err != context.DeadlineExceeded {
+		t.Fatalf("Wait = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestConfirmationsRevalidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		reorg   func(*testChain)
+		wantErr error
+	}{
+		{"unchanged", func(*testChain) {}, nil},
+		{"dropped", func(c *testChain) { c.set(10, testBlock{hash: common.HexToHash("0xc10")}) }, ErrEventVanished},
+		{"moved", func(c *testChain) {
+			c.set(10, testBlock{hash: common.HexToHash("0xc10")})
+			c.set(11, testBlock{hash: common.HexToHash("0xc11"), txs: []common.Hash{eventTx}})
+		}, ErrEventVanished},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newTestChain()
+			chain.set(10, eventBlock)
+			confirmations := testConfirmations(chain, 1)
+			tt.reorg(chain)
+
+			err := confirmations.Revalidate(context.Background(), eventTx, 10, eventBlock.hash)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Revalidate = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Without a TxLocator there is nothing to re-check
+	if err := NewConfirmations(newTestChain(), 1).Revalidate(context.Background(), eventTx, 10, eventBlock.hash); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfirmationsRevalidateEventVanishedAfterSigning(t *testing.T) {
+	chain := newTestChain()
+	chain.set(10, eventBlock)
+	chain.set(11, testBlock{hash: common.HexToHash("0xb11")})
+	confirmations := testConfirmations(chain, 1)
+	signer := testSigner(t)
+
+	// As the log handlers do: confirm the event, sign its claim, then re-check before submitting
+	ctx := context.Background()
+	if err := confirmations.Wait(ctx, 10, eventBlock.hash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.Sign(txs.PrefixMsg(eventTx.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	// A deep reorg replaces the confirmed blocks with ones not including the event
+	chain.set(10, testBlock{hash: common.HexToHash("0xc10")})
+	chain.set(11, testBlock{hash: common.HexToHash("0xc11")})
+	chain.set(12, testBlock{hash: common.HexToHash("0xc12")})
+
+	// The handlers abort the signed claim on ErrEventVanished rather than submitting it
+	if err := confirmations.Revalidate(ctx, eventTx, 10, eventBlock.hash); !errors.Is(err, ErrEventVanished) {
+		t.Fatalf("Revalidate after the event vanished = %v, want ErrEventVanished", err)
+	}
+	if signer.signed != 1 {
+		t.Fatalf("signed %d claims, want the one claim signed before the reorg", signer.signed)
+	}
+}
@@ -0,0 +1,174 @@
+package relayer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// Direction is which of a bridge contract's events are relayed
+type Direction int
+
+const (
+	// LockDirection relays a BridgeBank's lock events as unlock claims on the other chain
+	LockDirection Direction = iota
+	// UnlockDirection signs a bridge's unlock claim events as oracle claims on its own chain
+	UnlockDirection
+)
+
+// directionNames are the names ParseBridgeContract accepts
+var directionNames = map[Direction]string{
+	LockDirection:   "lock",
+	UnlockDirection: "unlock",
+}
+
+// String returns the direction's name
+func (d Direction) String() string {
+	if name, ok := directionNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// BridgeContract is a bridge contract whose events a sub relays. Besides the contracts its
+// BridgeRegistry names, a sub may watch those of other bridge instances on the same chain, each
+// with its own token set.
+type BridgeContract struct {
+	Address   common.Address
+	Direction Direction
+	// Registry is the BridgeRegistry of the contract's bridge instance on the chain its claims are
+	// submitted to: the other chain for a lock contract, its own chain for an unlock contract. It
+	// is the sub's own registry for that chain if zero.
+	Registry common.Address
+}
+
+// RegistryOr returns the contract's Registry, or fallback if it has none
+func (c BridgeContract) RegistryOr(fallback common.Address) common.Address {
+	if c.Registry == (common.Address{}) {
+		return fallback
+	}
+	return c.Registry
+}
+
+// ParseBridgeContract parses a bridge contract given as "address=direction", or as
+// "address=direction@registry" for one whose claims go to another BridgeRegistry
+func ParseBridgeContract(value string) (BridgeContract, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return BridgeContract{}, fmt.Errorf("invalid bridge contract %q: expected address=direction[@registry]", value)
+	}
+	if err := txs.ValidateAddressChecksum(parts[0], false); err != nil {
+		return BridgeContract{}, fmt.Errorf("invalid bridge contract %q: %w", value, err)
+	}
+	contract := BridgeContract{Address: common.HexToAddress(parts[0])}
+
+	direction := parts[1]
+	if i := strings.IndexByte(direction, '@'); i >= 0 {
+		if err := txs.ValidateAddressChecksum(direction[i+1:], false); err != nil {
+			return BridgeContract{}, fmt.Errorf("invalid bridge contract %q: invalid registry address: %w", value, err)
+		}
+		contract.Registry = common.HexToAddress(direction[i+1:])
+		direction = direction[:i]
+	}
+	for d, name := range directionNames {
+		if strings.EqualFold(direction, name) {
+			contract.Direction = d
+			return contract, nil
+		}
+	}
+	return BridgeContract{}, fmt.Errorf("invalid bridge contract %q: unknown direction %q, expected lock or unlock", value, direction)
+}
+
+// ContractRoutes indexes the bridge contracts a sub relays events from by address, routing each
+// event to its contract's handling
+type ContractRoutes map[common.Address]BridgeContract
+
+// NewContractRoutes routes the events of the lockContract and unlockContract a sub's
+// BridgeRegistry names, and of the other bridge instances' contracts
+func NewContractRoutes(lockContract, unlockContract common.Address, contracts []BridgeContract) (ContractRoutes, error) {
+	routes := ContractRoutes{}
+	for _, contract := range append([]BridgeContract{
+		{Address: lockContract, Direction: LockDirection},
+		{Address: unlockContract, Direction: UnlockDirection},
+	}, contracts...) {
+		if err := routes.Add(contract); err != nil {
+			return nil, err
+		}
+	}
+	return routes, nil
+}
+
+// Add routes contract's events, returning an error if its address is already routed
+func (r ContractRoutes) Add(contract BridgeContract) error {
+	if existing, ok := r[contract.Address]; ok {
+		return fmt.Errorf("bridge contract %s is already watched as a %v contract", contract.Address.Hex(), existing.Direction)
+	}
+	r[contract.Address] = contract
+	return nil
+}
+
+// Filter returns the EventFilter expecting lockTopic from the lock contracts and any of
+// unlockTopics, one per version of the unlock event, from the unlock contracts
+func (r ContractRoutes) Filter(lockTopic common.Hash, unlockTopics ...common.Hash) EventFilter {
+	filter := EventFilter{}
+	for _, contract := range r.Sorted() {
+		if contract.Direction == LockDirection {
+			filter.Add(lockTopic, contract.Address)
+			continue
+		}
+		for _, unlockTopic := range unlockTopics {
+			filter.Add(unlockTopic, contract.Address)
+		}
+	}
+	return filter
+}
+
+// Sorted returns the routed contracts in address order
+func (r ContractRoutes) Sorted() []BridgeContract {
+	contracts := make([]BridgeContract, 0, len(r))
+	for _, contract := range r {
+		contracts = append(contracts, contract)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		return strings.Compare(contracts[i].Address.Hex(), contracts[j].Address.Hex()) < 0
+	})
+	return contracts
+}
+
+// ContractCheckpointKey returns the checkpoint key of contract's events on chain
+func ContractCheckpointKey(chain string, contract common.Address) string {
+	return chain + "/" + contract.Hex()
+}
+
+// ContractsStartBlock returns the block to resume processing routes' events on chain from: the
+// earliest of their StartBlocks. A contract without its own checkpoint resumes from chain's, as
+// checkpointed before contracts were checkpointed separately.
+func ContractsStartBlock(store CheckpointStore, chain string, routes ContractRoutes, configuredStart uint64) (uint64, error) {
+	chainStart, err := StartBlock(store, chain, configuredStart)
+	if err != nil {
+		return 0, err
+	}
+
+	start := chainStart
+	for i, contract := range routes.Sorted() {
+		checkpoint, err := store.Load(ContractCheckpointKey(chain, contract.Address))
+		if err != nil {
+			return 0, err
+		}
+		contractStart := chainStart
+		if checkpoint > 0 {
+			contractStart = checkpoint
+			if configuredStart > checkpoint {
+				contractStart = configuredStart
+			}
+		}
+		if i == 0 || contractStart < start {
+			start = contractStart
+		}
+	}
+	return start, nil
+}
@@ -0,0 +1,130 @@
+package relayer
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseBridgeContract(t *testing.T) {
+	address := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	registry := "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+	tests := []struct {
+		value string
+		want  BridgeContract
+	}{
+		{address + "=lock", BridgeContract{Address: common.HexToAddress(address), Direction: LockDirection}},
+		{address + "=Unlock", BridgeContract{Address: common.HexToAddress(address), Direction: UnlockDirection}},
+		{address + "=lock@" + registry, BridgeContract{Address: common.HexToAddress(address), Direction: LockDirection,
+			Registry: common.HexToAddress(registry)}},
+	}
+	for _, tt := range tests {
+		contract, err := ParseBridgeContract(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contract != tt.want {
+			t.Fatalf("ParseBridgeContract(%q) = %+v, want %+v", tt.value, contract, tt.want)
+		}
+	}
+
+	for _, value := range []string{address, address + "=relay", "0x1=lock", address + "=lock@0x1", "0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed=lock"} {
+		if _, err := ParseBridgeContract(value); err == nil {
+			t.Fatalf("ParseBridgeContract(%q) succeeded", value)
+		}
+	}
+}
+
+func TestContractRoutesTwoBridgeBanks(t *testing.T) {
+	lockTopic := common.HexToHash("0x10c")
+	unlockTopic := common.HexToHash("0xc1a")
+	bridgeBank := common.HexToAddress("0xb0")
+	bridge := common.HexToAddress("0xb1")
+	otherBridgeBank := common.HexToAddress("0xb2")
+	ownRegistry := common.HexToAddress("0xa0")
+	otherRegistry := common.HexToAddress("0xa2")
+
+	routes, err := NewContractRoutes(bridgeBank, bridge, []BridgeContract{
+		{Address: otherBridgeBank, Direction: LockDirection, Registry: otherRegistry},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := routes.Filter(lockTopic, unlockTopic)
+	if !filter.Expected([]common.Hash{lockTopic}, bridgeBank) || !filter.Expected([]common.Hash{lockTopic}, otherBridgeBank) ||
+		!filter.Expected([]common.Hash{unlockTopic}, bridge) {
+		t.Fatal("filter rejected an event from a watched contract")
+	}
+	if filter.Expected([]common.Hash{unlockTopic}, otherBridgeBank) {
+		t.Fatal("filter expected an unlock claim from a lock contract")
+	}
+
+	// As the log handlers do: relay each lock to its own contract's destination registry
+	for emitter, want := range map[common.Address]common.Address{bridgeBank: ownRegistry, otherBridgeBank: otherRegistry} {
+		contract, ok := routes[emitter]
+		if !ok || contract.Direction != LockDirection {
+			t.Fatalf("lock of %s routed to %+v", emitter.Hex(), contract)
+		}
+		if registry := contract.RegistryOr(ownRegistry); registry != want {
+			t.Fatalf("lock of %s relayed to registry %s, want %s", emitter.Hex(), registry.Hex(), want.Hex())
+		}
+	}
+
+	if _, err := NewContractRoutes(bridgeBank, bridge, []BridgeContract{{Address: bridgeBank, Direction: UnlockDirection}}); err == nil {
+		t.Fatal("routed one contract twice")
+	}
+}
+
+func TestContractCheckpointsResumeIndependently(t *testing.T) {
+	dir, cleanup := testDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "checkpoints.json")
+	bridgeBank := common.HexToAddress("0xb0")
+	otherBridgeBank := common.HexToAddress("0xb2")
+	routes, err := NewContractRoutes(bridgeBank, common.HexToAddress("0xb1"), []BridgeContract{
+		{Address: otherBridgeBank, Direction: LockDirection},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The chain was checkpointed at 100 before contracts were checkpointed separately
+	store := NewFileCheckpointStore(path)
+	if err := store.Save(EthereumChain, 100); err != nil {
+		t.Fatal(err)
+	}
+	checkpoints := NewEventCheckpoints(store, EthereumChain)
+	for _, done := range []struct {
+		contract common.Address
+		block    uint64
+		err      error
+	}{
+		{bridgeBank, 110, nil},
+		{otherBridgeBank, 112, errors.New("submission reverted")},
+		{bridgeBank, 120, nil},
+		{otherBridgeBank, 125, nil},
+	} {
+		if err := checkpoints.Done(done.contract, done.block, done.err); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One contract's failure doesn't hold the other's checkpoint back
+	store = NewFileCheckpointStore(path)
+	for contract, want := range map[common.Address]uint64{bridgeBank: 120, otherBridgeBank: 0} {
+		if block, err := store.Load(ContractCheckpointKey(EthereumChain, contract)); err != nil || block != want {
+			t.Fatalf("checkpoint of %s = %d, %v, want %d", contract.Hex(), block, err, want)
+		}
+	}
+	// The failed contract, and the unlock contract without events, resume from the chain's
+	// checkpoint, saved at 110 before the failure and so still before the failed event
+	start, err := ContractsStartBlock(store, EthereumChain, routes, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 110 {
+		t.Fatalf("ContractsStartBlock = %d, want the chain's checkpoint 110", start)
+	}
+}
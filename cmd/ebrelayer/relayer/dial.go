@@ -0,0 +1,121 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+	"github.com/mochi-lab/eth-one-bridge/hmyclient"
+	"github.com/mochi-lab/eth-one-bridge/rpc"
+)
+
+// DialFunc connects to the RPC node at rawurl
+type DialFunc func(ctx context.Context, rawurl string) error
+
+// DialWithRetry calls dial until it connects or ctx is done, logging each failed attempt and
+// backing off between them per policy, or DefaultReconnectBackoff if policy is the zero value.
+// A positive policy.MaxAttempts bounds the attempts made.
+func DialWithRetry(ctx context.Context, rawurl string, policy txs.RetryPolicy, dial DialFunc,
+	logger tmLog.Logger) error {
+	if policy.BaseDelay == 0 {
+		policy = DefaultReconnectBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := dial(ctx, rawurl)
+		if err == nil {
+			return nil
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return fmt.Errorf("dialing after %d attempts: %w", attempt, err)
+		}
+
+		delay := policy.Delay(attempt)
+		logger.Error(fmt.Sprintf("Dial attempt %d failed, retrying in %s: %s", attempt, delay, err.Error()))
+		if !sleepContext(ctx, delay) {
+			return fmt.Errorf("dialing: %w", ctx.Err())
+		}
+	}
+}
+
+// EthDialWithRetry dials an Ethereum node with DialWithRetry, failing over to rawurl's fallback
+// endpoints if any are registered
+func EthDialWithRetry(ctx context.Context, rawurl string, policy txs.RetryPolicy,
+	logger tmLog.Logger) (*ethclient.Client, error) {
+	var client *ethclient.Client
+	err := DialWithRetry(ctx, rawurl, policy, func(ctx context.Context, rawurl string) error {
+		return txs.DialEndpoint(ctx, rawurl, func(ctx context.Context, rawurl string) (err error) {
+			client, err = ethclient.DialContext(ctx, rawurl)
+			return err
+		})
+	}, logger)
+	return client, err
+}
+
+// HmyDialWithRetry dials a Harmony node with DialWithRetry, failing over to rawurl's fallback
+// endpoints if any are registered
+func HmyDialWithRetry(ctx context.Context, rawurl string, policy txs.RetryPolicy,
+	logger tmLog.Logger) (*hmyclient.Client, error) {
+	var client *hmyclient.Client
+	err := DialWithRetry(ctx, rawurl, policy, func(ctx context.Context, rawurl string) error {
+		return txs.DialEndpoint(ctx, rawurl, func(ctx context.Context, rawurl string) (err error) {
+			client, err = hmyclient.DialContext(ctx, rawurl)
+			return err
+		})
+	}, logger)
+	return client, err
+}
+
+// IsConnectionError reports whether err came from losing the connection to a node, rather than
+// from a request the node answered
+func IsConnectionError(err error) bool {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &netErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, gethrpc.ErrClientQuit), errors.Is(err, rpc.ErrClientQuit):
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, lost := range []string{"connection refused", "connection reset", "broken pipe", "websocket: close"} {
+		if strings.Contains(message, lost) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunWithReconnect calls run until it returns nil, fails with something other than a connection
+// error, or ctx is done. After a connection error it backs off per policy, then calls run again,
+// which redials.
+func RunWithReconnect(ctx context.Context, run func(ctx context.Context) error, policy txs.RetryPolicy,
+	logger tmLog.Logger) error {
+	if policy.BaseDelay == 0 {
+		policy = DefaultReconnectBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := run(ctx)
+		if err == nil || ctx.Err() != nil || !IsConnectionError(err) {
+			return err
+		}
+
+		delay := policy.Delay(attempt)
+		logger.Error(fmt.Sprintf("Connection lost, reconnecting in %s: %s", delay, err.Error()))
+		if !sleepContext(ctx, delay) {
+			return nil
+		}
+	}
+}
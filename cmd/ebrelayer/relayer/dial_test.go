@@ -0,0 +1,65 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tmLog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+func TestDialWithRetryBacksOffUntilConnected(t *testing.T) {
+	var attempts []time.Time
+	dial := func(_ context.Context, rawurl string) error {
+		attempts = append(attempts, time.Now())
+		if rawurl != "ws://node" {
+			t.Fatalf("dialed %q, want ws://node", rawurl)
+		}
+		if len(attempts) <= 2 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	policy := txs.RetryPolicy{BaseDelay: 5 * time.Millisecond, MaxDelay: time.Second}
+	if err := DialWithRetry(context.Background(), "ws://node", policy, dial, tmLog.NewNopLogger()); err != nil {
+		t.Fatalf("DialWithRetry = %v, want connected on the third attempt", err)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("dialed %d times, want 3", len(attempts))
+	}
+	// The wait doubles after each failure
+	if first := attempts[1].Sub(attempts[0]); first < 5*time.Millisecond {
+		t.Fatalf("waited %s after the first failure, want at least 5ms", first)
+	}
+	if second := attempts[2].Sub(attempts[1]); second < 10*time.Millisecond {
+		t.Fatalf("waited %s after the second failure, want at least 10ms", second)
+	}
+}
+
+func TestDialWithRetryGivesUp(t *testing.T) {
+	refused := errors.New("connection refused")
+	dials := 0
+	dial := func(context.Context, string) error {
+		dials++
+		return refused
+	}
+
+	policy := txs.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	if err := DialWithRetry(context.Background(), "ws://node", policy, dial, tmLog.NewNopLogger()); !errors.Is(err, refused) {
+		t.Fatalf("DialWithRetry = %v, want the last dial error", err)
+	}
+	if dials != 2 {
+		t.Fatalf("dialed %d times, want MaxAttempts 2", dials)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	policy = txs.RetryPolicy{BaseDelay: time.Hour}
+	if err := DialWithRetry(ctx, "ws://node", policy, dial, tmLog.NewNopLogger()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DialWithRetry = %v, want %v while backing off", err, context.DeadlineExceeded)
+	}
+}
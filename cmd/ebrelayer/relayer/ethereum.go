@@ -3,10 +3,12 @@ package relayer
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"os"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -32,6 +34,33 @@ type EthereumSub struct {
 	EthPrivateKey          *ecdsa.PrivateKey
 	HmyPrivatekey          *ecdsa.PrivateKey
 	Logger                 tmLog.Logger
+	// Checkpoints, if set, persists progress so missed events are replayed from StartBlock onward
+	Checkpoints CheckpointStore
+	StartBlock  uint64
+	// Seen, if set, skips events which were already relayed
+	Seen *SeenSet
+	// ConfirmationDepth is the number of blocks an event must be buried under before it is relayed
+	ConfirmationDepth uint64
+	// MaxReorgDepth, if set, halts claim signing when a reorg replaces a processed block more than
+	// this many blocks deep
+	MaxReorgDepth uint64
+	// MaxEventBlocks and MaxEventAge, if set, skip events more than this many blocks or this long
+	// behind the chain head, logging them for manual review instead of relaying them
+	MaxEventBlocks uint64
+	MaxEventAge    time.Duration
+	// ReconnectBackoff paces resubscription after the event subscription drops
+	ReconnectBackoff txs.RetryPolicy
+	// PollInterval, if set, polls for events with FilterLogs instead of subscribing to them
+	PollInterval time.Duration
+	// MaxBlockRange caps the blocks queried per poll, to stay within provider limits
+	MaxBlockRange uint64
+	// Progress, if set, records the last block processed, as reported by a HealthCheck
+	Progress *Progress
+	// Workers is the number of events processed at once. Checkpoints still advance in chain order.
+	Workers int
+	// Contracts lists the bridge contracts of other bridge instances watched on the chain, besides
+	// those the BridgeRegistry names. Each contract's events are checkpointed separately.
+	Contracts []BridgeContract
 }
 
 // NewEthereumSub initializes a new EthereumSub
@@ -49,72 +78,235 @@ func NewEthereumSub(inBuf io.Reader, validatorMoniker, ethereumProvider string,
 	}, nil
 }
 
-// Start an Ethereum chain subscription
+// Start an Ethereum chain subscription, restarting it after a lost connection and exiting the
+// process if it otherwise fails
 func (sub EthereumSub) Start() {
-	client, err := EthSetupWebsocketClient(sub.EthereumProvider)
-	if err != nil {
+	if err := RunWithReconnect(context.Background(), sub.Run, sub.ReconnectBackoff, sub.Logger); err != nil {
 		sub.Logger.Error(err.Error())
 		os.Exit(1)
 	}
-	clientChainID, err := client.NetworkID(context.Background())
+}
+
+// Run an Ethereum chain subscription until ctx is cancelled. Cancellation stops accepting new events
+// but lets the event being processed complete and checkpoint; an event still awaiting
+// confirmations is abandoned uncheckpointed, so it is replayed on the next run.
+func (sub EthereumSub) Run(ctx context.Context) error {
+	client, err := EthSetupWebsocketClient(ctx, sub.EthereumProvider, sub.ReconnectBackoff, sub.Logger)
 	if err != nil {
-		sub.Logger.Error(err.Error())
-		os.Exit(1)
+		return err
 	}
-	sub.Logger.Info("Started Ethereum websocket with provider:", sub.EthereumProvider)
+	defer client.Close()
 
-	// We will check logs for new events
-	logs := make(chan ctypes.Log)
+	clientChainID, err := client.NetworkID(ctx)
+	if err != nil {
+		return err
+	}
+	sub.Logger.Info("Started Ethereum websocket with provider:", sub.EthereumProvider)
 
-	// Start BridgeBank subscription, prepare contract ABI and EthLogLock event signature
-	bridgeBankAddress, subBridgeBank := sub.EthStartContractEventSub(logs, client, txs.BridgeBank)
+	// Look up BridgeBank, prepare contract ABI and EthLogLock event signature
+	bridgeBankAddress, err := txs.EthGetAddressFromBridgeRegistry(sub.EthPrivateKey, client, sub.EthereumBridgeRegistry, txs.BridgeBank)
+	if err != nil {
+		return err
+	}
 	bridgeBankContractABI := contract.EthLoadABI(txs.BridgeBank)
 	eventLogLockSignature := bridgeBankContractABI.Events[types.EthLogLock.String()].ID.Hex()
 
-	// Start harmonyBridge subscription, prepare contract ABI and EthLogNewUnlockClaim event signature
-	_, subHarmonyBridge := sub.EthStartContractEventSub(logs, client, txs.HarmonyBridge)
-	harmonyBridgeContractABI := contract.EthLoadABI(txs.HarmonyBridge)
-	eventLogNewUnlockClaimSignature := harmonyBridgeContractABI.Events[types.EthLogNewUnlockClaim.String()].ID.Hex()
-
-	for {
-		select {
-		// Handle any errors
-		case err := <-subBridgeBank.Err():
-			sub.Logger.Error("Ethereum - Sub bridgeBank error: ", err.Error())
-			client, err = EthSetupWebsocketClient(sub.EthereumProvider)
-			if err != nil {
-				sub.Logger.Error(err.Error())
-				os.Exit(1)
-			}
-			_, subBridgeBank = sub.EthStartContractEventSub(logs, client, txs.BridgeBank)
-			_, subHarmonyBridge = sub.EthStartContractEventSub(logs, client, txs.HarmonyBridge)
-		case err := <-subHarmonyBridge.Err():
-			sub.Logger.Error("Ethereum - Sub harmonyBridge error:", err.Error())
-			client, err = EthSetupWebsocketClient(sub.EthereumProvider)
+	// Look up harmonyBridge and the signatures of each version of its EthLogNewUnlockClaim event
+	harmonyBridgeAddress, err := txs.EthGetAddressFromBridgeRegistry(sub.EthPrivateKey, client, sub.EthereumBridgeRegistry, txs.HarmonyBridge)
+	if err != nil {
+		return err
+	}
+	unlockClaimTopics, err := txs.EthUnlockClaimTopics()
+	if err != nil {
+		return err
+	}
+
+	// Look up the Oracle verifying claims, to select its signing scheme
+	oracleAddress, err := txs.EthGetAddressFromBridgeRegistry(sub.EthPrivateKey, client, sub.EthereumBridgeRegistry, txs.Oracle)
+	if err != nil {
+		return err
+	}
+
+	// Route the events of the registry's bridge contracts, and of those of other bridge instances
+	routes, err := NewContractRoutes(bridgeBankAddress, harmonyBridgeAddress, sub.Contracts)
+	if err != nil {
+		return err
+	}
+
+	// Look up the Oracle verifying each unlock contract's claims, by its BridgeRegistry
+	oracles := map[common.Address]common.Address{sub.EthereumBridgeRegistry: oracleAddress}
+	for _, contract := range routes.Sorted() {
+		registry := contract.RegistryOr(sub.EthereumBridgeRegistry)
+		if _, ok := oracles[registry]; ok || contract.Direction != UnlockDirection {
+			continue
+		}
+		if oracles[registry], err = txs.EthGetAddressFromBridgeRegistry(sub.EthPrivateKey, client, registry, txs.Oracle); err != nil {
+			return err
+		}
+	}
+
+	// Only fetch and relay the events emitted by the bridge contracts themselves
+	filter := routes.Filter(bridgeBankContractABI.Events[types.EthLogLock.String()].ID,
+		unlockClaimTopics...)
+
+	confirmations := NewConfirmations(NewEthCanonicalChain(client), sub.ConfirmationDepth)
+	confirmations.Txs = NewEthTxLocator(client)
+
+	// Halt signing if a reorg replaces processed blocks deeper than the confirmations guard against
+	var reorgs *ReorgGuard
+	if sub.MaxReorgDepth > 0 {
+		reorgs = NewReorgGuard(EthereumChain, NewEthCanonicalChain(client), sub.MaxReorgDepth)
+		go reorgs.Watch(ctx, DefaultReorgCheckInterval, sub.Logger)
+	}
+
+	expiry := EventExpiry{Chain: NewEthCanonicalChain(client), Clock: NewEthBlockClock(client), MaxBlocks: sub.MaxEventBlocks, MaxAge: sub.MaxEventAge}
+
+	// handleLog relays a witnessed event according to its signature
+	handleLog := func(ctx context.Context, vLog ctypes.Log) error {
+		if len(vLog.Topics) == 0 {
+			return nil
+		}
+
+		key := EventKey{TxHash: vLog.TxHash, LogIndex: vLog.Index}
+		if !filter.Expected(vLog.Topics, vLog.Address) {
+			sub.Logger.Error(fmt.Sprintf("Ignoring event %s from unexpected contract %s", key, vLog.Address.Hex()))
+			return nil
+		}
+		if sub.Seen != nil {
+			seen, err := sub.Seen.Has(key)
 			if err != nil {
-				sub.Logger.Error(err.Error())
-				os.Exit(1)
+				return err
 			}
-			_, subBridgeBank = sub.EthStartContractEventSub(logs, client, txs.BridgeBank)
-			_, subHarmonyBridge = sub.EthStartContractEventSub(logs, client, txs.HarmonyBridge)
-		// vLog is raw event data
-		case vLog := <-logs:
-			sub.Logger.Info(fmt.Sprintf("Witnessed tx %s on block %d\n", vLog.TxHash.Hex(), vLog.BlockNumber))
-			var err error
-			switch vLog.Topics[0].Hex() {
-			case eventLogLockSignature:
-				err = sub.EthHandleLogLockEvent(clientChainID, bridgeBankAddress, bridgeBankContractABI,
-					types.EthLogLock.String(), vLog)
-			case eventLogNewUnlockClaimSignature:
-				err = sub.EthHandleLogNewUnlockClaim(sub.EthereumBridgeRegistry, harmonyBridgeContractABI,
-					types.EthLogNewUnlockClaim.String(), vLog)
+			if seen {
+				sub.Logger.Info(fmt.Sprintf("Skipping already relayed event %s", key))
+				return nil
 			}
+		}
+
+		timer := txs.NewPipelineTimer(EthereumChain, nil)
+		timer.Mark(txs.StageObserved)
+
+		if vLog.Removed {
+			sub.Logger.Info(fmt.Sprintf("Skipping removed event %s", key))
+			return nil
+		}
+		err := confirmations.Wait(ctx, vLog.BlockNumber, vLog.BlockHash)
+		if err == ErrEventReorged {
+			sub.Logger.Info(fmt.Sprintf("Skipping event %s, block %d was reorged", key, vLog.BlockNumber))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		timer.Mark(txs.StageConfirmed)
+		err = expiry.Check(ctx, vLog.BlockNumber)
+		if errors.Is(err, ErrEventExpired) {
+			sub.Logger.Error(fmt.Sprintf("Skipping expired event %s for manual review: %s", key, err.Error()))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Hold the event while an operator has paused signing, leaving it uncheckpointed until then
+		if err := txs.WaitSigningResumed(ctx); err != nil {
+			return err
+		}
+
+		contract := routes[vLog.Address]
+		switch {
+		case vLog.Topics[0].Hex() == eventLogLockSignature:
+			err = sub.EthHandleLogLockEvent(ctx, confirmations, timer, clientChainID, vLog.Address, contract.RegistryOr(sub.HarmonyBridgeRegistry),
+				bridgeBankContractABI, types.EthLogLock.String(), vLog)
+		case containsTopic(unlockClaimTopics, vLog.Topics[0]):
+			registry := contract.RegistryOr(sub.EthereumBridgeRegistry)
+			err = sub.EthHandleLogNewUnlockClaim(ctx, confirmations, timer, registry, oracles[registry], vLog)
+		}
+		if err == txs.ErrDryRun {
+			return nil
+		}
+		if errors.Is(err, txs.ErrUndecodableEvent) {
+			sub.Logger.Error(fmt.Sprintf("Skipping undecodable event %s: %s", key, err.Error()))
+			return nil
+		}
+		if errors.Is(err, ErrEventVanished) {
+			sub.Logger.Error(fmt.Sprintf("Aborting claim for event %s before submission: %s", key, err.Error()))
+			return nil
+		}
+		if errors.Is(err, txs.ErrTokenNotAllowed) || errors.Is(err, txs.ErrClaimSkipped) {
+			err = nil
+		}
+		if err != nil || sub.Seen == nil {
+			return err
+		}
+		return sub.Seen.Add(key)
+	}
+
+	query := filter.Query()
+
+	// Replay events emitted since the last checkpoint, including any missed while stopped
+	if sub.Checkpoints != nil {
+		fromBlock, err := ContractsStartBlock(sub.Checkpoints, EthereumChain, routes, sub.StartBlock)
+		if err != nil {
+			return err
+		}
+		sub.Logger.Info(fmt.Sprintf("Ethereum - Replaying events from block %d", fromBlock))
+		query.FromBlock = new(big.Int).SetUint64(fromBlock)
+	}
+
+	wsSource := NewEthWSLogSource(client, NewEthCanonicalChain(client), query, sub.ReconnectBackoff, sub.Logger)
+	wsSource.OnProgress = recordProgress(sub.Progress, EthereumChain)
+	var source EthLogSource = wsSource
+	if sub.PollInterval > 0 {
+		pollSource := NewEthPollLogSource(client, NewEthCanonicalChain(client), query, sub.ConfirmationDepth, sub.Logger)
+		pollSource.PollInterval = sub.PollInterval
+		pollSource.MaxBlockRange = sub.MaxBlockRange
+		pollSource.OnProgress = recordProgress(sub.Progress, EthereumChain)
+		source = pollSource
+	}
+	for _, contract := range routes.Sorted() {
+		sub.Logger.Info(fmt.Sprintf("Ethereum - Subscribed to %v contract at %s", contract.Direction, contract.Address.Hex()))
+	}
+
+	// A failed event holds its contract's checkpoint back, so it is replayed on the next run
+	var checkpoints *EventCheckpoints
+	if sub.Checkpoints != nil && !txs.DryRun {
+		checkpoints = NewEventCheckpoints(sub.Checkpoints, EthereumChain)
+	}
+	pool := NewWorkerPool(sub.Workers)
+	err = source.Run(ctx, func(vLog ctypes.Log) {
+		sub.Logger.Info(fmt.Sprintf("Witnessed tx %s on block %d\n", vLog.TxHash.Hex(), vLog.BlockNumber))
+		var err error
+		pool.Go(func() {
+			err = handleLog(ctx, vLog)
+		}, func() {
 			// TODO: Check local events store for status, if retryable, attempt relay again
+			if _, ok := routes[vLog.Address]; ok {
+				outcome := txs.ContractEventProcessed
+				if err != nil {
+					outcome = txs.ContractEventFailed
+				}
+				txs.RecordContractEvent(EthereumChain, vLog.Address, outcome)
+			}
 			if err != nil {
 				sub.Logger.Error("Ethereum error: ", err.Error())
 			}
-		}
-	}
+			if checkpoints != nil {
+				if err := checkpoints.Done(vLog.Address, vLog.BlockNumber, err); err != nil {
+					sub.Logger.Error("Ethereum - checkpoint error: ", err.Error())
+				}
+			}
+			if err == nil && sub.Progress != nil {
+				sub.Progress.Record(EthereumChain, vLog.BlockNumber)
+			}
+			if err == nil && reorgs != nil {
+				reorgs.Record(vLog.BlockNumber, vLog.BlockHash)
+			}
+		})
+	})
+	pool.Wait()
+	sub.Logger.Info("Ethereum - Stopping subscriptions")
+	return err
 }
 
 // EthStartContractEventSub : starts an event subscription on the specified Ethereum contract
@@ -132,7 +324,11 @@ func (sub EthereumSub) EthStartContractEventSub(logs chan ctypes.Log, client *et
 	}
 
 	// Start the contract subscription
-	contractSub, err := client.SubscribeFilterLogs(context.Background(), subQuery, logs)
+	var contractSub ethereum.Subscription
+	err = txs.Retry(context.Background(), func() error {
+		contractSub, err = client.SubscribeFilterLogs(context.Background(), subQuery, logs)
+		return err
+	}, txs.DefaultRetryPolicy)
 	if err != nil {
 		sub.Logger.Error(err.Error())
 	}
@@ -140,17 +336,18 @@ func (sub EthereumSub) EthStartContractEventSub(logs chan ctypes.Log, client *et
 	return subContractAddress, contractSub
 }
 
-// EthHandleLogLockEvent unpacks an EthLogLockEvent, and relays a tx to Harmony
-func (sub EthereumSub) EthHandleLogLockEvent(clientChainID *big.Int, contractAddress common.Address,
+// EthHandleLogLockEvent unpacks an EthLogLockEvent, and relays a tx to the Harmony bridge of harmonyBridgeRegistry
+func (sub EthereumSub) EthHandleLogLockEvent(ctx context.Context, confirmations Confirmations, timer *txs.PipelineTimer,
+	clientChainID *big.Int, contractAddress, harmonyBridgeRegistry common.Address,
 	contractABI abi.ABI, eventName string, cLog ctypes.Log) error {
 	// Parse the event's attributes via contract ABI
-	fmt.Println(cLog, "\n")
+	fmt.Println(cLog)
 	event := types.EthLogLockEvent{}
-	err := contractABI.Unpack(&event, eventName, cLog.Data)
-	if err != nil {
-		sub.Logger.Error("error unpacking: %v", err)
+	if err := txs.UnpackEvent(contractABI, &event, eventName, cLog.Topics, cLog.Data); err != nil {
+		return err
 	}
 	event.BridgeBankAddress = contractAddress
+	event.TxHash = cLog.TxHash
 	event.EthereumChainID = clientChainID
 	sub.Logger.Info(event.String())
 
@@ -161,25 +358,53 @@ func (sub EthereumSub) EthHandleLogLockEvent(clientChainID *big.Int, contractAdd
 	if err != nil {
 		return err
 	}
+	amount := txs.FormatTokenAmount(unlockClaim.Token, unlockClaim.Amount)
+	sub.Logger.Info(fmt.Sprintf("Relaying lock of %s to Harmony", amount))
 
-	return txs.RelayUnlockClaimToHarmony(sub.HarmonyProvider, sub.HarmonyBridgeRegistry, types.EthLogLock, unlockClaim, sub.HmyPrivatekey)
+	// Hold the claim while submission is halted by consecutive reverts, leaving it uncheckpointed
+	err = submitWhenAllowed(ctx, txs.HmySubmitBreaker, func() error {
+		if err := confirmations.Revalidate(ctx, cLog.TxHash, cLog.BlockNumber, cLog.BlockHash); err != nil {
+			return err
+		}
+		return txs.RelayUnlockClaimToHarmony(sub.HarmonyProvider, harmonyBridgeRegistry, types.EthLogLock, unlockClaim, sub.HmyPrivatekey, timer)
+	})
+	if err == nil {
+		recordClaim(sub.Progress, EthereumChain, amount)
+	}
+	return err
 }
 
 // EthHandleLogNewUnlockClaim unpacks a EthLogNewUnlockClaim event, builds a new OracleClaim, and relays it to Ethereum
-func (sub EthereumSub) EthHandleLogNewUnlockClaim(contractAddress common.Address, contractABI abi.ABI,
-	eventName string, cLog ctypes.Log) error {
+func (sub EthereumSub) EthHandleLogNewUnlockClaim(ctx context.Context, confirmations Confirmations, timer *txs.PipelineTimer,
+	contractAddress, oracleAddress common.Address, cLog ctypes.Log) error {
 	// Parse the event's attributes via contract ABI
-	event := types.EthLogNewUnlockClaimEvent{}
-	err := contractABI.Unpack(&event, eventName, cLog.Data)
+	event, err := txs.ParseEthUnlockClaim(cLog)
 	if err != nil {
-		sub.Logger.Error("error unpacking: %v", err)
+		return err
 	}
 	sub.Logger.Info(event.String())
+	unlockID, _, _, token, tokenAmount := event.ClaimFields()
+	amount := txs.FormatTokenAmount(token, tokenAmount)
+	sub.Logger.Info(fmt.Sprintf("Claiming unlock %v of %s", unlockID, amount))
 
-	oracleClaim, err := txs.EthUnlockClaimToSignedOracleClaim(event, sub.EthPrivateKey)
+	oracleClaim, err := txs.EthUnlockClaimToSignedOracleClaim(event, sub.EthPrivateKey, oracleAddress)
 	if err != nil {
 		return err
 	}
-	return txs.RelayOracleClaimToEthereum(sub.EthereumProvider, contractAddress, types.EthLogNewUnlockClaim,
-		oracleClaim, sub.EthPrivateKey)
+	timer.Mark(txs.StageSigned)
+	if err := txs.WaitSigningResumed(ctx); err != nil {
+		return err
+	}
+	// Hold the claim while submission is halted by consecutive reverts, leaving it uncheckpointed
+	err = submitWhenAllowed(ctx, txs.EthSubmitBreaker, func() error {
+		if err := confirmations.Revalidate(ctx, cLog.TxHash, cLog.BlockNumber, cLog.BlockHash); err != nil {
+			return err
+		}
+		return txs.RelayOracleClaimToEthereum(sub.EthereumProvider, contractAddress, types.EthLogNewUnlockClaim,
+			oracleClaim, sub.EthPrivateKey, timer)
+	})
+	if err == nil {
+		recordClaim(sub.Progress, EthereumChain, amount)
+	}
+	return err
 }
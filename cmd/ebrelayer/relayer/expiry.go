@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrEventExpired is returned for an event older than the maximum event age
+var ErrEventExpired = errors.New("event is older than the maximum event age")
+
+// BlockClock queries the timestamps of a chain's canonical blocks
+type BlockClock interface {
+	// BlockTimestampByNumber returns the timestamp, in seconds since the epoch, of the block number,
+	// or of the latest block if number is nil
+	BlockTimestampByNumber(ctx context.Context, number *big.Int) (uint64, error)
+}
+
+// NewEthBlockClock returns a BlockClock backed by an Ethereum client's block headers
+func NewEthBlockClock(client *ethclient.Client) BlockClock {
+	return ethCanonicalChain{client}
+}
+
+// BlockTimestampByNumber implements BlockClock
+func (c ethCanonicalChain) BlockTimestampByNumber(ctx context.Context, number *big.Int) (uint64, error) {
+	header, err := c.client.HeaderByNumber(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	return header.Time, nil
+}
+
+// EventExpiry holds back claims for stale events, such as those replayed after a long outage,
+// whose claims may no longer be wanted. An event is expired once the chain head is more than
+// MaxBlocks blocks past its block, or once the head block's timestamp is more than MaxAge past its
+// block's. Either limit is disabled when zero.
+type EventExpiry struct {
+	Chain     CanonicalChain
+	Clock     BlockClock
+	MaxBlocks uint64
+	MaxAge    time.Duration
+}
+
+// Check returns ErrEventExpired if an event in the block blockNumber is expired
+func (e EventExpiry) Check(ctx context.Context, blockNumber uint64) error {
+	if e.MaxBlocks == 0 && e.MaxAge == 0 {
+		return nil
+	}
+
+	head, err := e.Chain.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head <= blockNumber {
+		return nil
+	}
+	if e.MaxBlocks > 0 && head-blockNumber > e.MaxBlocks {
+		return fmt.Errorf("%w: block %d is %d blocks behind head %d, more than %d", ErrEventExpired, blockNumber,
+			head-blockNumber, head, e.MaxBlocks)
+	}
+	if e.MaxAge == 0 {
+		return nil
+	}
+
+	eventTime, err := e.Clock.BlockTimestampByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return err
+	}
+	headTime, err := e.Clock.BlockTimestampByNumber(ctx, new(big.Int).SetUint64(head))
+	if err != nil {
+		return err
+	}
+	if headTime <= eventTime {
+		return nil
+	}
+	age := time.Duration(headTime-eventTime) * time.Second
+	if age > e.MaxAge {
+		return fmt.Errorf("%w: block %d is %s older than head %d, more than %s", ErrEventExpired, blockNumber, age,
+			head, e.MaxAge)
+	}
+	return nil
+}
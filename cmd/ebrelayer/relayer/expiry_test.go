@@ -0,0 +1,49 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// blockClock is a BlockClock where block n was mined n*interval seconds after the epoch
+type blockClock uint64
+
+func (c blockClock) BlockTimestampByNumber(_ context.Context, number *big.Int) (uint64, error) {
+	return number.Uint64() * uint64(c), nil
+}
+
+func TestEventExpiry(t *testing.T) {
+	chain := newTestChain()
+	chain.set(1000, testBlock{hash: common.HexToHash("0x1000")})
+
+	tests := []struct {
+		name    string
+		expiry  EventExpiry
+		block   uint64
+		wantErr error
+	}{
+		{"no limits", EventExpiry{}, 1, nil},
+		{"within blocks", EventExpiry{MaxBlocks: 100}, 900, nil},
+		{"beyond blocks", EventExpiry{MaxBlocks: 100}, 899, ErrEventExpired},
+		{"within age", EventExpiry{MaxAge: time.Hour}, 1000 - 300, nil},
+		{"beyond age", EventExpiry{MaxAge: time.Hour}, 1000 - 301, ErrEventExpired},
+		// A block within the block limit is still expired by its age
+		{"beyond age within blocks", EventExpiry{MaxBlocks: 1000, MaxAge: time.Hour}, 1000 - 301, ErrEventExpired},
+		{"at head", EventExpiry{MaxBlocks: 1, MaxAge: time.Second}, 1000, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiry := tt.expiry
+			// Blocks are 12 seconds apart, so 300 blocks are exactly an hour
+			expiry.Chain, expiry.Clock = chain, blockClock(12)
+			if err := expiry.Check(context.Background(), tt.block); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check(%d) = %v, want %v", tt.block, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package relayer
+
+import (
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EventFilter maps the signature topic of each event relayed from a chain to the bridge contracts
+// expected to emit it, so that look-alike events from other contracts are neither fetched nor
+// relayed
+type EventFilter map[common.Hash][]common.Address
+
+// Add expects the event with signature topic to be emitted by address, among any others added
+func (f EventFilter) Add(topic common.Hash, address common.Address) {
+	for _, expected := range f[topic] {
+		if expected == address {
+			return
+		}
+	}
+	f[topic] = append(f[topic], address)
+}
+
+// Query returns a filter query matching only f's events, emitted by any of their contracts
+func (f EventFilter) Query() ethereum.FilterQuery {
+	var addresses []common.Address
+	var topics []common.Hash
+	seen := make(map[common.Address]bool, len(f))
+	for topic, expected := range f {
+		topics = append(topics, topic)
+		for _, address := range expected {
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return ethereum.FilterQuery{Addresses: addresses, Topics: [][]common.Hash{topics}}
+}
+
+// Expected reports whether topics starts with one of f's events, emitted by a contract expected
+// to emit it
+func (f EventFilter) Expected(topics []common.Hash, emitter common.Address) bool {
+	if len(topics) == 0 {
+		return false
+	}
+	for _, address := range f[topics[0]] {
+		if address == emitter {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTopic reports whether topic is among topics
+func containsTopic(topics []common.Hash, topic common.Hash) bool {
+	for _, t := range topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEventFilterIgnoresOtherContracts(t *testing.T) {
+	lock := common.HexToHash("0x10c")
+	claim := common.HexToHash("0xc1a")
+	bridgeBank := common.HexToAddress("0xb0")
+	bridge := common.HexToAddress("0xb1")
+	impostor := common.HexToAddress("0xbad")
+
+	filter := EventFilter{}
+	filter.Add(lock, bridgeBank)
+	filter.Add(claim, bridge)
+	filter.Add(claim, bridge)
+
+	if !filter.Expected([]common.Hash{lock}, bridgeBank) || !filter.Expected([]common.Hash{claim, lock}, bridge) {
+		t.Fatal("filter rejected an event from its bridge contract")
+	}
+	// A look-alike event from another contract, or one contract's event from another, is ignored
+	if filter.Expected([]common.Hash{lock}, impostor) {
+		t.Fatal("filter expected an event from another contract")
+	}
+	if filter.Expected([]common.Hash{claim}, bridgeBank) {
+		t.Fatal("filter expected an event from the contract not emitting it")
+	}
+	if filter.Expected(nil, bridgeBank) || filter.Expected([]common.Hash{common.HexToHash("0x1")}, bridgeBank) {
+		t.Fatal("filter expected an unrelayed event")
+	}
+
+	query := filter.Query()
+	if len(query.Addresses) != 2 || len(query.Topics) != 1 || len(query.Topics[0]) != 2 {
+		t.Fatalf("Query = %+v, want both contracts and both topics", query)
+	}
+	for _, address := range query.Addresses {
+		if address == impostor {
+			t.Fatal("Query fetches another contract's events")
+		}
+	}
+}
@@ -3,10 +3,12 @@ package relayer
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"os"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -30,6 +32,33 @@ type HarmonySub struct {
 	HmyPrivateKey          *ecdsa.PrivateKey
 	EthPrivateKey          *ecdsa.PrivateKey
 	Logger                 tmLog.Logger
+	// Checkpoints, if set, persists progress so missed events are replayed from StartBlock onward
+	Checkpoints CheckpointStore
+	StartBlock  uint64
+	// Seen, if set, skips events which were already relayed
+	Seen *SeenSet
+	// ConfirmationDepth is the number of blocks an event must be buried under before it is relayed
+	ConfirmationDepth uint64
+	// MaxReorgDepth, if set, halts claim signing when a reorg replaces a processed block more than
+	// this many blocks deep
+	MaxReorgDepth uint64
+	// MaxEventBlocks and MaxEventAge, if set, skip events more than this many blocks or this long
+	// behind the chain head, logging them for manual review instead of relaying them
+	MaxEventBlocks uint64
+	MaxEventAge    time.Duration
+	// ReconnectBackoff paces resubscription after the event subscription drops
+	ReconnectBackoff txs.RetryPolicy
+	// PollInterval, if set, polls for events with FilterLogs instead of subscribing to them
+	PollInterval time.Duration
+	// MaxBlockRange caps the blocks queried per poll, to stay within provider limits
+	MaxBlockRange uint64
+	// Progress, if set, records the last block processed, as reported by a HealthCheck
+	Progress *Progress
+	// Workers is the number of events processed at once. Checkpoints still advance in chain order.
+	Workers int
+	// Contracts lists the bridge contracts of other bridge instances watched on the chain, besides
+	// those the BridgeRegistry names. Each contract's events are checkpointed separately.
+	Contracts []BridgeContract
 }
 
 // NewHarmonySub initializes a new HarmonySub
@@ -49,72 +78,235 @@ func NewHarmonySub(inBuf io.Reader, validatorMoniker,
 	}, nil
 }
 
-// Start an Harmony chain subscription
+// Start an Harmony chain subscription, restarting it after a lost connection and exiting the
+// process if it otherwise fails
 func (sub HarmonySub) Start() {
-	client, err := HmySetupWebsocketClient(sub.HarmonyProvider)
-	if err != nil {
+	if err := RunWithReconnect(context.Background(), sub.Run, sub.ReconnectBackoff, sub.Logger); err != nil {
 		sub.Logger.Error(err.Error())
 		os.Exit(1)
 	}
-	clientChainID, err := client.NetworkID(context.Background())
+}
+
+// Run an Harmony chain subscription until ctx is cancelled. Cancellation stops accepting new events
+// but lets the event being processed complete and checkpoint; an event still awaiting
+// confirmations is abandoned uncheckpointed, so it is replayed on the next run.
+func (sub HarmonySub) Run(ctx context.Context) error {
+	client, err := HmySetupWebsocketClient(ctx, sub.HarmonyProvider, sub.ReconnectBackoff, sub.Logger)
 	if err != nil {
-		sub.Logger.Error(err.Error())
-		os.Exit(1)
+		return err
 	}
-	sub.Logger.Info("Started Harmony websocket with provider:", sub.HarmonyProvider)
+	defer client.Close()
 
-	// We will check logs for new events
-	logs := make(chan htypes.Log)
+	clientChainID, err := client.NetworkID(ctx)
+	if err != nil {
+		return err
+	}
+	sub.Logger.Info("Started Harmony websocket with provider:", sub.HarmonyProvider)
 
-	// Start BridgeBank subscription, prepare contract ABI and HmyLockLog event signature
-	bridgeBankAddress, subBridgeBank := sub.HmyStartContractEventSub(logs, client, txs.BridgeBank)
+	// Look up BridgeBank, prepare contract ABI and HmyLogLock event signature
+	bridgeBankAddress, err := txs.HmyGetAddressFromBridgeRegistry(sub.HmyPrivateKey, client, sub.HarmonyBridgeRegistry, txs.BridgeBank)
+	if err != nil {
+		return err
+	}
 	bridgeBankContractABI := contract.HmyLoadABI(txs.BridgeBank)
 	eventLogLockSignature := bridgeBankContractABI.Events[types.HmyLogLock.String()].ID.Hex()
 
-	// Start ethereumBridge subscription, prepare contract ABI and HmyLogNewUnlockClaim event signature
-	_, subEthereumBridge := sub.HmyStartContractEventSub(logs, client, txs.EthereumBridge)
-	ethereumBridgeContractABI := contract.HmyLoadABI(txs.EthereumBridge)
-	eventLogNewUnlockClaimSignature := ethereumBridgeContractABI.Events[types.HmyLogNewUnlockClaim.String()].ID.Hex()
-
-	for {
-		select {
-		// Handle any errors
-		case err := <-subBridgeBank.Err():
-			sub.Logger.Error("Harmony - Sub bridgeBank error: ", err.Error())
-			client, err = HmySetupWebsocketClient(sub.HarmonyProvider)
-			if err != nil {
-				sub.Logger.Error(err.Error())
-				os.Exit(1)
-			}
-			_, subBridgeBank = sub.HmyStartContractEventSub(logs, client, txs.BridgeBank)
-			_, subEthereumBridge = sub.HmyStartContractEventSub(logs, client, txs.EthereumBridge)
-		case err := <-subEthereumBridge.Err():
-			sub.Logger.Error("Harmony - Sub ethereumBridge error: ", err.Error())
-			client, err = HmySetupWebsocketClient(sub.HarmonyProvider)
+	// Look up ethereumBridge and the signatures of each version of its HmyLogNewUnlockClaim event
+	ethereumBridgeAddress, err := txs.HmyGetAddressFromBridgeRegistry(sub.HmyPrivateKey, client, sub.HarmonyBridgeRegistry, txs.EthereumBridge)
+	if err != nil {
+		return err
+	}
+	unlockClaimTopics, err := txs.HmyUnlockClaimTopics()
+	if err != nil {
+		return err
+	}
+
+	// Look up the Oracle verifying claims, to select its signing scheme
+	oracleAddress, err := txs.HmyGetAddressFromBridgeRegistry(sub.HmyPrivateKey, client, sub.HarmonyBridgeRegistry, txs.Oracle)
+	if err != nil {
+		return err
+	}
+
+	// Route the events of the registry's bridge contracts, and of those of other bridge instances
+	routes, err := NewContractRoutes(bridgeBankAddress, ethereumBridgeAddress, sub.Contracts)
+	if err != nil {
+		return err
+	}
+
+	// Look up the Oracle verifying each unlock contract's claims, by its BridgeRegistry
+	oracles := map[common.Address]common.Address{sub.HarmonyBridgeRegistry: oracleAddress}
+	for _, contract := range routes.Sorted() {
+		registry := contract.RegistryOr(sub.HarmonyBridgeRegistry)
+		if _, ok := oracles[registry]; ok || contract.Direction != UnlockDirection {
+			continue
+		}
+		if oracles[registry], err = txs.HmyGetAddressFromBridgeRegistry(sub.HmyPrivateKey, client, registry, txs.Oracle); err != nil {
+			return err
+		}
+	}
+
+	// Only fetch and relay the events emitted by the bridge contracts themselves
+	filter := routes.Filter(bridgeBankContractABI.Events[types.HmyLogLock.String()].ID,
+		unlockClaimTopics...)
+
+	confirmations := NewConfirmations(client, sub.ConfirmationDepth)
+	confirmations.Txs = NewHmyTxLocator(client)
+
+	// Halt signing if a reorg replaces processed blocks deeper than the confirmations guard against
+	var reorgs *ReorgGuard
+	if sub.MaxReorgDepth > 0 {
+		reorgs = NewReorgGuard(HarmonyChain, client, sub.MaxReorgDepth)
+		go reorgs.Watch(ctx, DefaultReorgCheckInterval, sub.Logger)
+	}
+
+	expiry := EventExpiry{Chain: client, Clock: client, MaxBlocks: sub.MaxEventBlocks, MaxAge: sub.MaxEventAge}
+
+	// handleLog relays a witnessed event according to its signature
+	handleLog := func(ctx context.Context, vLog htypes.Log) error {
+		if len(vLog.Topics) == 0 {
+			return nil
+		}
+
+		key := EventKey{TxHash: vLog.TxHash, LogIndex: vLog.Index}
+		if !filter.Expected(vLog.Topics, vLog.Address) {
+			sub.Logger.Error(fmt.Sprintf("Ignoring event %s from unexpected contract %s", key, types.ToBech32(vLog.Address)))
+			return nil
+		}
+		if sub.Seen != nil {
+			seen, err := sub.Seen.Has(key)
 			if err != nil {
-				sub.Logger.Error(err.Error())
-				os.Exit(1)
+				return err
 			}
-			_, subBridgeBank = sub.HmyStartContractEventSub(logs, client, txs.BridgeBank)
-			_, subEthereumBridge = sub.HmyStartContractEventSub(logs, client, txs.EthereumBridge)
-		// vLog is raw event data
-		case vLog := <-logs:
-			sub.Logger.Info(fmt.Sprintf("Witnessed tx %s on block %d\n", vLog.TxHash.Hex(), vLog.BlockNumber))
-			var err error
-			switch vLog.Topics[0].Hex() {
-			case eventLogLockSignature:
-				err = sub.HmyHandleLogLockEvent(clientChainID, bridgeBankAddress, bridgeBankContractABI, types.HmyLogLock.String(), vLog)
-			case eventLogNewUnlockClaimSignature:
-				err = sub.HmyHandleLogNewUnlockClaim(sub.HarmonyBridgeRegistry, ethereumBridgeContractABI,
-					types.HmyLogNewUnlockClaim.String(), vLog)
+			if seen {
+				sub.Logger.Info(fmt.Sprintf("Skipping already relayed event %s", key))
+				return nil
 			}
+		}
+
+		timer := txs.NewPipelineTimer(HarmonyChain, nil)
+		timer.Mark(txs.StageObserved)
+
+		if vLog.Removed {
+			sub.Logger.Info(fmt.Sprintf("Skipping removed event %s", key))
+			return nil
+		}
+		err := confirmations.Wait(ctx, vLog.BlockNumber, vLog.BlockHash)
+		if err == ErrEventReorged {
+			sub.Logger.Info(fmt.Sprintf("Skipping event %s, block %d was reorged", key, vLog.BlockNumber))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		timer.Mark(txs.StageConfirmed)
+		err = expiry.Check(ctx, vLog.BlockNumber)
+		if errors.Is(err, ErrEventExpired) {
+			sub.Logger.Error(fmt.Sprintf("Skipping expired event %s for manual review: %s", key, err.Error()))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Hold the event while an operator has paused signing, leaving it uncheckpointed until then
+		if err := txs.WaitSigningResumed(ctx); err != nil {
+			return err
+		}
+
+		contract := routes[vLog.Address]
+		switch {
+		case vLog.Topics[0].Hex() == eventLogLockSignature:
+			err = sub.HmyHandleLogLockEvent(ctx, confirmations, timer, clientChainID, vLog.Address, contract.RegistryOr(sub.EthereumBridgeRegistry),
+				bridgeBankContractABI, types.HmyLogLock.String(), vLog)
+		case containsTopic(unlockClaimTopics, vLog.Topics[0]):
+			registry := contract.RegistryOr(sub.HarmonyBridgeRegistry)
+			err = sub.HmyHandleLogNewUnlockClaim(ctx, confirmations, timer, registry, oracles[registry], vLog)
+		}
+		if err == txs.ErrDryRun {
+			return nil
+		}
+		if errors.Is(err, txs.ErrUndecodableEvent) {
+			sub.Logger.Error(fmt.Sprintf("Skipping undecodable event %s: %s", key, err.Error()))
+			return nil
+		}
+		if errors.Is(err, ErrEventVanished) {
+			sub.Logger.Error(fmt.Sprintf("Aborting claim for event %s before submission: %s", key, err.Error()))
+			return nil
+		}
+		if errors.Is(err, txs.ErrTokenNotAllowed) || errors.Is(err, txs.ErrClaimSkipped) {
+			err = nil
+		}
+		if err != nil || sub.Seen == nil {
+			return err
+		}
+		return sub.Seen.Add(key)
+	}
+
+	query := filter.Query()
+
+	// Replay events emitted since the last checkpoint, including any missed while stopped
+	if sub.Checkpoints != nil {
+		fromBlock, err := ContractsStartBlock(sub.Checkpoints, HarmonyChain, routes, sub.StartBlock)
+		if err != nil {
+			return err
+		}
+		sub.Logger.Info(fmt.Sprintf("Harmony - Replaying events from block %d", fromBlock))
+		query.FromBlock = new(big.Int).SetUint64(fromBlock)
+	}
+
+	wsSource := NewHmyWSLogSource(client, client, query, sub.ReconnectBackoff, sub.Logger)
+	wsSource.OnProgress = recordProgress(sub.Progress, HarmonyChain)
+	var source HmyLogSource = wsSource
+	if sub.PollInterval > 0 {
+		pollSource := NewHmyPollLogSource(client, client, query, sub.ConfirmationDepth, sub.Logger)
+		pollSource.PollInterval = sub.PollInterval
+		pollSource.MaxBlockRange = sub.MaxBlockRange
+		pollSource.OnProgress = recordProgress(sub.Progress, HarmonyChain)
+		source = pollSource
+	}
+	for _, contract := range routes.Sorted() {
+		sub.Logger.Info(fmt.Sprintf("Harmony - Subscribed to %v contract at %s", contract.Direction, types.ToBech32(contract.Address)))
+	}
+
+	// A failed event holds its contract's checkpoint back, so it is replayed on the next run
+	var checkpoints *EventCheckpoints
+	if sub.Checkpoints != nil && !txs.DryRun {
+		checkpoints = NewEventCheckpoints(sub.Checkpoints, HarmonyChain)
+	}
+	pool := NewWorkerPool(sub.Workers)
+	err = source.Run(ctx, func(vLog htypes.Log) {
+		sub.Logger.Info(fmt.Sprintf("Witnessed tx %s on block %d\n", vLog.TxHash.Hex(), vLog.BlockNumber))
+		var err error
+		pool.Go(func() {
+			err = handleLog(ctx, vLog)
+		}, func() {
 			// TODO: Check local events store for status, if retryable, attempt relay again
+			if _, ok := routes[vLog.Address]; ok {
+				outcome := txs.ContractEventProcessed
+				if err != nil {
+					outcome = txs.ContractEventFailed
+				}
+				txs.RecordContractEvent(HarmonyChain, vLog.Address, outcome)
+			}
 			if err != nil {
 				sub.Logger.Error("Harmony error: ", err.Error())
 			}
-		}
-	}
-
+			if checkpoints != nil {
+				if err := checkpoints.Done(vLog.Address, vLog.BlockNumber, err); err != nil {
+					sub.Logger.Error("Harmony - checkpoint error: ", err.Error())
+				}
+			}
+			if err == nil && sub.Progress != nil {
+				sub.Progress.Record(HarmonyChain, vLog.BlockNumber)
+			}
+			if err == nil && reorgs != nil {
+				reorgs.Record(vLog.BlockNumber, vLog.BlockHash)
+			}
+		})
+	})
+	pool.Wait()
+	sub.Logger.Info("Harmony - Stopping subscriptions")
+	return err
 }
 
 // HmyStartContractEventSub : starts an event subscription on the specified Harmony contract
@@ -132,24 +324,29 @@ func (sub HarmonySub) HmyStartContractEventSub(logs chan htypes.Log, client *hmy
 	}
 
 	// Start the contract subscription
-	contractSub, err := client.SubscribeFilterLogs(context.Background(), subQuery, logs)
+	var contractSub ethereum.Subscription
+	err = txs.Retry(context.Background(), func() error {
+		contractSub, err = client.SubscribeFilterLogs(context.Background(), subQuery, logs)
+		return err
+	}, txs.DefaultRetryPolicy)
 	if err != nil {
 		sub.Logger.Error(err.Error())
 	}
-	sub.Logger.Info(fmt.Sprintf("Harmony - Subscribed to %v contract at address: %s", contractName, subContractAddress.Hex()))
+	sub.Logger.Info(fmt.Sprintf("Harmony - Subscribed to %v contract at address: %s", contractName, types.ToBech32(subContractAddress)))
 	return subContractAddress, contractSub
 }
 
-// HmyHandleLogLockEvent unpacks a HmyLogLockEvent, and relays a tx to Ethereum
-func (sub HarmonySub) HmyHandleLogLockEvent(clientChainID *big.Int, bridgeBankAddress common.Address,
+// HmyHandleLogLockEvent unpacks a HmyLogLockEvent, and relays a tx to the Ethereum bridge of ethereumBridgeRegistry
+func (sub HarmonySub) HmyHandleLogLockEvent(ctx context.Context, confirmations Confirmations, timer *txs.PipelineTimer,
+	clientChainID *big.Int, bridgeBankAddress, ethereumBridgeRegistry common.Address,
 	contractABI abi.ABI, eventName string, cLog htypes.Log) error {
 	// Parse the event's attributes via contract ABI
 	event := types.HmyLogLockEvent{}
-	err := contractABI.Unpack(&event, eventName, cLog.Data)
-	if err != nil {
-		sub.Logger.Error("error unpacking: %v", err)
+	if err := txs.UnpackEvent(contractABI, &event, eventName, cLog.Topics, cLog.Data); err != nil {
+		return err
 	}
 	event.BridgeBankAddress = bridgeBankAddress
+	event.TxHash = cLog.TxHash
 	event.HarmonyChainID = clientChainID
 
 	sub.Logger.Info(event.String())
@@ -160,25 +357,53 @@ func (sub HarmonySub) HmyHandleLogLockEvent(clientChainID *big.Int, bridgeBankAd
 	if err != nil {
 		return err
 	}
+	amount := txs.FormatTokenAmount(unlockClaim.Token, unlockClaim.Amount)
+	sub.Logger.Info(fmt.Sprintf("Relaying lock of %s to Ethereum", amount))
 
-	return txs.RelayUnlockClaimToEthereum(sub.EthereumProvider, sub.EthereumBridgeRegistry, types.HmyLogLock, unlockClaim, sub.EthPrivateKey)
+	// Hold the claim while submission is halted by consecutive reverts, leaving it uncheckpointed
+	err = submitWhenAllowed(ctx, txs.EthSubmitBreaker, func() error {
+		if err := confirmations.Revalidate(ctx, cLog.TxHash, cLog.BlockNumber, cLog.BlockHash); err != nil {
+			return err
+		}
+		return txs.RelayUnlockClaimToEthereum(sub.EthereumProvider, ethereumBridgeRegistry, types.HmyLogLock, unlockClaim, sub.EthPrivateKey, timer)
+	})
+	if err == nil {
+		recordClaim(sub.Progress, HarmonyChain, amount)
+	}
+	return err
 }
 
 // HmyHandleLogNewUnlockClaim unpacks a HmyLogNewUnlockClaim event, builds a new OracleClaim, and relays it to Harmony
-func (sub HarmonySub) HmyHandleLogNewUnlockClaim(contractAddress common.Address, contractABI abi.ABI,
-	eventName string, hLog htypes.Log) error {
+func (sub HarmonySub) HmyHandleLogNewUnlockClaim(ctx context.Context, confirmations Confirmations, timer *txs.PipelineTimer,
+	contractAddress, oracleAddress common.Address, hLog htypes.Log) error {
 	// Parse the event's attributes via contract ABI
-	event := types.HmyLogNewUnlockClaimEvent{}
-	err := contractABI.Unpack(&event, eventName, hLog.Data)
+	event, err := txs.ParseHmyUnlockClaim(hLog)
 	if err != nil {
-		sub.Logger.Error("error unpacking: %v", err)
+		return err
 	}
 	sub.Logger.Info(event.String())
+	unlockID, _, _, token, tokenAmount := event.ClaimFields()
+	amount := txs.FormatTokenAmount(token, tokenAmount)
+	sub.Logger.Info(fmt.Sprintf("Claiming unlock %v of %s", unlockID, amount))
 
-	oracleClaim, err := txs.HmyUnlockClaimToSignedOracleClaim(event, sub.HmyPrivateKey)
+	oracleClaim, err := txs.HmyUnlockClaimToSignedOracleClaim(event, sub.HmyPrivateKey, oracleAddress)
 	if err != nil {
 		return err
 	}
-	return txs.RelayOracleClaimToHarmony(sub.HarmonyProvider, contractAddress, types.HmyLogNewUnlockClaim,
-		oracleClaim, sub.HmyPrivateKey)
+	timer.Mark(txs.StageSigned)
+	if err := txs.WaitSigningResumed(ctx); err != nil {
+		return err
+	}
+	// Hold the claim while submission is halted by consecutive reverts, leaving it uncheckpointed
+	err = submitWhenAllowed(ctx, txs.HmySubmitBreaker, func() error {
+		if err := confirmations.Revalidate(ctx, hLog.TxHash, hLog.BlockNumber, hLog.BlockHash); err != nil {
+			return err
+		}
+		return txs.RelayOracleClaimToHarmony(sub.HarmonyProvider, contractAddress, types.HmyLogNewUnlockClaim,
+			oracleClaim, sub.HmyPrivateKey, timer)
+	})
+	if err == nil {
+		recordClaim(sub.Progress, HarmonyChain, amount)
+	}
+	return err
 }
@@ -0,0 +1,248 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+const (
+	// HealthPath is the path a HealthCheck is mounted at
+	HealthPath = "/health"
+	// DefaultHealthTimeout bounds the RPC head query made for each chain per health check
+	DefaultHealthTimeout = 5 * time.Second
+)
+
+// Progress records the last block processed on each chain, and the formatted amount of the last
+// claim relayed from it. It is safe for concurrent use.
+type Progress struct {
+	mu     sync.RWMutex
+	blocks map[string]uint64
+	claims map[string]string
+}
+
+// NewProgress initializes a new, empty Progress
+func NewProgress() *Progress {
+	return &Progress{blocks: make(map[string]uint64), claims: make(map[string]string)}
+}
+
+// Record marks chain as processed through block. Progress never moves backward.
+func (p *Progress) Record(chain string, block uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if last, ok := p.blocks[chain]; !ok || block > last {
+		p.blocks[chain] = block
+	}
+}
+
+// Last returns the last block processed on chain, if any
+func (p *Progress) Last(chain string) (uint64, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	block, ok := p.blocks[chain]
+	return block, ok
+}
+
+// RecordClaim marks amount, formatted for display, as the last claim relayed from chain
+func (p *Progress) RecordClaim(chain, amount string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.claims[chain] = amount
+}
+
+// LastClaim returns the formatted amount of the last claim relayed from chain, if any
+func (p *Progress) LastClaim(chain string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	amount, ok := p.claims[chain]
+	return amount, ok
+}
+
+// recordClaim records amount as chain's last claim in p, if p is set
+func recordClaim(p *Progress, chain, amount string) {
+	if p != nil {
+		p.RecordClaim(chain, amount)
+	}
+}
+
+// recordProgress returns a callback recording chain's progress in p, or nil if p is nil
+func recordProgress(p *Progress, chain string) func(uint64) {
+	if p == nil {
+		return nil
+	}
+	return func(block uint64) { p.Record(chain, block) }
+}
+
+// ChainHealth reports a chain's progress and RPC connection health
+type ChainHealth struct {
+	Validator          string `json:"validator"`
+	LastProcessedBlock uint64 `json:"lastProcessedBlock"`
+	Head               uint64 `json:"head"`
+	Lag                uint64 `json:"lag"`
+	RPCHealthy         bool   `json:"rpcHealthy"`
+	LastClaimAmount    string `json:"lastClaimAmount,omitempty"`
+	ActiveEndpoint     string `json:"activeEndpoint,omitempty"`
+	GasBalance         string `json:"gasBalance,omitempty"`
+	GasBalanceLow      bool   `json:"gasBalanceLow,omitempty"`
+	Error              string `json:"error,omitempty"`
+}
+
+// HealthReport is the JSON body served by a HealthCheck. Paused reports claim signing paused
+// through an Admin, which doesn't make the relayer unhealthy: it is still following both chains.
+type HealthReport struct {
+	Healthy bool                   `json:"healthy"`
+	Paused  bool                   `json:"paused"`
+	Chains  map[string]ChainHealth `json:"chains"`
+}
+
+// healthChain is a chain watched by a HealthCheck
+type healthChain struct {
+	chain     CanonicalChain
+	validator common.Address
+	endpoints *txs.Endpoints
+	balances  txs.BalanceReader
+	guard     *txs.GasBalanceGuard
+}
+
+// HealthCheck is an http.Handler reporting each chain's last processed block, its lag behind the
+// chain head, the validator address, its gas balance if checked, and whether the chain's RPC
+// connection responds. It responds 503 Service Unavailable if any chain's RPC is down, its gas
+// balance is below the minimum or, unless MaxLag is 0, it lags more than MaxLag blocks.
+type HealthCheck struct {
+	Progress *Progress
+	MaxLag   uint64
+	Timeout  time.Duration
+
+	mu     sync.RWMutex
+	chains map[string]healthChain
+}
+
+// NewHealthCheck initializes a new HealthCheck reporting progress, unhealthy beyond maxLag blocks
+func NewHealthCheck(progress *Progress, maxLag uint64) *HealthCheck {
+	return &HealthCheck{
+		Progress: progress,
+		MaxLag:   maxLag,
+		Timeout:  DefaultHealthTimeout,
+		chains:   make(map[string]healthChain),
+	}
+}
+
+// AddChain reports the named chain, queried through chain, with the validator signing for it
+func (h *HealthCheck) AddChain(name string, chain CanonicalChain, validator common.Address) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.chains[name] = healthChain{chain: chain, validator: validator}
+}
+
+// SetEndpoints reports which of endpoints the named chain, already added, is connected to
+func (h *HealthCheck) SetEndpoints(name string, endpoints *txs.Endpoints) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c, ok := h.chains[name]; ok {
+		c.endpoints = endpoints
+		h.chains[name] = c
+	}
+}
+
+// SetGasBalance reports the validator's balance on the named chain, already added, queried
+// through balances and checked against guard's minimum
+func (h *HealthCheck) SetGasBalance(name string, balances txs.BalanceReader, guard *txs.GasBalanceGuard) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c, ok := h.chains[name]; ok {
+		c.balances, c.guard = balances, guard
+		h.chains[name] = c
+	}
+}
+
+// Register mounts the HealthCheck on mux at HealthPath
+func (h *HealthCheck) Register(mux *http.ServeMux) {
+	mux.Handle(HealthPath, h)
+}
+
+// Report queries each chain's head and compares it to the progress recorded for the chain. A
+// chain with no progress recorded yet reports no lag.
+func (h *HealthCheck) Report(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	report := HealthReport{Healthy: true, Paused: txs.SigningPaused(), Chains: make(map[string]ChainHealth, len(h.chains))}
+	for name, c := range h.chains {
+		health := ChainHealth{Validator: c.validator.Hex()}
+
+		queryCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+		head, err := c.chain.BlockNumber(queryCtx)
+		cancel()
+		if err != nil {
+			health.Error = err.Error()
+			report.Healthy = false
+		} else {
+			health.RPCHealthy = true
+			health.Head = head
+		}
+
+		if last, ok := h.lastProcessed(name); ok {
+			health.LastProcessedBlock = last
+			if health.RPCHealthy && head > last {
+				health.Lag = head - last
+			}
+		}
+		if h.Progress != nil {
+			health.LastClaimAmount, _ = h.Progress.LastClaim(name)
+		}
+		if c.endpoints != nil {
+			health.ActiveEndpoint = txs.RedactURL(c.endpoints.Active())
+		}
+		if c.guard != nil && c.balances != nil {
+			queryCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+			balance, err := c.guard.Refresh(queryCtx, c.balances, c.validator)
+			cancel()
+			if err == nil {
+				health.GasBalance = balance.String()
+				health.GasBalanceLow = c.guard.Low()
+			} else if health.Error == "" {
+				health.Error = err.Error()
+			}
+			if health.GasBalanceLow {
+				report.Healthy = false
+			}
+		}
+		if h.MaxLag > 0 && health.Lag > h.MaxLag {
+			report.Healthy = false
+		}
+		report.Chains[name] = health
+	}
+	return report
+}
+
+// lastProcessed returns the last block processed on chain, if any
+func (h *HealthCheck) lastProcessed(chain string) (uint64, bool) {
+	if h.Progress == nil {
+		return 0, false
+	}
+	return h.Progress.Last(chain)
+}
+
+// ServeHTTP implements http.Handler
+func (h *HealthCheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := h.Report(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,88 @@
+package relayer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// serveHealth returns the status code and decoded body of a request to h
+func serveHealth(t *testing.T, h *HealthCheck) (int, map[string]interface{}) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", contentType)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	return rec.Code, body
+}
+
+func TestHealthCheckJSON(t *testing.T) {
+	chain := newTestChain()
+	chain.set(100, testBlock{})
+	progress := NewProgress()
+	progress.Record("ethereum", 95)
+	validator := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+	h := NewHealthCheck(progress, 10)
+	h.AddChain("ethereum", chain, validator)
+	code, body := serveHealth(t, h)
+	if code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	if body["healthy"] != true || body["paused"] != false {
+		t.Fatalf("healthy %v, paused %v, want true and false", body["healthy"], body["paused"])
+	}
+	chains, ok := body["chains"].(map[string]interface{})
+	if !ok || len(chains) != 1 {
+		t.Fatalf("chains %v, want the ethereum chain only", body["chains"])
+	}
+	want := map[string]interface{}{
+		"validator":          validator.Hex(),
+		"lastProcessedBlock": float64(95),
+		"head":               float64(100),
+		"lag":                float64(5),
+		"rpcHealthy":         true,
+	}
+	ethereum, _ := chains["ethereum"].(map[string]interface{})
+	if len(ethereum) != len(want) {
+		t.Fatalf("ethereum health %v, want %v", ethereum, want)
+	}
+	for key, value := range want {
+		if ethereum[key] != value {
+			t.Fatalf("ethereum %s = %v, want %v", key, ethereum[key], value)
+		}
+	}
+}
+
+func TestHealthCheckLag(t *testing.T) {
+	chain := newTestChain()
+	chain.set(100, testBlock{})
+	progress := NewProgress()
+	progress.Record("ethereum", 89)
+
+	h := NewHealthCheck(progress, 10)
+	h.AddChain("ethereum", chain, common.Address{})
+	if code, body := serveHealth(t, h); code != http.StatusServiceUnavailable || body["healthy"] != false {
+		t.Fatalf("lagging 11 blocks: status %d, healthy %v, want 503 and false", code, body["healthy"])
+	}
+
+	// A MaxLag of 0 disables the lag check
+	h.MaxLag = 0
+	if code, _ := serveHealth(t, h); code != http.StatusOK {
+		t.Fatalf("lagging with no MaxLag: status %d, want 200", code)
+	}
+
+	h.MaxLag = 10
+	progress.Record("ethereum", 90)
+	if code, _ := serveHealth(t, h); code != http.StatusOK {
+		t.Fatalf("lagging MaxLag blocks: status %d, want 200", code)
+	}
+}
@@ -1,11 +1,15 @@
 package relayer
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
 	"github.com/mochi-lab/eth-one-bridge/hmyclient"
 )
 
@@ -18,8 +22,10 @@ func IsWebsocketURL(rawurl string) bool {
 	return u.Scheme == "ws" || u.Scheme == "wss"
 }
 
-// EthSetupWebsocketClient returns boolean indicating if a URL is valid websocket ethclient
-func EthSetupWebsocketClient(ethURL string) (*ethclient.Client, error) {
+// EthSetupWebsocketClient returns boolean indicating if a URL is valid websocket ethclient,
+// dialing it with EthDialWithRetry so a node which is down at startup is waited for
+func EthSetupWebsocketClient(ctx context.Context, ethURL string, policy txs.RetryPolicy,
+	logger tmLog.Logger) (*ethclient.Client, error) {
 	if strings.TrimSpace(ethURL) == "" {
 		return nil, nil
 	}
@@ -28,7 +34,7 @@ func EthSetupWebsocketClient(ethURL string) (*ethclient.Client, error) {
 		return nil, fmt.Errorf("invalid websocket eth client URL: %s", ethURL)
 	}
 
-	client, err := ethclient.Dial(ethURL)
+	client, err := EthDialWithRetry(ctx, ethURL, policy, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -36,8 +42,10 @@ func EthSetupWebsocketClient(ethURL string) (*ethclient.Client, error) {
 	return client, nil
 }
 
-// HmySetupWebsocketClient returns boolean indicating if a URL is valid websocket hmyclient
-func HmySetupWebsocketClient(hmyURL string) (*hmyclient.Client, error) {
+// HmySetupWebsocketClient returns boolean indicating if a URL is valid websocket hmyclient,
+// dialing it with HmyDialWithRetry so a node which is down at startup is waited for
+func HmySetupWebsocketClient(ctx context.Context, hmyURL string, policy txs.RetryPolicy,
+	logger tmLog.Logger) (*hmyclient.Client, error) {
 	if strings.TrimSpace(hmyURL) == "" {
 		return nil, nil
 	}
@@ -46,7 +54,7 @@ func HmySetupWebsocketClient(hmyURL string) (*hmyclient.Client, error) {
 		return nil, fmt.Errorf("invalid websocket eth client URL: %s", hmyURL)
 	}
 
-	client, err := hmyclient.Dial(hmyURL)
+	client, err := HmyDialWithRetry(ctx, hmyURL, policy, logger)
 	if err != nil {
 		return nil, err
 	}
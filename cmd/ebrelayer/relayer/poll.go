@@ -0,0 +1,200 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	htypes "github.com/harmony-one/harmony/core/types"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// DefaultPollInterval is how often a polling log source queries for new blocks
+	DefaultPollInterval = 15 * time.Second
+	// DefaultMaxBlockRange is the most blocks a polling log source queries per FilterLogs request
+	DefaultMaxBlockRange = 1000
+)
+
+// pollCursor tracks the next block a polling log source queries
+type pollCursor struct {
+	next    uint64
+	started bool
+}
+
+// window returns the next block range to query, at most maxRange blocks long and ending no later
+// than confirmations blocks behind head. ok is false once the cursor has caught up. An unstarted
+// cursor starts after the latest confirmed block, so only new logs are delivered.
+func (c *pollCursor) window(head, confirmations, maxRange uint64) (from, to uint64, ok bool) {
+	if head < confirmations {
+		return 0, 0, false
+	}
+	safe := head - confirmations
+	if !c.started {
+		c.next, c.started = safe+1, true
+	}
+	if c.next > safe {
+		return 0, 0, false
+	}
+
+	to = safe
+	if maxRange > 0 && safe-c.next >= maxRange {
+		to = c.next + maxRange - 1
+	}
+	return c.next, to, true
+}
+
+// EthPollLogSource is an EthLogSource polling FilterLogs, for providers which don't support
+// eth_subscribe. Every PollInterval it queries the blocks from the last one queried up to
+// Confirmations blocks behind the head, in requests of at most MaxBlockRange blocks. If
+// Query.FromBlock is set, polling starts from it, otherwise from the latest confirmed block.
+type EthPollLogSource struct {
+	Client        EthLogFilterer
+	Chain         CanonicalChain
+	Query         ethereum.FilterQuery
+	Confirmations uint64
+	PollInterval  time.Duration
+	MaxBlockRange uint64
+	Logger        tmLog.Logger
+	// OnProgress, if set, is called with the last block of each range once its logs were delivered
+	OnProgress func(block uint64)
+}
+
+// NewEthPollLogSource initializes a new EthPollLogSource with DefaultPollInterval and
+// DefaultMaxBlockRange
+func NewEthPollLogSource(client EthLogFilterer, chain CanonicalChain, query ethereum.FilterQuery,
+	confirmations uint64, logger tmLog.Logger) *EthPollLogSource {
+	return &EthPollLogSource{
+		Client:        client,
+		Chain:         chain,
+		Query:         query,
+		Confirmations: confirmations,
+		PollInterval:  DefaultPollInterval,
+		MaxBlockRange: DefaultMaxBlockRange,
+		Logger:        logger,
+	}
+}
+
+// Run implements EthLogSource. It only returns once ctx is done.
+func (s *EthPollLogSource) Run(ctx context.Context, handle func(ctypes.Log)) error {
+	var cursor pollCursor
+	if s.Query.FromBlock != nil {
+		cursor.next, cursor.started = s.Query.FromBlock.Uint64(), true
+	}
+
+	for {
+		head, err := s.Chain.BlockNumber(ctx)
+		if err != nil {
+			s.Logger.Error("Ethereum - head query error: ", err.Error())
+		}
+
+		for err == nil {
+			from, to, ok := cursor.window(head, s.Confirmations, s.MaxBlockRange)
+			if !ok {
+				break
+			}
+
+			query := s.Query
+			query.FromBlock = new(big.Int).SetUint64(from)
+			query.ToBlock = new(big.Int).SetUint64(to)
+			var logs []ctypes.Log
+			if logs, err = s.Client.FilterLogs(ctx, query); err != nil {
+				s.Logger.Error(fmt.Sprintf("Ethereum - poll error over blocks %d to %d: ", from, to), err.Error())
+				break
+			}
+			for _, vLog := range logs {
+				if ctx.Err() != nil {
+					return nil
+				}
+				handle(vLog)
+			}
+			cursor.next = to + 1
+			if s.OnProgress != nil {
+				s.OnProgress(to)
+			}
+		}
+
+		if !sleepContext(ctx, s.PollInterval) {
+			return nil
+		}
+	}
+}
+
+// HmyPollLogSource is an HmyLogSource polling FilterLogs, for providers which don't support
+// hmy_subscribe. Every PollInterval it queries the blocks from the last one queried up to
+// Confirmations blocks behind the head, in requests of at most MaxBlockRange blocks. If
+// Query.FromBlock is set, polling starts from it, otherwise from the latest confirmed block.
+type HmyPollLogSource struct {
+	Client        HmyLogFilterer
+	Chain         CanonicalChain
+	Query         ethereum.FilterQuery
+	Confirmations uint64
+	PollInterval  time.Duration
+	MaxBlockRange uint64
+	Logger        tmLog.Logger
+	// OnProgress, if set, is called with the last block of each range once its logs were delivered
+	OnProgress func(block uint64)
+}
+
+// NewHmyPollLogSource initializes a new HmyPollLogSource with DefaultPollInterval and
+// DefaultMaxBlockRange
+func NewHmyPollLogSource(client HmyLogFilterer, chain CanonicalChain, query ethereum.FilterQuery,
+	confirmations uint64, logger tmLog.Logger) *HmyPollLogSource {
+	return &HmyPollLogSource{
+		Client:        client,
+		Chain:         chain,
+		Query:         query,
+		Confirmations: confirmations,
+		PollInterval:  DefaultPollInterval,
+		MaxBlockRange: DefaultMaxBlockRange,
+		Logger:        logger,
+	}
+}
+
+// Run implements HmyLogSource. It only returns once ctx is done.
+func (s *HmyPollLogSource) Run(ctx context.Context, handle func(htypes.Log)) error {
+	var cursor pollCursor
+	if s.Query.FromBlock != nil {
+		cursor.next, cursor.started = s.Query.FromBlock.Uint64(), true
+	}
+
+	for {
+		head, err := s.Chain.BlockNumber(ctx)
+		if err != nil {
+			s.Logger.Error("Harmony - head query error: ", err.Error())
+		}
+
+		for err == nil {
+			from, to, ok := cursor.window(head, s.Confirmations, s.MaxBlockRange)
+			if !ok {
+				break
+			}
+
+			query := s.Query
+			query.FromBlock = new(big.Int).SetUint64(from)
+			query.ToBlock = new(big.Int).SetUint64(to)
+			var logs []htypes.Log
+			if logs, err = s.Client.FilterLogs(ctx, query); err != nil {
+				s.Logger.Error(fmt.Sprintf("Harmony - poll error over blocks %d to %d: ", from, to), err.Error())
+				break
+			}
+			for _, vLog := range logs {
+				if ctx.Err() != nil {
+					return nil
+				}
+				handle(vLog)
+			}
+			cursor.next = to + 1
+			if s.OnProgress != nil {
+				s.OnProgress(to)
+			}
+		}
+
+		if !sleepContext(ctx, s.PollInterval) {
+			return nil
+		}
+	}
+}
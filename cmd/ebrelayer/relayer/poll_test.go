@@ -0,0 +1,118 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+)
+
+// blockRange is a range of blocks queried by FilterLogs
+type blockRange struct {
+	from, to uint64
+}
+
+// rangeRecorder is an EthLogFilterer recording the block ranges queried, failing those in fail
+// once each
+type rangeRecorder struct {
+	*testLogClient
+	mu     sync.Mutex
+	ranges []blockRange
+	fail   map[blockRange]bool
+}
+
+func (r *rangeRecorder) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]ctypes.Log, error) {
+	queried := blockRange{q.FromBlock.Uint64(), q.ToBlock.Uint64()}
+	r.mu.Lock()
+	r.ranges = append(r.ranges, queried)
+	failed := r.fail[queried]
+	delete(r.fail, queried)
+	r.mu.Unlock()
+
+	if failed {
+		return nil, errors.New("request timed out")
+	}
+	return r.testLogClient.FilterLogs(ctx, q)
+}
+
+func TestEthPollLogSourceAdvancesCursor(t *testing.T) {
+	chain := newTestChain()
+	chain.set(100, testBlock{})
+	client := newTestLogClient()
+	for block := uint64(85); block <= 110; block++ {
+		client.emit(block, 0)
+	}
+	// The second range fails on its first attempt
+	filterer := &rangeRecorder{testLogClient: client, fail: map[blockRange]bool{{94, 97}: true}}
+
+	source := NewEthPollLogSource(filterer, chain, ethereum.FilterQuery{FromBlock: big.NewInt(90)}, 2, tmLog.NewNopLogger())
+	source.PollInterval = time.Millisecond
+	source.MaxBlockRange = 4
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source.OnProgress = func(block uint64) {
+		switch block {
+		case 98:
+			// The chain grows between poll cycles
+			chain.set(105, testBlock{})
+		case 103:
+			cancel()
+		}
+	}
+	var delivered []uint64
+	if err := source.Run(ctx, func(vLog ctypes.Log) { delivered = append(delivered, vLog.BlockNumber) }); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRanges := []blockRange{{90, 93}, {94, 97}, {94, 97}, {98, 98}, {99, 102}, {103, 103}}
+	if len(filterer.ranges) != len(wantRanges) {
+		t.Fatalf("queried ranges %v, want %v", filterer.ranges, wantRanges)
+	}
+	for i, queried := range filterer.ranges {
+		if queried != wantRanges[i] {
+			t.Fatalf("queried ranges %v, want %v", filterer.ranges, wantRanges)
+		}
+	}
+
+	// Every log from the start block to the last confirmed one, each once and in order
+	if len(delivered) != 14 {
+		t.Fatalf("delivered logs of blocks %v, want blocks 90 to 103", delivered)
+	}
+	for i, block := range delivered {
+		if block != uint64(90+i) {
+			t.Fatalf("delivered logs of blocks %v, want blocks 90 to 103", delivered)
+		}
+	}
+}
+
+func TestPollCursorWindow(t *testing.T) {
+	var cursor pollCursor
+	if _, _, ok := cursor.window(5, 10, 100); ok {
+		t.Fatal("window before the chain has confirmed blocks")
+	}
+
+	// An unstarted cursor begins after the latest confirmed block
+	if _, _, ok := cursor.window(50, 10, 100); ok {
+		t.Fatal("unstarted cursor queried already confirmed blocks")
+	}
+	from, to, ok := cursor.window(55, 10, 100)
+	if !ok || from != 41 || to != 45 {
+		t.Fatalf("window = %d-%d, %v, want 41-45", from, to, ok)
+	}
+
+	// The cursor only moves once the caller has delivered the range
+	if from, to, _ = cursor.window(55, 10, 100); from != 41 {
+		t.Fatalf("window repeated = %d-%d, want 41-45 again", from, to)
+	}
+	cursor.next = to + 1
+	if from, to, ok = cursor.window(200, 10, 100); !ok || from != 46 || to != 145 {
+		t.Fatalf("window = %d-%d, %v, want 46-145 capped at 100 blocks", from, to, ok)
+	}
+}
@@ -0,0 +1,71 @@
+package relayer
+
+import "sync"
+
+// DefaultWorkers is the number of events processed at once unless configured otherwise
+const DefaultWorkers = 1
+
+// WorkerPool processes events on a bounded number of goroutines. Each event's task runs as soon
+// as a worker is free, but its done callback only runs once every earlier event's callback has,
+// so checkpoints advance in chain order even as events finish out of order.
+type WorkerPool struct {
+	slots chan struct{}
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	next  uint64
+	ready uint64
+	done  map[uint64]func()
+}
+
+// NewWorkerPool initializes a new WorkerPool running at most size tasks at once. A size below 1
+// uses DefaultWorkers.
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = DefaultWorkers
+	}
+	return &WorkerPool{slots: make(chan struct{}, size), done: make(map[uint64]func())}
+}
+
+// Go runs task on a free worker, blocking until one is available, then runs done in submission
+// order. done may be nil.
+func (p *WorkerPool) Go(task, done func()) {
+	p.slots <- struct{}{}
+	p.mu.Lock()
+	seq := p.next
+	p.next++
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		task()
+		<-p.slots
+		p.complete(seq, done)
+	}()
+}
+
+// complete records the task at seq as finished, running the done callbacks of every finished
+// task no longer waiting on an earlier one
+func (p *WorkerPool) complete(seq uint64, done func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if done == nil {
+		done = func() {}
+	}
+	p.done[seq] = done
+	for {
+		callback, ok := p.done[p.ready]
+		if !ok {
+			return
+		}
+		delete(p.done, p.ready)
+		p.ready++
+		callback()
+	}
+}
+
+// Wait blocks until every submitted task and its done callback has run
+func (p *WorkerPool) Wait() {
+	p.wg.Wait()
+}
@@ -0,0 +1,100 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// zeroNonces is a txs.PendingNonceSource with no pending transactions for any account
+type zeroNonces struct{}
+
+func (zeroNonces) PendingNonceAt(context.Context, common.Address) (uint64, error) { return 0, nil }
+
+// runPool processes events through a pool of size, each taking work and a nonce, returning how
+// long they took, the nonces in the order claims were sent, and the order done callbacks ran in
+func runPool(t *testing.T, size, events int, work time.Duration) (time.Duration, []uint64, []int) {
+	nonces := txs.NewNonceManager(zeroNonces{})
+	account := common.HexToAddress("0x1")
+	var (
+		submitMu sync.Mutex
+		sent     []uint64
+		done     []int
+	)
+
+	pool := NewWorkerPool(size)
+	start := time.Now()
+	for i := 0; i < events; i++ {
+		i := i
+		pool.Go(func() {
+			// Events finish out of order, the later ones first
+			time.Sleep(work * time.Duration(events-i) / time.Duration(events))
+
+			// As the Relay functions do, hold the signer's submission lock from nonce to send
+			submitMu.Lock()
+			defer submitMu.Unlock()
+			nonce, err := nonces.Next(context.Background(), account)
+			if err != nil {
+				t.Error(err)
+			}
+			sent = append(sent, nonce)
+		}, func() { done = append(done, i) })
+	}
+	pool.Wait()
+	return time.Since(start), sent, done
+}
+
+func TestWorkerPoolParallelism(t *testing.T) {
+	const events = 16
+	work := 20 * time.Millisecond
+	serial, _, _ := runPool(t, 1, events, work)
+	parallel, sent, done := runPool(t, 8, events, work)
+
+	if parallel >= serial/2 {
+		t.Fatalf("%d events took %s on 8 workers and %s on 1, want at least twice as fast", events, parallel, serial)
+	}
+	for i, nonce := range sent {
+		if nonce != uint64(i) {
+			t.Fatalf("nonces sent %v, want contiguous from 0", sent)
+		}
+	}
+	if len(done) != events {
+		t.Fatalf("%d done callbacks ran, want %d", len(done), events)
+	}
+	for i, event := range done {
+		if event != i {
+			t.Fatalf("done callbacks ran in order %v, want submission order", done)
+		}
+	}
+}
+
+func TestWorkerPoolBounded(t *testing.T) {
+	var (
+		mu              sync.Mutex
+		running, maxRan int
+	)
+	pool := NewWorkerPool(3)
+	for i := 0; i < 12; i++ {
+		pool.Go(func() {
+			mu.Lock()
+			running++
+			if running > maxRan {
+				maxRan = running
+			}
+			mu.Unlock()
+			time.Sleep(2 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}, nil)
+	}
+	pool.Wait()
+	if maxRan > 3 {
+		t.Fatalf("%d tasks ran at once, want at most 3", maxRan)
+	}
+}
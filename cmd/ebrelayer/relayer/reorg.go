@@ -0,0 +1,151 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+const (
+	// DefaultReorgCheckInterval is how often processed blocks are checked against the canonical chain
+	DefaultReorgCheckInterval = 30 * time.Second
+	// DefaultReorgWindow is how many of the latest processed blocks are remembered and checked
+	DefaultReorgWindow = 64
+)
+
+// ErrDeepReorg is returned when a processed block was replaced by a reorg deeper than MaxDepth
+var ErrDeepReorg = errors.New("reorg deeper than the maximum reorg depth")
+
+// ReorgGuard remembers the hashes of the latest blocks events were processed in, and checks them
+// against the canonical chain. A reorg replacing a processed block more than MaxDepth blocks
+// deep halts claim signing with txs.HaltSigning, since claims may already have been signed for
+// events which will never be final. It is safe for concurrent use.
+type ReorgGuard struct {
+	Name     string
+	Chain    CanonicalChain
+	MaxDepth uint64
+	Window   int
+
+	mu     sync.Mutex
+	blocks map[uint64]common.Hash
+}
+
+// NewReorgGuard initializes a new ReorgGuard for the named chain, remembering DefaultReorgWindow blocks
+func NewReorgGuard(name string, chain CanonicalChain, maxDepth uint64) *ReorgGuard {
+	return &ReorgGuard{
+		Name:     name,
+		Chain:    chain,
+		MaxDepth: maxDepth,
+		Window:   DefaultReorgWindow,
+		blocks:   make(map[uint64]common.Hash),
+	}
+}
+
+// Record remembers that events were processed in the block number with hash, forgetting the
+// oldest block beyond Window
+func (g *ReorgGuard) Record(number uint64, hash common.Hash) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.blocks[number] = hash
+	if len(g.blocks) <= g.Window {
+		return
+	}
+	oldest := number
+	for height := range g.blocks {
+		if height < oldest {
+			oldest = height
+		}
+	}
+	delete(g.blocks, oldest)
+}
+
+// Check compares each remembered block with the canonical block at its height. A replaced block
+// at height n means the last head-n+1 blocks were reorged. If that is more than MaxDepth, signing
+// is halted and ErrDeepReorg returned; shallower reorgs are logged and the replaced blocks
+// forgotten.
+func (g *ReorgGuard) Check(ctx context.Context, logger tmLog.Logger) error {
+	head, err := g.Chain.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, number := range g.heights() {
+		g.mu.Lock()
+		hash, ok := g.blocks[number]
+		g.mu.Unlock()
+		if !ok || number > head {
+			continue
+		}
+
+		canonicalHash, err := g.Chain.BlockHashByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		if canonicalHash == hash {
+			continue
+		}
+
+		depth := head - number + 1
+		if depth > g.MaxDepth {
+			err := fmt.Errorf("%w: %s block %d changed from %s to %s, reorging %d blocks at head %d, more than %d",
+				ErrDeepReorg, g.Name, number, hash.Hex(), canonicalHash.Hex(), depth, head, g.MaxDepth)
+			if haltErr := txs.HaltSigning(err.Error()); haltErr != nil {
+				logger.Error(fmt.Sprintf("%s - recording signing halt: %s", g.Name, haltErr.Error()))
+			}
+			return err
+		}
+		logger.Error(fmt.Sprintf("%s - processed block %d was reorged %d blocks deep, within %d", g.Name,
+			number, depth, g.MaxDepth))
+		g.mu.Lock()
+		delete(g.blocks, number)
+		g.mu.Unlock()
+	}
+	return nil
+}
+
+// Watch runs Check every interval until ctx is done or a deep reorg halts signing. Failed checks
+// are logged and retried on the next tick.
+func (g *ReorgGuard) Watch(ctx context.Context, interval time.Duration, logger tmLog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := g.Check(ctx, logger)
+		if errors.Is(err, ErrDeepReorg) {
+			logger.Error(fmt.Sprintf("%s - %s", g.Name, err.Error()))
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			logger.Error(fmt.Sprintf("%s - reorg check failed: %s", g.Name, err.Error()))
+		}
+	}
+}
+
+// heights returns the remembered block heights, lowest first, so the deepest reorg is found first
+func (g *ReorgGuard) heights() []uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	heights := make([]uint64, 0, len(g.blocks))
+	for height := range g.blocks {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights
+}
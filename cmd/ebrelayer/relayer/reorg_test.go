@@ -0,0 +1,98 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// blockHash returns a distinct hash for block number of fork
+func blockHash(fork byte, number uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(uint64(fork)<<32 | number))
+}
+
+// processBlocks extends chain with blocks from..to of fork, recording each in guard as processed
+func processBlocks(chain *testChain, guard *ReorgGuard, fork byte, from, to uint64) {
+	for number := from; number <= to; number++ {
+		chain.set(number, testBlock{hash: blockHash(fork, number)})
+		guard.Record(number, blockHash(fork, number))
+	}
+}
+
+func TestReorgGuardShallowReorg(t *testing.T) {
+	chain := newTestChain()
+	guard := NewReorgGuard("Ethereum", chain, 3)
+	processBlocks(chain, guard, 0, 1, 10)
+
+	// Replacing blocks 9 and 10 reorgs 2 blocks, within the maximum
+	chain.set(9, testBlock{hash: blockHash(1, 9)})
+	chain.set(10, testBlock{hash: blockHash(1, 10)})
+	if err := guard.Check(context.Background(), tmLog.NewNopLogger()); err != nil {
+		t.Fatalf("Check after a shallow reorg = %v", err)
+	}
+	if _, halted := txs.SigningHalted(); halted {
+		t.Fatal("a shallow reorg halted signing")
+	}
+	// The replaced blocks are forgotten, so they aren't reported again
+	if err := guard.Check(context.Background(), tmLog.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReorgGuardDeepReorgHaltsSigning(t *testing.T) {
+	dir, cleanup := testDir(t)
+	defer cleanup()
+	// The halt is recorded in the file, so that removing it resumes signing for the tests after
+	// this one, as an operator would
+	txs.SigningHaltFile = filepath.Join(dir, "halted")
+
+	chain := newTestChain()
+	guard := NewReorgGuard("Ethereum", chain, 3)
+	processBlocks(chain, guard, 0, 1, 10)
+
+	// A fork from block 7 replaces 4 blocks, one more than the maximum
+	for number := uint64(7); number <= 10; number++ {
+		chain.set(number, testBlock{hash: blockHash(1, number)})
+	}
+	err := guard.Check(context.Background(), tmLog.NewNopLogger())
+	if !errors.Is(err, ErrDeepReorg) {
+		t.Fatalf("Check after a deep reorg = %v, want ErrDeepReorg", err)
+	}
+	if _, halted := txs.SigningHalted(); !halted {
+		t.Fatal("a deep reorg didn't halt signing")
+	}
+	if _, err := os.Stat(txs.SigningHaltFile); err != nil {
+		t.Fatalf("halt file not written: %v", err)
+	}
+
+	if err := os.Remove(txs.SigningHaltFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, halted := txs.SigningHalted(); halted {
+		t.Fatal("signing still halted after removing the halt file")
+	}
+}
+
+func TestReorgGuardWindow(t *testing.T) {
+	chain := newTestChain()
+	guard := NewReorgGuard("Harmony", chain, 1)
+	guard.Window = 3
+	processBlocks(chain, guard, 0, 1, 5)
+
+	// Blocks beyond the window are no longer checked
+	chain.set(1, testBlock{hash: blockHash(1, 1)})
+	if err := guard.Check(context.Background(), tmLog.NewNopLogger()); err != nil {
+		t.Fatalf("Check of a reorg beyond the window = %v", err)
+	}
+	if heights := guard.heights(); len(heights) != 3 || heights[0] != 3 {
+		t.Fatalf("remembered blocks %v, want the latest 3", heights)
+	}
+}
@@ -0,0 +1,103 @@
+package relayer
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// DefaultSeenCapacity is the number of events a SeenSet remembers in memory
+const DefaultSeenCapacity = 10000
+
+// EventKey uniquely identifies an event log by its transaction and position within the block
+type EventKey struct {
+	TxHash   common.Hash
+	LogIndex uint
+}
+
+// String returns the key as "txHash:logIndex"
+func (k EventKey) String() string {
+	return fmt.Sprintf("%s:%d", k.TxHash.Hex(), k.LogIndex)
+}
+
+// SeenSet records processed events so that events delivered twice, such as during reorgs or
+// overlapping replays, are only relayed once. The most recent events are kept in an in-memory
+// LRU, backed by an optional types.KeyStore so deduplication survives restarts. It is safe for
+// concurrent use.
+type SeenSet struct {
+	capacity int
+	backend  types.KeyStore
+	mu       sync.Mutex
+	order    *list.List
+	entries  map[EventKey]*list.Element
+}
+
+// NewSeenSet initializes a new SeenSet remembering up to capacity events in memory. backend may
+// be nil to deduplicate in memory only.
+func NewSeenSet(capacity int, backend types.KeyStore) *SeenSet {
+	if capacity < 1 {
+		capacity = DefaultSeenCapacity
+	}
+	return &SeenSet{
+		capacity: capacity,
+		backend:  backend,
+		order:    list.New(),
+		entries:  make(map[EventKey]*list.Element),
+	}
+}
+
+// Has reports whether the event was already processed
+func (s *SeenSet) Has(key EventKey) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.order.MoveToFront(element)
+		return true, nil
+	}
+	if s.backend == nil {
+		return false, nil
+	}
+
+	seen, err := s.backend.Has(key.String())
+	if err != nil {
+		return false, err
+	}
+	if seen {
+		s.remember(key)
+	}
+	return seen, nil
+}
+
+// Add records the event as processed
+func (s *SeenSet) Add(key EventKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.backend != nil {
+		if err := s.backend.Add(key.String()); err != nil {
+			return err
+		}
+	}
+	s.remember(key)
+	return nil
+}
+
+// remember adds key to the LRU, evicting the least recently used key when full
+func (s *SeenSet) remember(key EventKey) {
+	if element, ok := s.entries[key]; ok {
+		s.order.MoveToFront(element)
+		return
+	}
+	s.entries[key] = s.order.PushFront(key)
+
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(EventKey))
+	}
+}
@@ -0,0 +1,134 @@
+package relayer
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// countingSigner is a txs.Signer counting the claims it signs
+type countingSigner struct {
+	txs.Signer
+	mu     sync.Mutex
+	signed int
+}
+
+func (s *countingSigner) Sign(msg []byte) ([]byte, error) {
+	s.mu.Lock()
+	s.signed++
+	s.mu.Unlock()
+	return s.Signer.Sign(msg)
+}
+
+// relaySeen signs a claim for vLog unless seen already holds it, guarding the event as the chain
+// handlers do: checking the seen set first and recording the event once its claim is signed
+func relaySeen(t *testing.T, seen *SeenSet, signer txs.Signer, vLog ctypes.Log) {
+	t.Helper()
+	key := EventKey{TxHash: vLog.TxHash, LogIndex: vLog.Index}
+	relayed, err := seen.Has(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relayed {
+		return
+	}
+	if _, err := signer.Sign(txs.PrefixMsg(crypto.Keccak256(vLog.Data))); err != nil {
+		t.Fatal(err)
+	}
+	if err := seen.Add(key); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testSigner(t *testing.T) *countingSigner {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &countingSigner{Signer: txs.NewKeySigner(key)}
+}
+
+func TestSeenSetSignsDuplicateEventOnce(t *testing.T) {
+	dir, cleanup := testDir(t)
+	defer cleanup()
+	store, err := types.NewFileKeyStore(filepath.Join(dir, "seen"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	signer := testSigner(t)
+	seen := NewSeenSet(DefaultSeenCapacity, store)
+	vLog := ctypes.Log{TxHash: common.HexToHash("0xaa"), Index: 3, Data: []byte("claim")}
+	relaySeen(t, seen, signer, vLog)
+	relaySeen(t, seen, signer, vLog)
+	if signer.signed != 1 {
+		t.Fatalf("signed %d claims for an event delivered twice, want 1", signer.signed)
+	}
+
+	// Another log of the same transaction is a different event
+	relaySeen(t, seen, signer, ctypes.Log{TxHash: vLog.TxHash, Index: 4, Data: []byte("claim")})
+	if signer.signed != 2 {
+		t.Fatalf("signed %d claims, want the second log of the transaction signed too", signer.signed)
+	}
+
+	// A restarted relayer replaying the event finds it in the store
+	restarted := NewSeenSet(DefaultSeenCapacity, store)
+	relaySeen(t, restarted, signer, vLog)
+	if signer.signed != 2 {
+		t.Fatal("replayed event signed again after a restart")
+	}
+}
+
+func TestSeenSetConcurrentDuplicates(t *testing.T) {
+	seen := NewSeenSet(DefaultSeenCapacity, types.NewMemoryKeyStore())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := EventKey{TxHash: common.BigToHash(common.Big1), LogIndex: uint(j)}
+				if _, err := seen.Has(key); err != nil {
+					t.Error(err)
+				}
+				if err := seen.Add(key); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSeenSetEviction(t *testing.T) {
+	first := EventKey{TxHash: common.HexToHash("0x1")}
+	second := EventKey{TxHash: common.HexToHash("0x2")}
+
+	memory := NewSeenSet(1, nil)
+	backed := NewSeenSet(1, types.NewMemoryKeyStore())
+	for _, seen := range []*SeenSet{memory, backed} {
+		for _, key := range []EventKey{first, second} {
+			if err := seen.Add(key); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if relayed, err := memory.Has(first); err != nil || relayed {
+		t.Fatalf("Has(evicted) = %v, %v, want it forgotten without a backend", relayed, err)
+	}
+	if relayed, err := backed.Has(first); err != nil || !relayed {
+		t.Fatalf("Has(evicted) = %v, %v, want it found in the backend", relayed, err)
+	}
+	if relayed, err := memory.Has(second); err != nil || !relayed {
+		t.Fatalf("Has(latest) = %v, %v, want it remembered", relayed, err)
+	}
+}
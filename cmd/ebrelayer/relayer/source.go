@@ -0,0 +1,310 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	htypes "github.com/harmony-one/harmony/core/types"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// DefaultReconnectBackoff paces resubscription attempts after a subscription drops
+var DefaultReconnectBackoff = txs.RetryPolicy{
+	BaseDelay: time.Second,
+	MaxDelay:  time.Minute,
+	Jitter:    0.2,
+}
+
+// EthLogSource delivers the logs matching a query, in chain order, until ctx is cancelled
+type EthLogSource interface {
+	Run(ctx context.Context, handle func(ctypes.Log)) error
+}
+
+// HmyLogSource delivers the logs matching a query, in chain order, until ctx is cancelled
+type HmyLogSource interface {
+	Run(ctx context.Context, handle func(htypes.Log)) error
+}
+
+// EthLogFilterer queries Ethereum logs
+type EthLogFilterer interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]ctypes.Log, error)
+}
+
+// EthLogClient queries and subscribes to Ethereum logs
+type EthLogClient interface {
+	EthLogFilterer
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- ctypes.Log) (ethereum.Subscription, error)
+}
+
+// HmyLogFilterer queries Harmony logs
+type HmyLogFilterer interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]htypes.Log, error)
+}
+
+// HmyLogClient queries and subscribes to Harmony logs
+type HmyLogClient interface {
+	HmyLogFilterer
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- htypes.Log) (ethereum.Subscription, error)
+}
+
+// logPosition orders logs by block and index within the block
+type logPosition struct {
+	block uint64
+	index uint
+}
+
+// after reports whether p comes after other
+func (p logPosition) after(other logPosition) bool {
+	return p.block > other.block || (p.block == other.block && p.index > other.index)
+}
+
+// logCursor tracks the last delivered log and the block to backfill from after a disconnect
+type logCursor struct {
+	last      logPosition
+	delivered bool
+	resume    uint64
+	marked    bool
+}
+
+// advance reports whether the log at pos is new, recording it as delivered if so. Removed logs
+// from reorgs are always new, as they revisit earlier positions.
+func (c *logCursor) advance(pos logPosition, removed bool) bool {
+	if removed {
+		return true
+	}
+	if c.delivered && !pos.after(c.last) {
+		return false
+	}
+	c.last, c.delivered = pos, true
+	c.resume, c.marked = pos.block, true
+	return true
+}
+
+// mark raises the resume block to head, once everything up to head was delivered
+func (c *logCursor) mark(head uint64) {
+	if !c.marked || head > c.resume {
+		c.resume, c.marked = head, true
+	}
+}
+
+// EthWSLogSource is an EthLogSource over an eth_subscribe subscription. When the subscription
+// drops it resubscribes with backoff, then backfills the blocks it may have missed with
+// FilterLogs before resuming the live stream, so no logs are lost across the reconnect. If
+// Query.FromBlock is set, logs from that block onward are backfilled on the first subscription.
+type EthWSLogSource struct {
+	Client  EthLogClient
+	Chain   CanonicalChain
+	Query   ethereum.FilterQuery
+	Backoff txs.RetryPolicy
+	Logger  tmLog.Logger
+	// OnProgress, if set, is called with the head block once every log through it was delivered
+	OnProgress func(block uint64)
+}
+
+// NewEthWSLogSource initializes a new EthWSLogSource, using DefaultReconnectBackoff if backoff
+// is the zero value
+func NewEthWSLogSource(client EthLogClient, chain CanonicalChain, query ethereum.FilterQuery,
+	backoff txs.RetryPolicy, logger tmLog.Logger) *EthWSLogSource {
+	if backoff.BaseDelay == 0 {
+		backoff = DefaultReconnectBackoff
+	}
+	return &EthWSLogSource{Client: client, Chain: chain, Query: query, Backoff: backoff, Logger: logger}
+}
+
+// Run implements EthLogSource. It only returns once ctx is done.
+func (s *EthWSLogSource) Run(ctx context.Context, handle func(ctypes.Log)) error {
+	// A query starting at a past block is backfilled from it on the first subscription
+	var cursor logCursor
+	if s.Query.FromBlock != nil {
+		cursor.mark(s.Query.FromBlock.Uint64())
+	}
+	liveQuery := s.Query
+	liveQuery.FromBlock = nil
+
+	deliver := func(vLog ctypes.Log) {
+		if cursor.advance(logPosition{vLog.BlockNumber, vLog.Index}, vLog.Removed) {
+			handle(vLog)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && !sleepContext(ctx, s.Backoff.Delay(attempt)) {
+			return nil
+		}
+
+		head, err := s.Chain.BlockNumber(ctx)
+		if err != nil {
+			s.Logger.Error("Ethereum - head query error: ", err.Error())
+			continue
+		}
+
+		logs := make(chan ctypes.Log)
+		subscription, err := s.Client.SubscribeFilterLogs(ctx, liveQuery, logs)
+		if err != nil {
+			s.Logger.Error("Ethereum - subscription error: ", err.Error())
+			continue
+		}
+
+		// Backfill anything emitted while disconnected; the new subscription buffers meanwhile
+		if cursor.marked {
+			query := s.Query
+			query.FromBlock = new(big.Int).SetUint64(cursor.resume)
+			missedLogs, err := s.Client.FilterLogs(ctx, query)
+			if err != nil {
+				subscription.Unsubscribe()
+				s.Logger.Error("Ethereum - backfill error: ", err.Error())
+				continue
+			}
+			s.Logger.Info(fmt.Sprintf("Ethereum - Backfilled %d events from block %d", len(missedLogs), cursor.resume))
+			for _, vLog := range missedLogs {
+				if ctx.Err() != nil {
+					subscription.Unsubscribe()
+					return nil
+				}
+				deliver(vLog)
+			}
+		}
+		cursor.mark(head)
+		if s.OnProgress != nil {
+			s.OnProgress(head)
+		}
+		attempt = 0
+
+	live:
+		for {
+			select {
+			case <-ctx.Done():
+				subscription.Unsubscribe()
+				return nil
+			case err := <-subscription.Err():
+				subscription.Unsubscribe()
+				if err != nil {
+					s.Logger.Error("Ethereum - subscription dropped: ", err.Error())
+				}
+				break live
+			case vLog := <-logs:
+				deliver(vLog)
+			}
+		}
+	}
+}
+
+// HmyWSLogSource is an HmyLogSource over a hmy_subscribe subscription. When the subscription
+// drops it resubscribes with backoff, then backfills the blocks it may have missed with
+// FilterLogs before resuming the live stream, so no logs are lost across the reconnect. If
+// Query.FromBlock is set, logs from that block onward are backfilled on the first subscription.
+type HmyWSLogSource struct {
+	Client  HmyLogClient
+	Chain   CanonicalChain
+	Query   ethereum.FilterQuery
+	Backoff txs.RetryPolicy
+	Logger  tmLog.Logger
+	// OnProgress, if set, is called with the head block once every log through it was delivered
+	OnProgress func(block uint64)
+}
+
+// NewHmyWSLogSource initializes a new HmyWSLogSource, using DefaultReconnectBackoff if backoff
+// is the zero value
+func NewHmyWSLogSource(client HmyLogClient, chain CanonicalChain, query ethereum.FilterQuery,
+	backoff txs.RetryPolicy, logger tmLog.Logger) *HmyWSLogSource {
+	if backoff.BaseDelay == 0 {
+		backoff = DefaultReconnectBackoff
+	}
+	return &HmyWSLogSource{Client: client, Chain: chain, Query: query, Backoff: backoff, Logger: logger}
+}
+
+// Run implements HmyLogSource. It only returns once ctx is done.
+func (s *HmyWSLogSource) Run(ctx context.Context, handle func(htypes.Log)) error {
+	// A query starting at a past block is backfilled from it on the first subscription
+	var cursor logCursor
+	if s.Query.FromBlock != nil {
+		cursor.mark(s.Query.FromBlock.Uint64())
+	}
+	liveQuery := s.Query
+	liveQuery.FromBlock = nil
+
+	deliver := func(vLog htypes.Log) {
+		if cursor.advance(logPosition{vLog.BlockNumber, vLog.Index}, vLog.Removed) {
+			handle(vLog)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && !sleepContext(ctx, s.Backoff.Delay(attempt)) {
+			return nil
+		}
+
+		head, err := s.Chain.BlockNumber(ctx)
+		if err != nil {
+			s.Logger.Error("Harmony - head query error: ", err.Error())
+			continue
+		}
+
+		logs := make(chan htypes.Log)
+		subscription, err := s.Client.SubscribeFilterLogs(ctx, liveQuery, logs)
+		if err != nil {
+			s.Logger.Error("Harmony - subscription error: ", err.Error())
+			continue
+		}
+
+		// Backfill anything emitted while disconnected; the new subscription buffers meanwhile
+		if cursor.marked {
+			query := s.Query
+			query.FromBlock = new(big.Int).SetUint64(cursor.resume)
+			missedLogs, err := s.Client.FilterLogs(ctx, query)
+			if err != nil {
+				subscription.Unsubscribe()
+				s.Logger.Error("Harmony - backfill error: ", err.Error())
+				continue
+			}
+			s.Logger.Info(fmt.Sprintf("Harmony - Backfilled %d events from block %d", len(missedLogs), cursor.resume))
+			for _, vLog := range missedLogs {
+				if ctx.Err() != nil {
+					subscription.Unsubscribe()
+					return nil
+				}
+				deliver(vLog)
+			}
+		}
+		cursor.mark(head)
+		if s.OnProgress != nil {
+			s.OnProgress(head)
+		}
+		attempt = 0
+
+	live:
+		for {
+			select {
+			case <-ctx.Done():
+				subscription.Unsubscribe()
+				return nil
+			case err := <-subscription.Err():
+				subscription.Unsubscribe()
+				if err != nil {
+					s.Logger.Error("Harmony - subscription dropped: ", err.Error())
+				}
+				break live
+			case vLog := <-logs:
+				deliver(vLog)
+			}
+		}
+	}
+}
+
+// sleepContext waits for d, returning false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
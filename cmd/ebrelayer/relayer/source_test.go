@@ -0,0 +1,192 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	tmLog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// testLogSubscription is an ethereum.Subscription whose logs and failure tests drive by hand
+type testLogSubscription struct {
+	logs chan<- ctypes.Log
+	err  chan error
+}
+
+func (s *testLogSubscription) Err() <-chan error { return s.err }
+
+func (s *testLogSubscription) Unsubscribe() {}
+
+// testLogClient is an EthLogClient over logs emitted by tests, handing each subscription made to
+// subscriptions
+type testLogClient struct {
+	mu            sync.Mutex
+	logs          []ctypes.Log
+	subscriptions chan *testLogSubscription
+}
+
+func newTestLogClient() *testLogClient {
+	return &testLogClient{subscriptions: make(chan *testLogSubscription, 1)}
+}
+
+// emit adds a log at block and index, returning it
+func (c *testLogClient) emit(block uint64, index uint) ctypes.Log {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	txHash := common.BigToHash(new(big.Int).SetUint64(block*100 + uint64(index)))
+	vLog := ctypes.Log{BlockNumber: block, Index: index, TxHash: txHash}
+	c.logs = append(c.logs, vLog)
+	return vLog
+}
+
+func (c *testLogClient) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]ctypes.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var logs []ctypes.Log
+	for _, vLog := range c.logs {
+		if q.FromBlock != nil && vLog.BlockNumber < q.FromBlock.Uint64() {
+			continue
+		}
+		if q.ToBlock != nil && vLog.BlockNumber > q.ToBlock.Uint64() {
+			continue
+		}
+		logs = append(logs, vLog)
+	}
+	return logs, nil
+}
+
+func (c *testLogClient) SubscribeFilterLogs(_ context.Context, _ ethereum.FilterQuery, ch chan<- ctypes.Log) (ethereum.Subscription, error) {
+	subscription := &testLogSubscription{logs: ch, err: make(chan error, 1)}
+	c.subscriptions <- subscription
+	return subscription, nil
+}
+
+// nextSubscription waits for the source to subscribe
+func (c *testLogClient) nextSubscription(t *testing.T) *testLogSubscription {
+	t.Helper()
+	select {
+	case subscription := <-c.subscriptions:
+		return subscription
+	case <-time.After(time.Second):
+		t.Fatal("source didn't subscribe")
+		return nil
+	}
+}
+
+// collectLogs returns a handler sending each log delivered to the returned channel
+func collectLogs() (func(ctypes.Log), chan ctypes.Log) {
+	delivered := make(chan ctypes.Log, 100)
+	return func(vLog ctypes.Log) { delivered <- vLog }, delivered
+}
+
+// expectLogs fails t unless exactly want are delivered, in order
+func expectLogs(t *testing.T, delivered chan ctypes.Log, want []ctypes.Log) {
+	t.Helper()
+	for i, wantLog := range want {
+		select {
+		case vLog := <-delivered:
+			if vLog.BlockNumber != wantLog.BlockNumber || vLog.Index != wantLog.Index {
+				t.Fatalf("log %d delivered at %d:%d, want %d:%d", i, vLog.BlockNumber, vLog.Index,
+					wantLog.BlockNumber, wantLog.Index)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%d logs delivered, want %d", i, len(want))
+		}
+	}
+}
+
+func TestEthWSLogSourceResubscribesWithoutLosingLogs(t *testing.T) {
+	chain := newTestChain()
+	chain.set(10, testBlock{})
+	client := newTestLogClient()
+	source := NewEthWSLogSource(client, chain, ethereum.FilterQuery{},
+		txs.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, tmLog.NewNopLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handle, delivered := collectLogs()
+	done := make(chan error, 1)
+	go func() { done <- source.Run(ctx, handle) }()
+
+	first := client.nextSubscription(t)
+	live := client.emit(11, 0)
+	first.logs <- live
+	expectLogs(t, delivered, []ctypes.Log{live})
+
+	// Logs emitted while the subscription is down only reach the client's history
+	chain.set(13, testBlock{})
+	missed := []ctypes.Log{client.emit(12, 0), client.emit(12, 1), client.emit(13, 0)}
+	first.err <- errors.New("websocket: close 1006 (abnormal closure)")
+
+	second := client.nextSubscription(t)
+	expectLogs(t, delivered, missed)
+	resumed := client.emit(14, 0)
+	second.logs <- resumed
+	// The log delivered before the drop is backfilled again but not redelivered
+	expectLogs(t, delivered, []ctypes.Log{resumed})
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 0 {
+		t.Fatalf("%d logs delivered twice", len(delivered))
+	}
+}
+
+func TestEthWSLogSourceBackfillsFromBlock(t *testing.T) {
+	chain := newTestChain()
+	chain.set(20, testBlock{})
+	client := newTestLogClient()
+	client.emit(4, 0)
+	history := []ctypes.Log{client.emit(5, 0), client.emit(9, 2)}
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(5)}
+	source := NewEthWSLogSource(client, chain, query, txs.RetryPolicy{}, tmLog.NewNopLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handle, delivered := collectLogs()
+	go source.Run(ctx, handle)
+
+	client.nextSubscription(t)
+	expectLogs(t, delivered, history)
+}
+
+func TestLogCursor(t *testing.T) {
+	var cursor logCursor
+	if !cursor.advance(logPosition{10, 1}, false) {
+		t.Fatal("first log not delivered")
+	}
+	if cursor.advance(logPosition{10, 1}, false) || cursor.advance(logPosition{10, 0}, false) ||
+		cursor.advance(logPosition{9, 5}, false) {
+		t.Fatal("log at or before the last delivered one delivered again")
+	}
+	// A reorg revisits earlier positions with removed logs
+	if !cursor.advance(logPosition{9, 5}, true) {
+		t.Fatal("removed log not delivered")
+	}
+	if !cursor.advance(logPosition{10, 2}, false) || !cursor.advance(logPosition{11, 0}, false) {
+		t.Fatal("later log not delivered")
+	}
+
+	// Backfill resumes from the last delivered block, or the head once it was reached
+	if cursor.resume != 11 {
+		t.Fatalf("resume = %d, want the last delivered block 11", cursor.resume)
+	}
+	cursor.mark(15)
+	cursor.mark(12)
+	if cursor.resume != 15 {
+		t.Fatalf("resume = %d, want the highest marked head 15", cursor.resume)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+)
+
+// replayCmd : Replays recorded UnlockClaim logs through claim message generation
+func replayCmd() *cobra.Command {
+	replayCmd := &cobra.Command{
+		Use:   "replay [fixtures]",
+		Short: "Replay recorded UnlockClaim logs, checking their claim messages and signers against the recorded ones",
+		Args:  cobra.ExactArgs(1),
+		Example: "ebrelayer replay ./fixtures --ethereum-claim-chain-id 1 " +
+			"--token-decimals 0x...=6:18:USDC",
+		RunE: RunReplayCmd,
+	}
+
+	replayCmd.Flags().Uint64(FlagEthereumClaimChainID, 0,
+		"chain ID bound into claims verified on Ethereum when the fixtures were recorded; 0 for the legacy claim layout")
+	replayCmd.Flags().Uint64(FlagHarmonyClaimChainID, 0,
+		"chain ID bound into claims verified on Harmony when the fixtures were recorded; 0 for the legacy claim layout")
+	replayCmd.Flags().StringSlice(FlagTokenDecimals, nil,
+		"token decimals as address=source:dest[:symbol] the fixtures' claims were rescaled by; may be repeated")
+	replayCmd.Flags().StringSlice(FlagTokenAmountScale, nil,
+		"a token's amount scale as address=*factor or address=/factor the fixtures' claims were hashed at; may be repeated")
+
+	return replayCmd
+}
+
+// RunReplayCmd : executes the replayCmd
+func RunReplayCmd(cmd *cobra.Command, args []string) error {
+	fixtures, err := txs.LoadReplayFixtures(args[0])
+	if err != nil {
+		return errors.Errorf("invalid [fixtures]: %v", err)
+	}
+	if len(fixtures) == 0 {
+		return errors.Errorf("invalid [fixtures]: no fixtures in %s", args[0])
+	}
+
+	for flag, chainID := range map[string]**big.Int{
+		FlagEthereumClaimChainID: &txs.EthClaimChainID,
+		FlagHarmonyClaimChainID:  &txs.HmyClaimChainID,
+	} {
+		value, err := cmd.Flags().GetUint64(flag)
+		if err != nil {
+			return err
+		}
+		if value != 0 {
+			*chainID = new(big.Int).SetUint64(value)
+		}
+	}
+	tokenDecimals, err := cmd.Flags().GetStringSlice(FlagTokenDecimals)
+	if err != nil {
+		return err
+	}
+	if len(tokenDecimals) != 0 {
+		txs.Tokens = txs.NewTokenRegistry()
+		for _, value := range tokenDecimals {
+			token, decimals, err := txs.ParseTokenDecimals(value)
+			if err != nil {
+				return errors.Errorf("invalid [%s]: %v", FlagTokenDecimals, err)
+			}
+			txs.Tokens.Register(token, decimals.Source, decimals.Dest, decimals.Symbol)
+		}
+	}
+
+	amountScales, err := cmd.Flags().GetStringSlice(FlagTokenAmountScale)
+	if err != nil {
+		return err
+	}
+	if len(amountScales) != 0 {
+		txs.AmountScales = txs.NewAmountScaleRegistry()
+		for _, value := range amountScales {
+			token, scale, err := txs.ParseAmountScale(value)
+			if err != nil {
+				return errors.Errorf("invalid [%s]: %v", FlagTokenAmountScale, err)
+			}
+			txs.AmountScales.Register(token, scale)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	failed := 0
+	for _, fixture := range fixtures {
+		result, err := fixture.Replay()
+		if err != nil {
+			fmt.Fprintf(out, "FAIL %s: %v\n", fixture.Name, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "ok   %s: message %s", fixture.Name, hexutil.Encode(result.Message))
+		if len(fixture.Signature) != 0 {
+			fmt.Fprintf(out, ", signer %s", result.Signer.Hex())
+		}
+		fmt.Fprintln(out)
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d fixtures failed", failed, len(fixtures))
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/relayer"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+const (
+	// FlagSubmitEthereumProvider is the Ethereum websocket URL claims are submitted through
+	FlagSubmitEthereumProvider = "ethereum-provider"
+	// FlagSubmitEthereumRegistry is the Ethereum BridgeRegistry the Oracle is looked up from
+	FlagSubmitEthereumRegistry = "ethereum-registry"
+	// FlagSubmitHarmonyProvider is the Harmony websocket URL claims are submitted through
+	FlagSubmitHarmonyProvider = "harmony-provider"
+	// FlagSubmitHarmonyRegistry is the Harmony BridgeRegistry the Oracle is looked up from
+	FlagSubmitHarmonyRegistry = "harmony-registry"
+)
+
+// submitCmd : Submits the signed claims a relayer run with --claim-export wrote
+func submitCmd() *cobra.Command {
+	submitCmd := &cobra.Command{
+		Use:   "submit [claims]",
+		Short: "Submit the signed claims exported by a relayer run with --claim-export",
+		Args:  cobra.ExactArgs(1),
+		Example: "ebrelayer submit ./claims --ethereum-provider wss://... --ethereum-registry 0x... " +
+			"--harmony-provider wss://... --harmony-registry 0x...",
+		RunE: RunSubmitCmd,
+	}
+
+	submitCmd.Flags().String(FlagEthereumKeyEnv, txs.EthereumPrivateKeyEnv,
+		"environment variable holding the Ethereum private key of the validator which signed the claims")
+	submitCmd.Flags().String(FlagHarmonyKeyEnv, txs.HarmonyPrivateKeyEnv,
+		"environment variable holding the Harmony private key of the validator which signed the claims")
+	submitCmd.Flags().String(FlagSubmitEthereumProvider, "", "Ethereum websocket URL, required for Ethereum claims")
+	submitCmd.Flags().String(FlagSubmitEthereumRegistry, "", "Ethereum BridgeRegistry address, required for Ethereum claims")
+	submitCmd.Flags().String(FlagSubmitHarmonyProvider, "", "Harmony websocket URL, required for Harmony claims")
+	submitCmd.Flags().String(FlagSubmitHarmonyRegistry, "", "Harmony BridgeRegistry address, required for Harmony claims")
+
+	return submitCmd
+}
+
+// submitTarget is where and with which key the claims for one chain are submitted
+type submitTarget struct {
+	provider string
+	registry common.Address
+	key      *ecdsa.PrivateKey
+}
+
+// RunSubmitCmd : executes the submitCmd
+func RunSubmitCmd(cmd *cobra.Command, args []string) error {
+	claims, err := txs.ReadSignedClaimsFrom(args[0])
+	if err != nil {
+		return errors.Errorf("invalid [claims]: %v", err)
+	}
+
+	targets := make(map[string]*submitTarget)
+	out := cmd.OutOrStdout()
+	failed := 0
+	for _, claim := range claims {
+		target, ok := targets[claim.Chain]
+		if !ok {
+			if target, err = loadSubmitTarget(cmd, claim.Chain); err != nil {
+				return err
+			}
+			targets[claim.Chain] = target
+		}
+
+		err := submitSignedClaim(target, claim)
+		switch {
+		case errors.Is(err, txs.ErrClaimSkipped):
+			fmt.Fprintf(out, "Skipped %s claim %v: %v\n", claim.Chain, claim.UnlockID.Int(), err)
+		case err != nil:
+			fmt.Fprintf(out, "Failed %s claim %v: %v\n", claim.Chain, claim.UnlockID.Int(), err)
+			failed++
+		default:
+			fmt.Fprintf(out, "Submitted %s claim %v\n", claim.Chain, claim.UnlockID.Int())
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d claims failed", failed, len(claims))
+	}
+	return nil
+}
+
+// loadSubmitTarget reads the provider, registry and key claims for chain are submitted with
+func loadSubmitTarget(cmd *cobra.Command, chain string) (*submitTarget, error) {
+	var providerFlag, registryFlag, keyEnvFlag string
+	switch chain {
+	case relayer.EthereumChain:
+		providerFlag, registryFlag, keyEnvFlag = FlagSubmitEthereumProvider, FlagSubmitEthereumRegistry, FlagEthereumKeyEnv
+	case relayer.HarmonyChain:
+		providerFlag, registryFlag, keyEnvFlag = FlagSubmitHarmonyProvider, FlagSubmitHarmonyRegistry, FlagHarmonyKeyEnv
+	default:
+		return nil, errors.Errorf("invalid claim chain: %s", chain)
+	}
+
+	provider, err := cmd.Flags().GetString(providerFlag)
+	if err != nil {
+		return nil, err
+	}
+	if !relayer.IsWebsocketURL(provider) {
+		return nil, errors.Errorf("invalid [%s]: %s", providerFlag, provider)
+	}
+	registry, err := cmd.Flags().GetString(registryFlag)
+	if err != nil {
+		return nil, err
+	}
+	if err := txs.ValidateAddressChecksum(registry, false); err != nil {
+		return nil, errors.Errorf("invalid [%s]: %v", registryFlag, err)
+	}
+	keyEnv, err := cmd.Flags().GetString(keyEnvFlag)
+	if err != nil {
+		return nil, err
+	}
+	key, err := txs.LoadPrivateKeyFromEnv(keyEnv)
+	if err != nil {
+		return nil, errors.Errorf("invalid [%s] environment variable", keyEnv)
+	}
+	return &submitTarget{provider: provider, registry: common.HexToAddress(registry), key: key}, nil
+}
+
+// submitSignedClaim submits claim to its chain's Oracle. The Oracle only accepts a claim sent by
+// the validator which signed it, so target's key must be the claim signer's.
+func submitSignedClaim(target *submitTarget, claim txs.SignedClaimJSON) error {
+	if sender := crypto.PubkeyToAddress(target.key.PublicKey); sender != claim.Signer {
+		return fmt.Errorf("claim was signed by %s, but would be sent by %s", claim.Signer.Hex(), sender.Hex())
+	}
+
+	if claim.Chain == relayer.HarmonyChain {
+		oracleClaim, err := claim.HmyOracleClaim()
+		if err != nil {
+			return err
+		}
+		return txs.RelayOracleClaimToHarmony(target.provider, target.registry, types.HmyLogNewUnlockClaim,
+			oracleClaim, target.key)
+	}
+	oracleClaim, err := claim.EthOracleClaim()
+	if err != nil {
+		return err
+	}
+	return txs.RelayOracleClaimToEthereum(target.provider, target.registry, types.EthLogNewUnlockClaim,
+		oracleClaim, target.key)
+}
@@ -0,0 +1,198 @@
+package txs
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// EncodingMode selects how values are laid out before they are hashed
+type EncodingMode int
+
+const (
+	// PackedEncoding lays values out as abi.encodePacked does
+	PackedEncoding EncodingMode = iota
+	// ABIEncoding lays values out as abi.encode does: each value padded to 32-byte words, with
+	// dynamic values placed after the head and referenced by their offset
+	ABIEncoding
+)
+
+// encodingModeNames are the names ParseEncodingMode accepts
+var encodingModeNames = map[EncodingMode]string{
+	PackedEncoding: "packed",
+	ABIEncoding:    "abi",
+}
+
+// String returns the mode's name
+func (m EncodingMode) String() string {
+	if name, ok := encodingModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("EncodingMode(%d)", int(m))
+}
+
+// ParseEncodingMode parses a mode's name
+func ParseEncodingMode(name string) (EncodingMode, error) {
+	for mode, modeName := range encodingModeNames {
+		if strings.EqualFold(name, modeName) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown encoding %q", name)
+}
+
+// ParseClaimEncoding parses a contract's claim message encoding given as "address=mode"
+func ParseClaimEncoding(value string) (common.Address, EncodingMode, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return common.Address{}, 0, fmt.Errorf("invalid claim encoding %q: expected address=mode", value)
+	}
+	if err := ValidateAddressChecksum(parts[0], false); err != nil {
+		return common.Address{}, 0, fmt.Errorf("invalid claim encoding %q: %w", value, err)
+	}
+	mode, err := ParseEncodingMode(parts[1])
+	if err != nil {
+		return common.Address{}, 0, err
+	}
+	return common.HexToAddress(parts[0]), mode, nil
+}
+
+// ABIEncode lays values out according to their Solidity types as abi.encode does. A value which
+// can't be encoded as its type returns an error instead of panicking.
+func ABIEncode(types []string, values ...interface{}) ([]byte, error) {
+	if err := checkTyped(types, values); err != nil {
+		return nil, err
+	}
+	encoded := make([][]byte, len(types))
+	for i, typ := range types {
+		var err error
+		if encoded[i], err = safePack(typ, values[i], abiEncodeValue); err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+	}
+	return abiLayout(types, encoded), nil
+}
+
+// StringArrayABI lays a string array out as abi.encode(string[]) does: the offset of the array,
+// its length, the offset of each string, then each string's length and its bytes right-padded to
+// a 32-byte word. Unlike StringArray, distinct lists always encode differently.
+func StringArrayABI(input interface{}) ([]byte, error) {
+	if input == nil || !isArray(input) {
+		return nil, fmt.Errorf("invalid value for string[]: %T is not an array", input)
+	}
+	return ABIEncode([]string{"string[]"}, input)
+}
+
+// abiEncode lays values out as the head and tail of an ABI-encoded tuple of types
+func abiEncode(types []string, values []interface{}) []byte {
+	encoded := make([][]byte, len(types))
+	for i, typ := range types {
+		encoded[i] = abiEncodeValue(typ, values[i])
+	}
+	return abiLayout(types, encoded)
+}
+
+// abiLayout lays the encoded values of types out as the head and tail of an ABI-encoded tuple
+func abiLayout(types []string, encoded [][]byte) []byte {
+	headSize := 0
+	for _, typ := range types {
+		headSize += abiHeadSize(typ)
+	}
+
+	var head, tail []byte
+	for i, typ := range types {
+		if isDynamicABIType(typ) {
+			head = append(head, abiWord(big.NewInt(int64(headSize+len(tail))))...)
+			tail = append(tail, encoded[i]...)
+		} else {
+			head = append(head, encoded[i]...)
+		}
+	}
+	return append(head, tail...)
+}
+
+// abiEncodeValue encodes a single value of typ, in place for a static type or as its tail for a
+// dynamic one
+func abiEncodeValue(typ string, value interface{}) []byte {
+	if components, ok := tupleComponents(typ); ok {
+		return abiEncode(components, tupleValues(typ, value, len(components)))
+	}
+
+	if matches := arrayTypePattern.FindStringSubmatch(typ); matches != nil {
+		elements := reflect.ValueOf(value)
+		if value == nil || (elements.Kind() != reflect.Slice && elements.Kind() != reflect.Array) {
+			panic(fmt.Sprintf("invalid value for %s: %T is not an array", typ, value))
+		}
+		values := make([]interface{}, elements.Len())
+		types := make([]string, elements.Len())
+		for i := range values {
+			values[i], types[i] = elements.Index(i).Interface(), matches[1]
+		}
+
+		if matches[2] == "" {
+			return append(abiWord(big.NewInt(int64(len(values)))), abiEncode(types, values)...)
+		}
+		if length, _ := strconv.Atoi(matches[2]); length != len(values) {
+			panic(fmt.Sprintf("invalid value for %s: %d elements", typ, len(values)))
+		}
+		return abiEncode(types, values)
+	}
+
+	switch {
+	case typ == "string":
+		data := String(value)
+		padded := make([]byte, (len(data)+31)/32*32)
+		copy(padded, data)
+		return append(abiWord(big.NewInt(int64(len(data)))), padded...)
+	case bytesTypePattern.MatchString(typ):
+		return common.RightPadBytes(pack(typ, value, false), 32)
+	}
+	// Addresses, bools and numbers pack to one word as array elements do
+	return pack(typ, value, true)
+}
+
+// isDynamicABIType reports whether typ is encoded in the tail of an ABI-encoded tuple: a
+// string, a dynamic array, or an array or tuple of dynamic types
+func isDynamicABIType(typ string) bool {
+	if components, ok := tupleComponents(typ); ok {
+		for _, component := range components {
+			if isDynamicABIType(component) {
+				return true
+			}
+		}
+		return false
+	}
+	if matches := arrayTypePattern.FindStringSubmatch(typ); matches != nil {
+		return matches[2] == "" || isDynamicABIType(matches[1])
+	}
+	return typ == "string"
+}
+
+// abiHeadSize returns the bytes a value of typ occupies in the head of an ABI-encoded tuple
+func abiHeadSize(typ string) int {
+	if isDynamicABIType(typ) {
+		return 32
+	}
+	if components, ok := tupleComponents(typ); ok {
+		size := 0
+		for _, component := range components {
+			size += abiHeadSize(component)
+		}
+		return size
+	}
+	if matches := arrayTypePattern.FindStringSubmatch(typ); matches != nil {
+		length, _ := strconv.Atoi(matches[2])
+		return length * abiHeadSize(matches[1])
+	}
+	return 32
+}
+
+// abiWord encodes n as a 32-byte word
+func abiWord(n *big.Int) []byte {
+	return math.U256Bytes(n)
+}
@@ -0,0 +1,124 @@
+package txs
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestABIEncodeMatchesGoEthereum(t *testing.T) {
+	types := []string{"address", "uint256", "bool", "string", "bytes32", "uint256[]", "string[]", "uint8[2]"}
+	values := []interface{}{
+		goldenClaim.token,
+		goldenClaim.amount,
+		true,
+		"Harmony ONE bridge claim, longer than a single 32-byte word",
+		[32]byte{1, 2, 3},
+		[]*big.Int{big.NewInt(1), goldenClaim.amount},
+		[]string{"USDT", "", "ONE"},
+		[2]uint8{7, 255},
+	}
+
+	arguments := make(abi.Arguments, len(types))
+	for i, typ := range types {
+		abiType, err := abi.NewType(typ, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		arguments[i] = abi.Argument{Type: abiType}
+	}
+	want, err := arguments.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ABIEncode(types, values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ABIEncode = %x, want go-ethereum's %x", got, want)
+	}
+}
+
+func TestStringArrayMatchesGoEthereum(t *testing.T) {
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arrayType, err := abi.NewType("string[]", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long := "Harmony ONE bridge claim, longer than a single 32-byte word"
+
+	for _, list := range [][]string{{"USDT", long, ""}, {}, {"ONE"}} {
+		// abi.encode(string[])
+		want, err := abi.Arguments{{Type: arrayType}}.Pack(list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := StringArrayABI(list); err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("StringArrayABI(%q) = %x, %v, want go-ethereum's %x", list, got, err, want)
+		}
+
+		// abi.encodePacked(a, b, c): each string's bytes, cut from go-ethereum's encoding of it
+		// after its offset and length words
+		var packed []byte
+		for _, value := range list {
+			encoded, err := abi.Arguments{{Type: stringType}}.Pack(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			packed = append(packed, encoded[64:64+new(big.Int).SetBytes(encoded[32:64]).Int64()]...)
+		}
+		if got := StringArray(list); !bytes.Equal(got, packed) {
+			t.Fatalf("StringArray(%q) = %x, want %x", list, got, packed)
+		}
+		if got, err := SolidityPack([]string{"string"}, list); err != nil || !bytes.Equal(got, packed) {
+			t.Fatalf("SolidityPack(string, %q) = %x, %v, want %x", list, got, err, packed)
+		}
+	}
+
+	// Only the ABI encoding tells apart lists whose concatenations match
+	ab, c := []string{"ab", "c"}, []string{"a", "bc"}
+	if !bytes.Equal(StringArray(ab), StringArray(c)) {
+		t.Fatal("packed string lists of the same concatenation differ")
+	}
+	abiAB, _ := StringArrayABI(ab)
+	abiC, _ := StringArrayABI(c)
+	if bytes.Equal(abiAB, abiC) {
+		t.Fatalf("StringArrayABI of %q and %q are the same", ab, c)
+	}
+}
+
+func TestABIEncodeInvalidValue(t *testing.T) {
+	if _, err := ABIEncode([]string{"uint256[2]"}, []*big.Int{big.NewInt(1)}); err == nil {
+		t.Fatal("ABIEncode of a short fixed array succeeded")
+	}
+	if _, err := ABIEncode([]string{"address", "uint256"}, common.Address{}); err == nil {
+		t.Fatal("ABIEncode of fewer values than types succeeded")
+	}
+	if _, err := StringArrayABI("USDT"); err == nil {
+		t.Fatal("StringArrayABI of a string succeeded")
+	}
+}
+
+func TestParseClaimEncoding(t *testing.T) {
+	contract, mode, err := ParseClaimEncoding(checksummedAddress + "=ABI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contract != common.HexToAddress(checksummedAddress) || mode != ABIEncoding {
+		t.Fatalf("ParseClaimEncoding = %s, %s", contract.Hex(), mode)
+	}
+
+	for _, value := range []string{checksummedAddress, checksummedAddress + "=padded", corruptedAddress + "=abi"} {
+		if _, _, err := ParseClaimEncoding(value); err == nil {
+			t.Fatalf("ParseClaimEncoding(%q) succeeded", value)
+		}
+	}
+}
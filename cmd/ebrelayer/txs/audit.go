@@ -0,0 +1,175 @@
+package txs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrAuditLogTampered is returned when an audit log's entries are out of sequence, or their hash
+// chain doesn't verify, as an entry edited, removed or inserted after it was written leaves it
+var ErrAuditLogTampered = errors.New("audit log integrity check failed")
+
+// ClaimAudit, if set, records every claim the validator signs before it is exported or submitted
+var ClaimAudit *AuditLog
+
+// AuditEntry is one line of an audit log: a signed claim, when it was signed, and its position in
+// the log. In a hash-chained log Hash is keccak256 of the previous entry's Hash followed by the
+// entry's JSON encoding without Hash, so changing any entry breaks every hash after it.
+type AuditEntry struct {
+	Seq  uint64    `json:"seq"`
+	Time time.Time `json:"time"`
+	SignedClaimJSON
+	PrevHash hexutil.Bytes `json:"prevHash,omitempty"`
+	Hash     hexutil.Bytes `json:"hash,omitempty"`
+}
+
+// chainHash returns the hash chaining the entry to its PrevHash
+func (e AuditEntry) chainHash() ([]byte, error) {
+	e.Hash = nil
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(e.PrevHash, data), nil
+}
+
+// AuditLog appends AuditEntry records as JSON lines to a file opened append-only, syncing each to
+// disk before Append returns, and hash-chaining them if Chained is set. It is safe for concurrent
+// use.
+type AuditLog struct {
+	Chained bool
+
+	mu    sync.Mutex
+	file  *os.File
+	clock func() time.Time
+	seq   uint64
+	last  []byte
+}
+
+// NewAuditLog opens the audit log at path, creating it if needed, hash-chaining its entries if
+// chained is set or the log already is. The entries already in it are verified first, and new
+// entries continue their sequence and hash chain, so a log which fails verification is refused
+// with ErrAuditLogTampered.
+func NewAuditLog(path string, chained bool) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := VerifyAuditLog(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	l := &AuditLog{Chained: chained, file: file, clock: time.Now}
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		l.seq, l.last = last.Seq, last.Hash
+		l.Chained = l.Chained || len(last.Hash) != 0
+	}
+	return l, nil
+}
+
+// Append records claim as signed now, returning once its entry is synced to disk
+func (l *AuditLog) Append(claim SignedClaimJSON) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := AuditEntry{Seq: l.seq + 1, Time: l.clock().UTC(), SignedClaimJSON: claim}
+	if l.Chained {
+		entry.PrevHash = l.last
+		hash, err := entry.chainHash()
+		if err != nil {
+			return err
+		}
+		entry.Hash = hash
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.seq, l.last = entry.Seq, entry.Hash
+	return nil
+}
+
+// Close closes the audit log's file
+func (l *AuditLog) Close() error {
+	return l.file.Close()
+}
+
+// VerifyAuditLog reads the AuditEntry records in r, one per line as an AuditLog writes them,
+// returning ErrAuditLogTampered unless their sequence numbers run from 1 without gaps and each
+// hash-chained entry links to the one before and matches its own contents. Once a log is chained,
+// every later entry must be.
+func VerifyAuditLog(r io.Reader) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	var last []byte
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxClaimLine)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrAuditLogTampered, line, err)
+		}
+		if entry.Seq != uint64(len(entries))+1 {
+			return nil, fmt.Errorf("%w: line %d: entry %d, expected %d", ErrAuditLogTampered, line, entry.Seq,
+				len(entries)+1)
+		}
+
+		switch {
+		case len(entry.Hash) == 0 && len(last) != 0:
+			return nil, fmt.Errorf("%w: line %d: entry %d isn't chained", ErrAuditLogTampered, line, entry.Seq)
+		case len(entry.Hash) != 0:
+			if !bytes.Equal(entry.PrevHash, last) {
+				return nil, fmt.Errorf("%w: line %d: entry %d doesn't link to the entry before it", ErrAuditLogTampered,
+					line, entry.Seq)
+			}
+			hash, err := entry.chainHash()
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(hash, entry.Hash) {
+				return nil, fmt.Errorf("%w: line %d: entry %d was modified", ErrAuditLogTampered, line, entry.Seq)
+			}
+		}
+		last = entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// auditClaim records signer's signed claim for event with ClaimAudit, if set. A claim which can't
+// be recorded mustn't be submitted, so its error is returned.
+func auditClaim(event types.ClaimEvent, message, signature []byte, signer common.Address) error {
+	if ClaimAudit == nil {
+		return nil
+	}
+	if err := ClaimAudit.Append(NewSignedClaimJSON(event, message, signature, signer)); err != nil {
+		getLogger().Error("CRITICAL: recording signed claim in the audit log failed", "err", err)
+		return getMetrics().claimError(SignErrorReason, fmt.Errorf("recording claim in audit log: %w", err))
+	}
+	return nil
+}
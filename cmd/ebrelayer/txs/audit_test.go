@@ -0,0 +1,134 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// writeTestAuditLog appends a signed claim for each of the first n testClaimEvents to the audit
+// log at path, reopening it halfway as a restarted relayer would
+func writeTestAuditLog(t *testing.T, path string, n int) {
+	key := testKey(t)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	events := testClaimEvents(n)
+	for _, part := range [][]int{{0, n / 2}, {n / 2, n}} {
+		audit, err := NewAuditLog(path, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		audit.clock = func() time.Time { return time.Unix(1600000000, 0) }
+		for _, event := range events[part[0]:part[1]] {
+			message := ethClaimMessage(t, event)
+			sig, err := SignClaim(PrefixMsg(message), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := audit.Append(NewSignedClaimJSON(event, message, sig, signer)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := audit.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// verifyAuditFile runs VerifyAuditLog over the file at path
+func verifyAuditFile(t *testing.T, path string) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	return VerifyAuditLog(file)
+}
+
+func TestAuditLogAppendsInOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ebrelayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	writeTestAuditLog(t, path, 4)
+
+	entries, err := verifyAuditFile(t, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("audit log holds %d entries, want 4", len(entries))
+	}
+	for i, entry := range entries {
+		// The sequence and hash chain continue across the reopen
+		if entry.Seq != uint64(i+1) || entry.UnlockID.Int().Cmp(testClaimEvents(4)[i].UnlockID) != 0 {
+			t.Fatalf("entry %d is entry %d of unlock ID %v, want them in signing order", i, entry.Seq, entry.UnlockID.Int())
+		}
+		if len(entry.Hash) == 0 || (i > 0 && !bytes.Equal(entry.PrevHash, entries[i-1].Hash)) {
+			t.Fatalf("entry %d isn't chained to the entry before it", entry.Seq)
+		}
+		if entry.Chain != ethereumChainLabel || !entry.Time.Equal(time.Unix(1600000000, 0)) {
+			t.Fatalf("entry %d recorded chain %q at %v", entry.Seq, entry.Chain, entry.Time)
+		}
+	}
+}
+
+func TestVerifyAuditLogDetectsTampering(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ebrelayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	writeTestAuditLog(t, path, 3)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	// Entry 2 is edited to claim entry 1's amount, keeping its recorded hash
+	var entry AuditEntry
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	var first AuditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	entry.Amount = first.Amount
+	edited, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tampered := range map[string][]string{
+		"entry 2 was modified":  {lines[0], string(edited), lines[2]},
+		"entry 3, expected 2":   {lines[0], lines[2]},
+		"entry 2 isn't chained": {lines[0], strings.Replace(lines[1], `"hash"`, `"unchained"`, 1), lines[2]},
+	} {
+		if err := ioutil.WriteFile(path, []byte(strings.Join(tampered, "\n")+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := verifyAuditFile(t, path); !errors.Is(err, ErrAuditLogTampered) || !strings.Contains(err.Error(), name) {
+			t.Fatalf("verifying a log where %s = %v, want ErrAuditLogTampered saying so", name, err)
+		}
+		// The relayer refuses to extend it
+		if audit, err := NewAuditLog(path, true); !errors.Is(err, ErrAuditLogTampered) {
+			if err == nil {
+				audit.Close()
+			}
+			t.Fatalf("reopening a log where %s = %v, want ErrAuditLogTampered", name, err)
+		}
+	}
+}
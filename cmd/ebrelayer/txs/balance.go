@@ -0,0 +1,105 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInsufficientGasBalance is returned in place of submitting a claim while the relayer's account
+// holds less than its chain's minimum gas balance, so the transaction would fail to pay for gas
+var ErrInsufficientGasBalance = errors.New("gas balance below minimum")
+
+var (
+	// EthGasBalance, if set, checks the Ethereum account's balance before each claim is submitted
+	EthGasBalance *GasBalanceGuard
+	// HmyGasBalance, if set, checks the Harmony account's balance before each claim is submitted
+	HmyGasBalance *GasBalanceGuard
+)
+
+// BalanceReader queries an account's native token balance
+type BalanceReader interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// GasBalanceGuard checks that the account submitting claims to Chain holds at least Minimum of its
+// native token, raising a critical alert when it falls below, and if Pause is set, refusing to
+// submit claims until it is topped up. It is safe for concurrent use.
+type GasBalanceGuard struct {
+	Chain   string
+	Minimum *big.Int
+	Pause   bool
+
+	mu      sync.RWMutex
+	balance *big.Int
+	low     bool
+}
+
+// NewGasBalanceGuard initializes a new GasBalanceGuard
+func NewGasBalanceGuard(chain string, minimum *big.Int, pause bool) *GasBalanceGuard {
+	return &GasBalanceGuard{Chain: chain, Minimum: minimum, Pause: pause}
+}
+
+// Refresh queries account's balance through client and records it, alerting once when it falls
+// below Minimum and logging once it recovers
+func (g *GasBalanceGuard) Refresh(ctx context.Context, client BalanceReader, account common.Address) (*big.Int, error) {
+	balance, err := client.BalanceAt(ctx, account, nil)
+	if err != nil {
+		return nil, fmt.Errorf("querying %s gas balance: %w", g.Chain, err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	low := balance.Cmp(g.Minimum) < 0
+	if low && !g.low {
+		getLogger().Error("CRITICAL: relayer gas balance below minimum, claim submissions will fail",
+			"chain", g.Chain, "account", chainAddress(g.Chain, account), "balance", balance, "minimum", g.Minimum,
+			"paused", g.Pause)
+	} else if !low && g.low {
+		getLogger().Info("Relayer gas balance restored", "chain", g.Chain, "account", chainAddress(g.Chain, account),
+			"balance", balance)
+	}
+	g.balance, g.low = balance, low
+	getMetrics().gasBalance(g.Chain, balance)
+	return balance, nil
+}
+
+// Balance returns the balance last refreshed, if any
+func (g *GasBalanceGuard) Balance() (*big.Int, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.balance, g.balance != nil
+}
+
+// Low reports whether the balance last refreshed was below Minimum
+func (g *GasBalanceGuard) Low() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.low
+}
+
+// Check refreshes account's balance before a claim is submitted, returning
+// ErrInsufficientGasBalance, marked as not worth retrying, if it is below Minimum and Pause is set.
+// A failed balance query doesn't hold up the submission.
+func (g *GasBalanceGuard) Check(ctx context.Context, client BalanceReader, account common.Address) error {
+	if g == nil {
+		return nil
+	}
+	balance, err := g.Refresh(ctx, client, account)
+	if err != nil {
+		getLogger().Error("Gas balance check failed", "chain", g.Chain, "err", err)
+		return nil
+	}
+	if g.Pause && balance.Cmp(g.Minimum) < 0 {
+		return Permanent(fmt.Errorf("%w: %s holds %v on %s, below %v", ErrInsufficientGasBalance,
+			chainAddress(g.Chain, account), balance, g.Chain, g.Minimum))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// testBalances is a BalanceReader whose balance tests set, failing while err is set
+type testBalances struct {
+	mu      sync.Mutex
+	balance *big.Int
+	err     error
+}
+
+func (b *testBalances) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.balance, b.err
+}
+
+func (b *testBalances) set(balance int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.balance = big.NewInt(balance)
+}
+
+func TestGasBalanceGuardPausesBelowMinimum(t *testing.T) {
+	m, disable := useTestMetrics(t)
+	defer disable()
+	recorder, restore := useRecordingLogger()
+	defer restore()
+	balances := &testBalances{balance: big.NewInt(5)}
+	guard := NewGasBalanceGuard(ethereumChainLabel, big.NewInt(10), true)
+	account := goldenClaim.recipient
+
+	// A claim submitted below the minimum is refused, and not retried
+	attempts := 0
+	err := Retry(context.Background(), func() error {
+		attempts++
+		return guard.Check(context.Background(), balances, account)
+	}, testRetryPolicy)
+	if !errors.Is(err, ErrInsufficientGasBalance) || attempts != 1 {
+		t.Fatalf("Retry of a check below the minimum = %v after %d attempts, want ErrInsufficientGasBalance at once", err, attempts)
+	}
+	if !guard.Low() {
+		t.Fatal("guard doesn't report the balance low")
+	}
+	if gauge := testutil.ToFloat64(m.GasBalance.WithLabelValues(ethereumChainLabel)); gauge != 5 {
+		t.Fatalf("gas_balance = %v, want 5", gauge)
+	}
+
+	// The alert is raised once however many claims are refused
+	if err := guard.Check(context.Background(), balances, account); !errors.Is(err, ErrInsufficientGasBalance) {
+		t.Fatalf("second Check = %v, want ErrInsufficientGasBalance", err)
+	}
+	if alerts := recorder.count("ERROR"); alerts != 1 {
+		t.Fatalf("logged %d alerts, want 1", alerts)
+	}
+
+	// Topping the account up resumes submissions
+	balances.set(10)
+	if err := guard.Check(context.Background(), balances, account); err != nil {
+		t.Fatalf("Check at the minimum = %v", err)
+	}
+	if guard.Low() || recorder.count("INFO") != 1 {
+		t.Fatal("guard didn't log the balance restored")
+	}
+	if balance, ok := guard.Balance(); !ok || balance.Int64() != 10 {
+		t.Fatalf("Balance = %v, %v, want 10", balance, ok)
+	}
+}
+
+func TestGasBalanceGuardAlertOnly(t *testing.T) {
+	_, disable := useTestMetrics(t)
+	defer disable()
+	recorder, restore := useRecordingLogger()
+	defer restore()
+	guard := NewGasBalanceGuard(harmonyChainLabel, big.NewInt(10), false)
+
+	// Without Pause a low balance alerts but doesn't hold the claim up
+	if err := guard.Check(context.Background(), &testBalances{balance: big.NewInt(1)}, goldenClaim.recipient); err != nil {
+		t.Fatalf("Check below the minimum without pausing = %v", err)
+	}
+	if !guard.Low() || recorder.count("ERROR") != 1 {
+		t.Fatal("low balance wasn't alerted")
+	}
+
+	// Nor does a failed balance query
+	failing := &testBalances{err: errors.New("connection refused")}
+	if err := NewGasBalanceGuard(harmonyChainLabel, big.NewInt(10), true).Check(context.Background(), failing, goldenClaim.recipient); err != nil {
+		t.Fatalf("Check with a failed balance query = %v", err)
+	}
+	// An unset guard checks nothing
+	var unset *GasBalanceGuard
+	if err := unset.Check(context.Background(), failing, goldenClaim.recipient); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,191 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// SignedClaim pairs an UnlockClaim's unlock ID with its signed claim message. Exported is set
+// for a claim handed to ClaimExport, which its external submitter submits in place of the caller.
+type SignedClaim struct {
+	UnlockID  *big.Int
+	Message   [32]byte
+	Signature []byte
+	Exported  bool
+}
+
+// SignClaimsBatch signs the claim message of each Ethereum UnlockClaim, returning the signed
+// claims in the same order as events. Each message is signed per the SigningSchemes config of the
+// optional target verifying contract.
+func SignClaimsBatch(signer Signer, events []types.EthLogNewUnlockClaimEvent, target ...common.Address) ([]SignedClaim, error) {
+	claimEvents := make([]types.ClaimEvent, len(events))
+	for i, event := range events {
+		claimEvents[i] = event
+	}
+	return SignClaimEvents(signer, claimEvents, runtime.NumCPU(), target...)
+}
+
+// HmySignClaimsBatch signs the claim message of each Harmony UnlockClaim, returning the signed
+// claims in the same order as events. Each message is signed per the SigningSchemes config of the
+// optional target verifying contract.
+func HmySignClaimsBatch(signer Signer, events []types.HmyLogNewUnlockClaimEvent, target ...common.Address) ([]SignedClaim, error) {
+	claimEvents := make([]types.ClaimEvent, len(events))
+	for i, event := range events {
+		claimEvents[i] = event
+	}
+	return SignClaimEvents(signer, claimEvents, runtime.NumCPU(), target...)
+}
+
+// SignClaimEvents signs the claim message of each event across up to workers goroutines. Output
+// order matches input order; the first error encountered, by input position, is returned. Each
+// message is signed per the SigningSchemes config of the optional target verifying contract, so
+// a contract registered as Raw gets signatures over the unprefixed claim hash.
+func SignClaimEvents(signer Signer, events []types.ClaimEvent, workers int, target ...common.Address) ([]SignedClaim, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	signed := make([]SignedClaim, len(events))
+	errs := make([]error, len(events))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				signed[i], errs[i] = signClaimEvent(signer, events[i], target)
+			}
+		}()
+	}
+
+	for i := range events {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("signing claim %d: %w", i, err)
+		}
+	}
+	return signed, nil
+}
+
+// signClaimEvent generates and signs a single event's claim message for the first of target, if given,
+// behind every guard a claim passes before signing, then audits and exports the signed claim
+func signClaimEvent(signer Signer, event types.ClaimEvent, target []common.Address) (SignedClaim, error) {
+	signedClaim := SignedClaim{}
+	start := time.Now()
+
+	if err := checkSigningHalted(); err != nil {
+		return signedClaim, err
+	}
+	if err := checkClaimSanity(event); err != nil {
+		return signedClaim, err
+	}
+	if err := checkTokenAllowed(event); err != nil {
+		return signedClaim, err
+	}
+	if err := checkAmountLimit(event); err != nil {
+		return signedClaim, err
+	}
+	message, err := ClaimMessage(event, target...)
+	if err != nil {
+		return signedClaim, getMetrics().claimError(SignErrorReason, err)
+	}
+	if DryRun {
+		return signedClaim, dryRunClaim(event, message, target)
+	}
+
+	digest, err := claimDigest(message, target)
+	if err != nil {
+		return signedClaim, getMetrics().claimError(SignErrorReason, err)
+	}
+	unlockID, _, _, _, _ := event.ClaimFields()
+	signature, err := signClaimDigest(claimEventChain(event), unlockID, signer.Address(), digest, signer.Sign)
+	if err != nil {
+		return signedClaim, getMetrics().claimError(SignErrorReason, err)
+	}
+	if err := auditClaim(event, message, signature, signer.Address()); err != nil {
+		return signedClaim, err
+	}
+	switch err := exportClaim(event, message, signature, signer.Address()); {
+	case errors.Is(err, ErrClaimExported):
+		signedClaim.Exported = true
+	case err != nil:
+		return signedClaim, err
+	}
+
+	signedClaim.UnlockID = unlockID
+	getMetrics().claimSigned(claimEventChain(event), time.Since(start))
+
+	copy(signedClaim.Message[:], message)
+	signedClaim.Signature = signature
+	return signedClaim, nil
+}
+
+// SignatureCheck is a signature over a 32-byte message digest, and the validator expected to
+// have produced it
+type SignatureCheck struct {
+	Msg      []byte
+	Sig      []byte
+	Expected common.Address
+}
+
+// VerifyBatch verifies each check across runtime.NumCPU goroutines, as VerifyBatchWorkers does
+func VerifyBatch(checks []SignatureCheck) []error {
+	return VerifyBatchWorkers(checks, runtime.NumCPU())
+}
+
+// VerifyBatchWorkers recovers the signer of each check across up to workers goroutines, returning
+// an error per check in input order: nil if its signature recovers to Expected, ErrSignerMismatch
+// if it recovers to another address, or the recovery error. Every check is verified, whatever the
+// others' results.
+func VerifyBatchWorkers(checks []SignatureCheck, workers int) []error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, len(checks))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = verifySignature(checks[i])
+			}
+		}()
+	}
+
+	for i := range checks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
+
+// verifySignature checks that check's signature recovers to its expected signer
+func verifySignature(check SignatureCheck) error {
+	signer, err := RecoverSigner(check.Msg, check.Sig)
+	if err != nil {
+		return err
+	}
+	if signer != check.Expected {
+		return fmt.Errorf("%w: %s, recovered %s", ErrSignerMismatch, check.Expected.Hex(), signer.Hex())
+	}
+	return nil
+}
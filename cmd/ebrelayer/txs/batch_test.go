@@ -0,0 +1,145 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// testClaimEvents returns n Ethereum UnlockClaims with unlock IDs 1 to n
+func testClaimEvents(n int) []types.EthLogNewUnlockClaimEvent {
+	events := make([]types.EthLogNewUnlockClaimEvent, n)
+	for i := range events {
+		events[i] = types.EthLogNewUnlockClaimEvent{
+			UnlockID:         big.NewInt(int64(i + 1)),
+			HarmonySender:    common.HexToAddress(testHarmonyHex),
+			EthereumReceiver: common.HexToAddress(checksummedAddress),
+			TokenAddress:     goldenClaim.token,
+			Amount:           big.NewInt(int64(1000 * (i + 1))),
+		}
+	}
+	return events
+}
+
+func TestSignClaimsBatchPreservesOrder(t *testing.T) {
+	key := testKey(t)
+	events := testClaimEvents(64)
+	signed, err := SignClaimsBatch(NewKeySigner(key), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != len(events) {
+		t.Fatalf("signed %d claims, want %d", len(signed), len(events))
+	}
+
+	for i, event := range events {
+		if signed[i].UnlockID.Cmp(event.UnlockID) != 0 {
+			t.Fatalf("signed claim %d has unlock ID %v, want %v", i, signed[i].UnlockID, event.UnlockID)
+		}
+		message := ethClaimMessage(t, event)
+		if !bytes.Equal(signed[i].Message[:], message) {
+			t.Fatalf("signed claim %d has message %x, want %x", i, signed[i].Message, message)
+		}
+		signature, err := SignClaim(PrefixMsg(message), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(signed[i].Signature, signature) {
+			t.Fatalf("signed claim %d has signature %x, want %x", i, signed[i].Signature, signature)
+		}
+	}
+}
+
+func TestSignClaimEventsReturnsFirstError(t *testing.T) {
+	events := []types.ClaimEvent{testClaimEvents(1)[0], types.EthLogNewUnlockClaimEvent{}}
+	if _, err := SignClaimEvents(NewKeySigner(testKey(t)), events, 2); err == nil {
+		t.Fatal("a claim without an unlock ID or amount was signed")
+	}
+}
+
+// TestVerifyBatchMixed is meant to be run with -race: workers write the per-check errors
+// concurrently
+func TestVerifyBatchMixed(t *testing.T) {
+	key := testKey(t)
+	validator := crypto.PubkeyToAddress(key.PublicKey)
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Valid signatures, signatures by another key and truncated ones, interleaved
+	checks := make([]SignatureCheck, 200)
+	for i, event := range testClaimEvents(len(checks)) {
+		digest := PrefixMsg(ethClaimMessage(t, event))
+		signer := key
+		if i%3 == 1 {
+			signer = other
+		}
+		sig, err := SignClaim(digest, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%3 == 2 {
+			sig = sig[:crypto.SignatureLength-1]
+		}
+		checks[i] = SignatureCheck{Msg: digest, Sig: sig, Expected: validator}
+	}
+
+	for _, workers := range []int{0, 8} {
+		errs := VerifyBatchWorkers(checks, workers)
+		if len(errs) != len(checks) {
+			t.Fatalf("%d workers returned %d errors for %d checks", workers, len(errs), len(checks))
+		}
+		for i, err := range errs {
+			switch i % 3 {
+			case 0:
+				if err != nil {
+					t.Fatalf("%d workers: valid check %d = %v", workers, i, err)
+				}
+			case 1:
+				if !errors.Is(err, ErrSignerMismatch) {
+					t.Fatalf("%d workers: check %d by another key = %v, want ErrSignerMismatch", workers, i, err)
+				}
+			case 2:
+				if err == nil || errors.Is(err, ErrSignerMismatch) {
+					t.Fatalf("%d workers: truncated check %d = %v, want a recovery error", workers, i, err)
+				}
+			}
+		}
+	}
+	if errs := VerifyBatch(checks[:3]); len(errs) != 3 || errs[0] != nil || errs[1] == nil || errs[2] == nil {
+		t.Fatalf("VerifyBatch = %v", errs)
+	}
+}
+
+func BenchmarkSignClaimsBatch(b *testing.B) {
+	signer := NewKeySigner(testKey(b))
+	events := testClaimEvents(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := SignClaimsBatch(signer, events); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSignClaimsSequential signs the same claims one at a time, as a baseline for
+// BenchmarkSignClaimsBatch
+func BenchmarkSignClaimsSequential(b *testing.B) {
+	signer := NewKeySigner(testKey(b))
+	events := testClaimEvents(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, event := range events {
+			if _, err := signClaimEvent(signer, event, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
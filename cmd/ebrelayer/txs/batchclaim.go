@@ -0,0 +1,313 @@
+package txs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	habi "github.com/harmony-one/harmony/accounts/abi"
+	hbind "github.com/harmony-one/harmony/accounts/abi/bind"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+	"github.com/mochi-lab/eth-one-bridge/hmyclient"
+)
+
+// SubmitBatchABI is the upgraded Oracle contract's batch entrypoint, taking a batch's claims as
+// parallel arrays in submission order. The generated Oracle bindings predate it.
+const SubmitBatchABI = `[{"type":"function","name":"submitBatch","stateMutability":"nonpayable","outputs":[],` +
+	`"inputs":[{"name":"unlockIDs","type":"uint256[]"},{"name":"messages","type":"bytes32[]"},` +
+	`{"name":"signatures","type":"bytes[]"}]}]`
+
+const (
+	// DefaultBatchMaxClaims is the most claims BatchClaim puts in one submitBatch call
+	DefaultBatchMaxClaims = 20
+	// DefaultBatchBaseGas estimates the gas of a submitBatch call before its claims
+	DefaultBatchBaseGas = uint64(50000)
+	// DefaultBatchClaimGas estimates the gas each claim adds to a submitBatch call
+	DefaultBatchClaimGas = uint64(100000)
+	// DefaultBatchReceiptTimeout bounds the wait for a submitted batch to be mined
+	DefaultBatchReceiptTimeout = 2 * time.Minute
+)
+
+// batchReceiptPollInterval is how often a submitted batch's receipt is polled
+const batchReceiptPollInterval = 2 * time.Second
+
+// ErrBatchReverted is returned when a submitBatch transaction was mined but reverted
+var ErrBatchReverted = errors.New("claim batch reverted")
+
+var (
+	submitBatchABIOnce sync.Once
+	submitBatchABI     abi.ABI
+	submitBatchABIErr  error
+)
+
+// BatchClaim groups signed claims into submitBatch calls of at most MaxClaims claims and, if
+// MaxGas is set, at most MaxGas estimated gas
+type BatchClaim struct {
+	MaxClaims int
+	MaxGas    uint64
+	BaseGas   uint64
+	ClaimGas  uint64
+}
+
+// NewBatchClaim initializes a new BatchClaim with the default gas estimates. A maxClaims below 1
+// uses DefaultBatchMaxClaims, and a zero maxGas only limits batches by count.
+func NewBatchClaim(maxClaims int, maxGas uint64) BatchClaim {
+	if maxClaims < 1 {
+		maxClaims = DefaultBatchMaxClaims
+	}
+	return BatchClaim{MaxClaims: maxClaims, MaxGas: maxGas, BaseGas: DefaultBatchBaseGas, ClaimGas: DefaultBatchClaimGas}
+}
+
+// Gas estimates the gas of a batch of n claims
+func (b BatchClaim) Gas(n int) uint64 {
+	return b.BaseGas + uint64(n)*b.ClaimGas
+}
+
+// Group splits claims into consecutive batches, preserving their order within and across
+// batches. Every batch holds at least one claim, even one alone over MaxGas.
+func (b BatchClaim) Group(claims []SignedClaim) [][]SignedClaim {
+	var batches [][]SignedClaim
+	for start := 0; start < len(claims); {
+		end := start + 1
+		for end < len(claims) && end-start < b.MaxClaims && (b.MaxGas == 0 || b.Gas(end-start+1) <= b.MaxGas) {
+			end++
+		}
+		batches = append(batches, claims[start:end])
+		start = end
+	}
+	return batches
+}
+
+// EncodeSubmitBatch returns the submitBatch calldata for a batch
+func EncodeSubmitBatch(batch []SignedClaim) ([]byte, error) {
+	submitBatchABIOnce.Do(func() {
+		submitBatchABI, submitBatchABIErr = abi.JSON(strings.NewReader(SubmitBatchABI))
+	})
+	if submitBatchABIErr != nil {
+		return nil, submitBatchABIErr
+	}
+
+	unlockIDs := make([]*big.Int, len(batch))
+	messages := make([][32]byte, len(batch))
+	signatures := make([][]byte, len(batch))
+	for i, claim := range batch {
+		unlockIDs[i], messages[i], signatures[i] = claim.UnlockID, claim.Message, claim.Signature
+	}
+	return submitBatchABI.Pack("submitBatch", unlockIDs, messages, signatures)
+}
+
+// BatchSubmitter sends claim batches and individual claims to a chain's Oracle contract
+type BatchSubmitter interface {
+	// SubmitBatch sends a batch in one transaction, returning ErrBatchReverted if it reverted
+	SubmitBatch(ctx context.Context, batch []SignedClaim, gasLimit uint64) error
+	// SubmitClaim sends a single claim
+	SubmitClaim(ctx context.Context, claim SignedClaim) error
+}
+
+// Submit sends claims in order, grouped into batches. A batch which reverts, as a whole or in
+// part, is resubmitted claim by claim in order, so the claims able to succeed still land.
+// Submission stops at the first claim failing on its own, leaving later claims unsubmitted so
+// they never land ahead of it. Exported claims are left to ClaimExport's external submitter.
+func (b BatchClaim) Submit(ctx context.Context, submitter BatchSubmitter, claims []SignedClaim) error {
+	unexported := make([]SignedClaim, 0, len(claims))
+	for _, claim := range claims {
+		if !claim.Exported {
+			unexported = append(unexported, claim)
+		}
+	}
+
+	for _, batch := range b.Group(unexported) {
+		err := submitter.SubmitBatch(ctx, batch, b.Gas(len(batch)))
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrBatchReverted) {
+			return err
+		}
+
+		getLogger().Warn("Claim batch reverted, submitting its claims individually", "claims", len(batch), "err", err)
+		for _, claim := range batch {
+			if err := submitter.SubmitClaim(ctx, claim); err != nil {
+				return fmt.Errorf("submitting claim %v: %w", claim.UnlockID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// EthBatchSubmitter is a BatchSubmitter for the Oracle contract on Ethereum
+type EthBatchSubmitter struct {
+	Provider   string
+	Registry   common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// SubmitBatch implements BatchSubmitter
+func (s EthBatchSubmitter) SubmitBatch(ctx context.Context, batch []SignedClaim, gasLimit uint64) error {
+	calldata, err := EncodeSubmitBatch(batch)
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+
+	if err := EthSubmitLimiter.Wait(ctx); err != nil {
+		return err
+	}
+	unlock := lockSubmission(s.Provider, s.PrivateKey)
+
+	client, auth, target, err := EthInitRelayConfig(s.Provider, s.Registry, types.EthLogNewUnlockClaim, s.PrivateKey)
+	if err != nil {
+		unlock()
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+	auth.GasLimit = gasLimit
+	settleNonce, err := assignNonce(s.Provider, client, auth)
+	if err != nil {
+		unlock()
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+
+	tx, err := bind.NewBoundContract(target, abi.ABI{}, client, client, client).RawTransact(auth, calldata)
+	settleNonce(err)
+	unlock()
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+	fmt.Println("submitBatch tx hash:", tx.Hash().Hex())
+
+	err = waitReceipt(ctx, tx.Hash(), ethReceiptStatus(client))
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+	for range batch {
+		getMetrics().claimSubmitted(ethereumChainLabel)
+	}
+	return nil
+}
+
+// SubmitClaim implements BatchSubmitter
+func (s EthBatchSubmitter) SubmitClaim(ctx context.Context, claim SignedClaim) error {
+	return RelayOracleClaimToEthereum(s.Provider, s.Registry, types.EthLogNewUnlockClaim, EthOracleClaim{
+		UnlockID: claim.UnlockID, Message: claim.Message, Signature: claim.Signature}, s.PrivateKey)
+}
+
+// HmyBatchSubmitter is a BatchSubmitter for the Oracle contract on Harmony
+type HmyBatchSubmitter struct {
+	Provider   string
+	Registry   common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// SubmitBatch implements BatchSubmitter
+func (s HmyBatchSubmitter) SubmitBatch(ctx context.Context, batch []SignedClaim, gasLimit uint64) error {
+	calldata, err := EncodeSubmitBatch(batch)
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+
+	if err := HmySubmitLimiter.Wait(ctx); err != nil {
+		return err
+	}
+	unlock := lockSubmission(s.Provider, s.PrivateKey)
+
+	client, auth, target, err := HmyInitRelayConfig(s.Provider, s.Registry, types.HmyLogNewUnlockClaim, s.PrivateKey)
+	if err != nil {
+		unlock()
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+	auth.GasLimit = gasLimit
+	settleNonce, err := assignNonce(s.Provider, client, auth)
+	if err != nil {
+		unlock()
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+
+	tx, err := hbind.NewBoundContract(target, habi.ABI{}, client, client, client).RawTransact(auth, calldata)
+	settleNonce(err)
+	unlock()
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+	txHash := client.TxHash(tx)
+	fmt.Println("submitBatch tx hash:", txHash.Hex())
+
+	err = waitReceipt(ctx, txHash, hmyReceiptStatus(client.Client))
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+	for range batch {
+		getMetrics().claimSubmitted(harmonyChainLabel)
+	}
+	return nil
+}
+
+// SubmitClaim implements BatchSubmitter
+func (s HmyBatchSubmitter) SubmitClaim(ctx context.Context, claim SignedClaim) error {
+	return RelayOracleClaimToHarmony(s.Provider, s.Registry, types.HmyLogNewUnlockClaim, HmyOracleClaim{
+		UnlockID: claim.UnlockID, Message: claim.Message, Signature: claim.Signature}, s.PrivateKey)
+}
+
+// ethReceiptReader looks up Ethereum transaction receipts
+type ethReceiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ctypes.Receipt, error)
+}
+
+// ethReceiptStatus returns a receipt status lookup for waitReceipt using client
+func ethReceiptStatus(client ethReceiptReader) func(ctx context.Context, txHash common.Hash) (uint64, error) {
+	return func(ctx context.Context, txHash common.Hash) (uint64, error) {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			return 0, err
+		}
+		return receipt.Status, nil
+	}
+}
+
+// hmyReceiptStatus returns a receipt status lookup for waitReceipt using client
+func hmyReceiptStatus(client *hmyclient.Client) func(ctx context.Context, txHash common.Hash) (uint64, error) {
+	return func(ctx context.Context, txHash common.Hash) (uint64, error) {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			return 0, err
+		}
+		return receipt.Status, nil
+	}
+}
+
+// waitReceipt polls status for txHash's receipt status until it is mined, returning
+// ErrBatchReverted if it failed. It gives up after DefaultBatchReceiptTimeout.
+func waitReceipt(ctx context.Context, txHash common.Hash,
+	status func(ctx context.Context, txHash common.Hash) (uint64, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultBatchReceiptTimeout)
+	defer cancel()
+
+	for {
+		receiptStatus, err := status(ctx, txHash)
+		switch {
+		case err == nil && receiptStatus == ctypes.ReceiptStatusFailed:
+			return fmt.Errorf("%w: %s", ErrBatchReverted, txHash.Hex())
+		case err == nil:
+			return nil
+		case err != ethereum.NotFound:
+			return err
+		}
+
+		timer := time.NewTimer(batchReceiptPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for %s: %w", txHash.Hex(), ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// testSignedClaims returns n signed claims, with unlock IDs 1 to n
+func testSignedClaims(n int) []SignedClaim {
+	claims := make([]SignedClaim, n)
+	for i := range claims {
+		claims[i] = SignedClaim{UnlockID: big.NewInt(int64(i + 1)), Message: [32]byte{byte(i + 1)}, Signature: []byte{byte(i + 1)}}
+	}
+	return claims
+}
+
+// unlockIDs returns the unlock IDs of claims
+func unlockIDs(claims []SignedClaim) []int64 {
+	ids := make([]int64, len(claims))
+	for i, claim := range claims {
+		ids[i] = claim.UnlockID.Int64()
+	}
+	return ids
+}
+
+// recordingSubmitter is a BatchSubmitter recording what it is sent, reverting the batches
+// holding a claim in revert and failing the individual claims in fail
+type recordingSubmitter struct {
+	revert, fail map[int64]bool
+
+	batches [][]int64
+	gas     []uint64
+	claims  []int64
+}
+
+func (s *recordingSubmitter) SubmitBatch(_ context.Context, batch []SignedClaim, gasLimit uint64) error {
+	ids := unlockIDs(batch)
+	s.batches = append(s.batches, ids)
+	s.gas = append(s.gas, gasLimit)
+	for _, id := range ids {
+		if s.revert[id] {
+			return ErrBatchReverted
+		}
+	}
+	return nil
+}
+
+func (s *recordingSubmitter) SubmitClaim(_ context.Context, claim SignedClaim) error {
+	s.claims = append(s.claims, claim.UnlockID.Int64())
+	if s.fail[claim.UnlockID.Int64()] {
+		return errors.New("reverted")
+	}
+	return nil
+}
+
+func TestBatchClaimGroup(t *testing.T) {
+	claims := testSignedClaims(7)
+	tests := []struct {
+		name  string
+		batch BatchClaim
+		want  [][]int64
+	}{
+		{"by count", NewBatchClaim(3, 0), [][]int64{{1, 2, 3}, {4, 5, 6}, {7}}},
+		// Room for the base gas and two claims
+		{"by gas", NewBatchClaim(5, DefaultBatchBaseGas+2*DefaultBatchClaimGas), [][]int64{{1, 2}, {3, 4}, {5, 6}, {7}}},
+		{"claim alone over the gas", NewBatchClaim(5, 1), [][]int64{{1}, {2}, {3}, {4}, {5}, {6}, {7}}},
+		{"default count", NewBatchClaim(0, 0), [][]int64{{1, 2, 3, 4, 5, 6, 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int64
+			for _, batch := range tt.batch.Group(claims) {
+				got = append(got, unlockIDs(batch))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Group = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeSubmitBatch(t *testing.T) {
+	batch := testSignedClaims(2)
+	calldata, err := EncodeSubmitBatch(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(SubmitBatchABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := parsed.Methods["submitBatch"]
+	if !reflect.DeepEqual(calldata[:4], method.ID) {
+		t.Fatalf("calldata selector %x, want submitBatch's %x", calldata[:4], method.ID)
+	}
+	args, err := method.Inputs.UnpackValues(calldata[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, messages, signatures := args[0].([]*big.Int), args[1].([][32]byte), args[2].([][]byte)
+	for i, claim := range batch {
+		if ids[i].Cmp(claim.UnlockID) != 0 || messages[i] != claim.Message || !reflect.DeepEqual(signatures[i], claim.Signature) {
+			t.Fatalf("claim %d decoded as %v, %x, %x, want %+v", i, ids[i], messages[i], signatures[i], claim)
+		}
+	}
+}
+
+func TestBatchClaimSubmit(t *testing.T) {
+	claims := testSignedClaims(5)
+	claims[1].Exported = true
+	submitter := &recordingSubmitter{}
+
+	batch := NewBatchClaim(2, 0)
+	if err := batch.Submit(context.Background(), submitter, claims); err != nil {
+		t.Fatal(err)
+	}
+	// Exported claims are left out of the batches
+	if want := [][]int64{{1, 3}, {4, 5}}; !reflect.DeepEqual(submitter.batches, want) {
+		t.Fatalf("submitted batches %v, want %v", submitter.batches, want)
+	}
+	if want := []uint64{batch.Gas(2), batch.Gas(2)}; !reflect.DeepEqual(submitter.gas, want) {
+		t.Fatalf("batch gas limits %v, want %v", submitter.gas, want)
+	}
+	if len(submitter.claims) != 0 {
+		t.Fatalf("submitted claims %v individually, want none", submitter.claims)
+	}
+}
+
+func TestBatchClaimSubmitFallback(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	claims := testSignedClaims(6)
+
+	// A reverted batch is resubmitted claim by claim, and later batches still go out
+	submitter := &recordingSubmitter{revert: map[int64]bool{3: true}}
+	if err := NewBatchClaim(2, 0).Submit(context.Background(), submitter, claims); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int64{{1, 2}, {3, 4}, {5, 6}}; !reflect.DeepEqual(submitter.batches, want) {
+		t.Fatalf("submitted batches %v, want %v", submitter.batches, want)
+	}
+	if want := []int64{3, 4}; !reflect.DeepEqual(submitter.claims, want) {
+		t.Fatalf("submitted claims %v individually, want %v", submitter.claims, want)
+	}
+
+	// A claim failing on its own stops submission before any later claim
+	submitter = &recordingSubmitter{revert: map[int64]bool{3: true}, fail: map[int64]bool{3: true}}
+	err := NewBatchClaim(2, 0).Submit(context.Background(), submitter, claims)
+	if err == nil || !strings.Contains(err.Error(), "submitting claim 3") {
+		t.Fatalf("Submit = %v, want claim 3's failure", err)
+	}
+	if want := [][]int64{{1, 2}, {3, 4}}; !reflect.DeepEqual(submitter.batches, want) {
+		t.Fatalf("submitted batches %v, want none after the failing claim", submitter.batches)
+	}
+	if want := []int64{3}; !reflect.DeepEqual(submitter.claims, want) {
+		t.Fatalf("submitted claims %v individually, want %v", submitter.claims, want)
+	}
+
+	// Errors other than a revert return without falling back
+	failing := errors.New("connection refused")
+	err = NewBatchClaim(2, 0).Submit(context.Background(), submitterFunc(func() error { return failing }), claims)
+	if !errors.Is(err, failing) {
+		t.Fatalf("Submit = %v, want %v", err, failing)
+	}
+}
+
+// submitterFunc is a BatchSubmitter failing every batch and claim with the error it returns
+type submitterFunc func() error
+
+func (f submitterFunc) SubmitBatch(context.Context, []SignedClaim, uint64) error {
+	return f()
+}
+
+func (f submitterFunc) SubmitClaim(context.Context, SignedClaim) error {
+	return f()
+}
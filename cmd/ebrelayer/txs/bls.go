@@ -0,0 +1,272 @@
+package txs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+const (
+	// BLSSecretKeyLength is the length of a big-endian BLS secret key
+	BLSSecretKeyLength = 32
+	// BLSPublicKeyLength is the length of an uncompressed BLS public key, a G1 point
+	BLSPublicKeyLength = 96
+	// BLSSignatureLength is the length of an uncompressed BLS signature, a G2 point
+	BLSSignatureLength = 192
+)
+
+// ErrInvalidBLSPoint is returned for a BLS public key or signature which isn't a valid point of
+// its group
+var ErrInvalidBLSPoint = errors.New("invalid BLS point")
+
+var (
+	// blsClaimDST separates the hashes of claims signed with BLS from any other use of the curve
+	blsClaimDST = []byte("EBRELAYER_BLS12381G2_XMD:SHA-256_SSWU_RO_CLAIM_")
+	// blsPossessionDST separates proofs of possession from claim signatures
+	blsPossessionDST = []byte("EBRELAYER_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+	// blsFieldModulus is p, the modulus of the BLS12-381 base field
+	blsFieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+)
+
+// BLSSigner is a Signer signing claim messages with a BLS12-381 key, for validator schemes
+// attesting with BLS signatures which aggregate into one. Public keys are G1 points and signatures
+// G2 points, both uncompressed. Messages are hashed to G2 with expand_message_xmd over SHA-256 and
+// the SWU map of go-ethereum's bls12381 package, so signatures verify with this package's
+// VerifyBLS and VerifyAggregateBLS rather than necessarily with other BLS libraries.
+type BLSSigner struct {
+	secret *big.Int
+	public *bls12381.PointG1
+}
+
+// NewBLSSigner initializes a new BLSSigner from a 32 byte big-endian secret key
+func NewBLSSigner(secret []byte) (*BLSSigner, error) {
+	if len(secret) != BLSSecretKeyLength {
+		return nil, fmt.Errorf("BLS secret key is %d bytes, expected %d", len(secret), BLSSecretKeyLength)
+	}
+	key := new(big.Int).SetBytes(secret)
+	if key.Sign() == 0 || key.Cmp(bls12381.NewG1().Q()) >= 0 {
+		return nil, errors.New("BLS secret key is out of range")
+	}
+	g1 := bls12381.NewG1()
+	return &BLSSigner{secret: key, public: g1.MulScalar(g1.New(), g1.One(), key)}, nil
+}
+
+// GenerateBLSSigner initializes a new BLSSigner with a random secret key
+func GenerateBLSSigner() (*BLSSigner, error) {
+	order := new(big.Int).Sub(bls12381.NewG1().Q(), big.NewInt(1))
+	key, err := rand.Int(rand.Reader, order)
+	if err != nil {
+		return nil, err
+	}
+	return NewBLSSigner(common.LeftPadBytes(key.Add(key, big.NewInt(1)).Bytes(), BLSSecretKeyLength))
+}
+
+// PublicKey returns the signer's uncompressed public key
+func (s *BLSSigner) PublicKey() []byte {
+	return bls12381.NewG1().ToBytes(s.public)
+}
+
+// Address implements Signer, identifying the BLS key by the last 20 bytes of the keccak256 hash
+// of its public key, as an Ethereum address is derived from an ECDSA key
+func (s *BLSSigner) Address() common.Address {
+	return common.BytesToAddress(Keccak256(s.PublicKey())[12:])
+}
+
+// Sign implements Signer, returning the BLS signature over msg
+func (s *BLSSigner) Sign(msg []byte) ([]byte, error) {
+	return s.sign(msg, blsClaimDST)
+}
+
+// ProvePossession signs the signer's public key, proving to VerifyBLSPossession that it holds the
+// secret key, as each validator must before its signatures are aggregated
+func (s *BLSSigner) ProvePossession() ([]byte, error) {
+	return s.sign(s.PublicKey(), blsPossessionDST)
+}
+
+// sign returns the signature over msg hashed to G2 with dst
+func (s *BLSSigner) sign(msg, dst []byte) ([]byte, error) {
+	point, err := hashToG2(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	g2 := bls12381.NewG2()
+	return g2.ToBytes(g2.MulScalar(g2.New(), point, s.secret)), nil
+}
+
+// VerifyBLS reports whether sig is publicKey's BLS signature over msg
+func VerifyBLS(publicKey, msg, sig []byte) (bool, error) {
+	return verifyBLS(publicKey, msg, sig, blsClaimDST)
+}
+
+// VerifyBLSPossession reports whether proof proves possession of publicKey's secret key
+func VerifyBLSPossession(publicKey, proof []byte) (bool, error) {
+	return verifyBLS(publicKey, publicKey, proof, blsPossessionDST)
+}
+
+// AggregateBLSSignatures aggregates signatures into one, which verifies with VerifyAggregateBLS
+// against the aggregate of their signers' public keys
+func AggregateBLSSignatures(sigs ...[]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("no BLS signatures to aggregate")
+	}
+	g2 := bls12381.NewG2()
+	aggregate := g2.Zero()
+	for i, sig := range sigs {
+		point, err := decodeBLSSignature(sig)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+		g2.Add(aggregate, aggregate, point)
+	}
+	return g2.ToBytes(aggregate), nil
+}
+
+// AggregateBLSPublicKeys aggregates publicKeys into the key their aggregated signatures over one
+// message verify against
+func AggregateBLSPublicKeys(publicKeys ...[]byte) ([]byte, error) {
+	aggregate, err := aggregateBLSPublicKeys(publicKeys)
+	if err != nil {
+		return nil, err
+	}
+	return bls12381.NewG1().ToBytes(aggregate), nil
+}
+
+// VerifyAggregateBLS reports whether aggregate is the aggregated BLS signature of every one of
+// publicKeys over msg, such as a claim attested by several validators. Each key's possession must
+// have been checked with VerifyBLSPossession, or a rogue key could forge the aggregate.
+func VerifyAggregateBLS(publicKeys [][]byte, msg, aggregate []byte) (bool, error) {
+	key, err := aggregateBLSPublicKeys(publicKeys)
+	if err != nil {
+		return false, err
+	}
+	return verifyBLS(bls12381.NewG1().ToBytes(key), msg, aggregate, blsClaimDST)
+}
+
+// aggregateBLSPublicKeys sums publicKeys
+func aggregateBLSPublicKeys(publicKeys [][]byte) (*bls12381.PointG1, error) {
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no BLS public keys to aggregate")
+	}
+	g1 := bls12381.NewG1()
+	aggregate := g1.Zero()
+	for i, publicKey := range publicKeys {
+		point, err := decodeBLSPublicKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %w", i, err)
+		}
+		g1.Add(aggregate, aggregate, point)
+	}
+	return aggregate, nil
+}
+
+// verifyBLS checks e(publicKey, H(msg)) == e(G1, sig), with msg hashed to G2 with dst
+func verifyBLS(publicKey, msg, sig, dst []byte) (bool, error) {
+	key, err := decodeBLSPublicKey(publicKey)
+	if err != nil {
+		return false, err
+	}
+	signature, err := decodeBLSSignature(sig)
+	if err != nil {
+		return false, err
+	}
+	point, err := hashToG2(msg, dst)
+	if err != nil {
+		return false, err
+	}
+	engine := bls12381.NewPairingEngine()
+	return engine.AddPair(key, point).AddPairInv(engine.G1.One(), signature).Check(), nil
+}
+
+// decodeBLSPublicKey decodes an uncompressed G1 point of the prime-order subgroup, other than
+// the identity
+func decodeBLSPublicKey(publicKey []byte) (*bls12381.PointG1, error) {
+	if len(publicKey) != BLSPublicKeyLength {
+		return nil, fmt.Errorf("%w: public key is %d bytes, expected %d", ErrInvalidBLSPoint, len(publicKey), BLSPublicKeyLength)
+	}
+	g1 := bls12381.NewG1()
+	point, err := g1.FromBytes(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBLSPoint, err)
+	}
+	if g1.IsZero(point) || !g1.InCorrectSubgroup(point) {
+		return nil, fmt.Errorf("%w: public key is not in the G1 subgroup", ErrInvalidBLSPoint)
+	}
+	return point, nil
+}
+
+// decodeBLSSignature decodes an uncompressed G2 point of the prime-order subgroup
+func decodeBLSSignature(sig []byte) (*bls12381.PointG2, error) {
+	if len(sig) != BLSSignatureLength {
+		return nil, fmt.Errorf("%w: signature is %d bytes, expected %d", ErrInvalidBLSPoint, len(sig), BLSSignatureLength)
+	}
+	g2 := bls12381.NewG2()
+	point, err := g2.FromBytes(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBLSPoint, err)
+	}
+	if !g2.InCorrectSubgroup(point) {
+		return nil, fmt.Errorf("%w: signature is not in the G2 subgroup", ErrInvalidBLSPoint)
+	}
+	return point, nil
+}
+
+// hashToG2 hashes msg to a G2 point: two Fp2 elements are derived with expand_message_xmd over
+// SHA-256 and dst, mapped to the curve and added
+func hashToG2(msg, dst []byte) (*bls12381.PointG2, error) {
+	uniform, err := expandMessageXMD(msg, dst, 4*64)
+	if err != nil {
+		return nil, err
+	}
+
+	g2 := bls12381.NewG2()
+	point := g2.Zero()
+	for i := 0; i < 2; i++ {
+		// The element c0 + c1*u is encoded as c1 || c0
+		c0 := new(big.Int).Mod(new(big.Int).SetBytes(uniform[128*i:128*i+64]), blsFieldModulus)
+		c1 := new(big.Int).Mod(new(big.Int).SetBytes(uniform[128*i+64:128*i+128]), blsFieldModulus)
+		mapped, err := g2.MapToCurve(append(common.LeftPadBytes(c1.Bytes(), 48), common.LeftPadBytes(c0.Bytes(), 48)...))
+		if err != nil {
+			return nil, err
+		}
+		g2.Add(point, point, mapped)
+	}
+	return g2.Affine(point), nil
+}
+
+// expandMessageXMD is expand_message_xmd of the hash-to-curve specification over SHA-256,
+// returning length uniformly random bytes derived from msg and dst
+func expandMessageXMD(msg, dst []byte, length int) ([]byte, error) {
+	blocks := (length + sha256.Size - 1) / sha256.Size
+	if blocks > 255 || len(dst) > 255 || length > 65535 {
+		return nil, errors.New("expand_message_xmd input too long")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	hasher := sha256.New()
+	hasher.Write(make([]byte, sha256.BlockSize))
+	hasher.Write(msg)
+	hasher.Write([]byte{byte(length >> 8), byte(length), 0})
+	hasher.Write(dstPrime)
+	b0 := hasher.Sum(nil)
+
+	out := make([]byte, 0, blocks*sha256.Size)
+	previous := make([]byte, sha256.Size)
+	for i := 1; i <= blocks; i++ {
+		input := make([]byte, sha256.Size)
+		for j := range input {
+			input[j] = b0[j] ^ previous[j]
+		}
+		hasher.Reset()
+		hasher.Write(input)
+		hasher.Write([]byte{byte(i)})
+		hasher.Write(dstPrime)
+		previous = hasher.Sum(nil)
+		out = append(out, previous...)
+	}
+	return out[:length], nil
+}
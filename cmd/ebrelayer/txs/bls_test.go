@@ -0,0 +1,174 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+// testBLSSigners returns n BLSSigners with the secret keys 1 to n
+func testBLSSigners(t *testing.T, n int) []*BLSSigner {
+	signers := make([]*BLSSigner, n)
+	for i := range signers {
+		signer, err := NewBLSSigner(common.LeftPadBytes([]byte{byte(i + 1)}, BLSSecretKeyLength))
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers[i] = signer
+	}
+	return signers
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	// The expand_message_xmd SHA-256 vectors of RFC 9380, appendix K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	tests := []struct {
+		msg    string
+		length int
+		want   string
+	}{
+		{"", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"abcdef0123456789", 0x20, "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+	}
+	for _, tt := range tests {
+		got, err := expandMessageXMD([]byte(tt.msg), dst, tt.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Fatalf("expand_message_xmd(%q, %d) = %x, want %s", tt.msg, tt.length, got, tt.want)
+		}
+	}
+	if _, err := expandMessageXMD(nil, dst, 256*32); err == nil {
+		t.Fatal("expanded to more than 255 blocks")
+	}
+}
+
+func TestBLSSignVerify(t *testing.T) {
+	signer := testBLSSigners(t, 1)[0]
+	g1 := bls12381.NewG1()
+	if !bytes.Equal(signer.PublicKey(), g1.ToBytes(g1.One())) {
+		t.Fatal("public key of secret key 1 isn't the G1 generator")
+	}
+	if len(signer.PublicKey()) != BLSPublicKeyLength {
+		t.Fatalf("public key is %d bytes, want %d", len(signer.PublicKey()), BLSPublicKeyLength)
+	}
+	if signer.Address() != common.BytesToAddress(Keccak256(signer.PublicKey())[12:]) {
+		t.Fatal("address isn't derived from the public key")
+	}
+
+	msg := PrefixMsg(common.Hex2Bytes(goldenClaim.message))
+	sig, err := signer.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig) != BLSSignatureLength {
+		t.Fatalf("signature is %d bytes, want %d", len(sig), BLSSignatureLength)
+	}
+	if ok, err := VerifyBLS(signer.PublicKey(), msg, sig); err != nil || !ok {
+		t.Fatalf("VerifyBLS = %v, %v, want the signature valid", ok, err)
+	}
+	if ok, err := VerifyBLS(signer.PublicKey(), PrefixMsg(Keccak256([]byte("other claim"))), sig); err != nil || ok {
+		t.Fatalf("VerifyBLS of another message = %v, %v, want it rejected", ok, err)
+	}
+	other := testBLSSigners(t, 2)[1]
+	if ok, err := VerifyBLS(other.PublicKey(), msg, sig); err != nil || ok {
+		t.Fatalf("VerifyBLS by another key = %v, %v, want it rejected", ok, err)
+	}
+
+	// A proof of possession isn't a claim signature over the public key, nor the other way round
+	proof, err := signer.ProvePossession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyBLSPossession(signer.PublicKey(), proof); err != nil || !ok {
+		t.Fatalf("VerifyBLSPossession = %v, %v, want the proof valid", ok, err)
+	}
+	if ok, _ := VerifyBLS(signer.PublicKey(), signer.PublicKey(), proof); ok {
+		t.Fatal("proof of possession verifies as a claim signature")
+	}
+	if ok, _ := VerifyBLSPossession(other.PublicKey(), proof); ok {
+		t.Fatal("proof of possession verifies for another key")
+	}
+}
+
+func TestBLSAggregateVerify(t *testing.T) {
+	signers := testBLSSigners(t, 3)
+	msg := PrefixMsg(common.Hex2Bytes(goldenClaim.message))
+	sigs := make([][]byte, len(signers))
+	publicKeys := make([][]byte, len(signers))
+	for i, signer := range signers {
+		sig, err := signer.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[i], publicKeys[i] = sig, signer.PublicKey()
+	}
+
+	aggregate, err := AggregateBLSSignatures(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyAggregateBLS(publicKeys, msg, aggregate); err != nil || !ok {
+		t.Fatalf("VerifyAggregateBLS = %v, %v, want the aggregate valid", ok, err)
+	}
+	// The aggregate of the keys verifies the aggregate signature as a single one
+	aggregateKey, err := AggregateBLSPublicKeys(publicKeys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyBLS(aggregateKey, msg, aggregate); err != nil || !ok {
+		t.Fatalf("VerifyBLS of the aggregate key = %v, %v", ok, err)
+	}
+
+	if ok, _ := VerifyAggregateBLS(publicKeys[:2], msg, aggregate); ok {
+		t.Fatal("aggregate verifies with a validator's key left out")
+	}
+	partial, err := AggregateBLSSignatures(sigs[:2]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := VerifyAggregateBLS(publicKeys, msg, partial); ok {
+		t.Fatal("aggregate of two signatures verifies for three validators")
+	}
+}
+
+func TestBLSInvalidInputs(t *testing.T) {
+	// Secret keys must be in [1, q)
+	for _, secret := range [][]byte{make([]byte, BLSSecretKeyLength), common.LeftPadBytes(bls12381.NewG1().Q().Bytes(), BLSSecretKeyLength)} {
+		if _, err := NewBLSSigner(secret); err == nil {
+			t.Fatalf("NewBLSSigner(%x) succeeded", secret)
+		}
+	}
+	if _, err := NewBLSSigner([]byte{1}); err == nil {
+		t.Fatal("NewBLSSigner of a short key succeeded")
+	}
+
+	signer := testBLSSigners(t, 1)[0]
+	sig, err := signer.Sign([]byte("claim"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyBLS(signer.PublicKey()[1:], []byte("claim"), sig); !errors.Is(err, ErrInvalidBLSPoint) {
+		t.Fatalf("VerifyBLS of a truncated key = %v, want ErrInvalidBLSPoint", err)
+	}
+	if _, err := VerifyBLS(signer.PublicKey(), []byte("claim"), make([]byte, BLSSignatureLength-1)); !errors.Is(err, ErrInvalidBLSPoint) {
+		t.Fatalf("VerifyBLS of a truncated signature = %v, want ErrInvalidBLSPoint", err)
+	}
+	if _, err := AggregateBLSSignatures(); err == nil {
+		t.Fatal("aggregated no signatures")
+	}
+
+	generated, err := GenerateBLSSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generated.Address() == signer.Address() {
+		t.Fatal("generated the secret key 1")
+	}
+}
@@ -0,0 +1,183 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultBreakerCooldown is how long a tripped CircuitBreaker refuses submissions before letting
+// one through to probe whether claims still revert
+const DefaultBreakerCooldown = 10 * time.Minute
+
+// ErrCircuitOpen is returned in place of submitting a claim while a chain's CircuitBreaker is open
+var ErrCircuitOpen = errors.New("claim submission circuit open after consecutive reverts")
+
+// EthSubmitBreaker and HmySubmitBreaker, if set, stop claim submission to their chain after
+// consecutive on-chain reverts
+var (
+	EthSubmitBreaker *CircuitBreaker
+	HmySubmitBreaker *CircuitBreaker
+)
+
+// IsRevertError reports whether err is a claim transaction rejected by the contracts, having been
+// mined but failed or refused by the node's execution, rather than a network or node error
+func IsRevertError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrTxReverted) || errors.Is(err, ErrBatchReverted) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "execution reverted")
+}
+
+// CircuitBreaker counts consecutive reverted claim submissions to Chain. After Threshold of them
+// it trips open, raising a critical alert and refusing submissions with ErrCircuitOpen so the
+// relayer stops spending gas on transactions the contracts will reject, as when a contract is
+// paused. Once Cooldown passes it lets a single submission through: if that one is mined the
+// breaker closes, and if it reverts the breaker opens again. Network errors neither count towards
+// tripping it nor reset the count. It is safe for concurrent use.
+type CircuitBreaker struct {
+	Chain     string
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	clock    func() time.Time
+	reverts  int
+	open     bool
+	openedAt time.Time
+	probing  bool
+	// changed is closed when Record next runs, waking Wait, and nil until Wait needs it
+	changed chan struct{}
+}
+
+// NewCircuitBreaker initializes a new, closed CircuitBreaker tripping after threshold consecutive
+// reverts on chain, and probing again after cooldown
+func NewCircuitBreaker(chain string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Chain: chain, Threshold: threshold, Cooldown: cooldown, clock: time.Now}
+}
+
+// Allow returns ErrCircuitOpen, marked as not worth retrying, unless a submission may be sent: the
+// breaker is closed, or its cooldown has passed and no other probing submission is outstanding
+func (b *CircuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return nil
+	}
+	if remaining := b.Cooldown - b.clock().Sub(b.openedAt); remaining > 0 {
+		return Permanent(fmt.Errorf("%w: %s, retrying in %s", ErrCircuitOpen, b.Chain, remaining.Round(time.Second)))
+	}
+	if b.probing {
+		return Permanent(fmt.Errorf("%w: %s, probing", ErrCircuitOpen, b.Chain))
+	}
+	b.probing = true
+	getLogger().Warn("Claim submission circuit half-open, probing with one submission", "chain", b.Chain)
+	return nil
+}
+
+// Record records the outcome of a submission which Allow let through: nil once it was mined, or
+// the error it failed with
+func (b *CircuitBreaker) Record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	probe := b.probing
+	b.probing = false
+	if b.changed != nil {
+		close(b.changed)
+		b.changed = nil
+	}
+	switch {
+	case err == nil:
+		if b.open {
+			getLogger().Info("Claim submission circuit closed, probe was mined", "chain", b.Chain)
+		}
+		b.reverts, b.open = 0, false
+	case IsRevertError(err):
+		b.reverts++
+		if probe || (!b.open && b.reverts >= b.Threshold) {
+			b.open, b.openedAt = true, b.clock()
+			getLogger().Error("CRITICAL: claim submission halted after consecutive reverts, check the contracts",
+				"chain", b.Chain, "reverts", b.reverts, "cooldown", b.Cooldown, "err", err)
+		}
+	}
+}
+
+// Wait blocks until Allow may let a submission through: the breaker is closed, or its cooldown
+// has passed and no probe is outstanding. It returns ctx.Err() if ctx is done first.
+func (b *CircuitBreaker) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		remaining := b.Cooldown - b.clock().Sub(b.openedAt)
+		if !b.open || (remaining <= 0 && !b.probing) {
+			b.mu.Unlock()
+			return nil
+		}
+		if b.changed == nil {
+			b.changed = make(chan struct{})
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		// While a probe is outstanding only its Record frees the breaker
+		var cooled <-chan time.Time
+		var timer *time.Timer
+		if remaining > 0 {
+			timer = time.NewTimer(remaining)
+			cooled = timer.C
+		}
+		select {
+		case <-changed:
+		case <-cooled:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Open reports whether the breaker is refusing submissions, or letting only a probe through
+func (b *CircuitBreaker) Open() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.open
+}
+
+// watchReverts records with breaker, if set, whether txHash is mined or reverts once status finds
+// its receipt. A transaction not mined within DefaultBatchReceiptTimeout is recorded as a network
+// error, which only frees the breaker to probe again.
+func watchReverts(breaker *CircuitBreaker, txHash common.Hash,
+	status func(ctx context.Context, txHash common.Hash) (uint64, error)) {
+	if breaker == nil {
+		return
+	}
+	go func() {
+		breaker.Record(waitReceipt(context.Background(), txHash, status))
+	}()
+}
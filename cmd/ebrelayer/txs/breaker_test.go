@@ -0,0 +1,152 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for CircuitBreaker cooldowns
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1600000000, 0)}
+	breaker := NewCircuitBreaker(ethereumChainLabel, threshold, cooldown)
+	breaker.clock = clock.Now
+	return breaker, clock
+}
+
+func TestCircuitBreakerTripsAndCloses(t *testing.T) {
+	breaker, clock := newTestBreaker(3, time.Minute)
+	reverted := errors.New("execution reverted: paused")
+
+	for i := 0; i < 3; i++ {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("Allow after %d reverts = %v, want nil", i, err)
+		}
+		breaker.Record(reverted)
+	}
+	if !breaker.Open() {
+		t.Fatal("breaker still closed after 3 consecutive reverts")
+	}
+	err := breaker.Allow()
+	if !errors.Is(err, ErrCircuitOpen) || IsRetryableError(err) {
+		t.Fatalf("Allow while open = %v, want a permanent ErrCircuitOpen", err)
+	}
+
+	// After the cooldown a single probe is let through, and its success closes the breaker
+	clock.Advance(time.Minute)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow after cooldown = %v, want the probe let through", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second Allow while probing = %v, want ErrCircuitOpen", err)
+	}
+	breaker.Record(nil)
+	if breaker.Open() {
+		t.Fatal("breaker still open after the probe was mined")
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow once closed = %v", err)
+	}
+}
+
+func TestCircuitBreakerProbeRevertReopens(t *testing.T) {
+	breaker, clock := newTestBreaker(1, time.Minute)
+	breaker.Record(ErrTxReverted)
+	clock.Advance(time.Minute)
+
+	if err := breaker.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	breaker.Record(ErrTxReverted)
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow after a reverted probe = %v, want the cooldown restarted", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresNetworkErrors(t *testing.T) {
+	breaker, _ := newTestBreaker(2, time.Minute)
+	breaker.Record(ErrTxReverted)
+	breaker.Record(errors.New("i/o timeout"))
+	if breaker.Open() {
+		t.Fatal("a network error counted towards tripping the breaker")
+	}
+	breaker.Record(ErrBatchReverted)
+	if !breaker.Open() {
+		t.Fatal("breaker closed after 2 reverts separated only by a network error")
+	}
+}
+
+func TestCircuitBreakerWaitHoldsUntilProbeRecorded(t *testing.T) {
+	breaker, clock := newTestBreaker(1, time.Minute)
+	breaker.Record(ErrTxReverted)
+	clock.Advance(time.Minute)
+	if err := breaker.Allow(); err != nil {
+		t.Fatal(err)
+	}
+
+	released := make(chan error, 1)
+	go func() {
+		released <- breaker.Wait(context.Background())
+	}()
+	select {
+	case err := <-released:
+		t.Fatalf("Wait returned %v while a probe was outstanding", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	breaker.Record(nil)
+	select {
+	case err := <-released:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait still blocked after the probe closed the breaker")
+	}
+}
+
+func TestCircuitBreakerWaitContextDone(t *testing.T) {
+	breaker, _ := newTestBreaker(1, time.Hour)
+	breaker.Record(ErrTxReverted)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := breaker.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait = %v, want the context's error", err)
+	}
+}
+
+func TestNilCircuitBreaker(t *testing.T) {
+	var breaker *CircuitBreaker
+	if err := breaker.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	breaker.Record(ErrTxReverted)
+	if breaker.Open() {
+		t.Fatal("nil breaker open")
+	}
+	if err := breaker.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
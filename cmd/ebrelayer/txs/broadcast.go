@@ -0,0 +1,491 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultBroadcastPollInterval is how often a broadcast transaction's receipt is polled
+	DefaultBroadcastPollInterval = 5 * time.Second
+	// DefaultGasBump is the fraction a dropped or stuck transaction's gas price is raised by when
+	// it is re-broadcast, above the MinGasBump nodes require to replace a transaction
+	DefaultGasBump = 0.125
+	// MinGasBump is the least fraction nodes accept a replacement transaction's gas price being
+	// raised by, which a smaller GasBump is raised to
+	MinGasBump = 0.10
+	// DefaultMaxRebroadcasts is how many times a dropped transaction is re-broadcast
+	DefaultMaxRebroadcasts = 3
+)
+
+var (
+	// ErrTxDropped is returned when a transaction fell out of the mempool and can't be re-broadcast
+	ErrTxDropped = errors.New("transaction dropped from the mempool")
+	// ErrTxReverted is returned when a transaction was mined but failed
+	ErrTxReverted = errors.New("transaction reverted")
+	// ErrTxNotPending is returned when speeding up or cancelling a transaction that isn't pending
+	ErrTxNotPending = errors.New("transaction not pending")
+	// ErrTxCancelled is returned when the self-transaction cancelling a transaction was mined in
+	// its place
+	ErrTxCancelled = errors.New("transaction cancelled")
+)
+
+// cancelGas is the gas limit of a cancelling self-transaction, that of a plain transfer
+const cancelGas = 21000
+
+// EthTxWatch, if set, watches each claim transaction submitted to Ethereum until it is mined,
+// re-broadcasting it with a bumped gas price if it drops out of the mempool, or, with SpeedUpAfter
+// set, is stuck in it
+var EthTxWatch *BroadcastPolicy
+
+// TxStatus is a broadcast transaction's last observed state
+type TxStatus int
+
+const (
+	// TxPending is a transaction sent and not yet mined
+	TxPending TxStatus = iota
+	// TxMined is a transaction included in a block
+	TxMined
+	// TxDropped is a transaction neither mined nor known to the node's mempool
+	TxDropped
+)
+
+// String implements fmt.Stringer
+func (s TxStatus) String() string {
+	return [...]string{"pending", "mined", "dropped"}[s]
+}
+
+// BroadcastClient sends transactions and reports on them
+type BroadcastClient interface {
+	SendTransaction(ctx context.Context, tx *ctypes.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ctypes.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*ctypes.Transaction, bool, error)
+}
+
+// TxSigner signs a transaction to re-broadcast
+type TxSigner func(tx *ctypes.Transaction) (*ctypes.Transaction, error)
+
+// BroadcastPolicy paces how a broadcast transaction is watched. A zero PollInterval, GasBump or
+// MaxRebroadcasts uses its default, and a zero Timeout waits until the caller's context is done.
+// A transaction still pending SpeedUpAfter it was sent is sped up, unless SpeedUpAfter is zero.
+type BroadcastPolicy struct {
+	PollInterval    time.Duration
+	Timeout         time.Duration
+	GasBump         float64
+	MaxRebroadcasts int
+	SpeedUpAfter    time.Duration
+}
+
+// Broadcaster sends transactions through Client and tracks them until they are mined, re-signing
+// dropped or stuck transactions with Signer at a bumped gas price
+type Broadcaster struct {
+	Client BroadcastClient
+	Signer TxSigner
+	Policy BroadcastPolicy
+
+	mu      sync.Mutex
+	handles map[common.Hash]*BroadcastHandle
+}
+
+// NewBroadcaster initializes a new Broadcaster
+func NewBroadcaster(client BroadcastClient, signer TxSigner, policy BroadcastPolicy) *Broadcaster {
+	if policy.PollInterval == 0 {
+		policy.PollInterval = DefaultBroadcastPollInterval
+	}
+	if policy.GasBump == 0 {
+		policy.GasBump = DefaultGasBump
+	}
+	if policy.MaxRebroadcasts == 0 {
+		policy.MaxRebroadcasts = DefaultMaxRebroadcasts
+	}
+	return &Broadcaster{Client: client, Signer: signer, Policy: policy, handles: make(map[common.Hash]*BroadcastHandle)}
+}
+
+// TransactOptsSigner returns a TxSigner re-signing transactions as the bindings sign them with opts
+func TransactOptsSigner(opts *bind.TransactOpts) TxSigner {
+	return func(tx *ctypes.Transaction) (*ctypes.Transaction, error) {
+		return opts.Signer(ctypes.HomesteadSigner{}, opts.From, tx)
+	}
+}
+
+// Broadcast sends tx and returns a handle tracking it
+func (b *Broadcaster) Broadcast(ctx context.Context, tx *ctypes.Transaction) (*BroadcastHandle, error) {
+	if err := b.Client.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	return b.Track(tx), nil
+}
+
+// Track returns a handle tracking tx, which was already sent
+func (b *Broadcaster) Track(tx *ctypes.Transaction) *BroadcastHandle {
+	handle := &BroadcastHandle{broadcaster: b, tx: tx, hashes: []common.Hash{tx.Hash()}, sentAt: time.Now()}
+	b.register(tx.Hash(), handle)
+	return handle
+}
+
+// Tracked returns the handle tracking the transaction with txHash among its versions, if any
+func (b *Broadcaster) Tracked(txHash common.Hash) (*BroadcastHandle, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	handle, ok := b.handles[txHash]
+	return handle, ok
+}
+
+// register indexes handle by txHash, one of its transaction's versions
+func (b *Broadcaster) register(txHash common.Hash, handle *BroadcastHandle) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.handles == nil {
+		b.handles = make(map[common.Hash]*BroadcastHandle)
+	}
+	b.handles[txHash] = handle
+}
+
+// SpeedUp replaces the pending transaction with txHash by one with the same nonce at a gas price
+// raised by Policy.GasBump, returning the handle tracking both. A transaction the Broadcaster
+// doesn't track yet is looked up and tracked from then on. It returns ErrTxNotPending if the
+// transaction was already mined or isn't known to the node.
+func (b *Broadcaster) SpeedUp(ctx context.Context, txHash common.Hash) (*BroadcastHandle, error) {
+	handle, ok := b.Tracked(txHash)
+	if !ok {
+		tx, pending, err := b.Client.TransactionByHash(ctx, txHash)
+		if err == ethereum.NotFound || (err == nil && !pending) {
+			return nil, fmt.Errorf("%w: %s", ErrTxNotPending, txHash.Hex())
+		}
+		if err != nil {
+			return nil, err
+		}
+		handle = b.Track(tx)
+	}
+	if _, err := handle.SpeedUp(ctx); err != nil {
+		return nil, err
+	}
+	return handle, nil
+}
+
+// CancelPending replaces the pending transaction the Broadcaster tracks at nonce by a zero-value
+// self-transaction at a gas price raised by Policy.GasBump, voiding it if the cancellation is mined
+// first. It returns the handle tracking both, or ErrTxNotPending if one of the transaction's
+// versions was already mined.
+func (b *Broadcaster) CancelPending(ctx context.Context, nonce uint64) (*BroadcastHandle, error) {
+	// Handles lock themselves before the Broadcaster, so they are only locked after it's released
+	b.mu.Lock()
+	handles := make([]*BroadcastHandle, 0, len(b.handles))
+	for _, tracked := range b.handles {
+		handles = append(handles, tracked)
+	}
+	b.mu.Unlock()
+
+	var handle *BroadcastHandle
+	for _, tracked := range handles {
+		if tracked.Nonce() == nonce {
+			handle = tracked
+			break
+		}
+	}
+
+	if handle == nil {
+		return nil, fmt.Errorf("%w: no transaction tracked at nonce %d", ErrTxNotPending, nonce)
+	}
+	if err := handle.Cancel(ctx); err != nil {
+		return nil, err
+	}
+	return handle, nil
+}
+
+// BroadcastHandle tracks a broadcast transaction and its re-broadcasts. It is safe for
+// concurrent use.
+type BroadcastHandle struct {
+	broadcaster *Broadcaster
+
+	mu           sync.Mutex
+	tx           *ctypes.Transaction
+	hashes       []common.Hash
+	status       TxStatus
+	receipt      *ctypes.Receipt
+	rebroadcasts int
+	sentAt       time.Time
+	cancel       *common.Hash
+}
+
+// Nonce returns the nonce every version of the transaction is sent at
+func (h *BroadcastHandle) Nonce() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.tx.Nonce()
+}
+
+// Hash returns the hash of the transaction last sent
+func (h *BroadcastHandle) Hash() common.Hash {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.tx.Hash()
+}
+
+// Status returns the transaction's status as of the last poll
+func (h *BroadcastHandle) Status() TxStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.status
+}
+
+// Hashes returns the hashes of every version of the transaction sent, oldest first
+func (h *BroadcastHandle) Hashes() []common.Hash {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]common.Hash(nil), h.hashes...)
+}
+
+// Rebroadcasts returns how many times the transaction was re-broadcast
+func (h *BroadcastHandle) Rebroadcasts() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.rebroadcasts
+}
+
+// Refresh polls for the receipt of every version of the transaction sent, since any of them may
+// be the one mined. If none is mined and the last is no longer in the mempool, or has been pending
+// for Policy.SpeedUpAfter, it is re-broadcast at a bumped gas price, returning ErrTxDropped once
+// Policy.MaxRebroadcasts is exhausted.
+func (h *BroadcastHandle) Refresh(ctx context.Context) (TxStatus, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client := h.broadcaster.Client
+	for _, hash := range h.hashes {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		if err == nil && receipt != nil {
+			h.status, h.receipt = TxMined, receipt
+			return h.status, nil
+		}
+		if err != nil && err != ethereum.NotFound {
+			return h.status, err
+		}
+	}
+
+	_, _, err := client.TransactionByHash(ctx, h.tx.Hash())
+	if err == nil {
+		h.status = TxPending
+		policy := h.broadcaster.Policy
+		if policy.SpeedUpAfter > 0 && time.Since(h.sentAt) >= policy.SpeedUpAfter &&
+			h.rebroadcasts < policy.MaxRebroadcasts && h.broadcaster.Signer != nil {
+			if err := h.replace(ctx, "Sped up stuck transaction"); err != nil {
+				return h.status, err
+			}
+		}
+		return h.status, nil
+	}
+	if err != ethereum.NotFound {
+		return h.status, err
+	}
+
+	h.status = TxDropped
+	if h.rebroadcasts >= h.broadcaster.Policy.MaxRebroadcasts || h.broadcaster.Signer == nil {
+		return h.status, fmt.Errorf("%w: %s after %d re-broadcasts", ErrTxDropped, h.tx.Hash().Hex(), h.rebroadcasts)
+	}
+	if err := h.replace(ctx, "Re-broadcast dropped transaction"); err != nil {
+		return h.status, err
+	}
+	h.status = TxPending
+	return h.status, nil
+}
+
+// SpeedUp replaces the transaction last sent by one with the same nonce at a gas price raised by
+// Policy.GasBump, and returns it. The handle then waits on either version being mined.
+func (h *BroadcastHandle) SpeedUp(ctx context.Context) (*ctypes.Transaction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.status == TxMined {
+		return nil, fmt.Errorf("%w: %s was mined", ErrTxNotPending, h.receipt.TxHash.Hex())
+	}
+	if h.broadcaster.Signer == nil {
+		return nil, fmt.Errorf("can't speed up %s without a signer", h.tx.Hash().Hex())
+	}
+	if err := h.replace(ctx, "Sped up transaction"); err != nil {
+		return nil, err
+	}
+	return h.tx, nil
+}
+
+// Cancel replaces the transaction by a zero-value transaction from its sender to itself at the
+// same nonce and a gas price raised by Policy.GasBump. It first checks every version sent for a
+// receipt, returning ErrTxNotPending if one was mined, and WaitMined returns ErrTxCancelled if the
+// cancellation is mined instead.
+func (h *BroadcastHandle) Cancel(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.broadcaster.Signer == nil {
+		return fmt.Errorf("can't cancel %s without a signer", h.tx.Hash().Hex())
+	}
+	for _, hash := range h.hashes {
+		receipt, err := h.broadcaster.Client.TransactionReceipt(ctx, hash)
+		if err == nil && receipt != nil {
+			h.status, h.receipt = TxMined, receipt
+			return fmt.Errorf("%w: %s was mined", ErrTxNotPending, hash.Hex())
+		}
+		if err != nil && err != ethereum.NotFound {
+			return err
+		}
+	}
+
+	tx := h.tx
+	sender, err := txSender(tx)
+	if err != nil {
+		return err
+	}
+	bumped := bumpGasPrice(tx.GasPrice(), h.broadcaster.Policy.GasBump)
+	signed, err := h.broadcaster.Signer(ctypes.NewTransaction(tx.Nonce(), sender, big.NewInt(0), cancelGas, bumped, nil))
+	if err != nil {
+		return err
+	}
+	if err := h.broadcaster.Client.SendTransaction(ctx, signed); err != nil {
+		return err
+	}
+
+	getLogger().Warn("Cancelled transaction", "cancelled", tx.Hash().Hex(), "tx", signed.Hash().Hex(),
+		"nonce", tx.Nonce(), "gasPrice", bumped)
+	cancel := signed.Hash()
+	h.tx, h.cancel = signed, &cancel
+	h.hashes = append(h.hashes, cancel)
+	h.status = TxPending
+	h.sentAt = time.Now()
+	h.broadcaster.register(cancel, h)
+	return nil
+}
+
+// replace re-signs and sends the transaction with its nonce at a gas price raised by
+// Policy.GasBump, logging msg
+func (h *BroadcastHandle) replace(ctx context.Context, msg string) error {
+	tx := h.tx
+	if tx.To() == nil {
+		return fmt.Errorf("can't replace %s: it creates a contract", tx.Hash().Hex())
+	}
+	bumped := bumpGasPrice(tx.GasPrice(), h.broadcaster.Policy.GasBump)
+	signed, err := h.broadcaster.Signer(ctypes.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), bumped, tx.Data()))
+	if err != nil {
+		return err
+	}
+	if err := h.broadcaster.Client.SendTransaction(ctx, signed); err != nil {
+		return err
+	}
+
+	getLogger().Warn(msg, "replaced", tx.Hash().Hex(), "tx", signed.Hash().Hex(), "nonce", tx.Nonce(),
+		"gasPrice", bumped)
+	if h.cancel != nil {
+		cancel := signed.Hash()
+		h.cancel = &cancel
+	}
+	h.tx = signed
+	h.hashes = append(h.hashes, signed.Hash())
+	h.rebroadcasts++
+	h.sentAt = time.Now()
+	h.broadcaster.register(signed.Hash(), h)
+	return nil
+}
+
+// txSender recovers the account that signed tx
+func txSender(tx *ctypes.Transaction) (common.Address, error) {
+	if tx.Protected() {
+		return ctypes.Sender(ctypes.NewEIP155Signer(tx.ChainId()), tx)
+	}
+	return ctypes.Sender(ctypes.HomesteadSigner{}, tx)
+}
+
+// WaitMined polls the transaction every Policy.PollInterval until one of its versions is mined,
+// returning its receipt, or ErrTxReverted along with it if it failed, or ErrTxCancelled if its
+// cancellation was mined. It gives up after
+// Policy.Timeout, if set, or once ctx is done.
+func (h *BroadcastHandle) WaitMined(ctx context.Context) (*ctypes.Receipt, error) {
+	if timeout := h.broadcaster.Policy.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for {
+		status, err := h.Refresh(ctx)
+		if errors.Is(err, ErrTxDropped) {
+			return nil, err
+		}
+		if status == TxMined {
+			h.mu.Lock()
+			receipt, cancel := h.receipt, h.cancel
+			h.mu.Unlock()
+			if cancel != nil && receipt.TxHash == *cancel {
+				return receipt, fmt.Errorf("%w: by %s", ErrTxCancelled, receipt.TxHash.Hex())
+			}
+			if receipt.Status == ctypes.ReceiptStatusFailed {
+				return receipt, fmt.Errorf("%w: %s", ErrTxReverted, receipt.TxHash.Hex())
+			}
+			return receipt, nil
+		}
+
+		timer := time.NewTimer(h.broadcaster.Policy.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("waiting for %s: %w", h.Hash().Hex(), ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// bumpGasPrice raises price by the fraction bump to the nearest wei, by at least MinGasBump
+// rounded up and 1 wei
+func bumpGasPrice(price *big.Int, bump float64) *big.Int {
+	if bump < MinGasBump {
+		bump = MinGasBump
+	}
+	// A fraction such as 0.2 isn't exact as a float, so truncating could fall 1 wei short of it
+	product := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(1+bump))
+	bumped, _ := product.Add(product, big.NewFloat(0.5)).Int(nil)
+
+	// Nodes compare the integer price*(100+10)/100, which float rounding may fall short of
+	minimum := new(big.Int).Mul(price, big.NewInt(int64(100+MinGasBump*100)))
+	minimum.Add(minimum, big.NewInt(99)).Div(minimum, big.NewInt(100))
+	if bumped.Cmp(minimum) < 0 {
+		bumped = minimum
+	}
+	if bumped.Cmp(price) <= 0 {
+		bumped = new(big.Int).Add(price, big.NewInt(1))
+	}
+	return bumped
+}
+
+// watchEthTx tracks a claim transaction sent to Ethereum with EthTxWatch, if set, logging whether
+// it was mined
+func watchEthTx(client BroadcastClient, auth *bind.TransactOpts, tx *ctypes.Transaction) {
+	if EthTxWatch == nil {
+		return
+	}
+	handle := NewBroadcaster(client, TransactOptsSigner(auth), *EthTxWatch).Track(tx)
+
+	go func() {
+		receipt, err := handle.WaitMined(context.Background())
+		if err != nil {
+			getLogger().Error("Claim transaction not mined", "tx", handle.Hash().Hex(), "status", handle.Status().String(),
+				"err", err)
+			return
+		}
+		getLogger().Info("Claim transaction mined", "tx", receipt.TxHash.Hex(), "block", receipt.BlockNumber,
+			"rebroadcasts", handle.Rebroadcasts())
+	}()
+}
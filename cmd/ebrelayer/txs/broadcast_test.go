@@ -0,0 +1,358 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testMempool is a BroadcastClient whose mempool and mined receipts tests change
+type testMempool struct {
+	mu      sync.Mutex
+	sent    []*ctypes.Transaction
+	pending map[common.Hash]*ctypes.Transaction
+	mined   map[common.Hash]*ctypes.Receipt
+}
+
+func newTestMempool() *testMempool {
+	return &testMempool{pending: make(map[common.Hash]*ctypes.Transaction), mined: make(map[common.Hash]*ctypes.Receipt)}
+}
+
+func (m *testMempool) SendTransaction(_ context.Context, tx *ctypes.Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = append(m.sent, tx)
+	m.pending[tx.Hash()] = tx
+	return nil
+}
+
+func (m *testMempool) TransactionReceipt(_ context.Context, txHash common.Hash) (*ctypes.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if receipt, ok := m.mined[txHash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (m *testMempool) TransactionByHash(_ context.Context, txHash common.Hash) (*ctypes.Transaction, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tx, ok := m.pending[txHash]; ok {
+		return tx, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+// mine includes the pending transaction with txHash in a block, with status
+func (m *testMempool) mine(txHash common.Hash, status uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.pending, txHash)
+	m.mined[txHash] = &ctypes.Receipt{TxHash: txHash, Status: status, BlockNumber: big.NewInt(100)}
+}
+
+// drop evicts every pending transaction, as a node restarting with an empty mempool does
+func (m *testMempool) drop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending = make(map[common.Hash]*ctypes.Transaction)
+}
+
+// testBroadcast broadcasts a claim transaction signed by testKey through a Broadcaster over
+// mempool, polling every millisecond
+func testBroadcast(t *testing.T, mempool *testMempool, policy BroadcastPolicy) *BroadcastHandle {
+	policy.PollInterval = time.Millisecond
+	signer := TransactOptsSigner(bind.NewKeyedTransactor(testKey(t)))
+	tx, err := signer(ctypes.NewTransaction(7, goldenClaim.recipient, big.NewInt(0), 200000, big.NewInt(1e9),
+		common.Hex2Bytes(goldenClaim.message)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := NewBroadcaster(mempool, signer, policy).Broadcast(context.Background(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handle
+}
+
+func TestBroadcastMined(t *testing.T) {
+	mempool := newTestMempool()
+	handle := testBroadcast(t, mempool, BroadcastPolicy{})
+	if status, err := handle.Refresh(context.Background()); err != nil || status != TxPending {
+		t.Fatalf("Refresh = %s, %v, want pending", status, err)
+	}
+
+	mempool.mine(handle.Hash(), ctypes.ReceiptStatusSuccessful)
+	receipt, err := handle.WaitMined(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.TxHash != handle.Hash() || handle.Status() != TxMined || handle.Rebroadcasts() != 0 {
+		t.Fatalf("mined %s, status %s, %d re-broadcasts", receipt.TxHash.Hex(), handle.Status(), handle.Rebroadcasts())
+	}
+
+	mempool = newTestMempool()
+	reverted := testBroadcast(t, mempool, BroadcastPolicy{})
+	mempool.mine(reverted.Hash(), ctypes.ReceiptStatusFailed)
+	if _, err := reverted.WaitMined(context.Background()); !errors.Is(err, ErrTxReverted) {
+		t.Fatalf("WaitMined of a failed transaction = %v, want ErrTxReverted", err)
+	}
+}
+
+func TestBroadcastDroppedIsRebroadcast(t *testing.T) {
+	mempool := newTestMempool()
+	handle := testBroadcast(t, mempool, BroadcastPolicy{MaxRebroadcasts: 1})
+	original := mempool.sent[0]
+
+	mempool.drop()
+	if status, err := handle.Refresh(context.Background()); err != nil || status != TxPending {
+		t.Fatalf("Refresh of a dropped transaction = %s, %v, want it pending again", status, err)
+	}
+	if len(mempool.sent) != 2 || handle.Rebroadcasts() != 1 {
+		t.Fatalf("sent %d transactions, %d re-broadcasts, want the dropped one sent again", len(mempool.sent), handle.Rebroadcasts())
+	}
+	rebroadcast := mempool.sent[1]
+	if rebroadcast.Nonce() != original.Nonce() || rebroadcast.GasPrice().Cmp(bumpGasPrice(original.GasPrice(), DefaultGasBump)) != 0 {
+		t.Fatalf("re-broadcast nonce %d at %s wei, want nonce %d at a bumped gas price",
+			rebroadcast.Nonce(), rebroadcast.GasPrice(), original.Nonce())
+	}
+
+	// Either version may be the one mined
+	mempool.mine(original.Hash(), ctypes.ReceiptStatusSuccessful)
+	receipt, err := handle.WaitMined(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.TxHash != original.Hash() {
+		t.Fatalf("mined %s, want the original %s", receipt.TxHash.Hex(), original.Hash().Hex())
+	}
+
+	// Once the re-broadcasts are exhausted, a dropped transaction is given up on
+	mempool = newTestMempool()
+	exhausted := testBroadcast(t, mempool, BroadcastPolicy{MaxRebroadcasts: 1})
+	mempool.drop()
+	if _, err := exhausted.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mempool.drop()
+	if _, err := exhausted.WaitMined(context.Background()); !errors.Is(err, ErrTxDropped) {
+		t.Fatalf("WaitMined after the re-broadcasts = %v, want ErrTxDropped", err)
+	}
+}
+
+func TestBroadcastTimeout(t *testing.T) {
+	handle := testBroadcast(t, newTestMempool(), BroadcastPolicy{Timeout: 10 * time.Millisecond})
+	if _, err := handle.WaitMined(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitMined of a transaction never mined = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if handle.Status() != TxPending {
+		t.Fatalf("status %s after timing out, want pending", handle.Status())
+	}
+}
+
+func TestBroadcastSpeedUp(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	mempool := newTestMempool()
+	handle := testBroadcast(t, mempool, BroadcastPolicy{GasBump: 0.2})
+	original := mempool.sent[0]
+
+	tx, err := handle.SpeedUp(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mempool.sent) != 2 || mempool.sent[1].Hash() != tx.Hash() {
+		t.Fatalf("sent %d transactions, want the sped-up one sent", len(mempool.sent))
+	}
+	// The replacement keeps the nonce and the call, at a higher gas price
+	if tx.Nonce() != original.Nonce() || tx.GasPrice().Cmp(original.GasPrice()) <= 0 ||
+		tx.GasPrice().Cmp(big.NewInt(1.2e9)) != 0 {
+		t.Fatalf("sped up to nonce %d at %s wei, want nonce %d above %s wei", tx.Nonce(), tx.GasPrice(),
+			original.Nonce(), original.GasPrice())
+	}
+	if *tx.To() != *original.To() || tx.Value().Cmp(original.Value()) != 0 || string(tx.Data()) != string(original.Data()) ||
+		tx.Gas() != original.Gas() {
+		t.Fatal("sped-up transaction doesn't make the original's call")
+	}
+	if sender, err := txSender(tx); err != nil || sender != crypto.PubkeyToAddress(testKey(t).PublicKey) {
+		t.Fatalf("sped-up transaction signed by %s, %v", sender.Hex(), err)
+	}
+	if tracked, ok := handle.broadcaster.Tracked(tx.Hash()); !ok || tracked != handle || handle.Hash() != tx.Hash() {
+		t.Fatal("sped-up transaction isn't tracked under its new hash")
+	}
+
+	// Whichever version is mined is the one reported
+	mempool.mine(original.Hash(), ctypes.ReceiptStatusSuccessful)
+	receipt, err := handle.WaitMined(context.Background())
+	if err != nil || receipt.TxHash != original.Hash() {
+		t.Fatalf("WaitMined = %v, %v, want the original mined", receipt, err)
+	}
+	if _, err := handle.SpeedUp(context.Background()); !errors.Is(err, ErrTxNotPending) {
+		t.Fatalf("SpeedUp of a mined transaction = %v, want ErrTxNotPending", err)
+	}
+}
+
+func TestBroadcasterSpeedUpUntracked(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	mempool := newTestMempool()
+	// The transaction was sent by another Broadcaster, such as before a restart
+	original := testBroadcast(t, mempool, BroadcastPolicy{}).Hash()
+	broadcaster := NewBroadcaster(mempool, TransactOptsSigner(bind.NewKeyedTransactor(testKey(t))), BroadcastPolicy{})
+
+	handle, err := broadcaster.SpeedUp(context.Background(), original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashes := handle.Hashes(); len(hashes) != 2 || hashes[0] != original {
+		t.Fatalf("tracking %v, want the original and its replacement", hashes)
+	}
+	if sped := mempool.sent[1]; sped.Nonce() != mempool.sent[0].Nonce() ||
+		sped.GasPrice().Cmp(bumpGasPrice(mempool.sent[0].GasPrice(), DefaultGasBump)) != 0 {
+		t.Fatalf("sped up to nonce %d at %s wei, want the same nonce at a bumped price", sped.Nonce(), sped.GasPrice())
+	}
+
+	if _, err := broadcaster.SpeedUp(context.Background(), common.HexToHash("0x1")); !errors.Is(err, ErrTxNotPending) {
+		t.Fatalf("SpeedUp of an unknown transaction = %v, want ErrTxNotPending", err)
+	}
+}
+
+func TestBroadcastSpeedUpAfter(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	mempool := newTestMempool()
+	handle := testBroadcast(t, mempool, BroadcastPolicy{SpeedUpAfter: 5 * time.Millisecond, MaxRebroadcasts: 1})
+
+	if _, err := handle.Refresh(context.Background()); err != nil || len(mempool.sent) != 1 {
+		t.Fatalf("Refresh = %v, sent %d, want the transaction left alone until it's stuck", err, len(mempool.sent))
+	}
+	time.Sleep(5 * time.Millisecond)
+	if status, err := handle.Refresh(context.Background()); err != nil || status != TxPending || len(mempool.sent) != 2 {
+		t.Fatalf("Refresh = %s, %v, sent %d, want the stuck transaction sped up", status, err, len(mempool.sent))
+	}
+	// Speed-ups count against MaxRebroadcasts
+	time.Sleep(5 * time.Millisecond)
+	if _, err := handle.Refresh(context.Background()); err != nil || len(mempool.sent) != 2 || handle.Rebroadcasts() != 1 {
+		t.Fatalf("Refresh = %v, sent %d, want no speed-up beyond MaxRebroadcasts", err, len(mempool.sent))
+	}
+}
+
+func TestBumpGasPrice(t *testing.T) {
+	tests := []struct {
+		price *big.Int
+		bump  float64
+		want  *big.Int
+	}{
+		{big.NewInt(1e9), 0.125, big.NewInt(1.125e9)},
+		// 0.2 is slightly below a fifth as a float
+		{big.NewInt(1e9), 0.2, big.NewInt(1.2e9)},
+		{big.NewInt(1e9 + 1), 0.2, big.NewInt(1.2e9 + 1)},
+		// Bumps below what nodes accept for a replacement are raised to it
+		{big.NewInt(1e9), 0.01, big.NewInt(1.1e9)},
+		// The integer minimum is rounded up
+		{big.NewInt(15), 0.10, big.NewInt(17)},
+		{big.NewInt(1), 0.10, big.NewInt(2)},
+		{big.NewInt(0), 0.10, big.NewInt(1)},
+	}
+	for _, tt := range tests {
+		if got := bumpGasPrice(tt.price, tt.bump); got.Cmp(tt.want) != 0 {
+			t.Fatalf("bumpGasPrice(%s, %v) = %s, want %s", tt.price, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestBroadcasterCancelPending(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	mempool := newTestMempool()
+	handle := testBroadcast(t, mempool, BroadcastPolicy{})
+	original := mempool.sent[0]
+	sender := crypto.PubkeyToAddress(testKey(t).PublicKey)
+
+	if _, err := handle.broadcaster.CancelPending(context.Background(), original.Nonce()+1); !errors.Is(err, ErrTxNotPending) {
+		t.Fatalf("CancelPending at an untracked nonce = %v, want ErrTxNotPending", err)
+	}
+	cancelled, err := handle.broadcaster.CancelPending(context.Background(), original.Nonce())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled != handle || len(mempool.sent) != 2 {
+		t.Fatalf("sent %d transactions, want the cancellation sent for the tracked handle", len(mempool.sent))
+	}
+	// A zero-value transfer to the sender itself, at the target nonce and a replacement's gas price
+	cancel := mempool.sent[1]
+	if cancel.Nonce() != original.Nonce() || *cancel.To() != sender || cancel.Value().Sign() != 0 ||
+		len(cancel.Data()) != 0 || cancel.Gas() != cancelGas {
+		t.Fatalf("cancellation is nonce %d to %s of %s wei with %d bytes of data, want a self-transfer at nonce %d",
+			cancel.Nonce(), cancel.To().Hex(), cancel.Value(), len(cancel.Data()), original.Nonce())
+	}
+	minimum := new(big.Int).Div(new(big.Int).Mul(original.GasPrice(), big.NewInt(110)), big.NewInt(100))
+	if cancel.GasPrice().Cmp(bumpGasPrice(original.GasPrice(), DefaultGasBump)) != 0 || cancel.GasPrice().Cmp(minimum) < 0 {
+		t.Fatalf("cancellation at %s wei, want at least %s wei to replace the original", cancel.GasPrice(), minimum)
+	}
+
+	mempool.mine(cancel.Hash(), ctypes.ReceiptStatusSuccessful)
+	if _, err := handle.WaitMined(context.Background()); !errors.Is(err, ErrTxCancelled) {
+		t.Fatalf("WaitMined of a cancelled transaction = %v, want ErrTxCancelled", err)
+	}
+}
+
+func TestBroadcastCancelAfterMined(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	mempool := newTestMempool()
+	handle := testBroadcast(t, mempool, BroadcastPolicy{})
+	mempool.mine(handle.Hash(), ctypes.ReceiptStatusSuccessful)
+
+	if err := handle.Cancel(context.Background()); !errors.Is(err, ErrTxNotPending) {
+		t.Fatalf("Cancel of a mined transaction = %v, want ErrTxNotPending", err)
+	}
+	if len(mempool.sent) != 1 {
+		t.Fatalf("sent %d transactions, want no cancellation", len(mempool.sent))
+	}
+	// The original still counts as mined
+	if receipt, err := handle.WaitMined(context.Background()); err != nil || receipt.TxHash != mempool.sent[0].Hash() {
+		t.Fatalf("WaitMined = %v, %v, want the original mined", receipt, err)
+	}
+}
+
+func TestBroadcastSpeedUpCancellation(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	mempool := newTestMempool()
+	handle := testBroadcast(t, mempool, BroadcastPolicy{})
+	if err := handle.Cancel(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	cancel := mempool.sent[1]
+
+	// Speeding up a cancelled handle re-sends the cancellation, not the original call
+	sped, err := handle.SpeedUp(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sped.Nonce() != cancel.Nonce() || *sped.To() != *cancel.To() || len(sped.Data()) != 0 ||
+		sped.GasPrice().Cmp(cancel.GasPrice()) <= 0 {
+		t.Fatalf("sped up to nonce %d to %s at %s wei, want the cancellation at a higher price",
+			sped.Nonce(), sped.To().Hex(), sped.GasPrice())
+	}
+	mempool.mine(sped.Hash(), ctypes.ReceiptStatusSuccessful)
+	if _, err := handle.WaitMined(context.Background()); !errors.Is(err, ErrTxCancelled) {
+		t.Fatalf("WaitMined of a sped-up cancellation = %v, want ErrTxCancelled", err)
+	}
+}
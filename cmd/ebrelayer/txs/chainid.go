@@ -0,0 +1,35 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrChainIDMismatch is returned when a node reports a chain ID other than the one configured for
+// its chain, such as a testnet endpoint given to a mainnet configuration
+var ErrChainIDMismatch = errors.New("node chain ID doesn't match the configured chain ID")
+
+// ChainIDReader reports the chain ID of the network a node serves
+type ChainIDReader interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// CheckChainID returns ErrChainIDMismatch if client's node reports a chain ID other than
+// configured, before any transaction is signed for the wrong network. A nil configured chain ID
+// defers to the node's, so isn't checked.
+func CheckChainID(ctx context.Context, chain string, client ChainIDReader, configured *big.Int) error {
+	if configured == nil {
+		return nil
+	}
+	reported, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("reading %s chain ID: %w", chain, err)
+	}
+	if reported.Cmp(configured) != 0 {
+		return fmt.Errorf("%w: %s node reports chain ID %v, configured %v", ErrChainIDMismatch, chain, reported,
+			configured)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// testChainIDReader is a ChainIDReader whose node reports chainID, or fails with err
+type testChainIDReader struct {
+	chainID *big.Int
+	err     error
+	calls   int
+}
+
+func (r *testChainIDReader) ChainID(context.Context) (*big.Int, error) {
+	r.calls++
+	return r.chainID, r.err
+}
+
+func TestCheckChainIDMismatch(t *testing.T) {
+	// A mainnet configuration pointed at a Goerli endpoint
+	goerli := &testChainIDReader{chainID: big.NewInt(5)}
+	err := CheckChainID(context.Background(), ethereumChainLabel, goerli, big.NewInt(1))
+	if !errors.Is(err, ErrChainIDMismatch) {
+		t.Fatalf("CheckChainID against another chain = %v, want ErrChainIDMismatch", err)
+	}
+	for _, want := range []string{ethereumChainLabel, "reports chain ID 5", "configured 1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("CheckChainID error %q doesn't name %q", err, want)
+		}
+	}
+
+	if err := CheckChainID(context.Background(), harmonyChainLabel, &testChainIDReader{chainID: big.NewInt(1666600000)},
+		big.NewInt(1666600000)); err != nil {
+		t.Fatalf("CheckChainID of the configured chain = %v", err)
+	}
+
+	// An unset chain ID defers to the node's without asking it
+	unset := &testChainIDReader{chainID: big.NewInt(5)}
+	if err := CheckChainID(context.Background(), ethereumChainLabel, unset, nil); err != nil || unset.calls != 0 {
+		t.Fatalf("CheckChainID without a configured chain ID = %v after %d calls", err, unset.calls)
+	}
+
+	failing := &testChainIDReader{err: errors.New("connection refused")}
+	if err := CheckChainID(context.Background(), ethereumChainLabel, failing, big.NewInt(1)); err == nil ||
+		errors.Is(err, ErrChainIDMismatch) {
+		t.Fatalf("CheckChainID with an unreachable node = %v, want the read error", err)
+	}
+}
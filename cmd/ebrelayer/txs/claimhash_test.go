@@ -0,0 +1,78 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// A claim's fields and the hash the bridge contracts compute for them, as
+// keccak256(abi.encodePacked(unlockID, sender, recipient, token, amount))
+var (
+	claimHashUnlockID  = big.NewInt(42)
+	claimHashSender    = common.HexToAddress("0x0B585F8DaEfBC68a311FbD4cB20d9174aD174016")
+	claimHashRecipient = common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	claimHashToken     = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	claimHashAmount    = big.NewInt(1500000000000000000)
+)
+
+const goldenClaimHash = "6b4b60580b5a8f3483953e1e4bd72b5ca7dfd36d3da1f5d69a22a14dc588f063"
+
+// packClaimByHand lays out the contract's abi.encodePacked of a claim by hand: 32-byte words for
+// the uint256s and the raw 20 bytes of each address
+func packClaimByHand(unlockID *big.Int, sender, recipient, token common.Address, amount *big.Int) []byte {
+	preimage := math.U256Bytes(new(big.Int).Set(unlockID))
+	preimage = append(preimage, sender.Bytes()...)
+	preimage = append(preimage, recipient.Bytes()...)
+	preimage = append(preimage, token.Bytes()...)
+	return append(preimage, math.U256Bytes(new(big.Int).Set(amount))...)
+}
+
+func TestBuildClaimHashGolden(t *testing.T) {
+	preimage := packClaimByHand(claimHashUnlockID, claimHashSender, claimHashRecipient, claimHashToken, claimHashAmount)
+	if hex.EncodeToString(crypto.Keccak256(preimage)) != goldenClaimHash {
+		t.Fatalf("hand-packed claim hashes to %x, want the golden %s", crypto.Keccak256(preimage), goldenClaimHash)
+	}
+	hash, err := BuildClaimHash(claimHashUnlockID, claimHashSender, claimHashRecipient, claimHashToken, claimHashAmount)
+	if err != nil || hex.EncodeToString(hash) != goldenClaimHash {
+		t.Fatalf("BuildClaimHash = %x, want the golden %s", hash, goldenClaimHash)
+	}
+
+	// Full-width fields, with the sender and recipient swapped
+	unlockID := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	amount := new(big.Int).Lsh(big.NewInt(1), 255)
+	want := crypto.Keccak256(packClaimByHand(unlockID, claimHashRecipient, claimHashSender, claimHashToken, amount))
+	if got, err := BuildClaimHash(unlockID, claimHashRecipient, claimHashSender, claimHashToken, amount); err != nil ||
+		!bytes.Equal(got, want) {
+		t.Fatalf("BuildClaimHash = %x, %v, want %x", got, err, want)
+	}
+
+	// The amount is hashed as given, where claim events have theirs rescaled
+	defer func(tokens *TokenRegistry) { Tokens = tokens }(Tokens)
+	Tokens = NewTokenRegistry()
+	Tokens.Register(claimHashToken, 6, 18)
+	want = crypto.Keccak256(packClaimByHand(claimHashUnlockID, claimHashSender, claimHashRecipient, claimHashToken,
+		big.NewInt(1500000)))
+	if got, err := BuildClaimHash(claimHashUnlockID, claimHashSender, claimHashRecipient, claimHashToken,
+		big.NewInt(1500000)); err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("BuildClaimHash = %x, %v, want the amount hashed unscaled", got, err)
+	}
+}
+
+func TestBuildClaimHashMissingFields(t *testing.T) {
+	for name, fields := range map[string][2]*big.Int{
+		"unlock ID": {nil, claimHashAmount},
+		"amount":    {claimHashUnlockID, nil},
+	} {
+		hash, err := BuildClaimHash(fields[0], claimHashSender, claimHashRecipient, claimHashToken, fields[1])
+		if !errors.Is(err, ErrMissingClaimField) || hash != nil {
+			t.Fatalf("BuildClaimHash without an %s = %x, %v, want ErrMissingClaimField", name, hash, err)
+		}
+	}
+}
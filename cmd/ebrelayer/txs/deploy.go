@@ -0,0 +1,93 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ErrUnexpectedContractAddress is returned when a configured contract address isn't the one its
+// deployment derives, so the relayer may be pointed at the wrong, possibly malicious, contract
+var ErrUnexpectedContractAddress = errors.New("contract address doesn't match its deployment")
+
+// ComputeCreate2Address returns the address a CREATE2 deployment by deployer of the init code
+// hashing to initCodeHash with salt creates, as EIP-1014 derives it
+func ComputeCreate2Address(deployer common.Address, salt [32]byte, initCodeHash [32]byte) common.Address {
+	return common.BytesToAddress(Keccak256([]byte{0xff}, deployer.Bytes(), salt[:], initCodeHash[:])[12:])
+}
+
+// ComputeCreateAddress returns the address a CREATE deployment by deployer at nonce creates: the
+// last 20 bytes of keccak256(rlp([deployer, nonce]))
+func ComputeCreateAddress(deployer common.Address, nonce uint64) common.Address {
+	encoded, err := rlp.EncodeToBytes([]interface{}{deployer, nonce})
+	if err != nil {
+		// An address and an integer always encode
+		panic(err)
+	}
+	return common.BytesToAddress(Keccak256(encoded)[12:])
+}
+
+// ContractOrigin is how a contract was deployed: by Deployer at Nonce with CREATE, or with CREATE2
+// from Salt and InitCodeHash if Create2 is set
+type ContractOrigin struct {
+	Deployer     common.Address
+	Nonce        uint64
+	Create2      bool
+	Salt         [32]byte
+	InitCodeHash [32]byte
+}
+
+// ParseContractOrigin parses a contract's origin given as "deployer:nonce" for a CREATE
+// deployment, or as "deployer:salt:initCodeHash" with 32-byte hex values for a CREATE2 deployment
+func ParseContractOrigin(value string) (ContractOrigin, error) {
+	parts := strings.Split(value, ":")
+	if (len(parts) != 2 && len(parts) != 3) || !common.IsHexAddress(parts[0]) {
+		return ContractOrigin{}, fmt.Errorf("invalid contract origin %q: expected deployer:nonce or deployer:salt:initCodeHash", value)
+	}
+	if err := ValidateAddressChecksum(parts[0], false); err != nil {
+		return ContractOrigin{}, fmt.Errorf("invalid contract origin %q: %w", value, err)
+	}
+	origin := ContractOrigin{Deployer: common.HexToAddress(parts[0])}
+
+	if len(parts) == 2 {
+		nonce, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return ContractOrigin{}, fmt.Errorf("invalid contract origin %q: invalid nonce: %v", value, err)
+		}
+		origin.Nonce = nonce
+		return origin, nil
+	}
+
+	origin.Create2 = true
+	for i, field := range []*[32]byte{&origin.Salt, &origin.InitCodeHash} {
+		decoded, err := hexutil.Decode(parts[i+1])
+		if err != nil || len(decoded) != 32 {
+			return ContractOrigin{}, fmt.Errorf("invalid contract origin %q: %s must be 32 hex bytes",
+				value, [...]string{"salt", "init code hash"}[i])
+		}
+		copy(field[:], decoded)
+	}
+	return origin, nil
+}
+
+// Address returns the address the deployment creates
+func (o ContractOrigin) Address() common.Address {
+	if o.Create2 {
+		return ComputeCreate2Address(o.Deployer, o.Salt, o.InitCodeHash)
+	}
+	return ComputeCreateAddress(o.Deployer, o.Nonce)
+}
+
+// Verify returns ErrUnexpectedContractAddress unless address is the one the deployment creates
+func (o ContractOrigin) Verify(address common.Address) error {
+	if expected := o.Address(); address != expected {
+		return fmt.Errorf("%w: configured %s, but the deployment creates %s", ErrUnexpectedContractAddress,
+			address.Hex(), expected.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package txs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestComputeCreate2Address(t *testing.T) {
+	// The examples of EIP-1014
+	tests := []struct {
+		deployer, salt, initCode, want string
+	}{
+		{"0x0000000000000000000000000000000000000000", "0x00", "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x00", "0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x000000000000000000000000feed000000000000000000000000000000000000",
+			"0x00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x0000000000000000000000000000000000000000", "0x00", "0xdeadbeef", "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e"},
+		{"0x00000000000000000000000000000000deadbeef", "0xcafebabe", "0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x00000000000000000000000000000000deadbeef", "0xcafebabe",
+			"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			"0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C"},
+		{"0x0000000000000000000000000000000000000000", "0x00", "0x", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+	for _, tt := range tests {
+		deployer, salt := common.HexToAddress(tt.deployer), common.HexToHash(tt.salt)
+		initCodeHash := crypto.Keccak256Hash(common.FromHex(tt.initCode))
+		got := ComputeCreate2Address(deployer, salt, initCodeHash)
+		if got != common.HexToAddress(tt.want) {
+			t.Fatalf("ComputeCreate2Address(%s, %s, keccak256(%s)) = %s, want %s", tt.deployer, tt.salt, tt.initCode,
+				got.Hex(), tt.want)
+		}
+		if geth := crypto.CreateAddress2(deployer, salt, initCodeHash[:]); got != geth {
+			t.Fatalf("ComputeCreate2Address = %s, but geth derives %s", got.Hex(), geth.Hex())
+		}
+	}
+}
+
+func TestComputeCreateAddress(t *testing.T) {
+	deployer := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	for nonce, want := range []string{
+		"0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d",
+		"0x343c43a37d37dff08ae8c4a11544c718abb4fcf8",
+		"0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91",
+		"0xfffd933a0bc612844eaf0c6fe3e5b8e9b6c1d19c",
+	} {
+		if got := ComputeCreateAddress(deployer, uint64(nonce)); got != common.HexToAddress(want) {
+			t.Fatalf("ComputeCreateAddress(%s, %d) = %s, want %s", deployer.Hex(), nonce, got.Hex(), want)
+		}
+	}
+	// Nonces beyond a single RLP byte
+	for _, nonce := range []uint64{127, 128, 1 << 20} {
+		if got, geth := ComputeCreateAddress(deployer, nonce), crypto.CreateAddress(deployer, nonce); got != geth {
+			t.Fatalf("ComputeCreateAddress(%d) = %s, but geth derives %s", nonce, got.Hex(), geth.Hex())
+		}
+	}
+}
+
+func TestContractOriginVerify(t *testing.T) {
+	deployer := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	salt := "0x000000000000000000000000000000000000000000000000000000000000002a"
+	initCodeHash := crypto.Keccak256Hash([]byte("registry")).Hex()
+
+	create, err := ParseContractOrigin(deployer + ":3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := create.Verify(crypto.CreateAddress(common.HexToAddress(deployer), 3)); err != nil {
+		t.Fatalf("Verify of the deployed address = %v", err)
+	}
+	create2, err := ParseContractOrigin(deployer + ":" + salt + ":" + initCodeHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployed := crypto.CreateAddress2(common.HexToAddress(deployer), common.HexToHash(salt), common.FromHex(initCodeHash))
+	if err := create2.Verify(deployed); err != nil {
+		t.Fatalf("Verify of the CREATE2-deployed address = %v", err)
+	}
+	if err := create2.Verify(create.Address()); !errors.Is(err, ErrUnexpectedContractAddress) {
+		t.Fatalf("Verify of another address = %v, want ErrUnexpectedContractAddress", err)
+	}
+
+	for _, value := range []string{
+		deployer,
+		deployer + ":-1",
+		deployer + ":" + salt,
+		deployer + ":0x2a:" + initCodeHash,
+		"0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed:3",
+		deployer + ":" + salt + ":" + initCodeHash + ":1",
+	} {
+		if _, err := ParseContractOrigin(value); err == nil {
+			t.Fatalf("ParseContractOrigin(%q) succeeded", value)
+		}
+	}
+}
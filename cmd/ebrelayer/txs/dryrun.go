@@ -0,0 +1,51 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// DryRun, if set, logs the claim message each event would be signed over, along with its packed
+// components, instead of signing or submitting anything
+var DryRun bool
+
+// ErrDryRun is returned in place of signing or submitting a claim while DryRun is set
+var ErrDryRun = errors.New("dry run: claim not signed or submitted")
+
+// dryRunClaim logs a claim event's message hash, the packed bytes of each of its components and
+// the whole preimage, encoded as the first of target, if given, expects, for comparison against
+// the hash computed on-chain, and returns ErrDryRun
+func dryRunClaim(event types.ClaimEvent, message []byte, target []common.Address) error {
+	unlockID, _, _, token, amount := event.ClaimFields()
+	encoding := claimEncoding(target)
+	keyvals := []interface{}{"chain", claimEventChain(event), "unlockID", unlockID,
+		"amount", FormatTokenAmount(token, amount), "hash", hexutil.Encode(message), "encoding", encoding}
+
+	layout, values, err := claimMessageComponents(event, claimChainID(event))
+	if err != nil {
+		return err
+	}
+	for i, typ := range layout {
+		keyvals = append(keyvals, fmt.Sprintf("%d:%s", i, typ), hexutil.Encode(pack(typ, values[i], false)))
+	}
+	if encoding == ABIEncoding {
+		if preimage, err := ABIEncode(layout, values...); err == nil {
+			keyvals = append(keyvals, "preimage", hexutil.Encode(preimage))
+		}
+	} else if _, preimage, err := SoliditySHA3WithPreimage(layout, values...); err == nil {
+		keyvals = append(keyvals, "preimage", hexutil.Encode(preimage))
+	}
+	getLogger().Info("Dry run claim message", keyvals...)
+	return ErrDryRun
+}
+
+// dryRunUnlockClaim logs an UnlockClaim which would be submitted to chain, and returns ErrDryRun
+func dryRunUnlockClaim(chain string, claim interface{}) error {
+	getLogger().Info("Dry run UnlockClaim", "chain", chain, "claim", fmt.Sprintf("%+v", claim))
+	return ErrDryRun
+}
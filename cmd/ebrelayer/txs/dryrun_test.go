@@ -0,0 +1,66 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// countingSigner is a Signer counting the messages it is asked to sign
+type countingSigner struct {
+	Signer
+	signed int
+}
+
+func (s *countingSigner) Sign(msg []byte) ([]byte, error) {
+	s.signed++
+	return s.Signer.Sign(msg)
+}
+
+func TestDryRunInvokesNoSigner(t *testing.T) {
+	defer func(dryRun bool) { DryRun = dryRun }(DryRun)
+	DryRun = true
+	recorder, restore := useRecordingLogger()
+	defer restore()
+
+	signer := &countingSigner{Signer: NewKeySigner(testKey(t))}
+	if _, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{goldenEthEvent()}); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("SignClaimsBatch in dry-run mode = %v, want ErrDryRun", err)
+	}
+	if signer.signed != 0 {
+		t.Fatalf("signer invoked %d times in dry-run mode", signer.signed)
+	}
+
+	// The claim hash is logged for comparison against the contract's
+	if len(recorder.entries) != 1 {
+		t.Fatalf("logged %d entries, want the dry run claim", len(recorder.entries))
+	}
+	if hash := recorder.entries[0].value("hash"); hash != "0x"+goldenClaim.message {
+		t.Fatalf("dry run logged hash %v, want 0x%s", hash, goldenClaim.message)
+	}
+	preimage, ok := recorder.entries[0].value("preimage").(string)
+	if !ok || hexutil.Encode(Keccak256(hexutil.MustDecode(preimage))) != "0x"+goldenClaim.message {
+		t.Fatalf("dry run logged preimage %v, want the packed claim", recorder.entries[0].value("preimage"))
+	}
+}
+
+func TestDryRunSubmitsNothing(t *testing.T) {
+	defer func(dryRun bool) { DryRun = dryRun }(DryRun)
+	DryRun = true
+	_, restore := useRecordingLogger()
+	defer restore()
+
+	// No provider is dialled and no key is needed
+	claim := EthUnlockClaim{HarmonyChainID: big.NewInt(1), Amount: big.NewInt(1)}
+	if err := RelayUnlockClaimToEthereum("", common.Address{}, types.HmyLogLock, claim, nil); err != ErrDryRun {
+		t.Fatalf("RelayUnlockClaimToEthereum in dry-run mode = %v, want ErrDryRun", err)
+	}
+	if err := RelayUnlockClaimToHarmony("", common.Address{}, types.EthLogLock, HmyUnlockClaim{}, nil); err != ErrDryRun {
+		t.Fatalf("RelayUnlockClaimToHarmony in dry-run mode = %v, want ErrDryRun", err)
+	}
+}
@@ -0,0 +1,146 @@
+package txs
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultPrimaryRetryInterval is how long dials keep to a fallback endpoint before trying the
+// primary again
+const DefaultPrimaryRetryInterval = time.Minute
+
+// RPCEndpoints, if set, holds the fallback endpoints of each chain's primary RPC URL. Dials to a
+// registered primary fail over to its fallbacks while it is down.
+var RPCEndpoints *EndpointRegistry
+
+// Endpoints is a chain's RPC URLs in order of preference, the first being the primary. It is safe
+// for concurrent use.
+type Endpoints struct {
+	RetryPrimaryAfter time.Duration
+
+	urls []string
+	now  func() time.Time
+
+	mu         sync.Mutex
+	active     int
+	failedOver time.Time
+}
+
+// NewEndpoints initializes new Endpoints preferring urls in order, retrying the primary after
+// DefaultPrimaryRetryInterval
+func NewEndpoints(urls ...string) *Endpoints {
+	return &Endpoints{
+		RetryPrimaryAfter: DefaultPrimaryRetryInterval,
+		urls:              append([]string(nil), urls...),
+		now:               time.Now,
+	}
+}
+
+// Primary returns the preferred URL
+func (e *Endpoints) Primary() string {
+	return e.urls[0]
+}
+
+// Active returns the URL last dialed successfully, or the primary if none was yet
+func (e *Endpoints) Active() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.urls[e.active]
+}
+
+// Dial calls dial with each URL in turn, starting from the active one, until one connects and
+// becomes active. Once RetryPrimaryAfter has passed since failing over, the primary is tried
+// first again, so that dials rotate back to it when it recovers. It returns the last URL's error
+// if none connects.
+func (e *Endpoints) Dial(ctx context.Context, dial func(ctx context.Context, rawurl string) error) error {
+	e.mu.Lock()
+	start := e.active
+	if start != 0 && e.now().Sub(e.failedOver) >= e.RetryPrimaryAfter {
+		start = 0
+	}
+	e.mu.Unlock()
+
+	var err error
+	for i := range e.urls {
+		index := (start + i) % len(e.urls)
+		if err = dial(ctx, e.urls[index]); err == nil {
+			e.setActive(index, start)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if len(e.urls) > 1 {
+			getLogger().Warn("RPC endpoint unreachable, failing over", "endpoint", RedactURL(e.urls[index]), "err", err)
+		}
+	}
+	return err
+}
+
+// setActive makes the URL at index active after a dial starting from start connected to it
+func (e *Endpoints) setActive(index, start int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if index != e.active {
+		getLogger().Info("Switched RPC endpoint", "from", RedactURL(e.urls[e.active]), "to", RedactURL(e.urls[index]))
+	}
+	// Count the wait before retrying the primary from each time it was found down
+	if index != 0 && (index != e.active || start == 0) {
+		e.failedOver = e.now()
+	}
+	e.active = index
+}
+
+// EndpointRegistry maps each chain's primary RPC URL to its Endpoints. It is safe for concurrent use.
+type EndpointRegistry struct {
+	mu        sync.RWMutex
+	endpoints map[string]*Endpoints
+}
+
+// NewEndpointRegistry initializes a new, empty EndpointRegistry
+func NewEndpointRegistry() *EndpointRegistry {
+	return &EndpointRegistry{endpoints: make(map[string]*Endpoints)}
+}
+
+// Register records endpoints under their primary URL
+func (r *EndpointRegistry) Register(endpoints *Endpoints) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.endpoints[endpoints.Primary()] = endpoints
+}
+
+// Endpoints returns the endpoints registered for primary, if any
+func (r *EndpointRegistry) Endpoints(primary string) (*Endpoints, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	endpoints, ok := r.endpoints[primary]
+	return endpoints, ok
+}
+
+// DialEndpoint calls dial with rawurl or, if fallbacks are registered for it in RPCEndpoints, with
+// each endpoint in turn until one connects
+func DialEndpoint(ctx context.Context, rawurl string, dial func(ctx context.Context, rawurl string) error) error {
+	if endpoints, ok := RPCEndpoints.Endpoints(rawurl); ok {
+		return endpoints.Dial(ctx, dial)
+	}
+	return dial(ctx, rawurl)
+}
+
+// RedactURL returns rawurl's scheme and host only, dropping credentials and API keys that
+// providers carry in the path or query, so that the URL can be logged and reported
+func RedactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return "invalid URL"
+	}
+	return u.Scheme + "://" + u.Host
+}
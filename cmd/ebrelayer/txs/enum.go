@@ -0,0 +1,130 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// enumTypePrefix begins the type of a value packed as a registered EnumType, as in "enum ClaimKind",
+// which is how Solidity ABIs name an enum's internal type
+const enumTypePrefix = "enum "
+
+// ErrUnknownEnumMember is returned for an enum value which names no member of its EnumType, or
+// whose index is out of its range
+var ErrUnknownEnumMember = errors.New("unknown enum member")
+
+// EnumType is a Solidity enum, packed as the uint8 index of its member as the contract stores it
+type EnumType struct {
+	Name    string
+	members []string
+	indices map[string]uint8
+}
+
+// NewEnumType initializes a new EnumType of members in declaration order, so that the first is
+// index 0 as in Solidity
+func NewEnumType(name string, members ...string) (*EnumType, error) {
+	if name == "" || strings.ContainsAny(name, " ()[],") {
+		return nil, fmt.Errorf("invalid enum name %q", name)
+	}
+	if len(members) == 0 || len(members) > 256 {
+		return nil, fmt.Errorf("enum %s has %d members, expected 1 to 256", name, len(members))
+	}
+	enum := &EnumType{Name: name, members: append([]string(nil), members...), indices: make(map[string]uint8)}
+	for i, member := range members {
+		if _, ok := enum.indices[member]; ok {
+			return nil, fmt.Errorf("enum %s declares member %s twice", name, member)
+		}
+		enum.indices[member] = uint8(i)
+	}
+	return enum, nil
+}
+
+// Type returns the type values of the enum are declared as, such as "enum ClaimKind"
+func (e *EnumType) Type() string {
+	return enumTypePrefix + e.Name
+}
+
+// Members returns the enum's members in declaration order
+func (e *EnumType) Members() []string {
+	return append([]string(nil), e.members...)
+}
+
+// Value returns the index of value, given as a member's name or as its index: a Go integer,
+// *big.Int or decimal string. It returns ErrUnknownEnumMember for any other name or an index out
+// of range.
+func (e *EnumType) Value(value interface{}) (uint8, error) {
+	if name, ok := value.(string); ok {
+		if index, ok := e.indices[name]; ok {
+			return index, nil
+		}
+	}
+
+	var index *big.Int
+	switch v := value.(type) {
+	case string:
+		if parsed, ok := new(big.Int).SetString(v, 10); ok {
+			index = parsed
+		}
+	case *big.Int:
+		index = v
+	case uint8:
+		index = new(big.Int).SetUint64(uint64(v))
+	case uint16:
+		index = new(big.Int).SetUint64(uint64(v))
+	case uint32:
+		index = new(big.Int).SetUint64(uint64(v))
+	case uint64:
+		index = new(big.Int).SetUint64(v)
+	case uint:
+		index = new(big.Int).SetUint64(uint64(v))
+	case int8:
+		index = big.NewInt(int64(v))
+	case int16:
+		index = big.NewInt(int64(v))
+	case int32:
+		index = big.NewInt(int64(v))
+	case int64:
+		index = big.NewInt(v)
+	case int:
+		index = big.NewInt(int64(v))
+	}
+	if index == nil || index.Sign() < 0 || index.Cmp(big.NewInt(int64(len(e.members)))) >= 0 {
+		return 0, fmt.Errorf("%w: %v is not a member of enum %s", ErrUnknownEnumMember, value, e.Name)
+	}
+	return uint8(index.Uint64()), nil
+}
+
+// enumTypes holds the enums registered with RegisterEnumType, by name. It is read by pack on any
+// goroutine, so it is guarded by enumTypesMu.
+var (
+	enumTypesMu sync.RWMutex
+	enumTypes   = make(map[string]*EnumType)
+)
+
+// RegisterEnumType registers enum, so that values declared as its Type in the typed API, such as
+// SolidityPack and ABIEncode, are packed as a uint8 from a member's name or index
+func RegisterEnumType(enum *EnumType) error {
+	enumTypesMu.Lock()
+	defer enumTypesMu.Unlock()
+
+	if _, ok := enumTypes[enum.Name]; ok {
+		return fmt.Errorf("enum %s is already registered", enum.Name)
+	}
+	enumTypes[enum.Name] = enum
+	return nil
+}
+
+// lookupEnumType returns the registered EnumType typ declares, if typ is an enum type rather than
+// an array of one
+func lookupEnumType(typ string) (enum *EnumType, isEnum bool) {
+	if !strings.HasPrefix(typ, enumTypePrefix) || arrayTypePattern.MatchString(typ) {
+		return nil, false
+	}
+	enumTypesMu.RLock()
+	defer enumTypesMu.RUnlock()
+
+	return enumTypes[strings.TrimPrefix(typ, enumTypePrefix)], true
+}
@@ -0,0 +1,85 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// registerTestEnum registers an enum ClaimKind of Lock and Unlock, returning a func which
+// unregisters it
+func registerTestEnum(t *testing.T) func() {
+	enum, err := NewEnumType("ClaimKind", "Lock", "Unlock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterEnumType(enum); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		enumTypesMu.Lock()
+		defer enumTypesMu.Unlock()
+
+		delete(enumTypes, enum.Name)
+	}
+}
+
+func TestEnumNameAndIndexPackSame(t *testing.T) {
+	defer registerTestEnum(t)()
+
+	for mode, pack := range map[string]func([]string, ...interface{}) ([]byte, error){
+		"packed": SolidityPack,
+		"abi":    ABIEncode,
+	} {
+		want, err := pack([]string{"uint8"}, uint8(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, value := range []interface{}{"Unlock", 1, uint8(1), big.NewInt(1), "1"} {
+			got, err := pack([]string{"enum ClaimKind"}, value)
+			if err != nil {
+				t.Fatalf("%s encoding of %v: %v", mode, value, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s encoding of %v = %x, want the uint8 1 %x", mode, value, got, want)
+			}
+		}
+
+		// Arrays and tuple components take names too
+		byName, err := pack([]string{"enum ClaimKind[]", "(enum ClaimKind,uint256)"}, []interface{}{"Lock", "Unlock"},
+			[]interface{}{"Unlock", goldenClaim.amount})
+		if err != nil {
+			t.Fatal(err)
+		}
+		byIndex, err := pack([]string{"enum ClaimKind[]", "(enum ClaimKind,uint256)"}, []interface{}{0, 1},
+			[]interface{}{1, goldenClaim.amount})
+		if err != nil || !bytes.Equal(byName, byIndex) {
+			t.Fatalf("%s encoding by name = %x, by index = %x, %v", mode, byName, byIndex, err)
+		}
+	}
+}
+
+func TestEnumRejectsUnknownMembers(t *testing.T) {
+	defer registerTestEnum(t)()
+
+	for _, value := range []interface{}{"Burn", "unlock", 2, big.NewInt(-1), "256"} {
+		if _, err := SolidityPack([]string{"enum ClaimKind"}, value); !errors.Is(err, ErrUnknownEnumMember) {
+			t.Fatalf("SolidityPack(enum ClaimKind, %v) = %v, want ErrUnknownEnumMember", value, err)
+		}
+	}
+	if _, err := SolidityPack([]string{"enum Unregistered"}, "Lock"); !errors.Is(err, ErrUnsupportedABIType) {
+		t.Fatalf("SolidityPack of an unregistered enum = %v, want ErrUnsupportedABIType", err)
+	}
+
+	if _, err := NewEnumType("ClaimKind", "Lock", "Lock"); err == nil {
+		t.Fatal("declared a member twice")
+	}
+	duplicate, err := NewEnumType("ClaimKind", "Lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterEnumType(duplicate); err == nil {
+		t.Fatal("registered an enum twice")
+	}
+}
@@ -0,0 +1,99 @@
+package txs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// EnvFile is the config file of environment variables LoadEnvOnce loads
+const EnvFile = ".env"
+
+var (
+	envOnce sync.Once
+	envErr  error
+)
+
+// LoadEnvOnce loads the variables in EnvFile into the process environment, reading the file only
+// on the first call and returning that call's error on every later one. Variables already set in
+// the process environment take precedence: the file only supplies those which are unset, so an
+// operator can override any of its keys by exporting them before starting the relayer. Keys the
+// file sets more than once, and keys whose file value the process environment overrides, are
+// logged by name.
+func LoadEnvOnce() error {
+	envOnce.Do(func() {
+		envErr = loadEnvFile(EnvFile)
+	})
+	return envErr
+}
+
+// loadEnvFile loads the variables in path which aren't already set, logging its duplicate and
+// overridden keys
+func loadEnvFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		getLogger().Error("Error loading .env file", "err", err)
+		return fmt.Errorf("loading .env file: %w", err)
+	}
+	if duplicates := duplicateEnvKeys(data); len(duplicates) > 0 {
+		getLogger().Info("Keys set more than once in .env file, the last setting is used",
+			"file", path, "keys", strings.Join(duplicates, ","))
+	}
+
+	vars, err := godotenv.Parse(bytes.NewReader(data))
+	if err != nil {
+		getLogger().Error("Error loading .env file", "err", err)
+		return fmt.Errorf("loading .env file: %w", err)
+	}
+	var overridden []string
+	for key, value := range vars {
+		current, ok := os.LookupEnv(key)
+		if ok {
+			if current != value {
+				overridden = append(overridden, key)
+			}
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("loading .env file: %w", err)
+		}
+	}
+	if len(overridden) > 0 {
+		sort.Strings(overridden)
+		getLogger().Info("Keys in .env file overridden by the process environment",
+			"file", path, "keys", strings.Join(overridden, ","))
+	}
+	return nil
+}
+
+// duplicateEnvKeys returns the keys an env file sets on more than one line, in order of their
+// second setting
+func duplicateEnvKeys(data []byte) []string {
+	seen := make(map[string]int)
+	var duplicates []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		separator := strings.IndexAny(line, "=:")
+		if separator < 0 {
+			continue
+		}
+		key := strings.TrimSpace(line[:separator])
+		seen[key]++
+		if seen[key] == 2 {
+			duplicates = append(duplicates, key)
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,64 @@
+package txs
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadEnvFilePrecedence(t *testing.T) {
+	recorder, restore := useRecordingLogger()
+	defer restore()
+	path, cleanup := writeKeyFile(t, strings.Join([]string{
+		"# relayer keys",
+		"TEST_ENV_SET=from-file",
+		"TEST_ENV_UNSET=from-file",
+		"export TEST_ENV_DUPLICATE=first",
+		"TEST_ENV_DUPLICATE=last",
+	}, "\n"))
+	defer cleanup()
+	defer setEnv(t, "TEST_ENV_SET", "from-process")()
+	defer os.Unsetenv("TEST_ENV_UNSET")
+	defer os.Unsetenv("TEST_ENV_DUPLICATE")
+
+	if err := loadEnvFile(path); err != nil {
+		t.Fatal(err)
+	}
+	// The process environment wins, the file supplies unset variables, and the last duplicate is used
+	for key, want := range map[string]string{
+		"TEST_ENV_SET":       "from-process",
+		"TEST_ENV_UNSET":     "from-file",
+		"TEST_ENV_DUPLICATE": "last",
+	} {
+		if got := os.Getenv(key); got != want {
+			t.Fatalf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	logged := make(map[string]interface{})
+	for _, entry := range recorder.entries {
+		logged[entry.msg] = entry.value("keys")
+	}
+	if keys := logged["Keys set more than once in .env file, the last setting is used"]; keys != "TEST_ENV_DUPLICATE" {
+		t.Fatalf("logged duplicate keys %v, want TEST_ENV_DUPLICATE", keys)
+	}
+	if keys := logged["Keys in .env file overridden by the process environment"]; keys != "TEST_ENV_SET" {
+		t.Fatalf("logged overridden keys %v, want TEST_ENV_SET", keys)
+	}
+	// Keys are logged by name, never with their values
+	for _, entry := range recorder.entries {
+		for _, value := range entry.keyvals {
+			if s, ok := value.(string); ok && strings.Contains(s, "from-") {
+				t.Fatalf("logged a value: %+v", entry)
+			}
+		}
+	}
+}
+
+func TestLoadEnvFileMissing(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	if err := loadEnvFile("testdata/missing.env"); err == nil {
+		t.Fatal("loaded a missing .env file")
+	}
+}
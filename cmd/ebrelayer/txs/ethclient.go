@@ -0,0 +1,50 @@
+package txs
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// EthClient is the Ethereum contract backend the claim bindings send through. It counts a retried
+// broadcast the node already holds as sent, and queries the network's chain ID once.
+type EthClient struct {
+	*ethclient.Client
+
+	mu      sync.Mutex
+	chainID *big.Int
+}
+
+// NewEthClient initializes a new EthClient sending through client
+func NewEthClient(client *ethclient.Client) *EthClient {
+	return &EthClient{Client: client}
+}
+
+// SendTransaction implements bind.ContractTransactor. A retried broadcast the node already holds
+// counts as sent, per sentIfKnown.
+func (c *EthClient) SendTransaction(ctx context.Context, tx *ctypes.Transaction) error {
+	err := c.Client.SendTransaction(ctx, tx)
+	return sentIfKnown(ctx, err, tx.Hash(), func(ctx context.Context, txHash common.Hash) error {
+		_, _, err := c.Client.TransactionByHash(ctx, txHash)
+		return err
+	})
+}
+
+// networkChainID returns the chain ID claim transactions are signed for, querying it once
+func (c *EthClient) networkChainID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.chainID == nil {
+		chainID, err := c.Client.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.chainID = chainID
+	}
+	return c.chainID, nil
+}
@@ -0,0 +1,159 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	htypes "github.com/harmony-one/harmony/core/types"
+	harmonybridge "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/harmonybridge"
+	ethereumbridge "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/harmony/bindings/ethereumbridge"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+var (
+	// ErrUnexpectedEvent is returned when parsing a log which isn't the expected event
+	ErrUnexpectedEvent = errors.New("log is not the expected event")
+	// ErrUndecodableEvent is returned when a log's data can't be unpacked as its event, such as
+	// when it is truncated or was emitted against another ABI
+	ErrUndecodableEvent = errors.New("undecodable event")
+)
+
+var (
+	harmonyBridgeABI     abi.ABI
+	harmonyBridgeABIErr  error
+	harmonyBridgeABIOnce sync.Once
+
+	ethereumBridgeABI     abi.ABI
+	ethereumBridgeABIErr  error
+	ethereumBridgeABIOnce sync.Once
+)
+
+// loadHarmonyBridgeABI returns the bundled ABI of the Ethereum HarmonyBridge contract
+func loadHarmonyBridgeABI() (abi.ABI, error) {
+	harmonyBridgeABIOnce.Do(func() {
+		harmonyBridgeABI, harmonyBridgeABIErr = abi.JSON(strings.NewReader(harmonybridge.HarmonyBridgeABI))
+	})
+	return harmonyBridgeABI, harmonyBridgeABIErr
+}
+
+// loadEthereumBridgeABI returns the bundled ABI of the Harmony EthereumBridge contract
+func loadEthereumBridgeABI() (abi.ABI, error) {
+	ethereumBridgeABIOnce.Do(func() {
+		ethereumBridgeABI, ethereumBridgeABIErr = abi.JSON(strings.NewReader(ethereumbridge.EthereumBridgeABI))
+	})
+	return ethereumBridgeABI, ethereumBridgeABIErr
+}
+
+// ParseEthUnlockClaim decodes an EthLogNewUnlockClaim log emitted by the Ethereum HarmonyBridge
+// contract, as the bundled ABI or a version registered in EthUnlockEvents declares it, returning
+// ErrUnexpectedEvent if the log's signature topic is another event's
+func ParseEthUnlockClaim(log ctypes.Log) (types.EthLogNewUnlockClaimEvent, error) {
+	event := types.EthLogNewUnlockClaimEvent{}
+	contractABI, err := loadHarmonyBridgeABI()
+	if err != nil {
+		return event, err
+	}
+
+	eventName := types.EthLogNewUnlockClaim.String()
+	if len(log.Topics) != 0 {
+		if version, ok := EthUnlockEvents.Lookup(log.Topics[0]); ok {
+			fields, err := version.Decode(log.Topics, log.Data)
+			if err != nil {
+				return event, err
+			}
+			return types.EthLogNewUnlockClaimEvent{UnlockID: fields.UnlockID, HarmonySender: fields.Sender,
+				EthereumReceiver: fields.Receiver, ValidatorAddress: fields.Validator, TokenAddress: fields.Token,
+				Amount: fields.Amount, TxHash: log.TxHash, Version: version.Name}, nil
+		}
+	}
+	if len(log.Topics) == 0 || log.Topics[0] != contractABI.Events[eventName].ID {
+		return event, fmt.Errorf("%w: expected %s", ErrUnexpectedEvent, eventName)
+	}
+	if err := UnpackEvent(contractABI, &event, eventName, log.Topics, log.Data); err != nil {
+		return event, err
+	}
+	event.TxHash = log.TxHash
+	return event, nil
+}
+
+// ParseHmyUnlockClaim decodes an HmyLogNewUnlockClaim log emitted by the Harmony EthereumBridge
+// contract, as the bundled ABI or a version registered in HmyUnlockEvents declares it, returning
+// ErrUnexpectedEvent if the log's signature topic is another event's
+func ParseHmyUnlockClaim(log htypes.Log) (types.HmyLogNewUnlockClaimEvent, error) {
+	event := types.HmyLogNewUnlockClaimEvent{}
+	contractABI, err := loadEthereumBridgeABI()
+	if err != nil {
+		return event, err
+	}
+
+	eventName := types.HmyLogNewUnlockClaim.String()
+	if len(log.Topics) != 0 {
+		if version, ok := HmyUnlockEvents.Lookup(log.Topics[0]); ok {
+			fields, err := version.Decode(log.Topics, log.Data)
+			if err != nil {
+				return event, err
+			}
+			return types.HmyLogNewUnlockClaimEvent{UnlockID: fields.UnlockID, EthereumSender: fields.Sender,
+				HarmonyReceiver: fields.Receiver, ValidatorAddress: fields.Validator, TokenAddress: fields.Token,
+				Amount: fields.Amount, TxHash: log.TxHash, Version: version.Name}, nil
+		}
+	}
+	if len(log.Topics) == 0 || log.Topics[0] != contractABI.Events[eventName].ID {
+		return event, fmt.Errorf("%w: expected %s", ErrUnexpectedEvent, eventName)
+	}
+	if err := UnpackEvent(contractABI, &event, eventName, log.Topics, log.Data); err != nil {
+		return event, err
+	}
+	event.TxHash = log.TxHash
+	return event, nil
+}
+
+// UnpackEvent unpacks a log of eventName of contractABI into out, decoding its indexed arguments
+// from topics and the others from data. It returns ErrUndecodableEvent if the log can't be
+// unpacked, including when the decoder panics over malformed data. Each failure is counted in
+// Metrics.ClaimErrors.
+func UnpackEvent(contractABI abi.ABI, out interface{}, eventName string, topics []common.Hash, data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: unpacking %s: %v", ErrUndecodableEvent, eventName, r)
+		}
+		if err != nil {
+			getMetrics().claimError(DecodeErrorReason, err)
+		}
+	}()
+
+	event, ok := contractABI.Events[eventName]
+	if !ok {
+		return fmt.Errorf("%w: no %s event in the ABI", ErrUndecodableEvent, eventName)
+	}
+	// The ABI decoder reads only the non-indexed arguments from data, but refuses empty data
+	// even when every argument is indexed
+	if len(event.Inputs.NonIndexed()) > 0 {
+		if err := contractABI.Unpack(out, eventName, data); err != nil {
+			return fmt.Errorf("%w: unpacking %s: %v", ErrUndecodableEvent, eventName, err)
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	// The first topic of a non-anonymous event is its signature, not an argument
+	if !event.Anonymous {
+		if len(topics) == 0 {
+			return fmt.Errorf("%w: unpacking %s: missing signature topic", ErrUndecodableEvent, eventName)
+		}
+		topics = topics[1:]
+	}
+	if err := abi.ParseTopics(out, indexed, topics); err != nil {
+		return fmt.Errorf("%w: unpacking %s topics: %v", ErrUndecodableEvent, eventName, err)
+	}
+	return nil
+}
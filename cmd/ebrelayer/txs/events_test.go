@@ -0,0 +1,181 @@
+package txs
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	htypes "github.com/harmony-one/harmony/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// Signature topics of the bridges' unlock claim events
+var (
+	ethUnlockClaimTopic = common.HexToHash("0x4389bb697e92204e405d58c4811114444ca51e675f1258844e426ce971bf4c56")
+	hmyUnlockClaimTopic = common.HexToHash("0xaeac38f4f561543d773d127aadc54cc1c83684be65b0ecf15dcc0232fadf1449")
+)
+
+// goldenClaimLogData is the data of an unlock claim log for goldenClaim, validated by
+// goldenValidator: the unlock ID, sender, receiver, validator, token and amount, one word each
+var goldenClaimLogData = strings.Join([]string{
+	"000000000000000000000000000000000000000000000000000000000000002a",
+	"0000000000000000000000000b585f8daefbc68a311fbd4cb20d9174ad174016",
+	"0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+	"00000000000000000000000000000000000000000000000000000000000000ff",
+	"000000000000000000000000dac17f958d2ee523a2206206994597c13d831ec7",
+	"00000000000000000000000000000000000000000000000014d1120d7b160000",
+}, "")
+
+var goldenValidator = common.HexToAddress("0xff")
+
+func goldenLogData(t *testing.T) []byte {
+	data, err := hex.DecodeString(goldenClaimLogData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseEthUnlockClaim(t *testing.T) {
+	txHash := common.HexToHash("0x77")
+	event, err := ParseEthUnlockClaim(ctypes.Log{Topics: []common.Hash{ethUnlockClaimTopic}, Data: goldenLogData(t), TxHash: txHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.UnlockID.Cmp(goldenClaim.unlockID) != 0 || event.HarmonySender != goldenClaim.sender ||
+		event.EthereumReceiver != goldenClaim.recipient || event.ValidatorAddress != goldenValidator ||
+		event.TokenAddress != goldenClaim.token || event.Amount.Cmp(goldenClaim.amount) != 0 {
+		t.Fatalf("decoded %+v, want the golden claim", event)
+	}
+	if event.TxHash != txHash || event.Version != "" {
+		t.Fatalf("decoded tx %s, version %q, want %s from the bundled ABI", event.TxHash.Hex(), event.Version, txHash.Hex())
+	}
+	if message := hex.EncodeToString(ethClaimMessage(t, event)); message != goldenClaim.message {
+		t.Fatalf("decoded claim message = %s, want the golden %s", message, goldenClaim.message)
+	}
+}
+
+func TestParseHmyUnlockClaim(t *testing.T) {
+	event, err := ParseHmyUnlockClaim(htypes.Log{Topics: []common.Hash{hmyUnlockClaimTopic}, Data: goldenLogData(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.UnlockID.Cmp(goldenClaim.unlockID) != 0 || event.EthereumSender != goldenClaim.sender ||
+		event.HarmonyReceiver != goldenClaim.recipient || event.ValidatorAddress != goldenValidator ||
+		event.TokenAddress != goldenClaim.token || event.Amount.Cmp(goldenClaim.amount) != 0 {
+		t.Fatalf("decoded %+v, want the golden claim", event)
+	}
+}
+
+func TestParseUnlockClaimErrors(t *testing.T) {
+	data := goldenLogData(t)
+	tests := []struct {
+		name    string
+		log     ctypes.Log
+		wantErr error
+	}{
+		{"no topics", ctypes.Log{Data: data}, ErrUnexpectedEvent},
+		// The Harmony bridge's event is not the Ethereum one
+		{"other event", ctypes.Log{Topics: []common.Hash{hmyUnlockClaimTopic}, Data: data}, ErrUnexpectedEvent},
+		{"truncated", ctypes.Log{Topics: []common.Hash{ethUnlockClaimTopic}, Data: data[:5*32]}, ErrUndecodableEvent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseEthUnlockClaim(tt.log); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseEthUnlockClaim = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCorruptLogSkippedInBatch(t *testing.T) {
+	m, disable := useTestMetrics(t)
+	defer disable()
+	data := goldenLogData(t)
+	logs := []ctypes.Log{
+		{Topics: []common.Hash{ethUnlockClaimTopic}, Data: data, TxHash: common.HexToHash("0x1")},
+		{Topics: []common.Hash{ethUnlockClaimTopic}, Data: data[:3*32+7], TxHash: common.HexToHash("0x2")},
+		{Topics: []common.Hash{ethUnlockClaimTopic}, Data: data, TxHash: common.HexToHash("0x3")},
+	}
+
+	// As the log handlers do: skip an undecodable event and carry on with the rest of the batch
+	var events []types.EthLogNewUnlockClaimEvent
+	for _, log := range logs {
+		event, err := ParseEthUnlockClaim(log)
+		if errors.Is(err, ErrUndecodableEvent) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parsing log of tx %s = %v", log.TxHash.Hex(), err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 2 || events[0].TxHash != logs[0].TxHash || events[1].TxHash != logs[2].TxHash {
+		t.Fatalf("decoded %d events, want the 2 valid ones", len(events))
+	}
+	signed, err := SignClaimsBatch(NewKeySigner(testKey(t)), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 2 {
+		t.Fatalf("signed %d claims, want 2", len(signed))
+	}
+	if decode := testutil.ToFloat64(m.ClaimErrors.WithLabelValues(DecodeErrorReason)); decode != 1 {
+		t.Fatalf("claim_errors_total{reason=decode} = %v, want 1", decode)
+	}
+}
+
+// indexedClaimABI declares an unlock claim event with its sender and receiver indexed
+const indexedClaimABI = `[{"type":"event","name":"NewUnlockClaim","anonymous":false,"inputs":[
+	{"name":"unlockID","type":"uint256","indexed":false},
+	{"name":"sender","type":"address","indexed":true},
+	{"name":"receiver","type":"address","indexed":true},
+	{"name":"amount","type":"uint256","indexed":false}]}]`
+
+// indexedClaim is an indexedClaimABI event
+type indexedClaim struct {
+	UnlockID *big.Int
+	Sender   common.Address
+	Receiver common.Address
+	Amount   *big.Int
+}
+
+func TestUnpackEventIndexedArguments(t *testing.T) {
+	_, disable := useTestMetrics(t)
+	defer disable()
+	contractABI, err := abi.JSON(strings.NewReader(indexedClaimABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	topics := []common.Hash{
+		contractABI.Events["NewUnlockClaim"].ID,
+		common.BytesToHash(goldenClaim.sender.Bytes()),
+		common.BytesToHash(goldenClaim.recipient.Bytes()),
+	}
+	data := append(math.U256Bytes(new(big.Int).Set(goldenClaim.unlockID)), math.U256Bytes(new(big.Int).Set(goldenClaim.amount))...)
+
+	var event indexedClaim
+	if err := UnpackEvent(contractABI, &event, "NewUnlockClaim", topics, data); err != nil {
+		t.Fatal(err)
+	}
+	if event.UnlockID.Cmp(goldenClaim.unlockID) != 0 || event.Sender != goldenClaim.sender ||
+		event.Receiver != goldenClaim.recipient || event.Amount.Cmp(goldenClaim.amount) != 0 {
+		t.Fatalf("decoded %+v, want the golden claim's fields from topics and data", event)
+	}
+
+	// A log missing an indexed argument's topic is undecodable
+	if err := UnpackEvent(contractABI, &indexedClaim{}, "NewUnlockClaim", topics[:2], data); !errors.Is(err, ErrUndecodableEvent) {
+		t.Fatalf("UnpackEvent without the receiver topic = %v, want ErrUndecodableEvent", err)
+	}
+	if err := UnpackEvent(contractABI, &indexedClaim{}, "NewUnlockClaim", nil, data); !errors.Is(err, ErrUndecodableEvent) {
+		t.Fatalf("UnpackEvent without the signature topic = %v, want ErrUndecodableEvent", err)
+	}
+}
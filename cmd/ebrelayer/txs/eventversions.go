@@ -0,0 +1,355 @@
+package txs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrEventVersionCollision is returned when registering an UnlockClaim event version whose name or
+// signature topic is already registered, or is the bundled ABI's
+var ErrEventVersionCollision = errors.New("event version collision")
+
+// The claim fields an UnlockEventVersion maps to its event's arguments
+const (
+	UnlockIDField  = "unlockID"
+	SenderField    = "sender"
+	ReceiverField  = "receiver"
+	ValidatorField = "validator"
+	TokenField     = "token"
+	AmountField    = "amount"
+)
+
+// unlockEventFieldTypes is the Solidity type of each claim field's event argument
+var unlockEventFieldTypes = map[string]string{
+	UnlockIDField:  "uint256",
+	SenderField:    "address",
+	ReceiverField:  "address",
+	ValidatorField: "address",
+	TokenField:     "address",
+	AmountField:    "uint256",
+}
+
+// EthUnlockEvents, if set, holds the versions of the Ethereum HarmonyBridge's UnlockClaim event
+// watched besides the bundled ABI's, such as during a contract upgrade
+var EthUnlockEvents *UnlockEventRegistry
+
+// HmyUnlockEvents, if set, holds the versions of the Harmony EthereumBridge's UnlockClaim event
+// watched besides the bundled ABI's
+var HmyUnlockEvents *UnlockEventRegistry
+
+// UnlockEventVersion is a version of a bridge contract's UnlockClaim event, whose signature changed
+// from the bundled ABI's, as when an upgrade adds or renames an argument
+type UnlockEventVersion struct {
+	Name  string
+	Event abi.Event
+	// Fields names the event argument holding each claim field, keyed by UnlockIDField and the
+	// others, where it differs from the bundled ABI's argument
+	Fields map[string]string
+	// ClaimChainID, if set, is mixed into this version's claim messages in place of its chain's
+	// EthClaimChainID or HmyClaimChainID
+	ClaimChainID *big.Int
+	// ClaimTxHash, if set, overrides its chain's EthClaimTxHash or HmyClaimTxHash for this version's
+	// claim messages
+	ClaimTxHash *bool
+	// ClaimTokenAmountHash, if set, overrides its chain's EthClaimTokenAmountHash or
+	// HmyClaimTokenAmountHash for this version's claim messages
+	ClaimTokenAmountHash *bool
+
+	// args names the event argument of every claim field, as resolved on registration
+	args map[string]string
+}
+
+// UnlockClaimFields are the claim fields decoded from an UnlockClaim event
+type UnlockClaimFields struct {
+	UnlockID  *big.Int
+	Sender    common.Address
+	Receiver  common.Address
+	Validator common.Address
+	Token     common.Address
+	Amount    *big.Int
+}
+
+// unlockEventVersionJSON is the file format LoadUnlockEventVersion reads
+type unlockEventVersionJSON struct {
+	Name         string            `json:"name"`
+	Event        json.RawMessage   `json:"event"`
+	Fields       map[string]string `json:"fields,omitempty"`
+	ClaimChainID *big.Int          `json:"claimChainID,omitempty"`
+	ClaimTxHash  *bool             `json:"claimTxHash,omitempty"`
+
+	ClaimTokenAmountHash *bool `json:"claimTokenAmountHash,omitempty"`
+}
+
+// LoadUnlockEventVersion reads an UnlockEventVersion from the JSON file at path, holding its name,
+// its event as an ABI JSON entry, and optionally the fields it renames and its claim layout:
+//
+//	{"name": "v2", "event": {"type": "event", "name": "EthLogNewUnlockClaim", "inputs": [...]},
+//	 "fields": {"unlockID": "_id"}, "claimChainID": 1, "claimTxHash": true, "claimTokenAmountHash": true}
+func LoadUnlockEventVersion(path string) (*UnlockEventVersion, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file unlockEventVersionJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if file.Name == "" {
+		return nil, fmt.Errorf("%s: event version has no name", path)
+	}
+
+	contractABI, err := abi.JSON(strings.NewReader("[" + string(file.Event) + "]"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid event: %w", path, err)
+	}
+	if len(contractABI.Events) != 1 {
+		return nil, fmt.Errorf("%s: expected one event, found %d", path, len(contractABI.Events))
+	}
+	version := &UnlockEventVersion{Name: file.Name, Fields: file.Fields, ClaimChainID: file.ClaimChainID,
+		ClaimTxHash: file.ClaimTxHash, ClaimTokenAmountHash: file.ClaimTokenAmountHash}
+	for _, event := range contractABI.Events {
+		version.Event = event
+	}
+	return version, nil
+}
+
+// Decode decodes the claim fields of a log of the version's event from its topics and data,
+// returning ErrUndecodableEvent if it can't be unpacked. The version must be registered.
+func (v *UnlockEventVersion) Decode(topics []common.Hash, data []byte) (fields UnlockClaimFields, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: unpacking %s %s: %v", ErrUndecodableEvent, v.Event.Name, v.Name, r)
+		}
+		if err != nil {
+			getMetrics().claimError(DecodeErrorReason, err)
+		}
+	}()
+
+	values := make(map[string]interface{})
+	if nonIndexed := v.Event.Inputs.NonIndexed(); len(nonIndexed) > 0 {
+		if err := nonIndexed.UnpackIntoMap(values, data); err != nil {
+			return fields, fmt.Errorf("%w: unpacking %s %s: %v", ErrUndecodableEvent, v.Event.Name, v.Name, err)
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range v.Event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if !v.Event.Anonymous {
+		if len(topics) == 0 {
+			return fields, fmt.Errorf("%w: unpacking %s %s: missing signature topic", ErrUndecodableEvent, v.Event.Name, v.Name)
+		}
+		topics = topics[1:]
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, topics); err != nil {
+		return fields, fmt.Errorf("%w: unpacking %s %s topics: %v", ErrUndecodableEvent, v.Event.Name, v.Name, err)
+	}
+
+	fields.UnlockID, _ = values[v.args[UnlockIDField]].(*big.Int)
+	fields.Sender, _ = values[v.args[SenderField]].(common.Address)
+	fields.Receiver, _ = values[v.args[ReceiverField]].(common.Address)
+	fields.Validator, _ = values[v.args[ValidatorField]].(common.Address)
+	fields.Token, _ = values[v.args[TokenField]].(common.Address)
+	fields.Amount, _ = values[v.args[AmountField]].(*big.Int)
+	if fields.UnlockID == nil || fields.Amount == nil {
+		return fields, fmt.Errorf("%w: unpacking %s %s: missing unlock ID or amount", ErrUndecodableEvent, v.Event.Name, v.Name)
+	}
+	return fields, nil
+}
+
+// UnlockEventRegistry holds the versions of a chain's UnlockClaim event, by signature topic, each
+// decoded against its own ABI. It is safe for concurrent use.
+type UnlockEventRegistry struct {
+	bundled abi.Event
+	// defaults names the bundled event's argument holding each claim field
+	defaults map[string]string
+
+	mu       sync.RWMutex
+	versions map[common.Hash]*UnlockEventVersion
+	names    map[string]*UnlockEventVersion
+}
+
+// NewEthUnlockEventRegistry initializes a new UnlockEventRegistry for the Ethereum HarmonyBridge's
+// UnlockClaim event, holding no version but the bundled ABI's
+func NewEthUnlockEventRegistry() (*UnlockEventRegistry, error) {
+	contractABI, err := loadHarmonyBridgeABI()
+	if err != nil {
+		return nil, err
+	}
+	return newUnlockEventRegistry(contractABI.Events[types.EthLogNewUnlockClaim.String()], "_harmonySender",
+		"_ethereumReceiver"), nil
+}
+
+// NewHmyUnlockEventRegistry initializes a new UnlockEventRegistry for the Harmony EthereumBridge's
+// UnlockClaim event, holding no version but the bundled ABI's
+func NewHmyUnlockEventRegistry() (*UnlockEventRegistry, error) {
+	contractABI, err := loadEthereumBridgeABI()
+	if err != nil {
+		return nil, err
+	}
+	return newUnlockEventRegistry(contractABI.Events[types.HmyLogNewUnlockClaim.String()], "_ethereumSender",
+		"_harmonyReceiver"), nil
+}
+
+// newUnlockEventRegistry initializes a new UnlockEventRegistry for the bundled event, whose sender
+// and receiver arguments are named as given
+func newUnlockEventRegistry(bundled abi.Event, sender, receiver string) *UnlockEventRegistry {
+	return &UnlockEventRegistry{
+		bundled: bundled,
+		defaults: map[string]string{
+			UnlockIDField:  "_unlockID",
+			SenderField:    sender,
+			ReceiverField:  receiver,
+			ValidatorField: "_validatorAddress",
+			TokenField:     "_tokenAddress",
+			AmountField:    "_amount",
+		},
+		versions: make(map[common.Hash]*UnlockEventVersion),
+		names:    make(map[string]*UnlockEventVersion),
+	}
+}
+
+// Register registers version, checking every claim field names an argument of its event of the
+// field's type. It returns ErrEventVersionCollision if the version's name or signature topic is
+// already registered, or its topic is the bundled event's.
+func (r *UnlockEventRegistry) Register(version *UnlockEventVersion) error {
+	for field := range version.Fields {
+		if _, ok := unlockEventFieldTypes[field]; !ok {
+			return fmt.Errorf("event version %s maps unknown claim field %q", version.Name, field)
+		}
+	}
+	args := make(map[string]string, len(r.defaults))
+	for field, typ := range unlockEventFieldTypes {
+		name := r.defaults[field]
+		if renamed, ok := version.Fields[field]; ok {
+			name = renamed
+		}
+		arg, ok := eventArgument(version.Event, name)
+		if !ok {
+			return fmt.Errorf("event version %s has no %s argument %s", version.Name, field, name)
+		}
+		if arg.Type.String() != typ {
+			return fmt.Errorf("event version %s %s argument %s is %s, expected %s", version.Name, field, name,
+				arg.Type.String(), typ)
+		}
+		args[field] = name
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	topic := version.Event.ID
+	if topic == r.bundled.ID {
+		return fmt.Errorf("%w: %s has the bundled event's signature %s", ErrEventVersionCollision, version.Name,
+			version.Event.Sig)
+	}
+	if existing, ok := r.versions[topic]; ok {
+		return fmt.Errorf("%w: %s and %s share the signature %s", ErrEventVersionCollision, existing.Name,
+			version.Name, version.Event.Sig)
+	}
+	if _, ok := r.names[version.Name]; ok {
+		return fmt.Errorf("%w: %s is registered twice", ErrEventVersionCollision, version.Name)
+	}
+	version.args = args
+	r.versions[topic] = version
+	r.names[version.Name] = version
+	return nil
+}
+
+// Lookup returns the version whose signature topic is topic, if registered
+func (r *UnlockEventRegistry) Lookup(topic common.Hash) (*UnlockEventVersion, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	version, ok := r.versions[topic]
+	return version, ok
+}
+
+// Version returns the version registered as name, if any
+func (r *UnlockEventRegistry) Version(name string) (*UnlockEventVersion, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	version, ok := r.names[name]
+	return version, ok
+}
+
+// Topics returns the signature topics of the registered versions, in order
+func (r *UnlockEventRegistry) Topics() []common.Hash {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	topics := make([]common.Hash, 0, len(r.versions))
+	for topic := range r.versions {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Hex() < topics[j].Hex() })
+	return topics
+}
+
+// eventArgument returns event's argument called name
+func eventArgument(event abi.Event, name string) (abi.Argument, bool) {
+	for _, arg := range event.Inputs {
+		if arg.Name == name {
+			return arg, true
+		}
+	}
+	return abi.Argument{}, false
+}
+
+// EthUnlockClaimTopics returns the signature topics of the Ethereum HarmonyBridge's UnlockClaim
+// event to watch: the bundled ABI's, followed by those of EthUnlockEvents
+func EthUnlockClaimTopics() ([]common.Hash, error) {
+	contractABI, err := loadHarmonyBridgeABI()
+	if err != nil {
+		return nil, err
+	}
+	return append([]common.Hash{contractABI.Events[types.EthLogNewUnlockClaim.String()].ID}, EthUnlockEvents.Topics()...), nil
+}
+
+// HmyUnlockClaimTopics returns the signature topics of the Harmony EthereumBridge's UnlockClaim
+// event to watch: the bundled ABI's, followed by those of HmyUnlockEvents
+func HmyUnlockClaimTopics() ([]common.Hash, error) {
+	contractABI, err := loadEthereumBridgeABI()
+	if err != nil {
+		return nil, err
+	}
+	return append([]common.Hash{contractABI.Events[types.HmyLogNewUnlockClaim.String()].ID}, HmyUnlockEvents.Topics()...), nil
+}
+
+// claimEventVersion returns the registered version a claim event was decoded with, if any
+func claimEventVersion(event types.ClaimEvent) (*UnlockEventVersion, bool) {
+	switch e := event.(type) {
+	case types.EthLogNewUnlockClaimEvent:
+		if e.Version != "" {
+			return EthUnlockEvents.Version(e.Version)
+		}
+	case types.HmyLogNewUnlockClaimEvent:
+		if e.Version != "" {
+			return HmyUnlockEvents.Version(e.Version)
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,141 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// testUnlockEventV2 is an upgraded UnlockClaim event which indexes the unlock ID as _id, adds a
+// trailing memo, and binds the source tx hash into its claims
+const testUnlockEventV2 = `{
+	"name": "v2",
+	"event": {"type": "event", "name": "EthLogNewUnlockClaim", "anonymous": false, "inputs": [
+		{"name": "_id", "type": "uint256", "indexed": true},
+		{"name": "_harmonySender", "type": "address", "indexed": false},
+		{"name": "_ethereumReceiver", "type": "address", "indexed": false},
+		{"name": "_validatorAddress", "type": "address", "indexed": false},
+		{"name": "_tokenAddress", "type": "address", "indexed": false},
+		{"name": "_amount", "type": "uint256", "indexed": false},
+		{"name": "_memo", "type": "string", "indexed": false}
+	]},
+	"fields": {"unlockID": "_id"},
+	"claimTxHash": true
+}`
+
+// loadTestUnlockEventV2 loads testUnlockEventV2 from a file, as --ethereum-unlock-event does
+func loadTestUnlockEventV2(t *testing.T) *UnlockEventVersion {
+	dir, err := ioutil.TempDir("", "ebrelayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "v2.json")
+	if err := ioutil.WriteFile(path, []byte(testUnlockEventV2), 0600); err != nil {
+		t.Fatal(err)
+	}
+	version, err := LoadUnlockEventVersion(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func TestUnlockEventVersionsBothDecode(t *testing.T) {
+	defer func(events *UnlockEventRegistry) { EthUnlockEvents = events }(EthUnlockEvents)
+	registry, err := NewEthUnlockEventRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2 := loadTestUnlockEventV2(t)
+	if err := registry.Register(v2); err != nil {
+		t.Fatal(err)
+	}
+	EthUnlockEvents = registry
+
+	topics, err := EthUnlockClaimTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0] != ethUnlockClaimTopic || topics[1] != v2.Event.ID {
+		t.Fatalf("watching topics %v, want the bundled and v2 topics", topics)
+	}
+
+	data, err := v2.Event.Inputs.NonIndexed().Pack(goldenClaim.sender, goldenClaim.recipient, goldenValidator,
+		goldenClaim.token, goldenClaim.amount, "upgraded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash := common.HexToHash("0x2")
+	logs := map[string]ctypes.Log{
+		"":   {Topics: []common.Hash{ethUnlockClaimTopic}, Data: goldenLogData(t), TxHash: common.HexToHash("0x1")},
+		"v2": {Topics: []common.Hash{v2.Event.ID, common.BigToHash(goldenClaim.unlockID)}, Data: data, TxHash: txHash},
+	}
+	for version, log := range logs {
+		event, err := ParseEthUnlockClaim(log)
+		if err != nil {
+			t.Fatalf("parsing a %q log: %v", version, err)
+		}
+		if event.UnlockID.Cmp(goldenClaim.unlockID) != 0 || event.HarmonySender != goldenClaim.sender ||
+			event.EthereumReceiver != goldenClaim.recipient || event.ValidatorAddress != goldenValidator ||
+			event.TokenAddress != goldenClaim.token || event.Amount.Cmp(goldenClaim.amount) != 0 {
+			t.Fatalf("decoded %q log as %+v, want the golden claim", version, event)
+		}
+		if event.Version != version || event.TxHash != log.TxHash {
+			t.Fatalf("decoded version %q of tx %s, want %q", event.Version, event.TxHash.Hex(), version)
+		}
+
+		// v2 claims bind their tx hash, while the bundled event's keep the deployed layout
+		message, err := ClaimMessage(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := common.Hex2Bytes(goldenClaim.message)
+		if version == "v2" {
+			if want, err = buildClaimHash(goldenClaim.unlockID, goldenClaim.sender, goldenClaim.recipient, goldenClaim.token,
+				goldenClaim.amount, nil, &txHash); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !bytes.Equal(message, want) {
+			t.Fatalf("claim message of a %q log = %x, want %s", version, message, hex.EncodeToString(want))
+		}
+	}
+
+	truncated := logs["v2"]
+	truncated.Data = truncated.Data[:5*32]
+	if _, err := ParseEthUnlockClaim(truncated); !errors.Is(err, ErrUndecodableEvent) {
+		t.Fatalf("parsing a truncated v2 log = %v, want ErrUndecodableEvent", err)
+	}
+}
+
+func TestUnlockEventRegistryRejects(t *testing.T) {
+	registry, err := NewEthUnlockEventRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(loadTestUnlockEventV2(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(loadTestUnlockEventV2(t)); !errors.Is(err, ErrEventVersionCollision) {
+		t.Fatalf("registering v2 twice = %v, want ErrEventVersionCollision", err)
+	}
+
+	unmapped := loadTestUnlockEventV2(t)
+	unmapped.Name, unmapped.Fields = "v3", nil
+	if err := registry.Register(unmapped); err == nil {
+		t.Fatal("registered a version whose unlock ID argument was renamed unmapped")
+	}
+	mistyped := loadTestUnlockEventV2(t)
+	mistyped.Name, mistyped.Fields = "v3", map[string]string{UnlockIDField: "_id", AmountField: "_memo"}
+	if err := registry.Register(mistyped); err == nil {
+		t.Fatal("registered a version mapping the amount to a string")
+	}
+}
@@ -0,0 +1,268 @@
+package txs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrClaimExported is returned in place of submitting a signed claim handed to ClaimExport
+var ErrClaimExported = fmt.Errorf("%w: exported for an external submitter", ErrClaimSkipped)
+
+// ClaimExport, if set, receives each signed oracle claim as a SignedClaimJSON record instead of
+// the relayer submitting it, for deployments which keep the signer apart from the submitter
+var ClaimExport *ClaimExporter
+
+// Decimal is a big integer marshaled as a JSON string of its decimal digits, so that values
+// beyond a float's precision survive readers which parse JSON numbers as floats
+type Decimal big.Int
+
+// NewDecimal returns n as a Decimal
+func NewDecimal(n *big.Int) *Decimal {
+	return (*Decimal)(new(big.Int).Set(n))
+}
+
+// Int returns d as a big.Int
+func (d *Decimal) Int() *big.Int {
+	return new(big.Int).Set((*big.Int)(d))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d *Decimal) MarshalText() ([]byte, error) {
+	return []byte((*big.Int)(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if _, ok := (*big.Int)(d).SetString(string(text), 10); !ok {
+		return fmt.Errorf("invalid decimal integer %q", text)
+	}
+	return nil
+}
+
+// SignedClaimJSON is the JSON record of a signed oracle claim, carrying everything an external
+// submitter needs to submit it to Chain, and the claim's fields to audit it against
+type SignedClaimJSON struct {
+	Chain     string         `json:"chain"`
+	UnlockID  *Decimal       `json:"unlockID"`
+	Sender    common.Address `json:"sender"`
+	Recipient common.Address `json:"recipient"`
+	Token     common.Address `json:"token"`
+	Amount    *Decimal       `json:"amount"`
+	Message   hexutil.Bytes  `json:"message"`
+	Signature hexutil.Bytes  `json:"signature"`
+	Signer    common.Address `json:"signer"`
+}
+
+// NewSignedClaimJSON returns the record of signer's signature over the claim message of event
+func NewSignedClaimJSON(event types.ClaimEvent, message, signature []byte, signer common.Address) SignedClaimJSON {
+	unlockID, sender, recipient, token, amount := event.ClaimFields()
+	return SignedClaimJSON{
+		Chain:     claimEventChain(event),
+		UnlockID:  NewDecimal(unlockID),
+		Sender:    sender,
+		Recipient: recipient,
+		Token:     token,
+		Amount:    NewDecimal(amount),
+		Message:   append(hexutil.Bytes{}, message...),
+		Signature: append(hexutil.Bytes{}, signature...),
+		Signer:    signer,
+	}
+}
+
+// check returns an error if the record is missing anything the oracle claim needs
+func (c SignedClaimJSON) check() error {
+	if c.UnlockID == nil {
+		return fmt.Errorf("claim has no unlock ID")
+	}
+	if len(c.Message) != 32 {
+		return fmt.Errorf("claim %v message is %d bytes, expected 32", c.UnlockID.Int(), len(c.Message))
+	}
+	if len(c.Signature) != crypto.SignatureLength {
+		return fmt.Errorf("claim %v signature is %d bytes, expected %d", c.UnlockID.Int(), len(c.Signature),
+			crypto.SignatureLength)
+	}
+	return nil
+}
+
+// EthOracleClaim returns the Ethereum oracle claim the record describes
+func (c SignedClaimJSON) EthOracleClaim() (EthOracleClaim, error) {
+	claim := EthOracleClaim{}
+	if c.Chain != ethereumChainLabel {
+		return claim, fmt.Errorf("claim is for %s, not %s", c.Chain, ethereumChainLabel)
+	}
+	if err := c.check(); err != nil {
+		return claim, err
+	}
+	claim.UnlockID = c.UnlockID.Int()
+	copy(claim.Message[:], c.Message)
+	claim.Signature = append([]byte(nil), c.Signature...)
+	return claim, nil
+}
+
+// HmyOracleClaim returns the Harmony oracle claim the record describes
+func (c SignedClaimJSON) HmyOracleClaim() (HmyOracleClaim, error) {
+	claim := HmyOracleClaim{}
+	if c.Chain != harmonyChainLabel {
+		return claim, fmt.Errorf("claim is for %s, not %s", c.Chain, harmonyChainLabel)
+	}
+	if err := c.check(); err != nil {
+		return claim, err
+	}
+	claim.UnlockID = c.UnlockID.Int()
+	copy(claim.Message[:], c.Message)
+	claim.Signature = append([]byte(nil), c.Signature...)
+	return claim, nil
+}
+
+// ClaimExporter writes SignedClaimJSON records: as JSON lines to a writer, or as one file per
+// claim into a directory, each written atomically so that a submitter polling the directory never
+// reads a partial record. It is safe for concurrent use.
+type ClaimExporter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	close func() error
+	dir   string
+}
+
+// NewClaimExporter initializes a new ClaimExporter writing to target: stdout if target is "-",
+// one file per claim if target is an existing directory, or otherwise lines appended to the file
+// target
+func NewClaimExporter(target string) (*ClaimExporter, error) {
+	if target == "-" {
+		return &ClaimExporter{w: os.Stdout}, nil
+	}
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return &ClaimExporter{dir: target}, nil
+	}
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimExporter{w: file, close: file.Close}, nil
+}
+
+// Export writes claim
+func (e *ClaimExporter) Export(claim SignedClaimJSON) error {
+	data, err := json.Marshal(claim)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.dir != "" {
+		name := fmt.Sprintf("%s-%s.json", claim.Chain, claim.UnlockID.Int())
+		return types.WriteFileAtomic(filepath.Join(e.dir, name), data)
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file written to, if any
+func (e *ClaimExporter) Close() error {
+	if e.close == nil {
+		return nil
+	}
+	return e.close()
+}
+
+// exportClaim hands signer's signed claim for event to ClaimExport, if set, returning
+// ErrClaimExported once it was
+func exportClaim(event types.ClaimEvent, message, signature []byte, signer common.Address) error {
+	if ClaimExport == nil {
+		return nil
+	}
+	record := NewSignedClaimJSON(event, message, signature, signer)
+	if err := ClaimExport.Export(record); err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
+	}
+	getLogger().Info("Exported signed claim", "chain", record.Chain, "unlockID", record.UnlockID.Int())
+	return ErrClaimExported
+}
+
+// maxClaimLine is the longest line ReadSignedClaims reads
+const maxClaimLine = 1 << 20
+
+// ReadSignedClaims decodes the SignedClaimJSON records in r, one per line as a ClaimExporter
+// writes them. Lines which aren't JSON objects, such as the relayer's own output when exporting
+// to stdout, are skipped.
+func ReadSignedClaims(r io.Reader) ([]SignedClaimJSON, error) {
+	var claims []SignedClaimJSON
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxClaimLine)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 || data[0] != '{' || !json.Valid(data) {
+			continue
+		}
+		var claim SignedClaimJSON
+		if err := json.Unmarshal(data, &claim); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		claims = append(claims, claim)
+	}
+	return claims, scanner.Err()
+}
+
+// ReadSignedClaimsFrom reads the SignedClaimJSON records a ClaimExporter wrote to source: stdin
+// if source is "-", each .json file in the directory source in name order, or the file source
+func ReadSignedClaimsFrom(source string) ([]SignedClaimJSON, error) {
+	if source == "-" {
+		return ReadSignedClaims(os.Stdin)
+	}
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return ReadSignedClaims(file)
+	}
+
+	entries, err := ioutil.ReadDir(source)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var claims []SignedClaimJSON
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(source, name))
+		if err != nil {
+			return nil, err
+		}
+		var claim SignedClaimJSON
+		if err := json.Unmarshal(data, &claim); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		claims = append(claims, claim)
+	}
+	return claims, nil
+}
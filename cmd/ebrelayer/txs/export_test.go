@@ -0,0 +1,186 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// hmyExportEvent is a Harmony claim whose 30-digit amount is beyond a float's precision
+func hmyExportEvent(t *testing.T) types.HmyLogNewUnlockClaimEvent {
+	amount, ok := new(big.Int).SetString("123456789012345678901234567891", 10)
+	if !ok {
+		t.Fatal("invalid amount")
+	}
+	return types.HmyLogNewUnlockClaimEvent{
+		UnlockID:        goldenClaim.unlockID,
+		EthereumSender:  goldenClaim.sender,
+		HarmonyReceiver: goldenClaim.recipient,
+		TokenAddress:    goldenClaim.token,
+		Amount:          amount,
+	}
+}
+
+func TestSignedClaimJSONRoundTrip(t *testing.T) {
+	signer, sig := testSignature(t)
+	event := hmyExportEvent(t)
+	record := NewSignedClaimJSON(event, common.Hex2Bytes(goldenClaim.message), sig, signer)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Big integers are strings, not JSON numbers a reader might round through a float
+	if !bytes.Contains(data, []byte(`"amount":"123456789012345678901234567891"`)) {
+		t.Fatalf("marshaled %s, want the amount as a decimal string", data)
+	}
+	var decoded SignedClaimJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, record) {
+		t.Fatalf("round trip = %+v, want %+v", decoded, record)
+	}
+
+	claim, err := decoded.HmyOracleClaim()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.UnlockID.Cmp(goldenClaim.unlockID) != 0 || common.Bytes2Hex(claim.Message[:]) != goldenClaim.message ||
+		!bytes.Equal(claim.Signature, sig) {
+		t.Fatalf("oracle claim = %+v, want the exported claim", claim)
+	}
+	if _, err := decoded.EthOracleClaim(); err == nil {
+		t.Fatal("converted a Harmony claim to an Ethereum one")
+	}
+
+	if err := json.Unmarshal([]byte(`{"unlockID":"0x2a"}`), &decoded); err == nil {
+		t.Fatal("decoded a hex unlock ID as a decimal")
+	}
+	truncated := record
+	truncated.Signature = truncated.Signature[:64]
+	if _, err := truncated.HmyOracleClaim(); err == nil {
+		t.Fatal("converted a claim with a truncated signature")
+	}
+}
+
+func TestClaimExporterRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	signer, sig := testSignature(t)
+	first := NewSignedClaimJSON(hmyExportEvent(t), common.Hex2Bytes(goldenClaim.message), sig, signer)
+	second := NewSignedClaimJSON(goldenEthEvent(), common.Hex2Bytes(goldenClaim.message), sig, signer)
+	want := []SignedClaimJSON{first, second}
+
+	claimsDir := filepath.Join(dir, "claims")
+	if err := os.Mkdir(claimsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	// Only .json files are claims
+	if err := ioutil.WriteFile(filepath.Join(claimsDir, "notes.txt"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range []string{claimsDir, filepath.Join(dir, "claims.jsonl")} {
+		exporter, err := NewClaimExporter(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, record := range want {
+			if err := exporter.Export(record); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := exporter.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		claims, err := ReadSignedClaimsFrom(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The directory is read in file name order, harmony-42.json after ethereum-42.json
+		if target == claimsDir {
+			claims[0], claims[1] = claims[1], claims[0]
+		}
+		if !reflect.DeepEqual(claims, want) {
+			t.Fatalf("read %+v from %s, want %+v", claims, target, want)
+		}
+	}
+}
+
+func TestReadSignedClaimsSkipsLogOutput(t *testing.T) {
+	signer, sig := testSignature(t)
+	record := NewSignedClaimJSON(hmyExportEvent(t), common.Hex2Bytes(goldenClaim.message), sig, signer)
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// As piped from the relayer's stdout, with its log lines in between
+	stream := strings.Join([]string{
+		`INFO [10-14|08:44:11] Exported signed claim chain=harmony unlockID=42`,
+		string(data),
+		`{"truncated":`,
+		"",
+	}, "\n")
+	claims, err := ReadSignedClaims(strings.NewReader(stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 1 || !reflect.DeepEqual(claims[0], record) {
+		t.Fatalf("read %+v, want only the exported claim", claims)
+	}
+}
+
+func TestSignClaimsBatchExports(t *testing.T) {
+	defer func(export *ClaimExporter) { ClaimExport = export }(ClaimExport)
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ClaimExport, err = NewClaimExporter(filepath.Join(dir, "claims.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ClaimExport.Close()
+
+	signed, err := SignClaimsBatch(NewKeySigner(testKey(t)), []types.EthLogNewUnlockClaimEvent{goldenEthEvent()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 1 || !signed[0].Exported {
+		t.Fatalf("signed %+v, want the claim marked exported", signed)
+	}
+	claims, err := ReadSignedClaimsFrom(filepath.Join(dir, "claims.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 1 || !bytes.Equal(claims[0].Signature, signed[0].Signature) {
+		t.Fatalf("exported %+v, want the signed claim", claims)
+	}
+	claim, err := claims[0].EthOracleClaim()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.Message != signed[0].Message || claim.UnlockID.Cmp(goldenClaim.unlockID) != 0 {
+		t.Fatalf("oracle claim = %+v, want the signed claim", claim)
+	}
+	if !errors.Is(ErrClaimExported, ErrClaimSkipped) {
+		t.Fatal("ErrClaimExported doesn't mark the event handled as skipped")
+	}
+}
@@ -0,0 +1,145 @@
+package txs
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ClaimTokenField and ClaimAmountField are the indexes of the token and amount among the fields
+// ClaimMessageFields returns
+const (
+	ClaimTokenField  = 3
+	ClaimAmountField = 4
+)
+
+// ClaimField is one Solidity-typed value packed into a claim message
+type ClaimField struct {
+	Type  string
+	Value interface{}
+}
+
+// ClaimLayout is the ordered list of fields a claim message is packed from, for claim layouts
+// beyond ClaimMessageLayout, such as an amount given as a mantissa and its decimals
+type ClaimLayout []ClaimField
+
+// Types returns the layout's Solidity types, in order
+func (l ClaimLayout) Types() []string {
+	types := make([]string, len(l))
+	for i, field := range l {
+		types[i] = field.Type
+	}
+	return types
+}
+
+// Values returns the layout's values, in order
+func (l ClaimLayout) Values() []interface{} {
+	values := make([]interface{}, len(l))
+	for i, field := range l {
+		values[i] = field.Value
+	}
+	return values
+}
+
+// Hash packs the layout's values against its types and hashes them, as SoliditySHA3Typed does
+func (l ClaimLayout) Hash(opts ...HashOption) ([]byte, error) {
+	if len(l) == 0 {
+		return nil, fmt.Errorf("claim layout has no fields")
+	}
+	return SoliditySHA3Typed(l.Types(), l.Values(), opts...)
+}
+
+// Replace returns the layout with its field at index replaced by fields, such as the amount at
+// ClaimAmountField by its DecimalAmountFields
+func (l ClaimLayout) Replace(index int, fields ...ClaimField) (ClaimLayout, error) {
+	if index < 0 || index >= len(l) {
+		return nil, fmt.Errorf("claim layout has no field %d", index)
+	}
+	replaced := make(ClaimLayout, 0, len(l)-1+len(fields))
+	replaced = append(replaced, l[:index]...)
+	replaced = append(replaced, fields...)
+	return append(replaced, l[index+1:]...), nil
+}
+
+// ClaimMessageFields returns the fields of a claim's message in ClaimMessageLayout order, followed
+// by chainID if set, for a caller to extend or replace before hashing them
+func ClaimMessageFields(unlockID *big.Int, sender, recipient, token common.Address, amount, chainID *big.Int) ClaimLayout {
+	return claimFields(claimMessageLayout([]interface{}{unlockID, sender, recipient, token, amount}, chainID, nil))
+}
+
+// claimFields pairs a claim message's layout with its values
+func claimFields(layout []string, values []interface{}) ClaimLayout {
+	fields := make(ClaimLayout, len(layout))
+	for i := range layout {
+		fields[i] = ClaimField{Type: layout[i], Value: values[i]}
+	}
+	return fields
+}
+
+// EventClaimFields returns the fields of a claim event's message as ClaimMessage packs them: with
+// the amount rescaled by Tokens, the token and amount hashed together if enabled, the chain ID
+// configured for the event's chain appended if any, and the event's source transaction hash after
+// it if enabled
+func EventClaimFields(event types.ClaimEvent) (ClaimLayout, error) {
+	layout, values, err := claimMessageComponents(event, claimChainID(event))
+	if err != nil {
+		return nil, err
+	}
+	return claimFields(layout, values), nil
+}
+
+// SubHash hashes values, packed against layout as SoliditySHA3Typed does, or ABI-encoded with
+// WithEncoding(ABIEncoding), returning the hash as a bytes32 field for a claim layout embedding
+// it in place of the values themselves
+func SubHash(layout []string, values []interface{}, opts ...HashOption) (ClaimField, error) {
+	hash, err := SoliditySHA3Typed(layout, values, opts...)
+	if err != nil {
+		return ClaimField{}, err
+	}
+	return ClaimField{Type: "bytes32", Value: common.BytesToHash(hash)}, nil
+}
+
+// TokenAmountField returns a claim's token and amount as the sub-hash
+// keccak256(abi.encode(token, amount)), the hash of the contracts' TokenAmount tuple
+func TokenAmountField(token common.Address, amount *big.Int) (ClaimField, error) {
+	if amount == nil {
+		return ClaimField{}, fmt.Errorf("token amount has no amount")
+	}
+	return SubHash([]string{"address", "uint256"}, []interface{}{token, amount}, WithEncoding(ABIEncoding))
+}
+
+// claimTokenAmountLayout replaces the token and amount of a claim message's layout by their
+// TokenAmountField, if the event's chain, or its event version, hashes them together
+func claimTokenAmountLayout(event types.ClaimEvent, layout []string, values []interface{}) ([]string, []interface{}, error) {
+	enabled := EthClaimTokenAmountHash
+	if _, ok := event.(types.HmyLogNewUnlockClaimEvent); ok {
+		enabled = HmyClaimTokenAmountHash
+	}
+	if version, ok := claimEventVersion(event); ok && version.ClaimTokenAmountHash != nil {
+		enabled = *version.ClaimTokenAmountHash
+	}
+	if !enabled {
+		return layout, values, nil
+	}
+
+	amount, _ := values[ClaimAmountField].(*big.Int)
+	field, err := TokenAmountField(values[ClaimTokenField].(common.Address), amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields, err := claimFields(layout, values).Replace(ClaimAmountField, field)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields = append(fields[:ClaimTokenField], fields[ClaimTokenField+1:]...)
+	return fields.Types(), fields.Values(), nil
+}
+
+// DecimalAmountFields returns an amount laid out as (uint256 mantissa, uint8 decimals), for
+// claim layouts encoding it as a fixed-point value
+func DecimalAmountFields(mantissa *big.Int, decimals uint8) ClaimLayout {
+	return ClaimLayout{{Type: "uint256", Value: mantissa}, {Type: "uint8", Value: decimals}}
+}
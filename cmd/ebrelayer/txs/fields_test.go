@@ -0,0 +1,130 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// goldenDecimalClaimHash is the hash of goldenClaim with its amount laid out as 15 with 17 decimals
+const goldenDecimalClaimHash = "612a4d51c7eb49c31d36edf9d07521bf85a5c35b39cfa54bdcd93bb3168a1a63"
+
+// goldenTokenAmountClaimHash is the hash of goldenClaim with its token and amount laid out as their
+// TokenAmount sub-hash
+const goldenTokenAmountClaimHash = "0ff181219aa3b9301110c5ae42f218f38b1dd6898db076ed264b091932fc0dfa"
+
+func TestClaimMessageFieldsMatchBuildClaimHash(t *testing.T) {
+	fields := ClaimMessageFields(goldenClaim.unlockID, goldenClaim.sender, goldenClaim.recipient, goldenClaim.token,
+		goldenClaim.amount, nil)
+	hash, err := fields.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(hash); got != goldenClaim.message {
+		t.Fatalf("standard fields hash = %s, want BuildClaimHash's %s", got, goldenClaim.message)
+	}
+
+	fields, err = EventClaimFields(goldenEthEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash, err = fields.Hash(); err != nil || hex.EncodeToString(hash) != goldenClaim.message {
+		t.Fatalf("event fields hash = %x, %v, want the golden %s", hash, err, goldenClaim.message)
+	}
+}
+
+func TestDecimalAmountLayoutHash(t *testing.T) {
+	mantissa := big.NewInt(15)
+	fields, err := ClaimMessageFields(goldenClaim.unlockID, goldenClaim.sender, goldenClaim.recipient,
+		goldenClaim.token, goldenClaim.amount, nil).Replace(ClaimAmountField, DecimalAmountFields(mantissa, 17)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := fields.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The packed preimage ends with the mantissa's word and the decimals as a single byte
+	preimage := bytes.Join([][]byte{
+		math.U256Bytes(new(big.Int).Set(goldenClaim.unlockID)),
+		goldenClaim.sender.Bytes(), goldenClaim.recipient.Bytes(), goldenClaim.token.Bytes(),
+		math.U256Bytes(new(big.Int).Set(mantissa)), {17},
+	}, nil)
+	if want := crypto.Keccak256(preimage); !bytes.Equal(hash, want) {
+		t.Fatalf("mantissa+decimals hash = %x, want keccak256 of the packed preimage %x", hash, want)
+	}
+	if got := hex.EncodeToString(hash); got != goldenDecimalClaimHash {
+		t.Fatalf("mantissa+decimals hash = %s, want the golden %s", got, goldenDecimalClaimHash)
+	}
+}
+
+func TestTokenAmountClaimHash(t *testing.T) {
+	defer func(eth, hmy bool) { EthClaimTokenAmountHash, HmyClaimTokenAmountHash = eth, hmy }(EthClaimTokenAmountHash,
+		HmyClaimTokenAmountHash)
+	EthClaimTokenAmountHash, HmyClaimTokenAmountHash = true, false
+
+	// As the contracts compute it: keccak256(abi.encodePacked(unlockID, sender, recipient,
+	// keccak256(abi.encode(token, amount))))
+	tokenAmount := crypto.Keccak256(common.LeftPadBytes(goldenClaim.token.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(goldenClaim.amount)))
+	want := crypto.Keccak256(math.U256Bytes(new(big.Int).Set(goldenClaim.unlockID)), goldenClaim.sender.Bytes(),
+		goldenClaim.recipient.Bytes(), tokenAmount)
+
+	field, err := TokenAmountField(goldenClaim.token, goldenClaim.amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if field.Type != "bytes32" || field.Value != common.BytesToHash(tokenAmount) {
+		t.Fatalf("TokenAmountField = %s %v, want bytes32 %x", field.Type, field.Value, tokenAmount)
+	}
+	message, err := ClaimMessage(goldenEthEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(message, want) {
+		t.Fatalf("nested-hash claim message = %x, want the contract's %x", message, want)
+	}
+	if got := hex.EncodeToString(message); got != goldenTokenAmountClaimHash {
+		t.Fatalf("nested-hash claim message = %s, want the golden %s", got, goldenTokenAmountClaimHash)
+	}
+	fields, err := EventClaimFields(goldenEthEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash, err := fields.Hash(); err != nil || !bytes.Equal(hash, want) {
+		t.Fatalf("EventClaimFields hash = %x, %v, want %x", hash, err, want)
+	}
+
+	// Harmony claims keep the inline layout while only Ethereum's hashes them together
+	hmyEvent := types.HmyLogNewUnlockClaimEvent{UnlockID: goldenClaim.unlockID, EthereumSender: goldenClaim.sender,
+		HarmonyReceiver: goldenClaim.recipient, TokenAddress: goldenClaim.token, Amount: goldenClaim.amount}
+	if message, err := ClaimMessage(hmyEvent); err != nil || hex.EncodeToString(message) != goldenClaim.message {
+		t.Fatalf("Harmony claim message = %x, %v, want the golden %s", message, err, goldenClaim.message)
+	}
+}
+
+func TestClaimLayoutReplace(t *testing.T) {
+	layout := ClaimLayout{{Type: "uint256", Value: big.NewInt(1)}, {Type: "address", Value: common.Address{}}}
+	if _, err := layout.Replace(2); err == nil {
+		t.Fatal("replaced a field beyond the layout")
+	}
+	if _, err := (ClaimLayout{}).Hash(); err == nil {
+		t.Fatal("hashed an empty layout")
+	}
+	// Replace leaves the layout it's called on unchanged
+	replaced, err := layout.Replace(0, DecimalAmountFields(big.NewInt(1), 2)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replaced) != 3 || replaced[1].Type != "uint8" || len(layout) != 2 || layout[0].Type != "uint256" {
+		t.Fatalf("Replace = %+v of %+v", replaced, layout)
+	}
+}
@@ -0,0 +1,67 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrTokenNotAllowed is returned in place of signing a claim for a token rejected by ClaimTokenFilter
+var ErrTokenNotAllowed = errors.New("token not allowed")
+
+// ClaimTokenFilter, if set, restricts the tokens claims are signed for
+var ClaimTokenFilter *TokenFilter
+
+// TokenFilterMode selects whether a TokenFilter's tokens are the only ones allowed, or denied
+type TokenFilterMode int
+
+const (
+	// AllowlistMode allows only the filter's tokens
+	AllowlistMode TokenFilterMode = iota
+	// DenylistMode allows every token but the filter's
+	DenylistMode
+)
+
+// String implements fmt.Stringer
+func (m TokenFilterMode) String() string {
+	return [...]string{"allowlist", "denylist"}[m]
+}
+
+// TokenFilter decides which tokens the relayer signs claims for. Tokens are compared as
+// common.Address, so the case of a hex address doesn't matter.
+type TokenFilter struct {
+	Mode   TokenFilterMode
+	tokens map[common.Address]struct{}
+}
+
+// NewTokenFilter initializes a new TokenFilter allowing or denying tokens according to mode
+func NewTokenFilter(mode TokenFilterMode, tokens ...common.Address) *TokenFilter {
+	filter := &TokenFilter{Mode: mode, tokens: make(map[common.Address]struct{}, len(tokens))}
+	for _, token := range tokens {
+		filter.tokens[token] = struct{}{}
+	}
+	return filter
+}
+
+// Allowed reports whether claims may be signed for token
+func (f *TokenFilter) Allowed(token common.Address) bool {
+	_, listed := f.tokens[token]
+	if f.Mode == DenylistMode {
+		return !listed
+	}
+	return listed
+}
+
+// checkTokenAllowed logs and returns ErrTokenNotAllowed if ClaimTokenFilter rejects the event's token
+func checkTokenAllowed(event types.ClaimEvent) error {
+	unlockID, _, _, token, amount := event.ClaimFields()
+	if ClaimTokenFilter == nil || ClaimTokenFilter.Allowed(token) {
+		return nil
+	}
+	getLogger().Info("Skipping claim for disallowed token", "unlockID", unlockID, "token", token.Hex(),
+		"amount", FormatTokenAmount(token, amount), "mode", ClaimTokenFilter.Mode.String())
+	return fmt.Errorf("%w: %s", ErrTokenNotAllowed, token.Hex())
+}
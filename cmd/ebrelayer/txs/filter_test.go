@@ -0,0 +1,53 @@
+package txs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+func TestTokenFilterModes(t *testing.T) {
+	listed := goldenClaim.token
+	other := common.HexToAddress(checksummedAddress)
+
+	allowlist := NewTokenFilter(AllowlistMode, listed)
+	if !allowlist.Allowed(listed) || allowlist.Allowed(other) {
+		t.Fatal("allowlist doesn't allow only its tokens")
+	}
+	denylist := NewTokenFilter(DenylistMode, listed)
+	if denylist.Allowed(listed) || !denylist.Allowed(other) {
+		t.Fatal("denylist doesn't deny only its tokens")
+	}
+	// Parsed hex addresses match whatever their case
+	if !allowlist.Allowed(common.HexToAddress(strings.ToLower(listed.Hex()))) {
+		t.Fatal("allowlist rejected a lower-case spelling of its token")
+	}
+}
+
+func TestClaimTokenFilter(t *testing.T) {
+	defer func(filter *TokenFilter) { ClaimTokenFilter = filter }(ClaimTokenFilter)
+	signer := NewKeySigner(testKey(t))
+	events := []types.EthLogNewUnlockClaimEvent{goldenEthEvent()}
+
+	ClaimTokenFilter = NewTokenFilter(AllowlistMode, goldenClaim.token)
+	signed, err := SignClaimsBatch(signer, events)
+	if err != nil {
+		t.Fatalf("signing an allowlisted token's claim = %v", err)
+	}
+	if common.Bytes2Hex(signed[0].Message[:]) != goldenClaim.message {
+		t.Fatalf("signed message %x, want the golden %s", signed[0].Message, goldenClaim.message)
+	}
+
+	ClaimTokenFilter = NewTokenFilter(DenylistMode, goldenClaim.token)
+	counting := &countingSigner{Signer: signer}
+	if _, err := SignClaimsBatch(counting, events); !errors.Is(err, ErrTokenNotAllowed) {
+		t.Fatalf("signing a denylisted token's claim = %v, want ErrTokenNotAllowed", err)
+	}
+	if counting.signed != 0 {
+		t.Fatal("signer invoked for a denylisted token")
+	}
+}
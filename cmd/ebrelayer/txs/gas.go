@@ -0,0 +1,63 @@
+package txs
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// EthGasStrategy prices claim transactions submitted to Ethereum
+var EthGasStrategy = Legacy(nil)
+
+// HmyGasStrategy prices claim transactions submitted to Harmony
+var HmyGasStrategy = Legacy(nil)
+
+// GasStrategy determines the gas price of submitted claim transactions. A nil GasPrice is queried
+// from the node and scaled by Multiplier, so claims can bid above the suggested price during
+// congestion. A zero Multiplier leaves queried prices unchanged.
+type GasStrategy struct {
+	GasPrice   *big.Int
+	Multiplier float64
+}
+
+// Legacy returns a GasStrategy using a single gas price, queried from eth_gasPrice if nil
+func Legacy(gasPrice *big.Int) GasStrategy {
+	return GasStrategy{GasPrice: gasPrice}
+}
+
+// Fees holds a transaction's fee fields
+type Fees struct {
+	GasPrice *big.Int
+}
+
+// GasPriceSuggester queries a node's suggested legacy gas price
+type GasPriceSuggester interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// Fees resolves the strategy into concrete fee fields
+func (s GasStrategy) Fees(ctx context.Context, client GasPriceSuggester) (Fees, error) {
+	if s.GasPrice != nil {
+		return Fees{GasPrice: s.GasPrice}, nil
+	}
+	suggested, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return Fees{}, err
+	}
+	return Fees{GasPrice: s.scale(suggested)}, nil
+}
+
+// Apply sets the fees on a contract binding's transaction options
+func (f Fees) Apply(opts *bind.TransactOpts) {
+	opts.GasPrice = f.GasPrice
+}
+
+// scale multiplies a queried price by the strategy's multiplier
+func (s GasStrategy) scale(price *big.Int) *big.Int {
+	if s.Multiplier == 0 || s.Multiplier == 1 {
+		return price
+	}
+	scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(s.Multiplier)).Int(nil)
+	return scaled
+}
@@ -0,0 +1,50 @@
+package txs
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// testGasPriceSuggester is a GasPriceSuggester suggesting price
+type testGasPriceSuggester struct {
+	price *big.Int
+	calls int
+}
+
+func (s *testGasPriceSuggester) SuggestGasPrice(context.Context) (*big.Int, error) {
+	s.calls++
+	return s.price, nil
+}
+
+func TestGasStrategyFees(t *testing.T) {
+	node := &testGasPriceSuggester{price: big.NewInt(20e9)}
+
+	// A queried price is bumped by the multiplier
+	strategy := Legacy(nil)
+	strategy.Multiplier = 1.5
+	fees, err := strategy.Fees(context.Background(), node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fees.GasPrice.Cmp(big.NewInt(30e9)) != 0 || node.calls != 1 {
+		t.Fatalf("gas price %v after %d queries, want 30 gwei after one", fees.GasPrice, node.calls)
+	}
+	var opts bind.TransactOpts
+	fees.Apply(&opts)
+	if opts.GasPrice.Cmp(fees.GasPrice) != 0 {
+		t.Fatalf("applied gas price %v, want %v", opts.GasPrice, fees.GasPrice)
+	}
+
+	// A configured price is used as given, without asking the node
+	strategy = Legacy(big.NewInt(5e9))
+	strategy.Multiplier = 1.5
+	if fees, err = strategy.Fees(context.Background(), node); err != nil {
+		t.Fatal(err)
+	}
+	if fees.GasPrice.Cmp(big.NewInt(5e9)) != 0 || node.calls != 1 {
+		t.Fatalf("gas price %v after %d queries, want the configured 5 gwei", fees.GasPrice, node.calls)
+	}
+}
@@ -0,0 +1,66 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrSigningHalted is returned in place of signing or submitting a claim while signing is halted
+var ErrSigningHalted = errors.New("claim signing halted")
+
+// SigningHaltFile, if set, persists a halt of claim signing across restarts. Signing is halted
+// exactly while the file exists, so an operator resumes it by removing the file.
+var SigningHaltFile string
+
+var (
+	haltMu     sync.RWMutex
+	haltReason string
+)
+
+// HaltSigning halts claim signing for reason until an operator intervenes: by removing
+// SigningHaltFile if set, or otherwise by restarting the relayer
+func HaltSigning(reason string) error {
+	haltMu.Lock()
+	defer haltMu.Unlock()
+
+	haltReason = reason
+	getLogger().Error("CRITICAL: claim signing halted, manual intervention required", "reason", reason,
+		"haltFile", SigningHaltFile)
+	getMetrics().signingHalted(true)
+	if SigningHaltFile == "" {
+		return nil
+	}
+	return ioutil.WriteFile(SigningHaltFile, []byte(reason+"\n"), 0600)
+}
+
+// SigningHalted returns why claim signing is halted, if it is
+func SigningHalted() (string, bool) {
+	haltMu.RLock()
+	defer haltMu.RUnlock()
+
+	if SigningHaltFile == "" {
+		return haltReason, haltReason != ""
+	}
+	data, err := ioutil.ReadFile(SigningHaltFile)
+	if os.IsNotExist(err) {
+		getMetrics().signingHalted(false)
+		return "", false
+	}
+	if err != nil {
+		// An unreadable halt file still halts signing
+		return err.Error(), true
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// checkSigningHalted returns ErrSigningHalted while claim signing is halted
+func checkSigningHalted() error {
+	if reason, halted := SigningHalted(); halted {
+		return fmt.Errorf("%w: %s", ErrSigningHalted, reason)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package txs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+func TestHaltSigningBlocksClaims(t *testing.T) {
+	defer func() { haltReason = "" }()
+	_, restore := useRecordingLogger()
+	defer restore()
+	m, disable := useTestMetrics(t)
+	defer disable()
+	counting := &countingSigner{Signer: NewKeySigner(testKey(t))}
+
+	if err := HaltSigning("Ethereum block 7 reorged 4 blocks deep"); err != nil {
+		t.Fatal(err)
+	}
+	if reason, halted := SigningHalted(); !halted || reason != "Ethereum block 7 reorged 4 blocks deep" {
+		t.Fatalf("SigningHalted = %q, %v", reason, halted)
+	}
+	if _, err := SignClaimsBatch(counting, []types.EthLogNewUnlockClaimEvent{goldenEthEvent()}); !errors.Is(err, ErrSigningHalted) {
+		t.Fatalf("signing while halted = %v, want ErrSigningHalted", err)
+	}
+	if counting.signed != 0 {
+		t.Fatal("signer invoked while signing was halted")
+	}
+	if halted := testutil.ToFloat64(m.SigningHalted); halted != 1 {
+		t.Fatalf("signing_halted = %v, want 1", halted)
+	}
+}
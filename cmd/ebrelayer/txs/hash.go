@@ -0,0 +1,82 @@
+package txs
+
+import (
+	"hash"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/sha3"
+)
+
+// keccakPool reuses legacy keccak256 hashers across calls
+var keccakPool = sync.Pool{
+	New: func() interface{} {
+		return sha3.NewLegacyKeccak256()
+	},
+}
+
+// Keccak256 calculates the keccak256 hash of the concatenated input
+func Keccak256(data ...[]byte) []byte {
+	hasher := keccakPool.Get().(hash.Hash)
+	defer keccakPool.Put(hasher)
+
+	hasher.Reset()
+	for _, b := range data {
+		hasher.Write(b)
+	}
+	return hasher.Sum(nil)
+}
+
+// Keccak256Hash calculates the keccak256 hash of the concatenated input as a common.Hash
+func Keccak256Hash(data ...[]byte) common.Hash {
+	return common.BytesToHash(Keccak256(data...))
+}
+
+// HashOption configures how SoliditySHA3Typed encodes values and the hash function it applies
+type HashOption func(*hashConfig)
+
+// hashConfig holds the encoding and hash function selected by HashOptions
+type hashConfig struct {
+	newHash  func() hash.Hash
+	encoding EncodingMode
+}
+
+// newHashConfig applies opts to the default config of packed encoding and legacy keccak256
+func newHashConfig(opts []HashOption) hashConfig {
+	var config hashConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// WithHash hashes with hashers made by newHash instead of legacy keccak256, such as sha3.New256
+// for a chain using standard SHA3-256, or a spy capturing the packed preimage in tests
+func WithHash(newHash func() hash.Hash) HashOption {
+	return func(c *hashConfig) {
+		c.newHash = newHash
+	}
+}
+
+// WithEncoding lays values out with mode instead of abi.encodePacked, such as ABIEncoding for a
+// contract hashing keccak256(abi.encode(...))
+func WithEncoding(mode EncodingMode) HashOption {
+	return func(c *hashConfig) {
+		c.encoding = mode
+	}
+}
+
+// hashWith hashes the concatenated input with the hash function selected by opts, defaulting to
+// legacy keccak256
+func hashWith(opts []HashOption, data ...[]byte) []byte {
+	config := newHashConfig(opts)
+	if config.newHash == nil {
+		return Keccak256(data...)
+	}
+
+	hasher := config.newHash()
+	for _, b := range data {
+		hasher.Write(b)
+	}
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,126 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/sha3"
+)
+
+// keccak256Vectors are the hashes of known inputs, as published for Ethereum's legacy keccak256
+var keccak256Vectors = []struct {
+	input string
+	hash  string
+}{
+	{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+	{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	{"hello world", "47173285a8d7341e5e972fc677286384f802f8ef42a5ec5f03bbfa254cb01fad"},
+	// The selector of ERC-20's transfer
+	{"transfer(address,uint256)", "a9059cbb2ab09eb219583f4a59a5d0623ade346d962bcd4e46b11da047c9049b"},
+}
+
+func TestKeccak256Vectors(t *testing.T) {
+	for _, tt := range keccak256Vectors {
+		if got := hex.EncodeToString(Keccak256([]byte(tt.input))); got != tt.hash {
+			t.Fatalf("Keccak256(%q) = %s, want %s", tt.input, got, tt.hash)
+		}
+		if got := Keccak256Hash([]byte(tt.input)); hex.EncodeToString(got.Bytes()) != tt.hash {
+			t.Fatalf("Keccak256Hash(%q) = %s, want %s", tt.input, got.Hex(), tt.hash)
+		}
+	}
+
+	// The input is hashed as one concatenation, however it is split
+	if !bytes.Equal(Keccak256([]byte("hello"), nil, []byte(" "), []byte("world")), Keccak256([]byte("hello world"))) {
+		t.Fatal("Keccak256 of split input differs from the hash of its concatenation")
+	}
+	if !bytes.Equal(Keccak256(), crypto.Keccak256()) {
+		t.Fatal("Keccak256 of no input differs from go-ethereum's")
+	}
+}
+
+func TestKeccak256Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan string, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for _, tt := range keccak256Vectors {
+					if got := hex.EncodeToString(Keccak256([]byte(tt.input))); got != tt.hash {
+						errs <- "Keccak256(" + tt.input + ") = " + got
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+// identityHash is a hash.Hash whose sum is its input, so a hash made with it is the preimage
+type identityHash struct {
+	bytes.Buffer
+}
+
+func (h *identityHash) Sum(b []byte) []byte {
+	return append(b, h.Bytes()...)
+}
+
+func (h *identityHash) Size() int {
+	return h.Len()
+}
+
+func (h *identityHash) BlockSize() int {
+	return 1
+}
+
+func TestWithHashCapturesPreimage(t *testing.T) {
+	types := []string{"uint256", "address"}
+	values := []interface{}{goldenClaim.unlockID, goldenClaim.recipient}
+	var spy *identityHash
+	newSpy := func() hash.Hash {
+		spy = &identityHash{}
+		return spy
+	}
+
+	preimage, err := SoliditySHA3Typed(types, values, WithHash(newSpy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(common.LeftPadBytes(goldenClaim.unlockID.Bytes(), 32), goldenClaim.recipient.Bytes()...)
+	if !bytes.Equal(preimage, want) || !bytes.Equal(spy.Bytes(), want) {
+		t.Fatalf("hasher fed %x, want the packed %x", spy.Bytes(), want)
+	}
+	if hash, _ := SoliditySHA3Typed(types, values); !bytes.Equal(hash, Keccak256(want)) {
+		t.Fatalf("default hash = %x, want keccak256 of the preimage", hash)
+	}
+
+	// The option composes with ABI encoding, which pads the address to a word
+	encoded, err := SoliditySHA3Typed(types, values, WithHash(newSpy), WithEncoding(ABIEncoding))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := append(want[:32:32], common.LeftPadBytes(goldenClaim.recipient.Bytes(), 32)...); !bytes.Equal(encoded, want) {
+		t.Fatalf("hasher fed %x, want the ABI encoded %x", encoded, want)
+	}
+
+	// A chain using standard SHA3-256 hashes to a different digest
+	sha3Hash, err := SoliditySHA3Typed(types, values, WithHash(sha3.New256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	standard := sha3.Sum256(want)
+	if !bytes.Equal(sha3Hash, standard[:]) {
+		t.Fatalf("SHA3-256 hash = %x, want %x", sha3Hash, standard)
+	}
+}
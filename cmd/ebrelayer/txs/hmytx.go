@@ -0,0 +1,209 @@
+package txs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	htypes "github.com/harmony-one/harmony/core/types"
+
+	"github.com/mochi-lab/eth-one-bridge/hmyclient"
+)
+
+const (
+	// DefaultHarmonyChainID is the Harmony chain ID claims are signed for unless configured otherwise
+	DefaultHarmonyChainID = 2
+	// DefaultHarmonyShardID is the Harmony shard claims are submitted to unless configured otherwise
+	DefaultHarmonyShardID = 0
+)
+
+var (
+	// HmyChainID is the chain ID Harmony transactions are signed for. It is distinct from
+	// HmyClaimChainID, which is bound into claim messages.
+	HmyChainID = big.NewInt(DefaultHarmonyChainID)
+	// HmyShardID is the shard Harmony transactions are sent on and to
+	HmyShardID uint32 = DefaultHarmonyShardID
+)
+
+// ErrInvalidHmySignature is returned when a Harmony transaction's signature doesn't recover
+var ErrInvalidHmySignature = errors.New("invalid Harmony transaction signature")
+
+// HmyTransaction is a Harmony transaction, which unlike an Ethereum transaction names the shard
+// it executes on and the shard its value is sent to
+type HmyTransaction struct {
+	Nonce     uint64
+	GasPrice  *big.Int
+	GasLimit  uint64
+	ShardID   uint32
+	ToShardID uint32
+	To        *common.Address `rlp:"nil"`
+	Amount    *big.Int
+	Data      []byte
+	V, R, S   *big.Int
+}
+
+// NewHmyTransaction initializes a new unsigned HmyTransaction within shardID
+func NewHmyTransaction(nonce uint64, to common.Address, shardID uint32, amount *big.Int, gasLimit uint64,
+	gasPrice *big.Int, data []byte) *HmyTransaction {
+	return &HmyTransaction{
+		Nonce:     nonce,
+		GasPrice:  new(big.Int).Set(gasPrice),
+		GasLimit:  gasLimit,
+		ShardID:   shardID,
+		ToShardID: shardID,
+		To:        &to,
+		Amount:    new(big.Int).Set(amount),
+		Data:      common.CopyBytes(data),
+		V:         new(big.Int),
+		R:         new(big.Int),
+		S:         new(big.Int),
+	}
+}
+
+// EncodeRLP returns the RLP encoding hmy_sendRawTransaction accepts
+func (tx *HmyTransaction) EncodeRLP() ([]byte, error) {
+	return rlp.EncodeToBytes(tx)
+}
+
+// Hash returns the transaction hash, by which its receipt is looked up
+func (tx *HmyTransaction) Hash() (common.Hash, error) {
+	encoded, err := tx.EncodeRLP()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(Keccak256(encoded)), nil
+}
+
+// HmySigner signs Harmony transactions with replay protection for ChainID, as Harmony's
+// EIP155Signer does: the signed hash covers both shard IDs, and V carries the chain ID
+type HmySigner struct {
+	ChainID *big.Int
+}
+
+// NewHmySigner initializes a new HmySigner for chainID
+func NewHmySigner(chainID *big.Int) HmySigner {
+	return HmySigner{ChainID: new(big.Int).Set(chainID)}
+}
+
+// Hash returns the hash signed over tx
+func (s HmySigner) Hash(tx *HmyTransaction) (common.Hash, error) {
+	encoded, err := rlp.EncodeToBytes([]interface{}{
+		tx.Nonce, tx.GasPrice, tx.GasLimit, tx.ShardID, tx.ToShardID, tx.To, tx.Amount, tx.Data,
+		s.ChainID, uint(0), uint(0),
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(Keccak256(encoded)), nil
+}
+
+// SignTx returns a copy of tx signed by privateKey
+func (s HmySigner) SignTx(tx *HmyTransaction, privateKey *ecdsa.PrivateKey) (*HmyTransaction, error) {
+	if privateKey == nil {
+		return nil, ErrMissingPrivateKey
+	}
+	hash, err := s.Hash(tx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(hash.Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := *tx
+	signed.R = new(big.Int).SetBytes(sig[:32])
+	signed.S = new(big.Int).SetBytes(sig[32:64])
+	signed.V = new(big.Int).SetUint64(uint64(sig[64]) + 35)
+	signed.V.Add(signed.V, new(big.Int).Mul(s.ChainID, big.NewInt(2)))
+	return &signed, nil
+}
+
+// Sender recovers the address which signed tx
+func (s HmySigner) Sender(tx *HmyTransaction) (common.Address, error) {
+	recoveryID := new(big.Int).Sub(tx.V, new(big.Int).Mul(s.ChainID, big.NewInt(2)))
+	recoveryID.Sub(recoveryID, big.NewInt(35))
+	if !recoveryID.IsUint64() || recoveryID.Uint64() > 1 || tx.R.BitLen() > 256 || tx.S.BitLen() > 256 {
+		return common.Address{}, fmt.Errorf("%w: V is %v for chain %v", ErrInvalidHmySignature, tx.V, s.ChainID)
+	}
+
+	hash, err := s.Hash(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig[32-len(tx.R.Bytes()):32], tx.R.Bytes())
+	copy(sig[64-len(tx.S.Bytes()):64], tx.S.Bytes())
+	sig[64] = byte(recoveryID.Uint64())
+	return RecoverSigner(hash.Bytes(), sig)
+}
+
+// HmyShardClient is a Harmony contract backend for one shard. Each transaction the bindings send
+// is rebuilt as an HmyTransaction for ShardID, signed with Signer and sent raw, so it carries
+// Harmony's chain ID and shard fields rather than the Ethereum signature it was made with.
+type HmyShardClient struct {
+	*hmyclient.Client
+	Signer     HmySigner
+	ShardID    uint32
+	PrivateKey *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	hashes map[common.Hash]common.Hash
+}
+
+// NewHmyShardClient initializes a new HmyShardClient sending transactions signed by privateKey
+func NewHmyShardClient(client *hmyclient.Client, signer HmySigner, shardID uint32,
+	privateKey *ecdsa.PrivateKey) *HmyShardClient {
+	return &HmyShardClient{
+		Client:     client,
+		Signer:     signer,
+		ShardID:    shardID,
+		PrivateKey: privateKey,
+		hashes:     make(map[common.Hash]common.Hash),
+	}
+}
+
+// SendTransaction implements bind.ContractTransactor. A retried broadcast the node already holds
+// counts as sent, per sentIfKnown.
+func (c *HmyShardClient) SendTransaction(ctx context.Context, tx *htypes.Transaction) error {
+	if tx.To() == nil {
+		return errors.New("contract creation is not supported on Harmony shards")
+	}
+	hmyTx, err := c.Signer.SignTx(NewHmyTransaction(tx.Nonce(), *tx.To(), c.ShardID, tx.Value(), tx.Gas(),
+		tx.GasPrice(), tx.Data()), c.PrivateKey)
+	if err != nil {
+		return err
+	}
+	encoded, err := hmyTx.EncodeRLP()
+	if err != nil {
+		return err
+	}
+
+	// The hash is recorded before sending, so a send the node reports as already known maps to it
+	txHash := common.BytesToHash(Keccak256(encoded))
+	c.mu.Lock()
+	c.hashes[tx.Hash()] = txHash
+	c.mu.Unlock()
+	return sentIfKnown(ctx, c.SendRawTransaction(ctx, encoded), txHash, func(ctx context.Context, txHash common.Hash) error {
+		_, _, err := c.Client.TransactionByHash(ctx, txHash)
+		return err
+	})
+}
+
+// TxHash returns the hash of the Harmony transaction sent in place of tx, or tx's own hash if
+// it wasn't sent through c
+func (c *HmyShardClient) TxHash(tx *htypes.Transaction) common.Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if hash, ok := c.hashes[tx.Hash()]; ok {
+		return hash
+	}
+	return tx.Hash()
+}
@@ -0,0 +1,80 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestHmySignerSignTx(t *testing.T) {
+	key := testKey(t)
+	to := common.HexToAddress(checksummedAddress)
+	data := []byte{0xde, 0xad, 0xbe, 0xef}
+	tx := NewHmyTransaction(5, to, 1, big.NewInt(0), 300000, big.NewInt(1000000000), data)
+	signer := NewHmySigner(big.NewInt(1666600000))
+
+	signed, err := signer.SignTx(tx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.V.Sign() != 0 || tx.R.Sign() != 0 {
+		t.Fatal("SignTx modified the unsigned transaction")
+	}
+
+	// The raw transaction carries both shard IDs, and V Harmony's EIP-155 chain ID
+	encoded, err := signed.EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded HmyTransaction
+	if err := rlp.DecodeBytes(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Nonce != 5 || decoded.GasPrice.Int64() != 1000000000 || decoded.GasLimit != 300000 ||
+		decoded.ShardID != 1 || decoded.ToShardID != 1 || *decoded.To != to || decoded.Amount.Sign() != 0 ||
+		string(decoded.Data) != string(data) {
+		t.Fatalf("decoded %+v, want the transaction's fields", decoded)
+	}
+	if v := decoded.V.Int64(); v != 2*1666600000+35 && v != 2*1666600000+36 {
+		t.Fatalf("V = %d, want the chain ID's EIP-155 V", v)
+	}
+
+	sender, err := signer.Sender(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); sender != want {
+		t.Fatalf("Sender = %s, want %s", sender.Hex(), want.Hex())
+	}
+	if _, err := NewHmySigner(big.NewInt(2)).Sender(&decoded); !errors.Is(err, ErrInvalidHmySignature) {
+		t.Fatalf("Sender for another chain = %v, want ErrInvalidHmySignature", err)
+	}
+}
+
+func TestHmySignerHashCoversShards(t *testing.T) {
+	signer := NewHmySigner(big.NewInt(DefaultHarmonyChainID))
+	to := common.HexToAddress(checksummedAddress)
+	shard0 := NewHmyTransaction(0, to, 0, big.NewInt(1), 21000, big.NewInt(1), nil)
+	shard1 := NewHmyTransaction(0, to, 1, big.NewInt(1), 21000, big.NewInt(1), nil)
+	crossShard := NewHmyTransaction(0, to, 0, big.NewInt(1), 21000, big.NewInt(1), nil)
+	crossShard.ToShardID = 1
+
+	hashes := make(map[common.Hash]bool)
+	for _, tx := range []*HmyTransaction{shard0, shard1, crossShard} {
+		hash, err := signer.Hash(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hashes[hash] {
+			t.Fatalf("transactions differing only in shards sign the same hash %s", hash.Hex())
+		}
+		hashes[hash] = true
+	}
+	if _, err := signer.SignTx(shard0, nil); !errors.Is(err, ErrMissingPrivateKey) {
+		t.Fatalf("SignTx without a key = %v, want ErrMissingPrivateKey", err)
+	}
+}
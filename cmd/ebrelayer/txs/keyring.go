@@ -0,0 +1,129 @@
+package txs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultKeyringService is the keyring service validator keys are stored under by default
+const DefaultKeyringService = "ebrelayer"
+
+var (
+	// ErrNoKeyringBackend is returned when no OS keyring is available to load a key from
+	ErrNoKeyringBackend = errors.New("no keyring backend available")
+	// ErrKeyringItemNotFound is returned when the keyring holds no secret for a service and account
+	ErrKeyringItemNotFound = errors.New("keyring item not found")
+)
+
+// KeyringBackend reads secrets from a keyring, by service and account
+type KeyringBackend interface {
+	Get(service, account string) (string, error)
+}
+
+// Keyring is the keyring LoadKeyFromKeyring reads from: the OS keyring by default
+var Keyring KeyringBackend = SystemKeyring{}
+
+// LoadKeyFromKeyring loads a private key, stored hex-encoded as the secret of service and account,
+// from Keyring, as an alternative to keeping it in the environment or a key file
+func LoadKeyFromKeyring(service, account string) (*ecdsa.PrivateKey, error) {
+	if Keyring == nil {
+		return nil, ErrNoKeyringBackend
+	}
+	name := service + "/" + account
+
+	rawPrivateKey, err := Keyring.Get(service, account)
+	if err != nil {
+		getLogger().Error("Error reading private key from keyring", "item", name, "err", err)
+		return nil, fmt.Errorf("reading private key from keyring: %w", err)
+	}
+	if strings.TrimSpace(rawPrivateKey) == "" {
+		return nil, fmt.Errorf("%w: keyring item %s", ErrMissingPrivateKey, name)
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimSpace(rawPrivateKey))
+	if err != nil {
+		getLogger().Error("Error parsing private key", "item", name, "err", err)
+		return nil, fmt.Errorf("parsing keyring item %s: %w", name, err)
+	}
+	if err := ValidatePrivateKey(privateKey); err != nil {
+		getLogger().Error("Error validating private key", "item", name, "err", err)
+		return nil, fmt.Errorf("parsing keyring item %s: %w", name, err)
+	}
+	return privateKey, nil
+}
+
+// SystemKeyring reads secrets from the OS keyring through its command-line client: the
+// Keychain's security on macOS, and libsecret's secret-tool elsewhere, which finds items stored
+// with attributes service and username as other keyring clients store them. Windows Credential
+// Manager has no such client, so there it returns ErrNoKeyringBackend.
+type SystemKeyring struct{}
+
+// Get implements KeyringBackend
+func (SystemKeyring) Get(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "windows":
+		return "", fmt.Errorf("%w: Windows Credential Manager is not supported", ErrNoKeyringBackend)
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "username", account)
+	}
+	if _, err := exec.LookPath(cmd.Args[0]); err != nil {
+		return "", fmt.Errorf("%w: %s not found", ErrNoKeyringBackend, cmd.Args[0])
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("running %s: %w", cmd.Args[0], err)
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s/%s: %s", ErrKeyringItemNotFound, service, account, message)
+		}
+		return "", fmt.Errorf("%w: %s/%s", ErrKeyringItemNotFound, service, account)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// MemoryKeyring is a KeyringBackend holding secrets in memory, for tests and tooling. It is safe
+// for concurrent use.
+type MemoryKeyring struct {
+	mu      sync.RWMutex
+	secrets map[string]string
+}
+
+// NewMemoryKeyring initializes a new, empty MemoryKeyring
+func NewMemoryKeyring() *MemoryKeyring {
+	return &MemoryKeyring{secrets: make(map[string]string)}
+}
+
+// Set stores secret under service and account
+func (k *MemoryKeyring) Set(service, account, secret string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.secrets[service+"\x00"+account] = secret
+}
+
+// Get implements KeyringBackend
+func (k *MemoryKeyring) Get(service, account string) (string, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	secret, ok := k.secrets[service+"\x00"+account]
+	if !ok {
+		return "", fmt.Errorf("%w: %s/%s", ErrKeyringItemNotFound, service, account)
+	}
+	return secret, nil
+}
@@ -0,0 +1,59 @@
+package txs
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestLoadKeyFromMemoryKeyring(t *testing.T) {
+	defer func(keyring KeyringBackend) { Keyring = keyring }(Keyring)
+	_, restore := useRecordingLogger()
+	defer restore()
+
+	keyring := NewMemoryKeyring()
+	keyring.Set(DefaultKeyringService, "ethereum", testPrivateKeyHex+"\n")
+	keyring.Set(DefaultKeyringService, "empty", " ")
+	keyring.Set(DefaultKeyringService, "garbled", "not a key")
+	Keyring = keyring
+
+	key, err := LoadKeyFromKeyring(DefaultKeyringService, "ethereum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(key.PublicKey) != crypto.PubkeyToAddress(testKey(t).PublicKey) {
+		t.Fatal("loaded another key than the one stored")
+	}
+
+	if _, err := LoadKeyFromKeyring(DefaultKeyringService, "harmony"); !errors.Is(err, ErrKeyringItemNotFound) {
+		t.Fatalf("loading a missing item = %v, want ErrKeyringItemNotFound", err)
+	}
+	if _, err := LoadKeyFromKeyring("other", "ethereum"); !errors.Is(err, ErrKeyringItemNotFound) {
+		t.Fatalf("loading from another service = %v, want ErrKeyringItemNotFound", err)
+	}
+	if _, err := LoadKeyFromKeyring(DefaultKeyringService, "empty"); !errors.Is(err, ErrMissingPrivateKey) {
+		t.Fatalf("loading an empty item = %v, want ErrMissingPrivateKey", err)
+	}
+	if _, err := LoadKeyFromKeyring(DefaultKeyringService, "garbled"); err == nil {
+		t.Fatal("loaded a garbled key")
+	}
+
+	Keyring = nil
+	if _, err := LoadKeyFromKeyring(DefaultKeyringService, "ethereum"); !errors.Is(err, ErrNoKeyringBackend) {
+		t.Fatalf("loading without a keyring = %v, want ErrNoKeyringBackend", err)
+	}
+}
+
+func TestSystemKeyringWithoutBackend(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("the Keychain client is always present on macOS")
+	}
+	// Without a PATH no keyring client can be found
+	defer setEnv(t, "PATH", "")()
+
+	if _, err := (SystemKeyring{}).Get(DefaultKeyringService, "ethereum"); !errors.Is(err, ErrNoKeyringBackend) {
+		t.Fatalf("SystemKeyring without a client = %v, want ErrNoKeyringBackend", err)
+	}
+}
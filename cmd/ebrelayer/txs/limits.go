@@ -0,0 +1,77 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrAmountExceedsLimit is returned in place of signing a claim whose amount is above its token's
+// limit, so it can be routed for manual review
+var ErrAmountExceedsLimit = errors.New("claim amount exceeds limit")
+
+// ClaimAmountLimits, if set, caps the amount of the claims signed automatically
+var ClaimAmountLimits *AmountLimits
+
+// AmountLimits caps the amount of a single claim per token, falling back to a default limit for
+// tokens without their own. A nil limit is unlimited. It is safe for concurrent use.
+type AmountLimits struct {
+	mu       sync.RWMutex
+	fallback *big.Int
+	tokens   map[common.Address]*big.Int
+}
+
+// NewAmountLimits initializes a new AmountLimits capping every token at defaultMax, which may be
+// nil to leave tokens without their own limit unlimited
+func NewAmountLimits(defaultMax *big.Int) *AmountLimits {
+	return &AmountLimits{fallback: defaultMax, tokens: make(map[common.Address]*big.Int)}
+}
+
+// SetMax caps the amount of token's claims at max
+func (l *AmountLimits) SetMax(token common.Address, max *big.Int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens[token] = max
+}
+
+// Max returns the limit on token's claims, or nil if unlimited
+func (l *AmountLimits) Max(token common.Address) *big.Int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if max, ok := l.tokens[token]; ok {
+		return max
+	}
+	return l.fallback
+}
+
+// Check returns ErrAmountExceedsLimit if amount is above token's limit. An amount equal to the
+// limit is allowed.
+func (l *AmountLimits) Check(token common.Address, amount *big.Int) error {
+	max := l.Max(token)
+	if max == nil || amount == nil || amount.Cmp(max) <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %v of %s is above %v", ErrAmountExceedsLimit, amount, token.Hex(), max)
+}
+
+// checkAmountLimit logs and returns ErrAmountExceedsLimit if ClaimAmountLimits rejects the
+// event's amount
+func checkAmountLimit(event types.ClaimEvent) error {
+	if ClaimAmountLimits == nil {
+		return nil
+	}
+	unlockID, _, _, token, amount := event.ClaimFields()
+	if err := ClaimAmountLimits.Check(token, amount); err != nil {
+		getLogger().Error("Claim requires manual review", "unlockID", unlockID,
+			"amount", FormatTokenAmount(token, amount), "err", err)
+		return getMetrics().claimError(AmountLimitErrorReason, err)
+	}
+	return nil
+}
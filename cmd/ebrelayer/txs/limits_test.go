@@ -0,0 +1,67 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+func TestAmountLimitsCheck(t *testing.T) {
+	limit := goldenClaim.amount
+	limits := NewAmountLimits(nil)
+	limits.SetMax(goldenClaim.token, limit)
+
+	tests := []struct {
+		name    string
+		amount  *big.Int
+		wantErr error
+	}{
+		{"below", new(big.Int).Sub(limit, big.NewInt(1)), nil},
+		{"at", new(big.Int).Set(limit), nil},
+		{"above", new(big.Int).Add(limit, big.NewInt(1)), ErrAmountExceedsLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := limits.Check(goldenClaim.token, tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check(%s) = %v, want %v", tt.amount, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Tokens without their own limit fall back to the default, here unlimited
+	if err := limits.Check(common.HexToAddress("0x1"), new(big.Int).Lsh(limit, 100)); err != nil {
+		t.Fatalf("Check of an unlimited token = %v", err)
+	}
+	capped := NewAmountLimits(big.NewInt(10))
+	if err := capped.Check(common.HexToAddress("0x1"), big.NewInt(11)); !errors.Is(err, ErrAmountExceedsLimit) {
+		t.Fatalf("Check above the default limit = %v, want ErrAmountExceedsLimit", err)
+	}
+}
+
+func TestClaimAmountLimits(t *testing.T) {
+	defer func(limits *AmountLimits) { ClaimAmountLimits = limits }(ClaimAmountLimits)
+	ClaimAmountLimits = NewAmountLimits(nil)
+	ClaimAmountLimits.SetMax(goldenClaim.token, goldenClaim.amount)
+	m, disable := useTestMetrics(t)
+	defer disable()
+	signer := NewKeySigner(testKey(t))
+
+	atLimit := goldenEthEvent()
+	if _, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{atLimit}); err != nil {
+		t.Fatalf("signing a claim at the limit = %v", err)
+	}
+
+	aboveLimit := goldenEthEvent()
+	aboveLimit.Amount = new(big.Int).Add(goldenClaim.amount, big.NewInt(1))
+	if _, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{aboveLimit}); !errors.Is(err, ErrAmountExceedsLimit) {
+		t.Fatalf("signing a claim above the limit = %v, want ErrAmountExceedsLimit", err)
+	}
+	if held := testutil.ToFloat64(m.ClaimErrors.WithLabelValues(AmountLimitErrorReason)); held != 1 {
+		t.Fatalf("claim_errors_total{reason=amount_limit} = %v, want 1", held)
+	}
+}
@@ -0,0 +1,81 @@
+package txs
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Logger is a minimal leveled logger taking a message and alternating key/value pairs. An adapter
+// for a structured logger such as zap or logrus can be installed with SetLogger.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// StdLogger is a Logger writing leveled lines to a standard library logger
+type StdLogger struct {
+	logger *log.Logger
+}
+
+// NewStdLogger initializes a new StdLogger writing to logger
+func NewStdLogger(logger *log.Logger) StdLogger {
+	return StdLogger{logger}
+}
+
+// Debug implements Logger
+func (l StdLogger) Debug(msg string, keyvals ...interface{}) { l.print("DEBUG", msg, keyvals) }
+
+// Info implements Logger
+func (l StdLogger) Info(msg string, keyvals ...interface{}) { l.print("INFO", msg, keyvals) }
+
+// Warn implements Logger
+func (l StdLogger) Warn(msg string, keyvals ...interface{}) { l.print("WARN", msg, keyvals) }
+
+// Error implements Logger
+func (l StdLogger) Error(msg string, keyvals ...interface{}) { l.print("ERROR", msg, keyvals) }
+
+// print writes a line formatted as "LEVEL msg key=value ..."
+func (l StdLogger) print(level, msg string, keyvals []interface{}) {
+	var line strings.Builder
+	line.WriteString(level + " " + msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 < len(keyvals) {
+			fmt.Fprintf(&line, " %v=%v", keyvals[i], keyvals[i+1])
+		} else {
+			fmt.Fprintf(&line, " %v", keyvals[i])
+		}
+	}
+	l.logger.Println(line.String())
+}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger = defaultLogger()
+)
+
+// defaultLogger returns the Logger used until SetLogger is called, writing to stderr
+func defaultLogger() Logger {
+	return NewStdLogger(log.New(os.Stderr, "", log.LstdFlags))
+}
+
+// SetLogger replaces the package's Logger. A nil logger restores the default.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = defaultLogger()
+	}
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	logger = l
+}
+
+// getLogger returns the package's current Logger
+func getLogger() Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return logger
+}
@@ -0,0 +1,89 @@
+package txs
+
+import (
+	"bytes"
+	"log"
+	"sync"
+	"testing"
+)
+
+// logEntry is a line logged through a recordingLogger
+type logEntry struct {
+	level   string
+	msg     string
+	keyvals []interface{}
+}
+
+// value returns the value logged for key, or nil
+func (e logEntry) value(key string) interface{} {
+	for i := 0; i+1 < len(e.keyvals); i += 2 {
+		if e.keyvals[i] == key {
+			return e.keyvals[i+1]
+		}
+	}
+	return nil
+}
+
+// recordingLogger is a Logger recording every entry
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) record(level, msg string, keyvals []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level, msg, keyvals})
+}
+
+// count returns how many entries were logged at level
+func (l *recordingLogger) count(level string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := 0
+	for _, entry := range l.entries {
+		if entry.level == level {
+			count++
+		}
+	}
+	return count
+}
+
+func (l *recordingLogger) Debug(msg string, keyvals ...interface{}) { l.record("DEBUG", msg, keyvals) }
+func (l *recordingLogger) Info(msg string, keyvals ...interface{})  { l.record("INFO", msg, keyvals) }
+func (l *recordingLogger) Warn(msg string, keyvals ...interface{})  { l.record("WARN", msg, keyvals) }
+func (l *recordingLogger) Error(msg string, keyvals ...interface{}) { l.record("ERROR", msg, keyvals) }
+
+// useRecordingLogger installs a recordingLogger, returning it and the function restoring the default
+func useRecordingLogger() (*recordingLogger, func()) {
+	recorder := &recordingLogger{}
+	SetLogger(recorder)
+	return recorder, func() { SetLogger(nil) }
+}
+
+func TestCustomLoggerReceivesMissingKeyError(t *testing.T) {
+	skipEnvFile()
+	recorder, restore := useRecordingLogger()
+	defer restore()
+
+	if _, err := LoadPrivateKeyFromEnv("TEST_UNSET_PRIVATE_KEY"); err == nil {
+		t.Fatal("loaded a key from an unset variable")
+	}
+	if len(recorder.entries) != 1 {
+		t.Fatalf("logged %d entries, want 1: %+v", len(recorder.entries), recorder.entries)
+	}
+	entry := recorder.entries[0]
+	if entry.level != "ERROR" || entry.value("env") != "TEST_UNSET_PRIVATE_KEY" {
+		t.Fatalf("logged %+v, want an error naming the missing variable", entry)
+	}
+}
+
+func TestStdLoggerFormat(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewStdLogger(log.New(&out, "", 0))
+	logger.Warn("Claim held", "chain", "ethereum", "unlockID", 7, "dangling")
+	if got, want := out.String(), "WARN Claim held chain=ethereum unlockID=7 dangling\n"; got != want {
+		t.Fatalf("logged %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrUnmappedToken is returned in place of an unlock claim for a token with no destination mapping
+	ErrUnmappedToken = errors.New("token has no destination mapping")
+	// ErrTokenMismatch is returned in place of an unlock claim for a lock naming a destination token
+	// other than the mapped one
+	ErrTokenMismatch = errors.New("lock's destination token doesn't match the mapped token")
+)
+
+// EthToHmyTokens, if set, maps the Ethereum token of each lock to the Harmony token its unlock
+// claim on Harmony is made for
+var EthToHmyTokens *TokenMapping
+
+// HmyToEthTokens, if set, maps the Harmony token of each lock to the Ethereum token its unlock
+// claim on Ethereum is made for
+var HmyToEthTokens *TokenMapping
+
+// BridgeBankTokens, if set, maps each listed BridgeBank to the TokenMapping of the locks it emits,
+// in place of EthToHmyTokens or HmyToEthTokens, for bridge instances with their own token sets
+var BridgeBankTokens map[common.Address]*TokenMapping
+
+// TokenMapping maps tokens locked on a source chain to the tokens unlocked for them on the
+// destination chain. It is safe for concurrent use.
+type TokenMapping struct {
+	mu     sync.RWMutex
+	tokens map[common.Address]common.Address
+}
+
+// NewTokenMapping initializes a new, empty TokenMapping
+func NewTokenMapping() *TokenMapping {
+	return &TokenMapping{tokens: make(map[common.Address]common.Address)}
+}
+
+// Register maps source to dest
+func (m *TokenMapping) Register(source, dest common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[source] = dest
+}
+
+// Dest returns the destination token mapped to source, if any
+func (m *TokenMapping) Dest(source common.Address) (common.Address, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	dest, ok := m.tokens[source]
+	return dest, ok
+}
+
+// ParseTokenMapping parses a token mapping given as "source=dest"
+func ParseTokenMapping(value string) (common.Address, common.Address, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) || !common.IsHexAddress(parts[1]) {
+		return common.Address{}, common.Address{}, fmt.Errorf("invalid token mapping %q: expected source=dest", value)
+	}
+	for _, address := range parts {
+		if err := ValidateAddressChecksum(address, false); err != nil {
+			return common.Address{}, common.Address{}, fmt.Errorf("invalid token mapping %q: %w", value, err)
+		}
+	}
+	return common.HexToAddress(parts[0]), common.HexToAddress(parts[1]), nil
+}
+
+// ParseBridgeBankTokenMapping parses a BridgeBank's token mapping given as "bridgeBank:source=dest"
+func ParseBridgeBankTokenMapping(value string) (bridgeBank, source, dest common.Address, err error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return common.Address{}, common.Address{}, common.Address{},
+			fmt.Errorf("invalid bridge token mapping %q: expected bridgeBank:source=dest", value)
+	}
+	if err := ValidateAddressChecksum(parts[0], false); err != nil {
+		return common.Address{}, common.Address{}, common.Address{},
+			fmt.Errorf("invalid bridge token mapping %q: %w", value, err)
+	}
+	if source, dest, err = ParseTokenMapping(parts[1]); err != nil {
+		return common.Address{}, common.Address{}, common.Address{}, err
+	}
+	return common.HexToAddress(parts[0]), source, dest, nil
+}
+
+// bridgeBankTokens returns the mapping of the tokens locked in bridgeBank: its BridgeBankTokens
+// mapping if it has one, or otherwise fallback
+func bridgeBankTokens(bridgeBank common.Address, fallback *TokenMapping) *TokenMapping {
+	if mapping, ok := BridgeBankTokens[bridgeBank]; ok {
+		return mapping
+	}
+	return fallback
+}
+
+// destinationToken returns the token an unlock claim for a lock of source is made for. Without a
+// mapping it is the lock's own destination token. With one it is the mapped token, which the
+// lock's destination token, if set, must match.
+func destinationToken(mapping *TokenMapping, source, lockDest common.Address) (common.Address, error) {
+	if mapping == nil {
+		return lockDest, nil
+	}
+	dest, ok := mapping.Dest(source)
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrUnmappedToken, source.Hex())
+	}
+	if !isZeroAddress(lockDest) && lockDest != dest {
+		return common.Address{}, fmt.Errorf("%w: %s maps to %s, lock names %s", ErrTokenMismatch, source.Hex(),
+			dest.Hex(), lockDest.Hex())
+	}
+	return dest, nil
+}
@@ -0,0 +1,80 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+func TestEthereumLockTokenMapping(t *testing.T) {
+	defer func(mapping *TokenMapping) { EthToHmyTokens = mapping }(EthToHmyTokens)
+	defer func(tokens map[common.Address]*TokenMapping) { BridgeBankTokens = tokens }(BridgeBankTokens)
+	usdt := goldenClaim.token
+	hmyUSDT := common.HexToAddress(checksummedAddress)
+	unmapped := common.HexToAddress("0x1")
+	lock := func(token, dest common.Address) *types.EthLogLockEvent {
+		return &types.EthLogLockEvent{EthereumChainID: big.NewInt(1), EthereumSender: goldenClaim.sender,
+			HarmonyReceiver: goldenClaim.recipient, EthereumToken: token, HarmonyToken: dest,
+			HarmonyTokenAmount: goldenClaim.amount}
+	}
+
+	// Without a mapping the lock's own destination token is claimed
+	EthToHmyTokens = nil
+	claim, err := EthereumEventToHarmonyClaim(lock(unmapped, hmyUSDT))
+	if err != nil || claim.Token != hmyUSDT {
+		t.Fatalf("unmapped claim token = %s, %v, want the lock's %s", claim.Token.Hex(), err, hmyUSDT.Hex())
+	}
+
+	EthToHmyTokens = NewTokenMapping()
+	EthToHmyTokens.Register(usdt, hmyUSDT)
+	for _, dest := range []common.Address{{}, hmyUSDT} {
+		claim, err := EthereumEventToHarmonyClaim(lock(usdt, dest))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claim.Token != hmyUSDT || claim.Amount.Cmp(goldenClaim.amount) != 0 || claim.HarmonyReceiver != goldenClaim.recipient {
+			t.Fatalf("mapped claim = %+v, want it for %s", claim, hmyUSDT.Hex())
+		}
+	}
+	if _, err := EthereumEventToHarmonyClaim(lock(unmapped, hmyUSDT)); !errors.Is(err, ErrUnmappedToken) {
+		t.Fatalf("claim of an unmapped token = %v, want ErrUnmappedToken", err)
+	}
+	if _, err := EthereumEventToHarmonyClaim(lock(usdt, unmapped)); !errors.Is(err, ErrTokenMismatch) {
+		t.Fatalf("claim naming another destination token = %v, want ErrTokenMismatch", err)
+	}
+
+	// A BridgeBank with its own token set is mapped by it alone
+	bridgeBank := common.HexToAddress("0xb0")
+	BridgeBankTokens = map[common.Address]*TokenMapping{bridgeBank: NewTokenMapping()}
+	BridgeBankTokens[bridgeBank].Register(unmapped, hmyUSDT)
+	own := lock(unmapped, common.Address{})
+	own.BridgeBankAddress = bridgeBank
+	if claim, err := EthereumEventToHarmonyClaim(own); err != nil || claim.Token != hmyUSDT {
+		t.Fatalf("BridgeBank claim token = %s, %v, want %s", claim.Token.Hex(), err, hmyUSDT.Hex())
+	}
+	own.EthereumToken = usdt
+	if _, err := EthereumEventToHarmonyClaim(own); !errors.Is(err, ErrUnmappedToken) {
+		t.Fatalf("BridgeBank claim of a token only EthToHmyTokens maps = %v, want ErrUnmappedToken", err)
+	}
+}
+
+func TestParseBridgeBankTokenMapping(t *testing.T) {
+	bridgeBank, source, dest, err := ParseBridgeBankTokenMapping(checksummedAddress + ":" + goldenClaim.token.Hex() + "=" + checksummedAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bridgeBank != common.HexToAddress(checksummedAddress) || source != goldenClaim.token || dest != common.HexToAddress(checksummedAddress) {
+		t.Fatalf("ParseBridgeBankTokenMapping = %s, %s, %s", bridgeBank.Hex(), source.Hex(), dest.Hex())
+	}
+
+	for _, value := range []string{goldenClaim.token.Hex() + "=" + checksummedAddress, checksummedAddress + ":" + goldenClaim.token.Hex(),
+		checksummedAddress + ":" + goldenClaim.token.Hex() + "=" + corruptedAddress, corruptedAddress + ":" + goldenClaim.token.Hex() + "=" + checksummedAddress} {
+		if _, _, _, err := ParseBridgeBankTokenMapping(value); err == nil {
+			t.Fatalf("ParseBridgeBankTokenMapping(%q) succeeded", value)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+var (
+	// ErrEmptyMerkleTree is returned when building a ClaimMerkleTree over no claims
+	ErrEmptyMerkleTree = errors.New("no claims to build a Merkle tree over")
+	// ErrClaimNotInTree is returned when asked for the proof of a claim a ClaimMerkleTree doesn't cover
+	ErrClaimNotInTree = errors.New("claim is not in the Merkle tree")
+)
+
+// ClaimMerkleTree is a keccak256 Merkle tree over the claim hashes of a batch of claims, so that
+// one signature over its root covers the whole batch and each claim is submitted with its proof.
+// The layout is the one OpenZeppelin's MerkleProof verifies: each leaf is a claim's ClaimMessage
+// hash, the leaves are sorted, each pair of nodes is hashed in sorted order, and a node left
+// without a pair is carried up to the next level unchanged.
+type ClaimMerkleTree struct {
+	levels [][]common.Hash
+	leaves map[string]common.Hash
+}
+
+// NewClaimMerkleTree builds the ClaimMerkleTree over the claims of events, each of which must
+// have its own unlock ID, hashing them as the optional target verifying contract does
+func NewClaimMerkleTree(events []types.ClaimEvent, target ...common.Address) (*ClaimMerkleTree, error) {
+	if len(events) == 0 {
+		return nil, ErrEmptyMerkleTree
+	}
+
+	tree := &ClaimMerkleTree{leaves: make(map[string]common.Hash, len(events))}
+	leaves := make([]common.Hash, 0, len(events))
+	for _, event := range events {
+		unlockID, _, _, _, _ := event.ClaimFields()
+		if _, ok := tree.leaves[unlockID.String()]; ok {
+			return nil, fmt.Errorf("duplicate claim %v in Merkle tree", unlockID)
+		}
+		message, err := ClaimMessage(event, target...)
+		if err != nil {
+			return nil, err
+		}
+		leaf := common.BytesToHash(message)
+		tree.leaves[unlockID.String()] = leaf
+		leaves = append(leaves, leaf)
+	}
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i][:], leaves[j][:]) < 0 })
+
+	tree.levels = [][]common.Hash{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashMerklePair(level[i], level[i+1]))
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+	return tree, nil
+}
+
+// Root returns the tree's root hash
+func (t *ClaimMerkleTree) Root() common.Hash {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Leaf returns the leaf hash of the claim unlockID, if the tree covers it
+func (t *ClaimMerkleTree) Leaf(unlockID *big.Int) (common.Hash, bool) {
+	leaf, ok := t.leaves[unlockID.String()]
+	return leaf, ok
+}
+
+// Proof returns the sibling hashes from the leaf of the claim unlockID up to the root, as
+// OpenZeppelin's MerkleProof.verify takes them
+func (t *ClaimMerkleTree) Proof(unlockID *big.Int) ([]common.Hash, error) {
+	leaf, ok := t.Leaf(unlockID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrClaimNotInTree, unlockID)
+	}
+	leaves := t.levels[0]
+	index := sort.Search(len(leaves), func(i int) bool { return bytes.Compare(leaves[i][:], leaf[:]) >= 0 })
+
+	var proof []common.Hash
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// SignRoot signs the tree's root as a claim message is signed, per the SigningSchemes config of
+// the optional target verifying contract
+func (t *ClaimMerkleTree) SignRoot(signer Signer, target ...common.Address) ([]byte, error) {
+	if err := checkSigningHalted(); err != nil {
+		return nil, err
+	}
+	root := t.Root()
+	digest, err := claimDigest(root.Bytes(), target)
+	if err != nil {
+		return nil, getMetrics().claimError(SignErrorReason, err)
+	}
+	signature, err := signer.Sign(digest)
+	if err != nil {
+		return nil, getMetrics().claimError(SignErrorReason, err)
+	}
+	return signature, nil
+}
+
+// VerifyMerkleProof reports whether proof proves leaf is in the tree with root, as OpenZeppelin's
+// MerkleProof.verify does
+func VerifyMerkleProof(proof []common.Hash, root, leaf common.Hash) bool {
+	computed := leaf
+	for _, sibling := range proof {
+		computed = hashMerklePair(computed, sibling)
+	}
+	return computed == root
+}
+
+// hashMerklePair hashes two nodes in sorted order, so that a proof needn't record which side
+// each sibling is on
+func hashMerklePair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return Keccak256Hash(a[:], b[:])
+}
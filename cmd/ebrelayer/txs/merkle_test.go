@@ -0,0 +1,116 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// merkleClaims returns n golden claims, each with its own unlock ID
+func merkleClaims(n int) []types.ClaimEvent {
+	events := make([]types.ClaimEvent, n)
+	for i := range events {
+		event := goldenEthEvent()
+		event.UnlockID = big.NewInt(int64(i + 1))
+		events[i] = event
+	}
+	return events
+}
+
+func TestClaimMerkleTreeProofs(t *testing.T) {
+	// Odd sizes carry a node up without a pair
+	for n := 1; n <= 9; n++ {
+		tree, err := NewClaimMerkleTree(merkleClaims(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i <= n; i++ {
+			unlockID := big.NewInt(int64(i))
+			leaf, ok := tree.Leaf(unlockID)
+			if !ok {
+				t.Fatalf("%d claims: no leaf for claim %d", n, i)
+			}
+			proof, err := tree.Proof(unlockID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyMerkleProof(proof, tree.Root(), leaf) {
+				t.Fatalf("%d claims: claim %d proof doesn't verify against the root", n, i)
+			}
+			tampered := leaf
+			tampered[0] ^= 1
+			if VerifyMerkleProof(proof, tree.Root(), tampered) {
+				t.Fatalf("%d claims: claim %d proof verifies a tampered leaf", n, i)
+			}
+		}
+	}
+}
+
+func TestClaimMerkleTreeLayout(t *testing.T) {
+	events := merkleClaims(2)
+	tree, err := NewClaimMerkleTree(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The leaves are the claims' messages, and the root is their hash in sorted order
+	first, err := ClaimMessage(events[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ClaimMessage(events[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf, _ := tree.Leaf(big.NewInt(1)); leaf != common.BytesToHash(first) {
+		t.Fatalf("leaf = %s, want the claim message %x", leaf.Hex(), first)
+	}
+	if hashMerklePair(common.BytesToHash(first), common.BytesToHash(second)) != tree.Root() ||
+		hashMerklePair(common.BytesToHash(second), common.BytesToHash(first)) != tree.Root() {
+		t.Fatalf("root = %s, want the sorted pair hash of the two claims", tree.Root().Hex())
+	}
+
+	if _, err := tree.Proof(big.NewInt(3)); !errors.Is(err, ErrClaimNotInTree) {
+		t.Fatalf("Proof of a claim outside the tree = %v, want ErrClaimNotInTree", err)
+	}
+	if _, err := NewClaimMerkleTree(nil); !errors.Is(err, ErrEmptyMerkleTree) {
+		t.Fatalf("NewClaimMerkleTree(nil) = %v, want ErrEmptyMerkleTree", err)
+	}
+	if _, err := NewClaimMerkleTree(append(events, events[0])); err == nil {
+		t.Fatal("built a tree with a duplicate claim")
+	}
+}
+
+func TestClaimMerkleTreeSignRoot(t *testing.T) {
+	key := testKey(t)
+	tree, err := NewClaimMerkleTree(merkleClaims(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := tree.SignRoot(NewKeySigner(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root()
+	signer, err := RecoverSigner(PrefixMsg(root.Bytes()), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("signed root recovers to %s, want the signer", signer.Hex())
+	}
+
+	// As the contract does for each claim of the batch: check the root's signature, then the proof
+	leaf, _ := tree.Leaf(big.NewInt(4))
+	proof, err := tree.Proof(big.NewInt(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyMerkleProof(proof, root, leaf) {
+		t.Fatal("claim proof doesn't verify against the signed root")
+	}
+}
@@ -0,0 +1,199 @@
+package txs
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+const (
+	// metricsNamespace prefixes every relayer metric name
+	metricsNamespace = "ebrelayer"
+
+	// ethereumChainLabel and harmonyChainLabel are the metrics' chain label values
+	ethereumChainLabel = "ethereum"
+	harmonyChainLabel  = "harmony"
+)
+
+// Claim error reasons reported by the claim_errors_total metric
+const (
+	SignErrorReason        = "sign"
+	ConfigErrorReason      = "config"
+	SubmitErrorReason      = "submit"
+	AmountLimitErrorReason = "amount_limit"
+	DecodeErrorReason      = "decode"
+	GasBalanceErrorReason  = "gas_balance"
+	CircuitOpenErrorReason = "circuit_open"
+)
+
+// Contract event outcomes reported by the contract_events_total metric
+const (
+	ContractEventProcessed = "processed"
+	ContractEventFailed    = "failed"
+)
+
+// Metrics tracks claim signing and submission. A nil *Metrics discards all observations.
+type Metrics struct {
+	ClaimsSigned    *prometheus.CounterVec
+	ClaimsSubmitted *prometheus.CounterVec
+	ClaimErrors     *prometheus.CounterVec
+	SigningLatency  *prometheus.HistogramVec
+	StageLatency    *prometheus.HistogramVec
+	SigningHalted   prometheus.Gauge
+	ContractEvents  *prometheus.CounterVec
+	GasBalance      *prometheus.GaugeVec
+}
+
+// NewMetrics initializes the claim metrics and registers them on registerer
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		ClaimsSigned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "claims_signed_total",
+			Help:      "Number of oracle claims signed, by the chain the claimed event was emitted on.",
+		}, []string{"chain"}),
+		ClaimsSubmitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "claims_submitted_total",
+			Help:      "Number of claim transactions submitted, by the chain they were submitted to.",
+		}, []string{"chain"}),
+		ClaimErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "claim_errors_total",
+			Help:      "Number of claims which failed to be signed or submitted, by reason.",
+		}, []string{"reason"}),
+		SigningLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "claim_signing_seconds",
+			Help:      "Time taken to generate and sign a claim message.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+		}, []string{"chain"}),
+		StageLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "pipeline_stage_seconds",
+			Help:      "Time taken by an event to move between claim pipeline stages, by the chain it was emitted on.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 4, 10),
+		}, []string{"chain", "from", "to"}),
+		SigningHalted: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "signing_halted",
+			Help:      "1 while claim signing is halted pending manual intervention, such as after a deep reorg.",
+		}),
+		ContractEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "contract_events_total",
+			Help:      "Number of bridge contract events handled, by the chain and contract emitting them and their outcome.",
+		}, []string{"chain", "contract", "outcome"}),
+		GasBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "gas_balance",
+			Help:      "Native token balance of the account submitting claims, in the chain's smallest unit, as last checked.",
+		}, []string{"chain"}),
+	}
+
+	collectors := []prometheus.Collector{m.ClaimsSigned, m.ClaimsSubmitted, m.ClaimErrors, m.SigningLatency, m.StageLatency,
+		m.SigningHalted, m.ContractEvents, m.GasBalance}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+var (
+	metricsMu sync.RWMutex
+	metrics   *Metrics
+)
+
+// SetMetrics installs the Metrics updated by claim signing and submission. A nil m disables metrics.
+func SetMetrics(m *Metrics) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	metrics = m
+}
+
+// getMetrics returns the installed Metrics, which may be nil
+func getMetrics() *Metrics {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+	return metrics
+}
+
+// claimSigned records a claim signed in elapsed time
+func (m *Metrics) claimSigned(chain string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.ClaimsSigned.WithLabelValues(chain).Inc()
+	m.SigningLatency.WithLabelValues(chain).Observe(elapsed.Seconds())
+}
+
+// claimSubmitted records a claim transaction submitted to chain
+func (m *Metrics) claimSubmitted(chain string) {
+	if m == nil {
+		return
+	}
+	m.ClaimsSubmitted.WithLabelValues(chain).Inc()
+}
+
+// stageReached records an event emitted on chain moving from one pipeline stage to the next in
+// elapsed time
+func (m *Metrics) stageReached(chain string, from, to PipelineStage, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.StageLatency.WithLabelValues(chain, from.String(), to.String()).Observe(elapsed.Seconds())
+}
+
+// RecordContractEvent records an event emitted on chain by a bridge contract, handled with outcome
+func RecordContractEvent(chain string, contract common.Address, outcome string) {
+	m := getMetrics()
+	if m == nil {
+		return
+	}
+	m.ContractEvents.WithLabelValues(chain, contract.Hex(), outcome).Inc()
+}
+
+// signingHalted records whether claim signing is halted
+func (m *Metrics) signingHalted(halted bool) {
+	if m == nil {
+		return
+	}
+	if halted {
+		m.SigningHalted.Set(1)
+	} else {
+		m.SigningHalted.Set(0)
+	}
+}
+
+// gasBalance records the balance of the account submitting claims to chain
+func (m *Metrics) gasBalance(chain string, balance *big.Int) {
+	if m == nil {
+		return
+	}
+	value, _ := new(big.Float).SetInt(balance).Float64()
+	m.GasBalance.WithLabelValues(chain).Set(value)
+}
+
+// claimError records a claim which failed for reason, passing err through
+func (m *Metrics) claimError(reason string, err error) error {
+	if m == nil || err == nil {
+		return err
+	}
+	m.ClaimErrors.WithLabelValues(reason).Inc()
+	return err
+}
+
+// claimEventChain returns the chain label of the chain a claim event was emitted on
+func claimEventChain(event types.ClaimEvent) string {
+	if _, ok := event.(types.HmyLogNewUnlockClaimEvent); ok {
+		return harmonyChainLabel
+	}
+	return ethereumChainLabel
+}
@@ -0,0 +1,86 @@
+package txs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// useTestMetrics installs Metrics registered on a fresh registry, returning them and the function
+// disabling them again
+func useTestMetrics(t *testing.T) (*Metrics, func()) {
+	m, err := NewMetrics(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetMetrics(m)
+	return m, func() { SetMetrics(nil) }
+}
+
+// failingSigner is a Signer refusing every message
+type failingSigner struct{}
+
+func (failingSigner) Address() common.Address { return common.Address{} }
+
+func (failingSigner) Sign([]byte) ([]byte, error) { return nil, errors.New("signer unavailable") }
+
+func TestSigningClaimIncrementsCounter(t *testing.T) {
+	m, disable := useTestMetrics(t)
+	defer disable()
+
+	signer := NewKeySigner(testKey(t))
+	event := testClaimEvents(1)[0]
+	if _, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{event}); err != nil {
+		t.Fatal(err)
+	}
+	if signed := testutil.ToFloat64(m.ClaimsSigned.WithLabelValues(ethereumChainLabel)); signed != 1 {
+		t.Fatalf("claims_signed_total{chain=ethereum} = %v, want 1", signed)
+	}
+
+	hmyEvent := types.HmyLogNewUnlockClaimEvent{
+		UnlockID:        event.UnlockID,
+		EthereumSender:  event.HarmonySender,
+		HarmonyReceiver: event.EthereumReceiver,
+		TokenAddress:    event.TokenAddress,
+		Amount:          event.Amount,
+	}
+	if _, err := HmySignClaimsBatch(signer, []types.HmyLogNewUnlockClaimEvent{hmyEvent}); err != nil {
+		t.Fatal(err)
+	}
+	if signed := testutil.ToFloat64(m.ClaimsSigned.WithLabelValues(harmonyChainLabel)); signed != 1 {
+		t.Fatalf("claims_signed_total{chain=harmony} = %v, want 1", signed)
+	}
+	if signed := testutil.ToFloat64(m.ClaimsSigned.WithLabelValues(ethereumChainLabel)); signed != 1 {
+		t.Fatalf("claims_signed_total{chain=ethereum} = %v after a Harmony claim, want 1", signed)
+	}
+}
+
+func TestSigningErrorIncrementsErrorCounter(t *testing.T) {
+	m, disable := useTestMetrics(t)
+	defer disable()
+
+	if _, err := SignClaimsBatch(failingSigner{}, testClaimEvents(1)); err == nil {
+		t.Fatal("signed a claim with a failing signer")
+	}
+	if failed := testutil.ToFloat64(m.ClaimErrors.WithLabelValues(SignErrorReason)); failed != 1 {
+		t.Fatalf("claim_errors_total{reason=sign} = %v, want 1", failed)
+	}
+	if signed := testutil.ToFloat64(m.ClaimsSigned.WithLabelValues(ethereumChainLabel)); signed != 0 {
+		t.Fatalf("claims_signed_total = %v for a failed claim, want 0", signed)
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.claimSigned(ethereumChainLabel, 0)
+	m.claimSubmitted(ethereumChainLabel)
+	failed := errors.New("failed")
+	if err := m.claimError(SignErrorReason, failed); err != failed {
+		t.Fatalf("claimError = %v, want %v passed through", err, failed)
+	}
+}
@@ -0,0 +1,142 @@
+package txs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PendingNonceSource queries an account's next nonce, including pending transactions
+type PendingNonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out monotonically increasing nonces per signer address, so back-to-back
+// claim submissions don't race on the account nonce. It is safe for concurrent use.
+type NonceManager struct {
+	source PendingNonceSource
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+}
+
+// NewNonceManager initializes a new NonceManager
+func NewNonceManager(source PendingNonceSource) *NonceManager {
+	return &NonceManager{
+		source: source,
+		nonces: make(map[common.Address]uint64),
+	}
+}
+
+// Next returns the account's next unused nonce, fetching the pending nonce on first use
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, ok := m.nonces[account]
+	if !ok {
+		var err error
+		nonce, err = m.source.PendingNonceAt(ctx, account)
+		if err != nil {
+			return 0, err
+		}
+	}
+	m.nonces[account] = nonce + 1
+	return nonce, nil
+}
+
+// Reset resyncs every tracked account's nonce from the chain, such as after a submission
+// failed and left a gap
+func (m *NonceManager) Reset(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for account := range m.nonces {
+		nonce, err := m.source.PendingNonceAt(ctx, account)
+		if err != nil {
+			return err
+		}
+		m.nonces[account] = nonce
+	}
+	return nil
+}
+
+// ResetAccount drops the account's tracked nonce, so its next use refetches the pending nonce from
+// the chain, such as after a transaction with the last nonce handed out was never broadcast
+func (m *NonceManager) ResetAccount(account common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.nonces, account)
+}
+
+var (
+	nonceManagersMu sync.Mutex
+	nonceManagers   = make(map[string]*NonceManager)
+)
+
+// nonceManagerFor returns the process-wide NonceManager for a provider, backed by the source it
+// was first requested with
+func nonceManagerFor(provider string, source PendingNonceSource) *NonceManager {
+	nonceManagersMu.Lock()
+	defer nonceManagersMu.Unlock()
+
+	manager, ok := nonceManagers[provider]
+	if !ok {
+		manager = NewNonceManager(source)
+		nonceManagers[provider] = manager
+	}
+	return manager
+}
+
+// assignNonce sets auth.Nonce to its sender's next nonce from provider's NonceManager, to be
+// called right before the transaction is broadcast. It returns the function settling the nonce
+// with the broadcast's error: unless the transaction was sent, the sender's nonce is resynced from
+// the chain, so a nonce never broadcast or rejected as too low leaves no gap.
+func assignNonce(provider string, source PendingNonceSource, auth *bind.TransactOpts) (func(error), error) {
+	manager := nonceManagerFor(provider, source)
+	nonce, err := manager.Next(context.Background(), auth.From)
+	if err != nil {
+		return nil, err
+	}
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	return func(err error) {
+		if err == nil {
+			return
+		}
+		getLogger().Warn("Claim transaction not sent, resyncing nonce", "account", auth.From.Hex(), "nonce", nonce,
+			"nonceTooLow", strings.Contains(strings.ToLower(err.Error()), "nonce too low"), "err", err)
+		manager.ResetAccount(auth.From)
+	}, nil
+}
+
+var (
+	submitLocksMu sync.Mutex
+	submitLocks   = make(map[string]*sync.Mutex)
+)
+
+// lockSubmission locks privateKey's signer on provider until its claim is sent, spanning nonce
+// assignment by assignNonce, so claims processed concurrently still reach the chain in nonce order. It returns the
+// function releasing the lock.
+func lockSubmission(provider string, privateKey *ecdsa.PrivateKey) func() {
+	if privateKey == nil {
+		return func() {}
+	}
+	key := provider + "/" + crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+
+	submitLocksMu.Lock()
+	lock, ok := submitLocks[key]
+	if !ok {
+		lock = new(sync.Mutex)
+		submitLocks[key] = lock
+	}
+	submitLocksMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
@@ -0,0 +1,163 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// pendingNonces is a PendingNonceSource of fixed pending nonces, counting its queries
+type pendingNonces struct {
+	mu      sync.Mutex
+	nonces  map[common.Address]uint64
+	queries int
+}
+
+func (p *pendingNonces) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queries++
+	return p.nonces[account], nil
+}
+
+func TestNonceManagerNextConcurrent(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	source := &pendingNonces{nonces: map[common.Address]uint64{account: 7}}
+	manager := NewNonceManager(source)
+
+	const callers = 64
+	nonces := make(chan uint64, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := manager.Next(context.Background(), account)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			nonces <- nonce
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := make(map[uint64]bool)
+	for nonce := range nonces {
+		if seen[nonce] {
+			t.Fatalf("nonce %d handed out twice", nonce)
+		}
+		seen[nonce] = true
+	}
+	for nonce := uint64(7); nonce < 7+callers; nonce++ {
+		if !seen[nonce] {
+			t.Fatalf("nonce %d never handed out", nonce)
+		}
+	}
+	if source.queries != 1 {
+		t.Fatalf("pending nonce queried %d times, want 1", source.queries)
+	}
+}
+
+func TestNonceManagerResetAccount(t *testing.T) {
+	account, other := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	source := &pendingNonces{nonces: map[common.Address]uint64{account: 3, other: 10}}
+	manager := NewNonceManager(source)
+
+	for _, a := range []common.Address{account, account, other} {
+		if _, err := manager.Next(context.Background(), a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manager.ResetAccount(account)
+
+	if nonce, _ := manager.Next(context.Background(), account); nonce != 3 {
+		t.Fatalf("nonce after reset = %d, want the pending nonce 3", nonce)
+	}
+	if nonce, _ := manager.Next(context.Background(), other); nonce != 11 {
+		t.Fatalf("other account's nonce = %d, want 11", nonce)
+	}
+}
+
+func TestAssignNonceSettle(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	source := &pendingNonces{nonces: map[common.Address]uint64{account: 5}}
+	provider := t.Name()
+
+	tests := []struct {
+		name string
+		err  error
+		want uint64
+	}{
+		{"sent", nil, 6},
+		{"not broadcast", errors.New("connection refused"), 5},
+		{"nonce too low", errors.New("nonce too low"), 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonceManagerFor(provider, source).ResetAccount(account)
+
+			auth := &bind.TransactOpts{From: account}
+			settle, err := assignNonce(provider, source, auth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if auth.Nonce.Uint64() != 5 {
+				t.Fatalf("assigned nonce %v, want 5", auth.Nonce)
+			}
+			settle(tt.err)
+
+			next, err := nonceManagerFor(provider, source).Next(context.Background(), account)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != tt.want {
+				t.Fatalf("next nonce = %d, want %d", next, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockSubmissionSendsNoncesInOrder(t *testing.T) {
+	key := testKey(t)
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	manager := NewNonceManager(&pendingNonces{nonces: map[common.Address]uint64{account: 3}})
+
+	const claims = 32
+	var (
+		mu   sync.Mutex
+		sent []uint64
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < claims; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := lockSubmission("test-provider", key)
+			defer unlock()
+
+			nonce, err := manager.Next(context.Background(), account)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			sent = append(sent, nonce)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for i, nonce := range sent {
+		if nonce != uint64(3+i) {
+			t.Fatalf("nonces sent in order %v, want contiguous from 3", sent)
+		}
+	}
+}
@@ -0,0 +1,802 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// abiPacked returns go-ethereum's ABI encoding of value as typ, cut down to Solidity's packed
+// encoding: the value alone at its own width, or each element of an array in its own word
+func abiPacked(typ abi.Type, value interface{}) ([]byte, error) {
+	encoded, err := abi.Arguments{{Type: typ}}.Pack(value)
+	if err != nil {
+		return nil, err
+	}
+	switch typ.T {
+	case abi.SliceTy:
+		// Skip the offset and length words
+		return encoded[64:], nil
+	case abi.ArrayTy:
+		return encoded, nil
+	case abi.IntTy, abi.UintTy:
+		return encoded[32-typ.Size/8:], nil
+	case abi.AddressTy:
+		return encoded[32-common.AddressLength:], nil
+	case abi.BoolTy:
+		return encoded[31:], nil
+	case abi.FixedBytesTy:
+		return encoded[:typ.Size], nil
+	}
+	return nil, fmt.Errorf("no packed encoding for %s", typ)
+}
+
+// checkPack fails t if pack's encoding of value as typ differs from go-ethereum's
+func checkPack(t *testing.T, typ string, value interface{}) {
+	t.Helper()
+	abiType, err := abi.NewType(typ, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := abiPacked(abiType, value)
+	if err != nil {
+		t.Fatalf("go-ethereum packing %v as %s: %v", value, typ, err)
+	}
+	if got := pack(typ, value, false); !bytes.Equal(got, want) {
+		t.Fatalf("pack(%s, %v) = %x, want %x", typ, value, got, want)
+	}
+}
+
+// randomInteger returns a random intN or uintN value of size bits, favouring the range's edges,
+// as the Go type go-ethereum packs it from
+func randomInteger(r *rand.Rand, goType reflect.Type, size int, signed bool) interface{} {
+	n := new(big.Int)
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(size))
+	switch r.Intn(6) {
+	case 0:
+		// Zero
+	case 1:
+		n.Sub(limit, big.NewInt(1))
+	case 2:
+		n.SetInt64(1)
+	default:
+		n.Rand(r, limit)
+	}
+	if signed {
+		// Shift the unsigned range down, so extremes give the signed minimum and maximum
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(size-1)))
+	}
+
+	if goType == reflect.TypeOf(&big.Int{}) {
+		return n
+	}
+	if signed {
+		return reflect.ValueOf(n.Int64()).Convert(goType).Interface()
+	}
+	return reflect.ValueOf(n.Uint64()).Convert(goType).Interface()
+}
+
+// randomValue returns a random value of typ, as the Go type go-ethereum packs it from
+func randomValue(r *rand.Rand, typ abi.Type) interface{} {
+	goType := typ.GetType()
+	switch typ.T {
+	case abi.IntTy:
+		return randomInteger(r, goType, typ.Size, true)
+	case abi.UintTy:
+		return randomInteger(r, goType, typ.Size, false)
+	case abi.BoolTy:
+		return r.Intn(2) == 1
+	case abi.AddressTy:
+		var address common.Address
+		r.Read(address[:])
+		return address
+	case abi.FixedBytesTy:
+		value := reflect.New(goType).Elem()
+		for i := 0; i < typ.Size; i++ {
+			value.Index(i).Set(reflect.ValueOf(byte(r.Intn(256))))
+		}
+		return value.Interface()
+	case abi.SliceTy, abi.ArrayTy:
+		length := typ.Size
+		value := reflect.New(goType).Elem()
+		if typ.T == abi.SliceTy {
+			length = r.Intn(4)
+			value = reflect.MakeSlice(goType, length, length)
+		}
+		for i := 0; i < length; i++ {
+			value.Index(i).Set(reflect.ValueOf(randomValue(r, *typ.Elem)))
+		}
+		return value.Interface()
+	}
+	panic("no random value for " + typ.String())
+}
+
+// randomPackType returns a random type pack supports: an address, bool, intN, uintN or bytesN,
+// or a dynamic or fixed-size array of one
+func randomPackType(r *rand.Rand) string {
+	var typ string
+	switch r.Intn(5) {
+	case 0:
+		typ = "address"
+	case 1:
+		typ = "bool"
+	case 2:
+		typ = fmt.Sprintf("int%d", 8*(1+r.Intn(32)))
+	case 3:
+		typ = fmt.Sprintf("uint%d", 8*(1+r.Intn(32)))
+	default:
+		typ = fmt.Sprintf("bytes%d", 1+r.Intn(32))
+	}
+	switch r.Intn(3) {
+	case 0:
+		typ += "[]"
+	case 1:
+		typ += fmt.Sprintf("[%d]", 1+r.Intn(3))
+	}
+	return typ
+}
+
+func TestPackMatchesGoEthereumSeeds(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"address", common.HexToAddress("0x538a7cc2b9f9e1d0118b50a7b7ef1b4b6e1f6e92")},
+		{"bool", true},
+		{"bool", false},
+		{"uint8", uint8(255)},
+		{"uint16", uint16(0x1234)},
+		{"uint24", big.NewInt(0xabcdef)},
+		{"uint32", uint32(1)},
+		{"uint64", uint64(1) << 63},
+		{"uint160", new(big.Int).Lsh(big.NewInt(1), 159)},
+		{"uint256", math.MaxBig256},
+		{"int8", int8(-1)},
+		{"int8", int8(-128)},
+		{"int16", int16(-300)},
+		{"int24", big.NewInt(-2)},
+		{"int32", int32(-2147483648)},
+		{"int64", int64(-1)},
+		{"int128", new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))},
+		{"int256", big.NewInt(-1)},
+		{"int256", new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))},
+		{"bytes1", [1]byte{0xff}},
+		{"bytes4", [4]byte{0xde, 0xad, 0xbe, 0xef}},
+		{"bytes32", [32]byte(common.HexToHash("0xab"))},
+		{"address[]", []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}},
+		{"bool[2]", [2]bool{true, false}},
+		{"uint8[]", []uint8{1, 2, 3}},
+		{"uint256[]", []*big.Int{big.NewInt(1), math.MaxBig256}},
+		{"int8[]", []int8{-1, 1}},
+		{"int128[2]", [2]*big.Int{big.NewInt(-5), big.NewInt(5)}},
+		{"bytes4[]", [][4]byte{{1, 2, 3, 4}, {0xff}}},
+		{"bytes32[1]", [1][32]byte{{0x01}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			checkPack(t, tt.typ, tt.value)
+		})
+	}
+}
+
+// TestPackMatchesGoEthereumRandom packs random values of random supported types, comparing each
+// with go-ethereum's encoding. Go 1.13 has no native fuzzing, so the inputs come from a fixed
+// seed, keeping any failure reproducible.
+func TestPackMatchesGoEthereumRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1028))
+	iterations := 5000
+	if testing.Short() {
+		iterations = 500
+	}
+	for i := 0; i < iterations; i++ {
+		typ := randomPackType(r)
+		abiType, err := abi.NewType(typ, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPack(t, typ, randomValue(r, abiType))
+	}
+}
+
+// panics reports whether f panics
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	f()
+	return false
+}
+
+func TestInt128MatchesGoEthereum(t *testing.T) {
+	maxInt128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	minInt128 := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+	int128Type, err := abi.NewType("int128", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []*big.Int{big.NewInt(-1), minInt128, maxInt128, big.NewInt(0), big.NewInt(1)} {
+		want, err := abiPacked(int128Type, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Int128(value); !bytes.Equal(got, want) {
+			t.Fatalf("Int128(%s) = %x, want %x", value, got, want)
+		}
+		// Decimal strings take the same path as the contracts' event values
+		if got := Int128(value.String()); !bytes.Equal(got, want) {
+			t.Fatalf("Int128(%q) = %x, want %x", value.String(), got, want)
+		}
+	}
+	if got := Int128(big.NewInt(-1)); !bytes.Equal(got, bytes.Repeat([]byte{0xff}, 16)) {
+		t.Fatalf("Int128(-1) = %x, want 16 bytes of 0xff", got)
+	}
+
+	uint128Type, err := abi.NewType("uint128", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []*big.Int{big.NewInt(0), big.NewInt(1), maxUint128} {
+		want, err := abiPacked(uint128Type, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Uint128(value); !bytes.Equal(got, want) {
+			t.Fatalf("Uint128(%s) = %x, want %x", value, got, want)
+		}
+	}
+
+	overflows := []struct {
+		name string
+		pack func()
+	}{
+		{"int128 max+1", func() { Int128(new(big.Int).Add(maxInt128, big.NewInt(1))) }},
+		{"int128 min-1", func() { Int128(new(big.Int).Sub(minInt128, big.NewInt(1))) }},
+		{"uint128 max+1", func() { Uint128(new(big.Int).Add(maxUint128, big.NewInt(1))) }},
+		{"uint128 -1", func() { Uint128(big.NewInt(-1)) }},
+	}
+	for _, tt := range overflows {
+		if !panics(tt.pack) {
+			t.Fatalf("packing %s didn't panic", tt.name)
+		}
+	}
+}
+
+func TestPackedArray(t *testing.T) {
+	tests := []struct {
+		typ    string
+		values interface{}
+		// elements are the values' individual Go values, as abi.encodePacked(a, b, ...) takes them
+		elements []interface{}
+		want     string
+	}{
+		{"uint8[]", []uint8{1, 2, 0xff}, []interface{}{uint8(1), uint8(2), uint8(0xff)}, "0102ff"},
+		{"uint16[]", []uint16{0x1234, 1}, []interface{}{uint16(0x1234), uint16(1)}, "12340001"},
+		{"uint16[2]", [2]uint16{0xabcd, 0}, []interface{}{uint16(0xabcd), uint16(0)}, "abcd0000"},
+		{"uint8[]", []uint8{}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			got, err := PackedArray(tt.typ, tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Fatalf("PackedArray(%s, %v) = %x, want %s", tt.typ, tt.values, got, tt.want)
+			}
+
+			// Each element packs as abi.encodePacked packs the bare value
+			elementType, err := abi.NewType(arrayTypePattern.FindStringSubmatch(tt.typ)[1], "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []byte
+			for _, element := range tt.elements {
+				packed, err := abiPacked(elementType, element)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want = append(want, packed...)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("PackedArray(%s, %v) = %x, want the packed elements %x", tt.typ, tt.values, got, want)
+			}
+		})
+	}
+
+	// An array value itself still packs with 32-byte elements, as abi.encodePacked pads them
+	for _, tt := range []struct {
+		typ   string
+		value interface{}
+	}{
+		{"uint8[]", []uint8{1, 2, 0xff}},
+		{"uint16[]", []uint16{0x1234, 1}},
+	} {
+		checkPack(t, tt.typ, tt.value)
+		if got := pack(tt.typ, tt.value, false); len(got) != 32*reflect.ValueOf(tt.value).Len() {
+			t.Fatalf("pack(%s) = %x, want a 32-byte word per element", tt.typ, got)
+		}
+	}
+}
+
+func TestPackedArrayErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   string
+		value interface{}
+	}{
+		{"not an array type", "uint8", []uint8{1}},
+		{"not an array value", "uint8[]", uint8(1)},
+		{"wrong length", "uint8[3]", []uint8{1, 2}},
+		{"unsupported element", "fixed128x18[]", []uint8{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if packed, err := PackedArray(tt.typ, tt.value); err == nil {
+				t.Fatalf("PackedArray(%s, %v) = %x, want an error", tt.typ, tt.value, packed)
+			}
+		})
+	}
+}
+
+func TestAddressArrayPacked(t *testing.T) {
+	addresses := []common.Address{common.HexToAddress(checksummedAddress), common.HexToAddress(testHarmonyHex)}
+	addressType, err := abi.NewType("address", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []byte
+	for _, address := range addresses {
+		packed, err := abiPacked(addressType, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, packed...)
+	}
+
+	if got := AddressArrayPacked(addresses); !bytes.Equal(got, want) || len(got) != 2*common.AddressLength {
+		t.Fatalf("AddressArrayPacked = %x, want the 20-byte addresses %x", got, want)
+	}
+	// Hex strings and raw bytes pack as the addresses they hold
+	mixed := []interface{}{checksummedAddress, addresses[1].Bytes()}
+	if got := AddressArrayPacked(mixed); !bytes.Equal(got, want) {
+		t.Fatalf("AddressArrayPacked(%v) = %x, want %x", mixed, got, want)
+	}
+
+	// The padded variant still matches an address[] value, for ABI encoding and packed hashes alike
+	checkPack(t, "address[]", addresses)
+	if got := AddressArray(addresses); len(got) != 2*32 {
+		t.Fatalf("AddressArray = %x, want a 32-byte word per address", got)
+	}
+
+	if !panics(func() { AddressArrayPacked([]interface{}{"0xnot-an-address"}) }) {
+		t.Fatal("AddressArrayPacked of an invalid address didn't panic")
+	}
+	if _, err := AddressArrayChecked([]interface{}{[]byte{0x01}}); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("AddressArrayChecked of a 1-byte address = %v, want ErrInvalidAddress", err)
+	}
+}
+
+func TestBoolArrayPacked(t *testing.T) {
+	flags := [3]bool{true, false, true}
+
+	// abi.encodePacked(true, false, true): a byte per bool
+	want, err := SolidityPack([]string{"bool", "bool", "bool"}, true, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want, []byte{1, 0, 1}) {
+		t.Fatalf("SolidityPack of three bools = %x, want 010001", want)
+	}
+	if got := BoolArrayPacked(flags); !bytes.Equal(got, want) {
+		t.Fatalf("BoolArrayPacked(%v) = %x, want %x", flags, got, want)
+	}
+	if got, err := PackedArray("bool[]", flags[:]); err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("PackedArray(bool[]) = %x, %v, want %x", got, err, want)
+	}
+
+	// abi.encodePacked of a bool[3] value pads each element to a word
+	checkPack(t, "bool[3]", flags)
+	if got := BoolArray(flags); len(got) != 3*32 || got[31] != 1 || got[63] != 0 || got[95] != 1 {
+		t.Fatalf("BoolArray(%v) = %x, want a 32-byte word per bool", flags, got)
+	}
+
+	if !panics(func() { BoolArrayPacked([]interface{}{true, 1}) }) || !panics(func() { BoolArrayPacked(true) }) {
+		t.Fatal("BoolArrayPacked of a non-bool didn't panic")
+	}
+}
+
+func TestUint256ArrayBoundaries(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	values := []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 255), maxUint256}
+	checkPack(t, "uint256[]", values)
+
+	packed := Uint256Array(values)
+	if len(packed) != len(values)*32 {
+		t.Fatalf("Uint256Array(%v) is %d bytes, want 32 per element", values, len(packed))
+	}
+	for i, value := range values {
+		if word := packed[32*i : 32*(i+1)]; new(big.Int).SetBytes(word).Cmp(value) != 0 {
+			t.Fatalf("element %d packed as %x, want %s", i, word, value)
+		}
+	}
+
+	// Decimal strings and full 32-byte words pack as the same words, never padded again
+	decimals := []string{"0", "1", values[2].String(), maxUint256.String()}
+	if got := Uint256Array(decimals); !bytes.Equal(got, packed) {
+		t.Fatalf("Uint256Array(%v) = %x, want %x", decimals, got, packed)
+	}
+	words := make([][]byte, len(values))
+	for i := range words {
+		words[i] = packed[32*i : 32*(i+1)]
+	}
+	if got := Uint256Array(words); !bytes.Equal(got, packed) {
+		t.Fatalf("Uint256Array of 32-byte words = %x, want %x", got, packed)
+	}
+}
+
+func TestAddressArrayChecked(t *testing.T) {
+	address := common.HexToAddress(checksummedAddress)
+	want := append(common.LeftPadBytes(address.Bytes(), 32), common.LeftPadBytes([]byte{0xff}, 32)...)
+
+	valid := []interface{}{
+		[]interface{}{address, "0xff"},
+		[]interface{}{checksummedAddress, common.HexToAddress("0xff").Bytes()},
+		[]interface{}{address.Bytes(), "ff"},
+		[][]byte{address.Bytes(), common.HexToAddress("0xff").Bytes()},
+		[]string{checksummedAddress, "0xff"},
+	}
+	for _, input := range valid {
+		got, err := AddressArrayChecked(input)
+		if err != nil {
+			t.Fatalf("AddressArrayChecked(%v) = %v", input, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("AddressArrayChecked(%v) = %x, want %x", input, got, want)
+		}
+	}
+
+	invalid := []interface{}{
+		[][]common.Address{{address}},
+		[]interface{}{address, []string{checksummedAddress}},
+		[][]byte{address.Bytes()[:19]},
+		[]interface{}{"0xzz"},
+		[]string{"0x" + strings.Repeat("ff", 21)},
+		[]int{1},
+		checksummedAddress,
+	}
+	for _, input := range invalid {
+		if got, err := AddressArrayChecked(input); !errors.Is(err, ErrInvalidAddress) {
+			t.Fatalf("AddressArrayChecked(%v) = %x, %v, want ErrInvalidAddress", input, got, err)
+		}
+	}
+	if !panics(func() { AddressArray([][]common.Address{{address}}) }) {
+		t.Fatal("AddressArray of a nested slice didn't panic")
+	}
+}
+
+func TestSolidityPackMixedTypes(t *testing.T) {
+	types := []string{"uint8", "int8", "int16", "int32", "int64", "int256", "bool", "address", "bytes4", "string",
+		"uint256[]", "int16[]", "(address,uint256)"}
+	values := []interface{}{
+		uint8(200),
+		big.NewInt(-2),
+		"-300",
+		big.NewInt(-1),
+		"-2",
+		big.NewInt(-1),
+		true,
+		goldenClaim.token,
+		[]byte{0xde, 0xad, 0xbe, 0xef},
+		"USDT",
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[]*big.Int{big.NewInt(-1)},
+		tokenAmount{Token: goldenClaim.token, Amount: goldenClaim.amount},
+	}
+	// As ethers' solidityPack lays them out: each value at its own width, negative integers in
+	// two's complement, and array elements in full words
+	want := strings.Join([]string{
+		"c8",
+		"fe",
+		"fed4",
+		"ffffffff",
+		"fffffffffffffffe",
+		strings.Repeat("ff", 32),
+		"01",
+		"dac17f958d2ee523a2206206994597c13d831ec7",
+		"deadbeef",
+		"55534454",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		strings.Repeat("ff", 32),
+		"dac17f958d2ee523a2206206994597c13d831ec7",
+		"00000000000000000000000000000000000000000000000014d1120d7b160000",
+	}, "")
+
+	packed, err := SolidityPack(types, values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(packed); got != want {
+		t.Fatalf("SolidityPack = %s, want %s", got, want)
+	}
+	hash, preimage, err := SoliditySHA3WithPreimage(types, values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(preimage, packed) || !bytes.Equal(hash, Keccak256(packed)) {
+		t.Fatalf("SoliditySHA3WithPreimage = %x, %x, want the hash of %x", hash, preimage, packed)
+	}
+
+	// A value that can't be packed as its type is an error rather than a panic
+	if _, err := SolidityPack([]string{"uint8[2]"}, []uint8{1}); err == nil {
+		t.Fatal("SolidityPack of a short fixed array succeeded")
+	}
+	if _, err := SolidityPack([]string{"uint256", "bool"}, big.NewInt(1)); err == nil {
+		t.Fatal("SolidityPack of fewer values than types succeeded")
+	}
+}
+
+func TestPackPanicBecomesError(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	tests := []struct {
+		name   string
+		types  []string
+		values []interface{}
+		want   []string
+	}{
+		{"short fixed array", []string{"bool", "uint256[3]"}, []interface{}{true, []*big.Int{big.NewInt(1)}},
+			[]string{"value 1", "uint256[3]", "[]*big.Int"}},
+		{"not an array", []string{"address[]"}, []interface{}{goldenClaim.amount},
+			[]string{"value 0", "address[]", "*big.Int(\"1500000000000000000\")"}},
+		{"long value", []string{"uint8[1]"}, []interface{}{[]string{long, long}},
+			[]string{"value 0", "uint8[1]", strings.Repeat("x", 60) + "..."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for mode, pack := range map[string]func() error{
+				"packed": func() error { _, err := SolidityPack(tt.types, tt.values...); return err },
+				"abi":    func() error { _, err := ABIEncode(tt.types, tt.values...); return err },
+				"hashed": func() error {
+					_, err := SoliditySHA3Typed(tt.types, tt.values, WithEncoding(ABIEncoding))
+					return err
+				},
+			} {
+				err := pack()
+				if err == nil {
+					t.Fatalf("%s encoding succeeded", mode)
+				}
+				for _, want := range tt.want {
+					if !strings.Contains(err.Error(), want) {
+						t.Fatalf("%s encoding error %q doesn't name %q", mode, err, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestUnsignedRejectsNegative(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"uint256", "-1"},
+		{"uint256", big.NewInt(-1)},
+		{"uint256", json.Number("-3")},
+		{"uint128", int64(-1)},
+		{"uint64", int(-1)},
+		{"uint32", int32(-5)},
+		{"uint8", int8(-1)},
+		{"uint256[]", []*big.Int{big.NewInt(1), big.NewInt(-1)}},
+	}
+	for _, tt := range tests {
+		if _, err := SolidityPack([]string{tt.typ}, tt.value); !errors.Is(err, ErrNegativeUnsigned) {
+			t.Fatalf("SolidityPack(%s, %v) = %v, want ErrNegativeUnsigned", tt.typ, tt.value, err)
+		}
+		if _, err := ABIEncode([]string{tt.typ}, tt.value); !errors.Is(err, ErrNegativeUnsigned) {
+			t.Fatalf("ABIEncode(%s, %v) = %v, want ErrNegativeUnsigned", tt.typ, tt.value, err)
+		}
+	}
+
+	// Zero and signed types are unaffected
+	if packed, err := SolidityPack([]string{"uint256", "int256"}, "0", big.NewInt(-1)); err != nil ||
+		!bytes.Equal(packed, append(make([]byte, 32), bytes.Repeat([]byte{0xff}, 32)...)) {
+		t.Fatalf("SolidityPack(0, -1) = %x, %v", packed, err)
+	}
+}
+
+func TestUnsignedPacksEveryIntegerKind(t *testing.T) {
+	five := []interface{}{int(5), int8(5), int16(5), int32(5), int64(5), uint(5), uint8(5), uint16(5),
+		uint32(5), uint64(5), big.NewInt(5), "5", json.Number("5")}
+	for _, typ := range []string{"uint8", "uint16", "uint32", "uint64", "uint128", "uint256"} {
+		bits, _ := strconv.Atoi(strings.TrimPrefix(typ, "uint"))
+		want := common.LeftPadBytes([]byte{5}, bits/8)
+		for _, value := range five {
+			if packed, err := SolidityPack([]string{typ}, value); err != nil || !bytes.Equal(packed, want) {
+				t.Fatalf("SolidityPack(%s, %T(5)) = %x, %v, want %x", typ, value, packed, err, want)
+			}
+		}
+	}
+}
+
+func TestUnsignedRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value interface{}
+		err   error
+	}{
+		{"uint256", "12abc", ErrUnsupportedType},
+		{"uint256", "0x05", ErrUnsupportedType},
+		{"uint8", "", ErrUnsupportedType},
+		{"uint256", true, ErrUnsupportedType},
+		{"uint64", 5.0, ErrUnsupportedType},
+		{"uint256", (*big.Int)(nil), ErrUnsupportedType},
+		{"uint256", new(big.Int).Lsh(big.NewInt(1), 256), ErrWordOverflow},
+		{"uint128", new(big.Int).Lsh(big.NewInt(1), 128), ErrIntegerOverflow},
+		{"uint64", new(big.Int).Lsh(big.NewInt(1), 64), ErrIntegerOverflow},
+		{"uint32", uint64(1) << 32, ErrIntegerOverflow},
+		{"uint16", int(1) << 16, ErrIntegerOverflow},
+		{"uint8", "256", ErrIntegerOverflow},
+		{"uint8", uint16(256), ErrIntegerOverflow},
+	}
+	for _, tt := range tests {
+		if _, err := SolidityPack([]string{tt.typ}, tt.value); !errors.Is(err, tt.err) {
+			t.Fatalf("SolidityPack(%s, %#v) = %v, want %v", tt.typ, tt.value, err, tt.err)
+		}
+		if _, err := ABIEncode([]string{tt.typ}, tt.value); !errors.Is(err, tt.err) {
+			t.Fatalf("ABIEncode(%s, %#v) = %v, want %v", tt.typ, tt.value, err, tt.err)
+		}
+	}
+
+	// The largest value of each width still packs
+	for _, bits := range []uint{8, 16, 32, 64, 128, 256} {
+		max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1))
+		want := bytes.Repeat([]byte{0xff}, int(bits/8))
+		if packed, err := SolidityPack([]string{fmt.Sprintf("uint%d", bits)}, max); err != nil || !bytes.Equal(packed, want) {
+			t.Fatalf("SolidityPack(uint%d, %v) = %x, %v", bits, max, packed, err)
+		}
+	}
+}
+
+// claimLikeLayout is a claim-like layout of values each packing helper is run over
+var (
+	claimLikeTypes  = []string{"uint256", "address", "bytes32", "uint8[]", "string", "int64"}
+	claimLikeValues = []interface{}{goldenClaim.amount, goldenClaim.token, common.HexToHash(goldenClaim.message),
+		[]uint8{1, 2, 3}, "USDT", big.NewInt(-7)}
+)
+
+func TestPackConcurrent(t *testing.T) {
+	packed, err := SolidityPack(claimLikeTypes, claimLikeValues...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := ABIEncode(claimLikeTypes, claimLikeValues...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := SoliditySHA3(goldenClaim.unlockID, goldenClaim.sender, goldenClaim.recipient, goldenClaim.token, goldenClaim.amount)
+
+	// Run with -race: every goroutine gets the single-goroutine results
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				gotPacked, err := SolidityPack(claimLikeTypes, claimLikeValues...)
+				if err != nil || !bytes.Equal(gotPacked, packed) {
+					errs <- fmt.Errorf("SolidityPack = %x, %v, want %x", gotPacked, err, packed)
+					return
+				}
+				gotEncoded, err := ABIEncode(claimLikeTypes, claimLikeValues...)
+				if err != nil || !bytes.Equal(gotEncoded, encoded) {
+					errs <- fmt.Errorf("ABIEncode = %x, %v, want %x", gotEncoded, err, encoded)
+					return
+				}
+				gotHash := SoliditySHA3(goldenClaim.unlockID, goldenClaim.sender, goldenClaim.recipient, goldenClaim.token, goldenClaim.amount)
+				if !bytes.Equal(gotHash, hash) {
+					errs <- fmt.Errorf("SoliditySHA3 = %x, want %x", gotHash, hash)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkSolidityPack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := SolidityPack(claimLikeTypes, claimLikeValues...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTypePatterns compares matching the claim-like types against the package-level patterns
+// with compiling the patterns for each match, as pack once did
+func BenchmarkTypePatterns(b *testing.B) {
+	patterns := []*regexp.Regexp{numberTypePattern, bytesTypePattern, arrayTypePattern}
+	match := func(compile func(*regexp.Regexp) *regexp.Regexp) {
+		for _, typ := range claimLikeTypes {
+			for _, pattern := range patterns {
+				compile(pattern).FindAllStringSubmatch(typ, -1)
+			}
+		}
+	}
+
+	b.Run("hoisted", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			match(func(pattern *regexp.Regexp) *regexp.Regexp { return pattern })
+		}
+	})
+	b.Run("compiled per match", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			match(func(pattern *regexp.Regexp) *regexp.Regexp { return regexp.MustCompile(pattern.String()) })
+		}
+	})
+}
+
+func TestPackBytesNLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		value   interface{}
+		want    string
+		wantErr error
+	}{
+		{"exact hex", "bytes4", "0xdeadbeef", "deadbeef", nil},
+		{"exact slice", "bytes4", []byte{0xde, 0xad, 0xbe, 0xef}, "deadbeef", nil},
+		{"exact array", "bytes4", [4]byte{0xde, 0xad, 0xbe, 0xef}, "deadbeef", nil},
+		{"exact hash", "bytes32", common.HexToHash("0xab"), common.HexToHash("0xab").Hex()[2:], nil},
+		{"short hex", "bytes4", "0xdead", "", ErrBytesLength},
+		{"short slice", "bytes32", []byte{0xab}, "", ErrBytesLength},
+		{"long hex", "bytes4", "0xdeadbeef00", "", ErrBytesLength},
+		// A full hash passed for a short field was once silently truncated
+		{"long hash", "bytes4", common.HexToHash("0xab"), "", ErrBytesLength},
+		{"short array element", "bytes4[]", [][]byte{{1, 2, 3, 4}, {1, 2}}, "", ErrBytesLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packed, err := SolidityPack([]string{tt.typ}, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SolidityPack(%s, %v) = %v, want %v", tt.typ, tt.value, err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.typ) {
+					t.Fatalf("error %q doesn't name the type %s", err, tt.typ)
+				}
+				if _, err := ABIEncode([]string{tt.typ}, tt.value); !errors.Is(err, ErrBytesLength) {
+					t.Fatalf("ABIEncode = %v, want ErrBytesLength", err)
+				}
+				return
+			}
+			if got := hex.EncodeToString(packed); got != tt.want {
+				t.Fatalf("SolidityPack(%s, %v) = %s, want %s", tt.typ, tt.value, got, tt.want)
+			}
+		})
+	}
+}
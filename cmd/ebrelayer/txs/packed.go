@@ -0,0 +1,84 @@
+package txs
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// arrayTypePattern matches a Solidity array type, capturing its element type and optional length
+var arrayTypePattern = regexp.MustCompile(`^(.*)\[([0-9]*)\]$`)
+
+// PackedArray tightly packs the elements of an array type such as "uint8[]", each at its own
+// width with no padding: one byte per uint8, two per uint16 and so on.
+//
+// Note this is not how abi.encodePacked encodes an array value. Solidity pads every element of an
+// array to 32 bytes even in packed mode, which is what SoliditySHA3Typed and SoliditySHA3 produce
+// for array types. Use PackedArray only to match hashes over elements concatenated at their own
+// width, such as abi.encodePacked(a, b, c) over individual values or hand-built bytes.
+func PackedArray(typ string, value interface{}) ([]byte, error) {
+	matches := arrayTypePattern.FindStringSubmatch(typ)
+	if matches == nil {
+		return nil, fmt.Errorf("%s is not an array type", typ)
+	}
+	if value == nil || !isArray(value) {
+		return nil, fmt.Errorf("invalid value for %s: expected an array or slice", typ)
+	}
+
+	elements := reflect.ValueOf(value)
+	if matches[2] != "" {
+		length, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return nil, err
+		}
+		if length != elements.Len() {
+			return nil, fmt.Errorf("invalid value for %s: %d elements", typ, elements.Len())
+		}
+	}
+
+	elementType := matches[1]
+	if err := checkABIType(elementType); err != nil {
+		return nil, err
+	}
+	var packed []byte
+	for i := 0; i < elements.Len(); i++ {
+		packed = append(packed, pack(elementType, elements.Index(i).Interface(), false)...)
+	}
+	return packed, nil
+}
+
+// AddressArrayPacked packs each address as its raw 20 bytes, the packed encoding of a bare
+// address, as used by hashes over addresses passed to abi.encodePacked individually. Unlike
+// AddressArray it doesn't match an address[] value, whose elements are padded to 32 bytes.
+func AddressArrayPacked(input interface{}) []byte {
+	addresses, err := addressElements(input)
+	if err != nil {
+		panic(err)
+	}
+	var values []byte
+	for _, address := range addresses {
+		values = append(values, address...)
+	}
+	return values
+}
+
+// BoolArrayPacked packs each bool as a single 0x00 or 0x01 byte, the packed encoding of a bare
+// bool, as used by hashes over bools passed to abi.encodePacked individually or over hand-built
+// flag bytes. Unlike BoolArray it doesn't match a bool[] value, whose elements abi.encodePacked
+// pads to 32 bytes. Panics unless input is an array or slice of bools.
+func BoolArrayPacked(input interface{}) []byte {
+	if input == nil || !isArray(input) {
+		panic(fmt.Errorf("%T is not a bool array", input))
+	}
+	s := reflect.ValueOf(input)
+	values := make([]byte, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		value, ok := s.Index(i).Interface().(bool)
+		if !ok {
+			panic(fmt.Errorf("bool array element %d is a %T, not a bool", i, s.Index(i).Interface()))
+		}
+		values = append(values, Bool(value)...)
+	}
+	return values
+}
@@ -14,52 +14,38 @@ const (
 	nullAddress = "0x0000000000000000000000000000000000000000"
 )
 
-// EthUnlockClaimToSignedOracleClaim packages and signs a unlock claim's data, returning a new oracle claim
-func EthUnlockClaimToSignedOracleClaim(event types.EthLogNewUnlockClaimEvent, key *ecdsa.PrivateKey) (EthOracleClaim, error) {
-	oracleClaim := EthOracleClaim{}
-
-	// Generate a hashed claim message which contains UnlockClaim's data
-	fmt.Println("Generating unique message for UnlockClaim", event.UnlockID)
-	message := EthGenerateClaimMessage(event)
-
-	// Sign the message using the validator's private key
-	fmt.Println("Signing message...")
-	signature, err := SignClaim(PrefixMsg(message), key)
+// EthUnlockClaimToSignedOracleClaim packages and signs a unlock claim's data, returning a new oracle claim. The
+// message is prefixed per the SigningSchemes config of the optional target verifying contract.
+func EthUnlockClaimToSignedOracleClaim(event types.EthLogNewUnlockClaimEvent, key *ecdsa.PrivateKey,
+	target ...common.Address) (EthOracleClaim, error) {
+	// Generate and sign a hashed claim message which contains UnlockClaim's data
+	fmt.Println("Generating and signing unique message for UnlockClaim", event.UnlockID)
+	signed, err := signClaimEvent(NewKeySigner(key), event, target)
 	if err != nil {
-		return oracleClaim, err
+		return EthOracleClaim{}, err
+	}
+	fmt.Println("Signature generated:", hexutil.Encode(signed.Signature))
+	if signed.Exported {
+		return EthOracleClaim{}, ErrClaimExported
 	}
-	fmt.Println("Signature generated:", hexutil.Encode(signature))
-
-	oracleClaim.UnlockID = event.UnlockID
-	var message32 [32]byte
-	copy(message32[:], message)
-	oracleClaim.Message = message32
-	oracleClaim.Signature = signature
-	return oracleClaim, nil
+	return EthOracleClaim{UnlockID: signed.UnlockID, Message: signed.Message, Signature: signed.Signature}, nil
 }
 
-// HmyUnlockClaimToSignedOracleClaim packages and signs a unlock claim's data, returning a new oracle claim
-func HmyUnlockClaimToSignedOracleClaim(event types.HmyLogNewUnlockClaimEvent, key *ecdsa.PrivateKey) (HmyOracleClaim, error) {
-	oracleClaim := HmyOracleClaim{}
-
-	// Generate a hashed claim message which contains UnlockClaim's data
-	fmt.Println("Generating unique message for UnlockClaim", event.UnlockID)
-	message := HmyGenerateClaimMessage(event)
-
-	// Sign the message using the validator's private key
-	fmt.Println("Signing message...")
-	signature, err := SignClaim(PrefixMsg(message), key)
+// HmyUnlockClaimToSignedOracleClaim packages and signs a unlock claim's data, returning a new oracle claim. The
+// message is prefixed per the SigningSchemes config of the optional target verifying contract.
+func HmyUnlockClaimToSignedOracleClaim(event types.HmyLogNewUnlockClaimEvent, key *ecdsa.PrivateKey,
+	target ...common.Address) (HmyOracleClaim, error) {
+	// Generate and sign a hashed claim message which contains UnlockClaim's data
+	fmt.Println("Generating and signing unique message for UnlockClaim", event.UnlockID)
+	signed, err := signClaimEvent(NewKeySigner(key), event, target)
 	if err != nil {
-		return oracleClaim, err
+		return HmyOracleClaim{}, err
 	}
-	fmt.Println("Signature generated:", hexutil.Encode(signature))
-
-	oracleClaim.UnlockID = event.UnlockID
-	var message32 [32]byte
-	copy(message32[:], message)
-	oracleClaim.Message = message32
-	oracleClaim.Signature = signature
-	return oracleClaim, nil
+	fmt.Println("Signature generated:", hexutil.Encode(signed.Signature))
+	if signed.Exported {
+		return HmyOracleClaim{}, ErrClaimExported
+	}
+	return HmyOracleClaim{UnlockID: signed.UnlockID, Message: signed.Message, Signature: signed.Signature}, nil
 }
 
 // isZeroAddress checks an Ethereum address and returns a bool which indicates if it is the null address
@@ -67,7 +53,9 @@ func isZeroAddress(address common.Address) bool {
 	return address == common.HexToAddress(nullAddress)
 }
 
-// HarmonyEventToEthereumClaim parses and packages an Ethereum event struct with a validator address in an EthBridgeClaim msg
+// HarmonyEventToEthereumClaim parses and packages an Ethereum event struct with a validator address in an EthBridgeClaim msg.
+// The claim's token is mapped by the BridgeBankTokens mapping of the lock's BridgeBank, or otherwise by HmyToEthTokens if
+// set, returning ErrUnmappedToken for a token without a mapping.
 func HarmonyEventToEthereumClaim(event *types.HmyLogLockEvent) (EthUnlockClaim, error) {
 	witnessClaim := EthUnlockClaim{}
 
@@ -80,8 +68,11 @@ func HarmonyEventToEthereumClaim(event *types.HmyLogLockEvent) (EthUnlockClaim,
 	// ethereumReceiver type casting (address.common -> string)
 	ethereumReceiver := event.EthereumReceiver
 
-	// token type casting (address.common -> string)
-	token := event.EthereumToken
+	// token is the Ethereum token mapped to the locked Harmony token
+	token, err := destinationToken(bridgeBankTokens(event.BridgeBankAddress, HmyToEthTokens), event.HarmonyToken, event.EthereumToken)
+	if err != nil {
+		return witnessClaim, getMetrics().claimError(ConfigErrorReason, err)
+	}
 
 	// amount is
 	amount := event.EthereumTokenAmount
@@ -92,11 +83,14 @@ func HarmonyEventToEthereumClaim(event *types.HmyLogLockEvent) (EthUnlockClaim,
 	witnessClaim.HarmonySender = harmonySender
 	witnessClaim.EthereumReceiver = ethereumReceiver
 	witnessClaim.Amount = amount
+	witnessClaim.LockTx = event.TxHash
 
 	return witnessClaim, nil
 }
 
-// EthereumEventToHarmonyClaim parses and packages an Ethereum event struct with a validator address in an EthBridgeClaim msg
+// EthereumEventToHarmonyClaim parses and packages an Ethereum event struct with a validator address in an EthBridgeClaim msg.
+// The claim's token is mapped by the BridgeBankTokens mapping of the lock's BridgeBank, or otherwise by EthToHmyTokens if
+// set, returning ErrUnmappedToken for a token without a mapping.
 func EthereumEventToHarmonyClaim(event *types.EthLogLockEvent) (HmyUnlockClaim, error) {
 	witnessClaim := HmyUnlockClaim{}
 
@@ -109,8 +103,11 @@ func EthereumEventToHarmonyClaim(event *types.EthLogLockEvent) (HmyUnlockClaim,
 	// harmonyReceiver type casting (address.common -> string)
 	harmonyReceiver := event.HarmonyReceiver
 
-	// token type casting (address.common -> string)
-	token := event.HarmonyToken
+	// token is the Harmony token mapped to the locked Ethereum token
+	token, err := destinationToken(bridgeBankTokens(event.BridgeBankAddress, EthToHmyTokens), event.EthereumToken, event.HarmonyToken)
+	if err != nil {
+		return witnessClaim, getMetrics().claimError(ConfigErrorReason, err)
+	}
 
 	// amount is
 	amount := event.HarmonyTokenAmount
@@ -121,6 +118,7 @@ func EthereumEventToHarmonyClaim(event *types.EthLogLockEvent) (HmyUnlockClaim,
 	witnessClaim.EthereumSender = ethereumSender
 	witnessClaim.HarmonyReceiver = harmonyReceiver
 	witnessClaim.Amount = amount
+	witnessClaim.LockTx = event.TxHash
 
 	return witnessClaim, nil
 }
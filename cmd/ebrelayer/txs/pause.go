@@ -0,0 +1,67 @@
+package txs
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	pauseMu sync.Mutex
+	// resumed is closed when paused claim signing resumes, and nil while it isn't paused
+	resumed chan struct{}
+)
+
+// PauseSigning pauses claim signing and submission until ResumeSigning is called. Unlike
+// HaltSigning, claims aren't refused: the relayer holds each at WaitSigningResumed, without
+// checkpointing it, so none are lost. It returns false if signing was already paused.
+func PauseSigning() bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+
+	if resumed != nil {
+		return false
+	}
+	resumed = make(chan struct{})
+	getLogger().Warn("Claim signing paused by operator")
+	return true
+}
+
+// ResumeSigning resumes claim signing paused by PauseSigning, releasing every held claim. It
+// returns false if signing wasn't paused.
+func ResumeSigning() bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+
+	if resumed == nil {
+		return false
+	}
+	close(resumed)
+	resumed = nil
+	getLogger().Info("Claim signing resumed by operator")
+	return true
+}
+
+// SigningPaused reports whether claim signing is paused
+func SigningPaused() bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+
+	return resumed != nil
+}
+
+// WaitSigningResumed blocks while claim signing is paused, returning ctx.Err() if ctx is done first
+func WaitSigningResumed(ctx context.Context) error {
+	pauseMu.Lock()
+	wait := resumed
+	pauseMu.Unlock()
+	if wait == nil {
+		return nil
+	}
+
+	select {
+	case <-wait:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
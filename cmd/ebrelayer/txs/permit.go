@@ -0,0 +1,101 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrPermitExpired is returned when building or signing a permit whose deadline has passed
+var ErrPermitExpired = errors.New("permit deadline has passed")
+
+var (
+	// eip712PermitTypeHash is keccak256 of the EIP-2612 Permit type
+	eip712PermitTypeHash = Keccak256([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+	// permitFieldNames are the names of the Permit's uint256 fields, in the order they're hashed
+	permitFieldNames = []string{"value", "nonce", "deadline"}
+)
+
+// Permit is an EIP-2612 permit, by which Owner allows Spender to transfer Value of its tokens
+// without an approval transaction. Nonce is the owner's current nonce on the token, and Deadline
+// the unix time after which the token rejects the permit.
+type Permit struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// PermitSignature is a permit signature split as the token's permit function takes it, with V
+// as 27 or 28
+type PermitSignature struct {
+	V uint8
+	R [32]byte
+	S [32]byte
+}
+
+// Bytes returns the signature as r || s || v
+func (s PermitSignature) Bytes() []byte {
+	return append(append(append([]byte{}, s.R[:]...), s.S[:]...), s.V)
+}
+
+// BuildPermitDigest returns the EIP-712 hash of permit under the domain of token, returning
+// ErrPermitExpired if its deadline has passed
+func BuildPermitDigest(domain EIP712Domain, token common.Address, permit Permit) ([]byte, error) {
+	return buildPermitDigest(domain, token, permit, time.Now())
+}
+
+// buildPermitDigest is BuildPermitDigest at the time now
+func buildPermitDigest(domain EIP712Domain, token common.Address, permit Permit, now time.Time) ([]byte, error) {
+	if domain.ChainID == nil {
+		return nil, fmt.Errorf("EIP-712 domain of %s has no chain ID", token.Hex())
+	}
+	for i, value := range []*big.Int{permit.Value, permit.Nonce, permit.Deadline} {
+		if value == nil || value.Sign() < 0 || value.BitLen() > 256 {
+			return nil, fmt.Errorf("invalid permit %s: %v", permitFieldNames[i], value)
+		}
+	}
+	if permit.Deadline.Cmp(big.NewInt(now.Unix())) < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrPermitExpired, permit.Deadline)
+	}
+
+	structHash := Keccak256(eip712PermitTypeHash,
+		common.LeftPadBytes(permit.Owner.Bytes(), 32), common.LeftPadBytes(permit.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(permit.Value)), math.U256Bytes(new(big.Int).Set(permit.Nonce)),
+		math.U256Bytes(new(big.Int).Set(permit.Deadline)))
+	return Keccak256([]byte{0x19, 0x01}, domain.Separator(token), structHash), nil
+}
+
+// SignPermit signs permit under the domain of token with signer, which must be the permit's
+// owner, returning ErrPermitExpired if its deadline has passed
+func SignPermit(signer Signer, domain EIP712Domain, token common.Address, permit Permit) (PermitSignature, error) {
+	if signer.Address() != permit.Owner {
+		return PermitSignature{}, fmt.Errorf("permit owner is %s, but signer is %s", permit.Owner.Hex(),
+			signer.Address().Hex())
+	}
+	digest, err := BuildPermitDigest(domain, token, permit)
+	if err != nil {
+		return PermitSignature{}, err
+	}
+	sig, err := signer.Sign(digest)
+	if err != nil {
+		return PermitSignature{}, err
+	}
+	if len(sig) != crypto.SignatureLength {
+		return PermitSignature{}, fmt.Errorf("permit signature is %d bytes, expected %d", len(sig), crypto.SignatureLength)
+	}
+
+	signature := PermitSignature{V: sig[64]}
+	if signature.V < 27 {
+		signature.V += 27
+	}
+	copy(signature.R[:], sig[:32])
+	copy(signature.S[:], sig[32:64])
+	return signature, nil
+}
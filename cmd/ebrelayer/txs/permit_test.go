@@ -0,0 +1,103 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testPermit returns a permit of testKey's tokens expiring an hour from now
+func testPermit(t *testing.T) (Permit, EIP712Domain) {
+	permit := Permit{
+		Owner:    crypto.PubkeyToAddress(testKey(t).PublicKey),
+		Spender:  goldenClaim.recipient,
+		Value:    new(big.Int).Set(goldenClaim.amount),
+		Nonce:    big.NewInt(3),
+		Deadline: big.NewInt(time.Now().Add(time.Hour).Unix()),
+	}
+	return permit, EIP712Domain{Name: "Tether USD", Version: "1", ChainID: big.NewInt(1)}
+}
+
+func TestPermitTypeHashes(t *testing.T) {
+	// OpenZeppelin ERC20Permit's PERMIT_TYPEHASH and EIP712's domain type hash
+	if got := common.Bytes2Hex(eip712PermitTypeHash); got != "6e71edae12b1b97f4d1f60370fef10105fa2faae0126114a169c64845d6126c9" {
+		t.Fatalf("permit type hash = %s", got)
+	}
+	if got := common.Bytes2Hex(eip712DomainTypeHash); got != "8b73c3c69bb8fe3d512ecc4cf759cc79239f7b179b0ffacaa9a75d522b39400f" {
+		t.Fatalf("domain type hash = %s", got)
+	}
+}
+
+func TestSignPermitRecoversOwner(t *testing.T) {
+	permit, domain := testPermit(t)
+	signature, err := SignPermit(NewKeySigner(testKey(t)), domain, goldenClaim.token, permit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature.V != 27 && signature.V != 28 {
+		t.Fatalf("v = %d, want 27 or 28", signature.V)
+	}
+
+	// As the token's permit function does: recover the owner from the digest and v, r, s
+	digest, err := BuildPermitDigest(domain, goldenClaim.token, permit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := RecoverSigner(digest, signature.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner != permit.Owner {
+		t.Fatalf("recovered %s, want the owner %s", owner.Hex(), permit.Owner.Hex())
+	}
+
+	// The signature covers the whole permit and the token's domain
+	raised := permit
+	raised.Value = new(big.Int).Add(permit.Value, big.NewInt(1))
+	otherToken := common.HexToAddress(checksummedAddress)
+	for name, digest := range map[string]func() ([]byte, error){
+		"raised value": func() ([]byte, error) { return BuildPermitDigest(domain, goldenClaim.token, raised) },
+		"other token":  func() ([]byte, error) { return BuildPermitDigest(domain, otherToken, permit) },
+	} {
+		hash, err := digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recovered, err := RecoverSigner(hash, signature.Bytes()); err == nil && recovered == permit.Owner {
+			t.Fatalf("%s: signature recovers to the owner", name)
+		}
+	}
+}
+
+func TestSignPermitErrors(t *testing.T) {
+	permit, domain := testPermit(t)
+	signer := NewKeySigner(testKey(t))
+
+	expired := permit
+	expired.Deadline = big.NewInt(time.Now().Add(-time.Minute).Unix())
+	if _, err := SignPermit(signer, domain, goldenClaim.token, expired); !errors.Is(err, ErrPermitExpired) {
+		t.Fatalf("signing an expired permit = %v, want ErrPermitExpired", err)
+	}
+	// The deadline itself is still valid
+	if _, err := buildPermitDigest(domain, goldenClaim.token, permit, time.Unix(permit.Deadline.Int64(), 0)); err != nil {
+		t.Fatalf("permit at its deadline = %v", err)
+	}
+
+	otherOwner := permit
+	otherOwner.Owner = goldenClaim.sender
+	if _, err := SignPermit(signer, domain, goldenClaim.token, otherOwner); err == nil {
+		t.Fatal("signed another owner's permit")
+	}
+	noValue := permit
+	noValue.Value = nil
+	if _, err := BuildPermitDigest(domain, goldenClaim.token, noValue); err == nil {
+		t.Fatal("built the digest of a permit without a value")
+	}
+	if _, err := BuildPermitDigest(EIP712Domain{Name: domain.Name}, goldenClaim.token, permit); err == nil {
+		t.Fatal("built the digest under a domain without a chain ID")
+	}
+}
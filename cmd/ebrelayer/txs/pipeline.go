@@ -0,0 +1,104 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PipelineStage is a step an event passes through on its way to an on-chain claim
+type PipelineStage int
+
+const (
+	// StageObserved is when the relayer receives the event
+	StageObserved PipelineStage = iota
+	// StageConfirmed is when the event's block reaches the confirmation depth
+	StageConfirmed
+	// StageSigned is when the event's claim is signed
+	StageSigned
+	// StageSubmitted is when the claim transaction is sent
+	StageSubmitted
+	// StageMined is when the claim transaction is included in a block
+	StageMined
+)
+
+// String implements fmt.Stringer
+func (s PipelineStage) String() string {
+	return [...]string{"observed", "confirmed", "signed", "submitted", "mined"}[s]
+}
+
+// PipelineTimer records when a single event reaches each PipelineStage, observing the time since
+// the previously reached stage in Metrics.StageLatency. A nil *PipelineTimer records nothing. It
+// is safe for concurrent use.
+type PipelineTimer struct {
+	chain  string
+	clock  func() time.Time
+	mu     sync.Mutex
+	stamps map[PipelineStage]time.Time
+	last   PipelineStage
+	marked bool
+}
+
+// NewPipelineTimer initializes a new PipelineTimer for an event emitted on chain, reading the time
+// from clock, or time.Now if clock is nil
+func NewPipelineTimer(chain string, clock func() time.Time) *PipelineTimer {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &PipelineTimer{chain: chain, clock: clock, stamps: make(map[PipelineStage]time.Time)}
+}
+
+// Mark records the event reaching stage now. A stage is only recorded the first time it is marked.
+func (t *PipelineTimer) Mark(stage PipelineStage) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.stamps[stage]; ok {
+		return
+	}
+	now := t.clock()
+	t.stamps[stage] = now
+	if t.marked {
+		getMetrics().stageReached(t.chain, t.last, stage, now.Sub(t.stamps[t.last]))
+	}
+	t.last, t.marked = stage, true
+}
+
+// Elapsed returns the time between the event reaching from and reaching to, if both were marked
+func (t *PipelineTimer) Elapsed(from, to PipelineStage) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	start, ok := t.stamps[from]
+	end, ok2 := t.stamps[to]
+	if !ok || !ok2 {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// markSubmitted marks the first of timer, if given, as submitted, then marks it mined in the
+// background once status finds txHash's receipt
+func markSubmitted(timer []*PipelineTimer, txHash common.Hash,
+	status func(ctx context.Context, txHash common.Hash) (uint64, error)) {
+	if len(timer) == 0 || timer[0] == nil {
+		return
+	}
+	timer[0].Mark(StageSubmitted)
+
+	go func() {
+		err := waitReceipt(context.Background(), txHash, status)
+		if err == nil || errors.Is(err, ErrBatchReverted) {
+			timer[0].Mark(StageMined)
+		}
+	}()
+}
@@ -0,0 +1,88 @@
+package txs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// stepClock is a clock advancing by the next of its steps each time it is read
+type stepClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	steps []time.Duration
+}
+
+func (c *stepClock) read() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.steps) > 0 {
+		c.now = c.now.Add(c.steps[0])
+		c.steps = c.steps[1:]
+	}
+	return c.now
+}
+
+func TestPipelineTimerRecordsStageDurations(t *testing.T) {
+	m, disable := useTestMetrics(t)
+	defer disable()
+	clock := &stepClock{now: time.Unix(1600000000, 0),
+		steps: []time.Duration{0, 12 * time.Second, 300 * time.Millisecond, 2 * time.Second, 15 * time.Second}}
+	timer := NewPipelineTimer("ethereum", clock.read)
+
+	// A mock pipeline: the event is observed, confirmed and signed, then its claim is submitted
+	// and found mined at the first receipt query
+	timer.Mark(StageObserved)
+	timer.Mark(StageConfirmed)
+	timer.Mark(StageSigned)
+	timer.Mark(StageSigned)
+	mined := func(context.Context, common.Hash) (uint64, error) { return ctypes.ReceiptStatusSuccessful, nil }
+	markSubmitted([]*PipelineTimer{timer}, common.HexToHash("0x1"), mined)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := timer.Elapsed(StageSubmitted, StageMined); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("claim never marked mined")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	tests := []struct {
+		from, to PipelineStage
+		want     time.Duration
+	}{
+		{StageObserved, StageConfirmed, 12 * time.Second},
+		// Marking a stage again doesn't move it
+		{StageConfirmed, StageSigned, 300 * time.Millisecond},
+		{StageSigned, StageSubmitted, 2 * time.Second},
+		{StageSubmitted, StageMined, 15 * time.Second},
+		{StageObserved, StageMined, 12*time.Second + 300*time.Millisecond + 17*time.Second},
+	}
+	for _, tt := range tests {
+		if got, ok := timer.Elapsed(tt.from, tt.to); !ok || got != tt.want {
+			t.Fatalf("%s to %s took %s, want %s", tt.from, tt.to, got, tt.want)
+		}
+	}
+	// One latency series per consecutive pair of stages
+	if series := testutil.CollectAndCount(m.StageLatency); series != 4 {
+		t.Fatalf("%d stage latency series, want 4", series)
+	}
+}
+
+func TestNilPipelineTimer(t *testing.T) {
+	var timer *PipelineTimer
+	timer.Mark(StageObserved)
+	if _, ok := timer.Elapsed(StageObserved, StageMined); ok {
+		t.Fatal("a nil timer recorded a duration")
+	}
+	markSubmitted(nil, common.Hash{}, nil)
+}
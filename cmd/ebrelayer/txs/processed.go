@@ -0,0 +1,108 @@
+package txs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	harmonybridge "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/harmonybridge"
+	ethoracle "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/oracle"
+	ethereumbridge "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/harmony/bindings/ethereumbridge"
+	hmyoracle "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/harmony/bindings/oracle"
+)
+
+// ErrClaimAlreadyProcessed is returned in place of submitting a claim which the contracts would
+// reject because its unlock was already processed, or the validator already claimed it
+var ErrClaimAlreadyProcessed = fmt.Errorf("%w: already processed", ErrClaimSkipped)
+
+// UnlockClaimCaller queries whether a bridge contract's unlock claim is still pending
+type UnlockClaimCaller interface {
+	IsUnlockClaimActive(opts *bind.CallOpts, unlockID *big.Int) (bool, error)
+}
+
+// OracleClaimCaller queries whether an Oracle contract already holds a validator's claim
+type OracleClaimCaller interface {
+	HasMadeClaim(opts *bind.CallOpts, unlockID *big.Int, validator common.Address) (bool, error)
+}
+
+// ClaimChecker checks with the contracts whether an oracle claim by Validator would be accepted.
+// The Oracle only accepts claims for unlocks Bridge still has pending, once per validator.
+type ClaimChecker struct {
+	Bridge    UnlockClaimCaller
+	Oracle    OracleClaimCaller
+	Validator common.Address
+}
+
+// ClaimAlreadyProcessed reports whether unlockID's unlock was already completed, by this
+// relayer or others, or Validator already made its claim, so that submitting it would revert
+func (c ClaimChecker) ClaimAlreadyProcessed(ctx context.Context, unlockID *big.Int) (bool, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	active, err := c.Bridge.IsUnlockClaimActive(opts, unlockID)
+	if err != nil {
+		return false, err
+	}
+	if !active {
+		return true, nil
+	}
+	return c.Oracle.HasMadeClaim(opts, unlockID, c.Validator)
+}
+
+// NewEthClaimChecker initializes a ClaimChecker for validator's claims to the Ethereum Oracle at
+// oracleAddress, and the HarmonyBridge it completes unlocks on
+func NewEthClaimChecker(ctx context.Context, caller bind.ContractCaller, oracleAddress,
+	validator common.Address) (ClaimChecker, error) {
+	oracleCaller, err := ethoracle.NewOracleCaller(oracleAddress, caller)
+	if err != nil {
+		return ClaimChecker{}, err
+	}
+	bridgeAddress, err := oracleCaller.HarmonyBridge(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return ClaimChecker{}, err
+	}
+	bridgeCaller, err := harmonybridge.NewHarmonyBridgeCaller(bridgeAddress, caller)
+	if err != nil {
+		return ClaimChecker{}, err
+	}
+	return ClaimChecker{Bridge: bridgeCaller, Oracle: oracleCaller, Validator: validator}, nil
+}
+
+// NewHmyClaimChecker initializes a ClaimChecker for validator's claims to the Harmony Oracle at
+// oracleAddress, and the EthereumBridge it completes unlocks on
+func NewHmyClaimChecker(ctx context.Context, caller bind.ContractCaller, oracleAddress,
+	validator common.Address) (ClaimChecker, error) {
+	oracleCaller, err := hmyoracle.NewOracleCaller(oracleAddress, caller)
+	if err != nil {
+		return ClaimChecker{}, err
+	}
+	bridgeAddress, err := oracleCaller.EthereumBridge(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return ClaimChecker{}, err
+	}
+	bridgeCaller, err := ethereumbridge.NewEthereumBridgeCaller(bridgeAddress, caller)
+	if err != nil {
+		return ClaimChecker{}, err
+	}
+	return ClaimChecker{Bridge: bridgeCaller, Oracle: oracleCaller, Validator: validator}, nil
+}
+
+// checkClaimPending returns ErrClaimAlreadyProcessed if the contracts would reject unlockID's
+// claim. A failed check is logged and lets the claim through, since the contracts reject it anyway.
+func checkClaimPending(ctx context.Context, chain string, unlockID *big.Int,
+	checker func() (ClaimChecker, error)) error {
+	c, err := checker()
+	if err == nil {
+		var processed bool
+		if processed, err = c.ClaimAlreadyProcessed(ctx, unlockID); err == nil && processed {
+			getLogger().Info("Skipping claim already processed on-chain", "chain", chain, "unlockID", unlockID)
+			return fmt.Errorf("%w: unlock ID %v on %s", ErrClaimAlreadyProcessed, unlockID, chain)
+		}
+	}
+	if err != nil {
+		getLogger().Warn("Checking claim status failed, submitting anyway", "chain", chain, "unlockID", unlockID,
+			"err", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// claimStatus is an UnlockClaimCaller and OracleClaimCaller answering from its fields
+type claimStatus struct {
+	active, claimed bool
+	err             error
+	validator       common.Address
+}
+
+func (s claimStatus) IsUnlockClaimActive(_ *bind.CallOpts, _ *big.Int) (bool, error) {
+	return s.active, s.err
+}
+
+func (s claimStatus) HasMadeClaim(_ *bind.CallOpts, _ *big.Int, validator common.Address) (bool, error) {
+	return s.claimed && validator == s.validator, nil
+}
+
+func TestClaimAlreadyProcessed(t *testing.T) {
+	validator := goldenValidator
+	queryFailed := errors.New("execution reverted")
+	tests := []struct {
+		name          string
+		status        claimStatus
+		wantProcessed bool
+		wantErr       error
+	}{
+		{"pending", claimStatus{active: true}, false, nil},
+		{"completed", claimStatus{active: false}, true, nil},
+		{"already claimed", claimStatus{active: true, claimed: true, validator: validator}, true, nil},
+		// Another validator's claim doesn't stop this one's
+		{"claimed by another", claimStatus{active: true, claimed: true, validator: common.HexToAddress("0x1")}, false, nil},
+		{"failed query", claimStatus{err: queryFailed}, false, queryFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := ClaimChecker{Bridge: tt.status, Oracle: tt.status, Validator: validator}
+			processed, err := checker.ClaimAlreadyProcessed(context.Background(), goldenClaim.unlockID)
+			if processed != tt.wantProcessed || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClaimAlreadyProcessed = %v, %v, want %v, %v", processed, err, tt.wantProcessed, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckClaimPending(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	checker := func(status claimStatus) func() (ClaimChecker, error) {
+		return func() (ClaimChecker, error) {
+			return ClaimChecker{Bridge: status, Oracle: status}, nil
+		}
+	}
+
+	if err := checkClaimPending(context.Background(), ethereumChainLabel, goldenClaim.unlockID, checker(claimStatus{active: true})); err != nil {
+		t.Fatalf("checkClaimPending of a pending claim = %v", err)
+	}
+	err := checkClaimPending(context.Background(), ethereumChainLabel, goldenClaim.unlockID, checker(claimStatus{}))
+	if !errors.Is(err, ErrClaimAlreadyProcessed) || !errors.Is(err, ErrClaimSkipped) {
+		t.Fatalf("checkClaimPending of a processed claim = %v, want ErrClaimAlreadyProcessed", err)
+	}
+
+	// A failed check lets the claim through for the contracts to judge
+	if err := checkClaimPending(context.Background(), ethereumChainLabel, goldenClaim.unlockID,
+		checker(claimStatus{err: errors.New("no contract code")})); err != nil {
+		t.Fatalf("checkClaimPending after a failed query = %v", err)
+	}
+	if err := checkClaimPending(context.Background(), ethereumChainLabel, goldenClaim.unlockID, func() (ClaimChecker, error) {
+		return ClaimChecker{}, errors.New("dial failed")
+	}); err != nil {
+		t.Fatalf("checkClaimPending without a checker = %v", err)
+	}
+}
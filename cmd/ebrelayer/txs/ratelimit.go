@@ -0,0 +1,86 @@
+package txs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EthSubmitLimiter, if set, paces claim submissions to Ethereum
+var EthSubmitLimiter *RateLimiter
+
+// HmySubmitLimiter, if set, paces claim submissions to Harmony
+var HmySubmitLimiter *RateLimiter
+
+// RateLimiter is a token bucket refilled at a steady rate up to a burst size. It is safe for
+// concurrent use, and waiters are granted tokens in the order they asked for them.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	mu    sync.Mutex
+	// tokens is the bucket's level at last, negative while waiters hold reservations
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter initializes a new, full RateLimiter granting rate tokens per second, with up to
+// burst available at once
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait blocks until a token is available, returning ctx's error without consuming one if ctx is
+// done first. A nil RateLimiter never blocks.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token, returning how long until the bucket has refilled enough to cover it
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns an abandoned reservation's token
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
@@ -0,0 +1,54 @@
+package txs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterMinimumDuration(t *testing.T) {
+	const (
+		rate  = 200
+		burst = 2
+		n     = 12
+	)
+	// The burst goes out at once, and each later submission waits for its token
+	minimum := time.Duration(n-burst) * time.Second / rate
+
+	limiter := NewRateLimiter(rate, burst)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < minimum {
+		t.Fatalf("%d submissions at %d/s took %s, want at least %s", n, rate, elapsed, minimum)
+	}
+}
+
+func TestRateLimiterWaitContextDone(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait on an empty bucket = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// A nil limiter never blocks
+	var unlimited *RateLimiter
+	if err := unlimited.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,13 +3,14 @@ package txs
 import (
 	"context"
 	"crypto/ecdsa"
-	"log"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	ethereumbridgeregistry "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/bridgeregistry"
+	valset "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/valset"
 	"github.com/mochi-lab/eth-one-bridge/hmyclient"
 )
 
@@ -45,12 +46,12 @@ func EthGetAddressFromBridgeRegistry(privateKey *ecdsa.PrivateKey, client *ethcl
 ) (common.Address, error) {
 	sender, err := LoadSender(privateKey)
 	if err != nil {
-		log.Fatal(err)
+		return common.Address{}, err
 	}
 
 	header, err := client.HeaderByNumber(context.Background(), nil)
 	if err != nil {
-		log.Fatal(err)
+		return common.Address{}, err
 	}
 
 	// Set up CallOpts auth
@@ -64,7 +65,7 @@ func EthGetAddressFromBridgeRegistry(privateKey *ecdsa.PrivateKey, client *ethcl
 	// Initialize BridgeRegistry instance
 	registryInstance, err := ethereumbridgeregistry.NewBridgeRegistry(registry, client)
 	if err != nil {
-		log.Fatal(err)
+		return common.Address{}, err
 	}
 
 	var address common.Address
@@ -82,7 +83,7 @@ func EthGetAddressFromBridgeRegistry(privateKey *ecdsa.PrivateKey, client *ethcl
 	}
 
 	if err != nil {
-		log.Fatal(err)
+		return common.Address{}, err
 	}
 
 	return address, nil
@@ -135,3 +136,28 @@ func HmyGetAddressFromBridgeRegistry(privateKey *ecdsa.PrivateKey, client *hmycl
 
 	return address, nil
 }
+
+// EthActiveValidator returns the first of candidates registered as an active validator by the
+// Ethereum Valset contract, or ErrNoMatchingSigner if none is
+func EthActiveValidator(privateKey *ecdsa.PrivateKey, client *ethclient.Client, registry common.Address,
+	candidates []common.Address) (common.Address, error) {
+	valsetAddress, err := EthGetAddressFromBridgeRegistry(privateKey, client, registry, Valset)
+	if err != nil {
+		return common.Address{}, err
+	}
+	valsetInstance, err := valset.NewValsetCaller(valsetAddress, client)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	for _, candidate := range candidates {
+		active, err := valsetInstance.IsActiveValidator(&bind.CallOpts{Context: context.Background()}, candidate)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if active {
+			return candidate, nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("%w: none of %d keys is an active validator", ErrNoMatchingSigner, len(candidates))
+}
@@ -3,12 +3,13 @@ package txs
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	harmonybridge "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/harmonybridge"
@@ -23,83 +24,190 @@ const (
 
 // RelayUnlockClaimToEthereum relays the provided UnlockClaim to HarmonyBridge contract on the Ethereum network
 func RelayUnlockClaimToEthereum(ethereumProvider string, ethereumBridgeRegistry common.Address, event types.Event,
-	claim EthUnlockClaim, privateKey *ecdsa.PrivateKey) error {
+	claim EthUnlockClaim, privateKey *ecdsa.PrivateKey, timer ...*PipelineTimer) error {
+	if DryRun {
+		return dryRunUnlockClaim(ethereumChainLabel, claim)
+	}
+
+	if err := checkSigningHalted(); err != nil {
+		return err
+	}
+	if err := EthSubmitLimiter.Wait(context.Background()); err != nil {
+		return err
+	}
+	defer lockSubmission(ethereumProvider, privateKey)()
+
 	// Initialize client service, validator's tx auth, and target contract address
-	client, auth, target := EthInitRelayConfig(ethereumProvider, ethereumBridgeRegistry, event, privateKey)
+	client, auth, target, err := EthInitRelayConfig(ethereumProvider, ethereumBridgeRegistry, event, privateKey)
+	if err != nil {
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	if err := EthGasBalance.Check(context.Background(), client, auth.From); err != nil {
+		return getMetrics().claimError(GasBalanceErrorReason, err)
+	}
 
 	// Initialize HarmonyBridge instance
 	fmt.Println("\nFetching HarmonyBridge contract...")
 	harmonyBridgeInstance, err := harmonybridge.NewHarmonyBridge(target, client)
 	if err != nil {
-		log.Fatal(err)
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	queued := ethUnlockSubmission(ethereumBridgeRegistry, claim)
+	if err := enqueueSubmission(queued); err != nil {
+		return err
+	}
+
+	if err := EthSubmitBreaker.Allow(); err != nil {
+		return getMetrics().claimError(CircuitOpenErrorReason, err)
+	}
+	settleNonce, err := assignNonce(ethereumProvider, client, auth)
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
 
 	// Send transaction
 	fmt.Println("Sending new UnlockClaim to HarmonyBridge...")
-	tx, err := harmonyBridgeInstance.NewUnlockClaim(auth,
-		claim.HarmonySender, claim.EthereumReceiver, claim.Token, claim.Amount)
+	var sent *ctypes.Transaction
+	err = Retry(context.Background(), func() error {
+		tx, err := harmonyBridgeInstance.NewUnlockClaim(auth,
+			claim.HarmonySender, claim.EthereumReceiver, claim.Token, claim.Amount)
+		if err != nil {
+			return err
+		}
+		sent = tx
+		return nil
+	}, DefaultRetryPolicy)
+	settleNonce(err)
 	if err != nil {
-		log.Fatal(err)
+		EthSubmitBreaker.Record(err)
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
-	fmt.Println("NewUnlockClaim tx hash:", tx.Hash().Hex())
+	fmt.Println("NewUnlockClaim tx hash:", sent.Hash().Hex())
+	getMetrics().claimSubmitted(ethereumChainLabel)
+	markSubmitted(timer, sent.Hash(), ethReceiptStatus(client))
+	watchReverts(EthSubmitBreaker, sent.Hash(), ethReceiptStatus(client))
+	watchSubmission(queued, sent.Hash(), ethReceiptStatus(client))
+	watchEthTx(client, auth, sent)
 
 	return nil
 }
 
 // RelayOracleClaimToEthereum relays the provided OracleClaim to Oracle contract on the Ethereum network
 func RelayOracleClaimToEthereum(provider string, contractAddress common.Address, event types.Event,
-	claim EthOracleClaim, privateKey *ecdsa.PrivateKey) error {
+	claim EthOracleClaim, privateKey *ecdsa.PrivateKey, timer ...*PipelineTimer) error {
+	if err := checkSigningHalted(); err != nil {
+		return err
+	}
+	if err := EthSubmitLimiter.Wait(context.Background()); err != nil {
+		return err
+	}
+	defer lockSubmission(provider, privateKey)()
+
 	// Initialize client service, validator's tx auth, and target contract address
-	client, auth, target := EthInitRelayConfig(provider, contractAddress, event, privateKey)
+	client, auth, target, err := EthInitRelayConfig(provider, contractAddress, event, privateKey)
+	if err != nil {
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	if err := EthGasBalance.Check(context.Background(), client, auth.From); err != nil {
+		return getMetrics().claimError(GasBalanceErrorReason, err)
+	}
 
 	// Initialize Oracle instance
 	fmt.Println("\nFetching Oracle contract...")
 	oracleInstance, err := oracle.NewOracle(target, client)
 	if err != nil {
-		log.Fatal(err)
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	// Skip claims the contracts would reject, having already processed them
+	err = checkClaimPending(context.Background(), ethereumChainLabel, claim.UnlockID, func() (ClaimChecker, error) {
+		return NewEthClaimChecker(context.Background(), client, target, auth.From)
+	})
+	queued := oracleSubmission(ethereumChainLabel, contractAddress, claim.UnlockID, claim.Message, claim.Signature)
+	if errors.Is(err, ErrClaimAlreadyProcessed) {
+		confirmSubmission(queued)
+	}
+	if err != nil {
+		return err
+	}
+	if err := enqueueSubmission(queued); err != nil {
+		return err
+	}
+
+	if err := EthSubmitBreaker.Allow(); err != nil {
+		return getMetrics().claimError(CircuitOpenErrorReason, err)
+	}
+	settleNonce, err := assignNonce(provider, client, auth)
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
 
 	// Send transaction
 	fmt.Println("Sending new OracleClaim to Oracle...")
-	tx, err := oracleInstance.NewOracleClaim(auth, claim.UnlockID, claim.Message, claim.Signature)
+	var sent *ctypes.Transaction
+	err = Retry(context.Background(), func() error {
+		tx, err := oracleInstance.NewOracleClaim(auth, claim.UnlockID, claim.Message, claim.Signature)
+		if err != nil {
+			return err
+		}
+		sent = tx
+		return nil
+	}, DefaultRetryPolicy)
+	settleNonce(err)
 	if err != nil {
-		log.Fatal(err)
+		EthSubmitBreaker.Record(err)
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
-	fmt.Println("NewOracleClaim tx hash:", tx.Hash().Hex())
+	fmt.Println("NewOracleClaim tx hash:", sent.Hash().Hex())
+	getMetrics().claimSubmitted(ethereumChainLabel)
+	markSubmitted(timer, sent.Hash(), ethReceiptStatus(client))
+	watchReverts(EthSubmitBreaker, sent.Hash(), ethReceiptStatus(client))
+	watchSubmission(queued, sent.Hash(), ethReceiptStatus(client))
+	watchEthTx(client, auth, sent)
 	return nil
 }
 
-// EthInitRelayConfig set up Ethereum client, validator's transaction auth, and the target contract's address
+// EthInitRelayConfig set up Ethereum client, validator's transaction auth, and the target contract's address.
+// The auth's nonce is left for assignNonce to set right before the transaction is broadcast.
 func EthInitRelayConfig(provider string, registry common.Address, event types.Event, privateKey *ecdsa.PrivateKey,
-) (*ethclient.Client, *bind.TransactOpts, common.Address) {
+) (*EthClient, *bind.TransactOpts, common.Address, error) {
 	// Start Ethereum client
-	client, err := ethclient.Dial(provider)
+	var client *EthClient
+	err := DialEndpoint(context.Background(), provider, func(ctx context.Context, rawurl string) error {
+		dialed, err := ethclient.DialContext(ctx, rawurl)
+		if err != nil {
+			return err
+		}
+		client = NewEthClient(dialed)
+		return nil
+	})
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
 
 	// Load the validator's address
-	sender, err := LoadSender(privateKey)
-	if err != nil {
-		log.Fatal(err)
+	if _, err := LoadSender(privateKey); err != nil {
+		return nil, nil, common.Address{}, err
 	}
 
-	nonce, err := client.PendingNonceAt(context.Background(), sender)
+	fees, err := EthGasStrategy.Fees(context.Background(), client)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
 
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	chainID, err := ethTransactionChainID(context.Background(), client)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
 
-	// Set up TransactOpts auth's tx signature authorization
-	transactOptsAuth := bind.NewKeyedTransactor(privateKey)
-	transactOptsAuth.Nonce = big.NewInt(int64(nonce))
+	// Set up TransactOpts auth's tx signature authorization, bound to the chain ID per EIP-155
+	transactOptsAuth := NewTransactOpts(NewKeySigner(privateKey), chainID)
 	transactOptsAuth.Value = big.NewInt(0) // in wei
 	transactOptsAuth.GasLimit = GasLimit
-	transactOptsAuth.GasPrice = gasPrice
+	fees.Apply(transactOptsAuth)
 
 	var targetContract ContractRegistry
 	switch event {
@@ -114,9 +222,9 @@ func EthInitRelayConfig(provider string, registry common.Address, event types.Ev
 	}
 
 	// Get the specific contract's address
-	target, err := EthGetAddressFromBridgeRegistry(privateKey, client, registry, targetContract)
+	target, err := EthGetAddressFromBridgeRegistry(privateKey, client.Client, registry, targetContract)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
-	return client, transactOptsAuth, target
+	return client, transactOptsAuth, target, nil
 }
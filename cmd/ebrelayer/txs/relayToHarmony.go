@@ -3,8 +3,8 @@ package txs
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -17,85 +17,181 @@ import (
 
 // RelayUnlockClaimToHarmony relays the provided UnlockClaim to EthereumBridge contract on the Ethereum network
 func RelayUnlockClaimToHarmony(harmonyProvider string, ethereumBridgeRegistry common.Address, event types.Event,
-	claim HmyUnlockClaim, privateKey *ecdsa.PrivateKey) error {
+	claim HmyUnlockClaim, privateKey *ecdsa.PrivateKey, timer ...*PipelineTimer) error {
+	if DryRun {
+		return dryRunUnlockClaim(harmonyChainLabel, claim)
+	}
+
+	if err := checkSigningHalted(); err != nil {
+		return err
+	}
+	if err := HmySubmitLimiter.Wait(context.Background()); err != nil {
+		return err
+	}
+	defer lockSubmission(harmonyProvider, privateKey)()
+
 	// Initialize client service, validator's tx auth, and target contract address
-	client, auth, target := HmyInitRelayConfig(harmonyProvider, ethereumBridgeRegistry, event, privateKey)
+	client, auth, target, err := HmyInitRelayConfig(harmonyProvider, ethereumBridgeRegistry, event, privateKey)
+	if err != nil {
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	if err := HmyGasBalance.Check(context.Background(), client, auth.From); err != nil {
+		return getMetrics().claimError(GasBalanceErrorReason, err)
+	}
 
 	// Initialize EthereumBridge instance
 	fmt.Println("\nFetching EthereumBridge contract...")
 	ethereumBridgeInstance, err := ethereumbridge.NewEthereumBridge(target, client)
 	if err != nil {
-		log.Fatal(err)
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	queued := hmyUnlockSubmission(ethereumBridgeRegistry, claim)
+	if err := enqueueSubmission(queued); err != nil {
+		return err
+	}
+
+	if err := HmySubmitBreaker.Allow(); err != nil {
+		return getMetrics().claimError(CircuitOpenErrorReason, err)
+	}
+	settleNonce, err := assignNonce(harmonyProvider, client, auth)
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
 
 	// Send transaction
 	fmt.Println("Sending new UnlockClaim to EthereumBridge...")
-	tx, err := ethereumBridgeInstance.NewUnlockClaim(auth,
-		claim.EthereumSender, claim.HarmonyReceiver, claim.Token, claim.Amount)
+	var txHash common.Hash
+	err = Retry(context.Background(), func() error {
+		tx, err := ethereumBridgeInstance.NewUnlockClaim(auth,
+			claim.EthereumSender, claim.HarmonyReceiver, claim.Token, claim.Amount)
+		if err != nil {
+			return err
+		}
+		txHash = client.TxHash(tx)
+		return nil
+	}, DefaultRetryPolicy)
+	settleNonce(err)
 	if err != nil {
-		log.Fatal(err)
+		HmySubmitBreaker.Record(err)
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
-	fmt.Println("NewUnlockClaim tx hash:", tx.Hash().Hex())
+	fmt.Println("NewUnlockClaim tx hash:", txHash.Hex())
+	getMetrics().claimSubmitted(harmonyChainLabel)
+	markSubmitted(timer, txHash, hmyReceiptStatus(client.Client))
+	watchReverts(HmySubmitBreaker, txHash, hmyReceiptStatus(client.Client))
+	watchSubmission(queued, txHash, hmyReceiptStatus(client.Client))
 	return nil
 }
 
 // RelayOracleClaimToHarmony relays the provided OracleClaim to Oracle contract on the Ethereum network
 func RelayOracleClaimToHarmony(provider string, contractAddress common.Address, event types.Event,
-	claim HmyOracleClaim, privateKey *ecdsa.PrivateKey) error {
+	claim HmyOracleClaim, privateKey *ecdsa.PrivateKey, timer ...*PipelineTimer) error {
+	if err := checkSigningHalted(); err != nil {
+		return err
+	}
+	if err := HmySubmitLimiter.Wait(context.Background()); err != nil {
+		return err
+	}
+	defer lockSubmission(provider, privateKey)()
+
 	// Initialize client service, validator's tx auth, and target contract address
-	client, auth, target := HmyInitRelayConfig(provider, contractAddress, event, privateKey)
+	client, auth, target, err := HmyInitRelayConfig(provider, contractAddress, event, privateKey)
+	if err != nil {
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	if err := HmyGasBalance.Check(context.Background(), client, auth.From); err != nil {
+		return getMetrics().claimError(GasBalanceErrorReason, err)
+	}
 
 	// Initialize Oracle instance
 	fmt.Println("\nFetching Oracle contract...")
 	oracleInstance, err := oracle.NewOracle(target, client)
 	if err != nil {
-		log.Fatal(err)
+		return getMetrics().claimError(ConfigErrorReason, err)
+	}
+
+	// Skip claims the contracts would reject, having already processed them
+	err = checkClaimPending(context.Background(), harmonyChainLabel, claim.UnlockID, func() (ClaimChecker, error) {
+		return NewHmyClaimChecker(context.Background(), client, target, auth.From)
+	})
+	queued := oracleSubmission(harmonyChainLabel, contractAddress, claim.UnlockID, claim.Message, claim.Signature)
+	if errors.Is(err, ErrClaimAlreadyProcessed) {
+		confirmSubmission(queued)
+	}
+	if err != nil {
+		return err
+	}
+	if err := enqueueSubmission(queued); err != nil {
+		return err
+	}
+
+	if err := HmySubmitBreaker.Allow(); err != nil {
+		return getMetrics().claimError(CircuitOpenErrorReason, err)
+	}
+	settleNonce, err := assignNonce(provider, client, auth)
+	if err != nil {
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
 
 	// Send transaction
 	fmt.Println("Sending new OracleClaim to Oracle...")
-	tx, err := oracleInstance.NewOracleClaim(auth, claim.UnlockID, claim.Message, claim.Signature)
+	var txHash common.Hash
+	err = Retry(context.Background(), func() error {
+		tx, err := oracleInstance.NewOracleClaim(auth, claim.UnlockID, claim.Message, claim.Signature)
+		if err != nil {
+			return err
+		}
+		txHash = client.TxHash(tx)
+		return nil
+	}, DefaultRetryPolicy)
+	settleNonce(err)
 	if err != nil {
-		log.Fatal(err)
+		HmySubmitBreaker.Record(err)
+		return getMetrics().claimError(SubmitErrorReason, err)
 	}
-	fmt.Println("NewOracleClaim tx hash:", tx.Hash().Hex())
+	fmt.Println("NewOracleClaim tx hash:", txHash.Hex())
+	getMetrics().claimSubmitted(harmonyChainLabel)
+	markSubmitted(timer, txHash, hmyReceiptStatus(client.Client))
+	watchReverts(HmySubmitBreaker, txHash, hmyReceiptStatus(client.Client))
+	watchSubmission(queued, txHash, hmyReceiptStatus(client.Client))
 	return nil
 }
 
-// HmyInitRelayConfig set up Ethereum client, validator's transaction auth, and the target contract's address
+// HmyInitRelayConfig set up Harmony client for HmyShardID, validator's transaction auth, and the target contract's address.
+// The auth's nonce is left for assignNonce to set right before the transaction is broadcast.
 func HmyInitRelayConfig(provider string, registry common.Address, event types.Event, privateKey *ecdsa.PrivateKey,
-) (*hmyclient.Client, *bind.TransactOpts, common.Address) {
-	// Start Ethereum client
-	client, err := hmyclient.Dial(provider)
+) (*HmyShardClient, *bind.TransactOpts, common.Address, error) {
+	// Start Harmony client
+	var client *hmyclient.Client
+	err := DialEndpoint(context.Background(), provider, func(ctx context.Context, rawurl string) (err error) {
+		client, err = hmyclient.DialContext(ctx, rawurl)
+		return err
+	})
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
 
 	// Load the validator's address
-	sender, err := LoadSender(privateKey)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	nonce, err := client.PendingNonceAt(context.Background(), sender)
-	if err != nil {
-		log.Fatal(err)
+	if _, err := LoadSender(privateKey); err != nil {
+		return nil, nil, common.Address{}, err
 	}
 
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	fees, err := HmyGasStrategy.Fees(context.Background(), client)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
 
 	// Set up TransactOpts auth's tx signature authorization
-	transactOptsAuth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(2))
+	transactOptsAuth, err := bind.NewKeyedTransactorWithChainID(privateKey, HmyChainID)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
-	transactOptsAuth.Nonce = big.NewInt(int64(nonce))
 	transactOptsAuth.Value = big.NewInt(0) // in wei
 	transactOptsAuth.GasLimit = GasLimit
-	transactOptsAuth.GasPrice = gasPrice
+	fees.Apply(transactOptsAuth)
 
 	var targetContract ContractRegistry
 	switch event {
@@ -112,7 +208,8 @@ func HmyInitRelayConfig(provider string, registry common.Address, event types.Ev
 	// Get the specific contract's address
 	target, err := HmyGetAddressFromBridgeRegistry(privateKey, client, registry, targetContract)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, common.Address{}, err
 	}
-	return client, transactOptsAuth, target
+	// Sent transactions are re-signed for Harmony's chain ID and shard
+	return NewHmyShardClient(client, NewHmySigner(HmyChainID), HmyShardID, privateKey), transactOptsAuth, target, nil
 }
@@ -0,0 +1,137 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	htypes "github.com/harmony-one/harmony/core/types"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrReplayMismatch is returned when a replayed log's claim message or signer differs from the
+// one recorded for it
+var ErrReplayMismatch = errors.New("replayed claim doesn't match its recording")
+
+// ReplayFixture is a recorded UnlockClaim log of Chain, as eth_getLogs returns it, with the claim
+// message and signature the relayer produced for it. Replaying it through parsing and message
+// generation anchors the claim encoding to observed on-chain data.
+type ReplayFixture struct {
+	Name  string          `json:"name"`
+	Chain string          `json:"chain"`
+	Log   json.RawMessage `json:"log"`
+	// Target is the contract verifying the claim, for SigningSchemes bound to it
+	Target    *common.Address `json:"target,omitempty"`
+	Message   hexutil.Bytes   `json:"message"`
+	Signature hexutil.Bytes   `json:"signature,omitempty"`
+	Signer    *common.Address `json:"signer,omitempty"`
+}
+
+// LoadReplayFixtures reads the fixtures of path: a JSON array of ReplayFixture, or a directory of
+// such files, read in name order
+func LoadReplayFixtures(path string) ([]ReplayFixture, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var fixtures []ReplayFixture
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var loaded []ReplayFixture
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for i := range loaded {
+			if loaded[i].Name == "" {
+				loaded[i].Name = fmt.Sprintf("%s#%d", filepath.Base(file), i)
+			}
+		}
+		fixtures = append(fixtures, loaded...)
+	}
+	return fixtures, nil
+}
+
+// ReplayResult is what replaying a fixture produced
+type ReplayResult struct {
+	Event   types.ClaimEvent
+	Message []byte
+	// Signer is who the recorded signature recovers to over the replayed message, if one was recorded
+	Signer common.Address
+}
+
+// Replay decodes the fixture's log as its chain's UnlockClaim, generates its claim message and
+// recovers the recorded signature's signer over it, returning ErrReplayMismatch if the message or
+// signer differs from the recorded one. The claim layout options, such as the claim chain IDs and
+// token decimals, must be configured as they were when the values were recorded.
+func (f ReplayFixture) Replay() (ReplayResult, error) {
+	result := ReplayResult{}
+	var err error
+	switch f.Chain {
+	case ethereumChainLabel:
+		var log ctypes.Log
+		if err := json.Unmarshal(f.Log, &log); err != nil {
+			return result, fmt.Errorf("%s: invalid log: %w", f.Name, err)
+		}
+		result.Event, err = ParseEthUnlockClaim(log)
+	case harmonyChainLabel:
+		var log htypes.Log
+		if err := json.Unmarshal(f.Log, &log); err != nil {
+			return result, fmt.Errorf("%s: invalid log: %w", f.Name, err)
+		}
+		result.Event, err = ParseHmyUnlockClaim(log)
+	default:
+		return result, fmt.Errorf("%s: unknown chain %q, expected %s or %s", f.Name, f.Chain, ethereumChainLabel,
+			harmonyChainLabel)
+	}
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	var target []common.Address
+	if f.Target != nil {
+		target = []common.Address{*f.Target}
+	}
+	if result.Message, err = ClaimMessage(result.Event, target...); err != nil {
+		return result, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if !bytes.Equal(result.Message, f.Message) {
+		return result, fmt.Errorf("%w: %s message is %s, recorded %s", ErrReplayMismatch, f.Name,
+			hexutil.Encode(result.Message), hexutil.Encode(f.Message))
+	}
+
+	if len(f.Signature) == 0 {
+		return result, nil
+	}
+	digest, err := claimDigest(result.Message, target)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if result.Signer, err = RecoverSigner(digest, f.Signature); err != nil {
+		return result, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if f.Signer != nil && result.Signer != *f.Signer {
+		return result, fmt.Errorf("%w: %s signature recovers to %s, recorded %s", ErrReplayMismatch, f.Name,
+			result.Signer.Hex(), f.Signer.Hex())
+	}
+	return result, nil
+}
@@ -0,0 +1,157 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RetryPolicy configures the attempts and exponential backoff used by Retry
+type RetryPolicy struct {
+	// MaxAttempts is the total number of calls made, including the first
+	MaxAttempts int
+	// BaseDelay is the wait after the first failure, doubled after each subsequent one
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts
+	MaxDelay time.Duration
+	// Jitter randomizes each wait by up to this fraction in either direction, from 0 to 1
+	Jitter float64
+	// IsRetryable classifies errors, defaulting to IsRetryableError
+	IsRetryable func(error) bool
+}
+
+// DefaultRetryPolicy is used for RPC calls and claim submission
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+	Jitter:      0.2,
+}
+
+// fatalErrorMessages are node errors which resubmitting the same request can never fix
+var fatalErrorMessages = []string{
+	"nonce too low",
+	"replacement transaction underpriced",
+	"insufficient funds",
+	"intrinsic gas too low",
+	"execution reverted",
+}
+
+// knownTransactionMessages are node errors for a transaction the node already holds, such as a
+// retried broadcast whose first attempt timed out after reaching the node
+var knownTransactionMessages = []string{
+	"already known",
+	"known transaction",
+}
+
+// IsKnownTransactionError reports whether err is a node refusing a transaction it already holds
+func IsKnownTransactionError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, known := range knownTransactionMessages {
+		if strings.Contains(message, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// sentIfKnown returns nil in place of a broadcast's err if the transaction with txHash reached the
+// node after all: the node reports it already known, or refuses its nonce as too low while lookup
+// still finds it, mined from an earlier attempt. Otherwise err is returned.
+func sentIfKnown(ctx context.Context, err error, txHash common.Hash,
+	lookup func(ctx context.Context, txHash common.Hash) error) error {
+	if err == nil {
+		return nil
+	}
+	if !IsKnownTransactionError(err) {
+		if !strings.Contains(strings.ToLower(err.Error()), "nonce too low") || lookup(ctx, txHash) != nil {
+			return err
+		}
+	}
+	getLogger().Info("Transaction already reached the node, treating it as sent", "tx", txHash.Hex(), "err", err)
+	return nil
+}
+
+// permanentError marks an error as not worth retrying
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry returns it immediately
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsRetryableError reports whether err is likely transient, such as a timeout or rate limit,
+// rather than a rejection which would recur on every attempt
+func IsRetryableError(err error) bool {
+	var permanent permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, fatal := range fatalErrorMessages {
+		if strings.Contains(message, fatal) {
+			return false
+		}
+	}
+	return true
+}
+
+// Retry calls fn until it succeeds, returns a non-retryable error, exhausts the policy's
+// attempts or ctx is done, returning fn's last error
+func Retry(ctx context.Context, fn func() error, policy RetryPolicy) error {
+	isRetryable := policy.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsRetryableError
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(policy.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Delay returns the jittered backoff to wait after the given failed attempt
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + p.Jitter*(2*rand.Float64()-1)))
+	}
+	return delay
+}
@@ -0,0 +1,103 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// testRetryPolicy retries quickly, so tests don't sleep through real backoff
+var testRetryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+func TestRetryFlakySucceedsOnThirdTry(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("429 Too Many Requests")
+		}
+		return nil
+	}, testRetryPolicy)
+	if err != nil {
+		t.Fatalf("Retry = %v, want success", err)
+	}
+	if calls != 3 {
+		t.Fatalf("called %d times, want 3", calls)
+	}
+}
+
+func TestRetryNonRetryableReturnsImmediately(t *testing.T) {
+	for _, fatal := range []error{
+		errors.New("nonce too low"),
+		errors.New("execution reverted: claim already processed"),
+		Permanent(errors.New("bad claim")),
+		context.Canceled,
+	} {
+		calls := 0
+		err := Retry(context.Background(), func() error {
+			calls++
+			return fatal
+		}, testRetryPolicy)
+		if !errors.Is(err, fatal) && err.Error() != fatal.Error() {
+			t.Fatalf("Retry = %v, want %v", err, fatal)
+		}
+		if calls != 1 {
+			t.Fatalf("%v: called %d times, want 1", fatal, calls)
+		}
+	}
+}
+
+func TestRetryExhaustsAttempts(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), func() error {
+		calls++
+		return errors.New("i/o timeout")
+	}, testRetryPolicy)
+	if err == nil || calls != testRetryPolicy.MaxAttempts {
+		t.Fatalf("Retry = %v after %d calls, want the last error after %d", err, calls, testRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestRetryStopsOnContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Retry(ctx, func() error {
+		calls++
+		cancel()
+		return errors.New("i/o timeout")
+	}, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour})
+	if err == nil || calls != 1 {
+		t.Fatalf("Retry = %v after %d calls, want to stop after the first", err, calls)
+	}
+}
+
+func TestSentIfKnown(t *testing.T) {
+	txHash := common.HexToHash("0x1")
+	found := func(context.Context, common.Hash) error { return nil }
+	notFound := func(context.Context, common.Hash) error { return errors.New("not found") }
+
+	tests := []struct {
+		name   string
+		err    error
+		lookup func(context.Context, common.Hash) error
+		sent   bool
+	}{
+		{"sent", nil, notFound, true},
+		{"already known", errors.New("already known"), notFound, true},
+		{"known transaction", errors.New("known transaction: 0xabc"), notFound, true},
+		{"nonce too low, mined", errors.New("nonce too low"), found, true},
+		{"nonce too low, another transaction", errors.New("nonce too low"), notFound, false},
+		{"other error", errors.New("insufficient funds for gas * price + value"), found, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sentIfKnown(context.Background(), tt.err, txHash, tt.lookup)
+			if sent := err == nil; sent != tt.sent {
+				t.Fatalf("sentIfKnown(%v) = %v, want sent %v", tt.err, err, tt.sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrClaimSkipped is returned in place of signing a claim which fails ClaimSanityChecks
+var ErrClaimSkipped = errors.New("claim skipped")
+
+const (
+	// ZeroAmountSkipReason is why a claim for a zero amount is skipped
+	ZeroAmountSkipReason = "zero amount"
+	// SelfTransferSkipReason is why a claim whose sender is its recipient is skipped
+	SelfTransferSkipReason = "sender is the recipient"
+)
+
+// ClaimSanityChecks selects the checks skipping claims which are almost always malformed or spam
+var ClaimSanityChecks SanityChecks
+
+// SanityChecks selects the sanity checks a claim must pass to be signed
+type SanityChecks struct {
+	// SkipZeroAmount skips claims for a zero amount
+	SkipZeroAmount bool
+	// SkipSelfTransfer skips claims whose sender on the source chain is the recipient
+	SkipSelfTransfer bool
+}
+
+// SkipReason returns why a claim fails the checks, or "" if it passes
+func (c SanityChecks) SkipReason(event types.ClaimEvent) string {
+	_, sender, recipient, _, amount := event.ClaimFields()
+	switch {
+	case c.SkipZeroAmount && (amount == nil || amount.Sign() == 0):
+		return ZeroAmountSkipReason
+	case c.SkipSelfTransfer && sender == recipient:
+		return SelfTransferSkipReason
+	}
+	return ""
+}
+
+// checkClaimSanity logs and returns ErrClaimSkipped if the event fails ClaimSanityChecks
+func checkClaimSanity(event types.ClaimEvent) error {
+	reason := ClaimSanityChecks.SkipReason(event)
+	if reason == "" {
+		return nil
+	}
+	unlockID, _, _, token, amount := event.ClaimFields()
+	getLogger().Info("Skipping claim", "unlockID", unlockID, "amount", FormatTokenAmount(token, amount),
+		"reason", reason)
+	return fmt.Errorf("%w: %s", ErrClaimSkipped, reason)
+}
@@ -0,0 +1,55 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+func TestClaimSanityChecksSkip(t *testing.T) {
+	defer func(checks SanityChecks) { ClaimSanityChecks = checks }(ClaimSanityChecks)
+	ClaimSanityChecks = SanityChecks{SkipZeroAmount: true, SkipSelfTransfer: true}
+	recorder, restore := useRecordingLogger()
+	defer restore()
+
+	zeroAmount := goldenEthEvent()
+	zeroAmount.Amount = big.NewInt(0)
+	selfTransfer := goldenEthEvent()
+	selfTransfer.EthereumReceiver = selfTransfer.HarmonySender
+
+	tests := []struct {
+		name   string
+		event  types.EthLogNewUnlockClaimEvent
+		reason string
+	}{
+		{"zero amount", zeroAmount, ZeroAmountSkipReason},
+		{"self-transfer", selfTransfer, SelfTransferSkipReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.entries = nil
+			signer := &countingSigner{Signer: NewKeySigner(testKey(t))}
+			if _, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{tt.event}); !errors.Is(err, ErrClaimSkipped) {
+				t.Fatalf("signing a %s claim = %v, want ErrClaimSkipped", tt.name, err)
+			}
+			if signer.signed != 0 {
+				t.Fatalf("signer invoked for a %s claim", tt.name)
+			}
+			if len(recorder.entries) != 1 || recorder.entries[0].value("reason") != tt.reason {
+				t.Fatalf("logged %+v, want the skip reason %q", recorder.entries, tt.reason)
+			}
+		})
+	}
+
+	if _, err := SignClaimsBatch(NewKeySigner(testKey(t)), []types.EthLogNewUnlockClaimEvent{goldenEthEvent()}); err != nil {
+		t.Fatalf("signing a sane claim = %v", err)
+	}
+
+	// With the checks off, both are signed
+	ClaimSanityChecks = SanityChecks{}
+	if _, err := SignClaimsBatch(NewKeySigner(testKey(t)), []types.EthLogNewUnlockClaimEvent{zeroAmount, selfTransfer}); err != nil {
+		t.Fatalf("signing unchecked claims = %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInexactScale is returned when an amount divided by its token's scale factor would leave a
+// remainder, rather than truncating it
+var ErrInexactScale = errors.New("amount is not a multiple of its scale factor")
+
+// AmountScales, if set, scales the amount of each claim for a token it lists by a fixed factor
+// before the amount is packed, after any rescaling by Tokens, for deployments which hash amounts
+// at a fixed scale such as 1e18 whatever the token
+var AmountScales *AmountScaleRegistry
+
+// AmountScale is a fixed factor a claim amount is scaled by: multiplied by Factor, or divided by
+// it exactly if Divide is set
+type AmountScale struct {
+	Factor *big.Int
+	Divide bool
+}
+
+// Apply scales amount, returning ErrInexactScale if dividing it would leave a remainder
+func (s AmountScale) Apply(amount *big.Int) (*big.Int, error) {
+	if amount == nil {
+		return nil, nil
+	}
+	if !s.Divide {
+		return new(big.Int).Mul(amount, s.Factor), nil
+	}
+	quotient, remainder := new(big.Int).QuoRem(amount, s.Factor, new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, fmt.Errorf("%w: %v divided by %v", ErrInexactScale, amount, s.Factor)
+	}
+	return quotient, nil
+}
+
+// String returns the scale as ParseAmountScale reads it
+func (s AmountScale) String() string {
+	if s.Divide {
+		return "/" + s.Factor.String()
+	}
+	return "*" + s.Factor.String()
+}
+
+// AmountScaleRegistry maps tokens to the scale their claim amounts are packed at. It is safe for
+// concurrent use.
+type AmountScaleRegistry struct {
+	mu     sync.RWMutex
+	scales map[common.Address]AmountScale
+}
+
+// NewAmountScaleRegistry initializes a new, empty AmountScaleRegistry
+func NewAmountScaleRegistry() *AmountScaleRegistry {
+	return &AmountScaleRegistry{scales: make(map[common.Address]AmountScale)}
+}
+
+// Register records the scale of token's claim amounts
+func (r *AmountScaleRegistry) Register(token common.Address, scale AmountScale) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scales[token] = scale
+}
+
+// Scale returns token's registered scale, if any
+func (r *AmountScaleRegistry) Scale(token common.Address) (AmountScale, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scale, ok := r.scales[token]
+	return scale, ok
+}
+
+// ScaleAmount scales amount by token's registered scale. The amount of an unregistered token is
+// returned unchanged.
+func (r *AmountScaleRegistry) ScaleAmount(token common.Address, amount *big.Int) (*big.Int, error) {
+	scale, ok := r.Scale(token)
+	if !ok {
+		return amount, nil
+	}
+	scaled, err := scale.Apply(amount)
+	if err != nil {
+		return nil, fmt.Errorf("scaling %s: %w", token.Hex(), err)
+	}
+	return scaled, nil
+}
+
+// ParseAmountScale parses a token's amount scale given as "address=*factor" to multiply amounts by
+// factor, or "address=/factor" to divide them. The factor is a positive integer, or a power of ten
+// written as 1eN.
+func ParseAmountScale(value string) (common.Address, AmountScale, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) || len(parts[1]) < 2 ||
+		(parts[1][0] != '*' && parts[1][0] != '/') {
+		return common.Address{}, AmountScale{}, fmt.Errorf("invalid amount scale %q: expected address=*factor or address=/factor", value)
+	}
+	if err := ValidateAddressChecksum(parts[0], false); err != nil {
+		return common.Address{}, AmountScale{}, fmt.Errorf("invalid amount scale %q: %w", value, err)
+	}
+
+	scale := AmountScale{Divide: parts[1][0] == '/'}
+	factor := parts[1][1:]
+	if strings.HasPrefix(factor, "1e") {
+		exponent, err := strconv.ParseUint(factor[2:], 10, 8)
+		if err != nil {
+			return common.Address{}, AmountScale{}, fmt.Errorf("invalid amount scale %q: invalid exponent: %v", value, err)
+		}
+		scale.Factor = decimalScale(uint8(exponent))
+	} else if parsed, ok := new(big.Int).SetString(factor, 10); ok && isDecimalDigits(factor) {
+		scale.Factor = parsed
+	}
+	if scale.Factor == nil || scale.Factor.Sign() <= 0 {
+		return common.Address{}, AmountScale{}, fmt.Errorf("invalid amount scale %q: factor must be a positive integer", value)
+	}
+	return common.HexToAddress(parts[0]), scale, nil
+}
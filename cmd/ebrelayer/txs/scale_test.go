@@ -0,0 +1,101 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAmountScaleApply(t *testing.T) {
+	registry := NewAmountScaleRegistry()
+	up, down := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	registry.Register(up, AmountScale{Factor: decimalScale(12)})
+	registry.Register(down, AmountScale{Factor: big.NewInt(1000), Divide: true})
+
+	tests := []struct {
+		name   string
+		token  common.Address
+		amount *big.Int
+		want   *big.Int
+	}{
+		{"scale-up", up, big.NewInt(5), big.NewInt(5000000000000)},
+		{"exact scale-down", down, big.NewInt(12000), big.NewInt(12)},
+		{"unregistered", common.HexToAddress("0x3"), big.NewInt(12345), big.NewInt(12345)},
+	}
+	for _, tt := range tests {
+		got, err := registry.ScaleAmount(tt.token, tt.amount)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got.Cmp(tt.want) != 0 {
+			t.Fatalf("%s: ScaleAmount(%s) = %s, want %s", tt.name, tt.amount, got, tt.want)
+		}
+	}
+
+	// An inexact scale-down is refused rather than truncated
+	if got, err := registry.ScaleAmount(down, big.NewInt(12345)); !errors.Is(err, ErrInexactScale) {
+		t.Fatalf("ScaleAmount(12345 / 1000) = %v, %v, want ErrInexactScale", got, err)
+	}
+}
+
+func TestAmountScaleClaimMessage(t *testing.T) {
+	defer func(scales *AmountScaleRegistry) { AmountScales = scales }(AmountScales)
+	event := goldenEthEvent()
+
+	AmountScales = NewAmountScaleRegistry()
+	AmountScales.Register(goldenClaim.token, AmountScale{Factor: big.NewInt(1000)})
+	message, err := ClaimMessage(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scaled := new(big.Int).Mul(goldenClaim.amount, big.NewInt(1000))
+	want, err := BuildClaimHash(goldenClaim.unlockID, goldenClaim.sender, goldenClaim.recipient, goldenClaim.token, scaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(message, want) {
+		t.Fatalf("ClaimMessage with the amount scaled up = %x, want %x", message, want)
+	}
+
+	// 1.5e18 isn't a multiple of 7, so the claim can't be hashed at that scale
+	AmountScales.Register(goldenClaim.token, AmountScale{Factor: big.NewInt(7), Divide: true})
+	if _, err := ClaimMessage(event); !errors.Is(err, ErrInexactScale) {
+		t.Fatalf("ClaimMessage with an inexact scale-down = %v, want ErrInexactScale", err)
+	}
+}
+
+func TestParseAmountScale(t *testing.T) {
+	tests := []struct {
+		value string
+		want  AmountScale
+	}{
+		{checksummedAddress + "=*1e18", AmountScale{Factor: decimalScale(18)}},
+		{checksummedAddress + "=/1000", AmountScale{Factor: big.NewInt(1000), Divide: true}},
+	}
+	for _, tt := range tests {
+		token, scale, err := ParseAmountScale(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != common.HexToAddress(checksummedAddress) || scale.String() != tt.want.String() {
+			t.Fatalf("ParseAmountScale(%q) = %s, %s, want %s", tt.value, token.Hex(), scale, tt.want)
+		}
+	}
+
+	for _, value := range []string{
+		checksummedAddress,
+		checksummedAddress + "=1000",
+		checksummedAddress + "=*0",
+		checksummedAddress + "=/-10",
+		checksummedAddress + "=*1e",
+		checksummedAddress + "=*0x10",
+		corruptedAddress + "=*10",
+	} {
+		if _, _, err := ParseAmountScale(value); err == nil {
+			t.Fatalf("ParseAmountScale(%q) succeeded", value)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package txs
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// SigningScheme selects how a claim message is prefixed before it is signed, to match how the
+// verifying contract recovers the signer
+type SigningScheme int
+
+const (
+	// EthSignedMessage signs PrefixMsg(message), as web3.eth.sign does
+	EthSignedMessage SigningScheme = iota
+	// Raw signs the claim message itself, the unprefixed keccak256 claim hash, for contracts
+	// calling ecrecover on it directly
+	Raw
+	// EIP712 signs the EIP-712 typed data hash of a Claim(bytes32 message) struct
+	EIP712
+	// IntendedValidator signs PrefixMsgIntendedValidator(message, contract)
+	IntendedValidator
+)
+
+// signingSchemeNames are the names ParseSigningScheme accepts
+var signingSchemeNames = map[SigningScheme]string{
+	EthSignedMessage:  "eth-signed-message",
+	Raw:               "raw",
+	EIP712:            "eip712",
+	IntendedValidator: "intended-validator",
+}
+
+// String returns the scheme's name
+func (s SigningScheme) String() string {
+	if name, ok := signingSchemeNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("SigningScheme(%d)", int(s))
+}
+
+// ParseSigningScheme parses a scheme's name
+func ParseSigningScheme(name string) (SigningScheme, error) {
+	for scheme, schemeName := range signingSchemeNames {
+		if strings.EqualFold(name, schemeName) {
+			return scheme, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown signing scheme %q", name)
+}
+
+// EIP712Domain is the EIP-712 domain of a contract verifying EIP712 claims
+type EIP712Domain struct {
+	Name    string
+	Version string
+	ChainID *big.Int
+}
+
+var (
+	// eip712DomainTypeHash is keccak256 of the EIP712Domain type
+	eip712DomainTypeHash = Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	// eip712ClaimTypeHash is keccak256 of the Claim type signed under EIP712
+	eip712ClaimTypeHash = Keccak256([]byte("Claim(bytes32 message)"))
+)
+
+// Separator returns the domain separator of the verifying contract
+func (d EIP712Domain) Separator(contract common.Address) []byte {
+	return Keccak256(eip712DomainTypeHash, Keccak256([]byte(d.Name)), Keccak256([]byte(d.Version)),
+		math.U256Bytes(new(big.Int).Set(d.ChainID)), common.LeftPadBytes(contract.Bytes(), 32))
+}
+
+// SigningConfig is the signing scheme of a verifying contract, with the EIP-712 domain if its
+// scheme is EIP712, and the encoding its claim messages are hashed over
+type SigningConfig struct {
+	Scheme   SigningScheme
+	Domain   EIP712Domain
+	Encoding EncodingMode
+}
+
+// Digest returns the hash signed over message for verification by contract
+func (c SigningConfig) Digest(message []byte, contract common.Address) ([]byte, error) {
+	if len(message) != 32 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidMessageLength, len(message))
+	}
+
+	switch c.Scheme {
+	case EthSignedMessage:
+		return PrefixMsg(message), nil
+	case Raw:
+		return message, nil
+	case EIP712:
+		if c.Domain.ChainID == nil {
+			return nil, fmt.Errorf("EIP-712 domain of %s has no chain ID", contract.Hex())
+		}
+		structHash := Keccak256(eip712ClaimTypeHash, message)
+		return Keccak256([]byte{0x19, 0x01}, c.Domain.Separator(contract), structHash), nil
+	case IntendedValidator:
+		return PrefixMsgIntendedValidator(message, contract), nil
+	}
+	return nil, fmt.Errorf("unknown signing scheme %v", c.Scheme)
+}
+
+// ParseSigningConfig parses a contract's signing scheme given as "address=scheme", where an
+// EIP712 scheme is followed by its domain as "eip712:name:version:chainID"
+func ParseSigningConfig(value string) (common.Address, SigningConfig, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return common.Address{}, SigningConfig{}, fmt.Errorf("invalid signing scheme %q: expected address=scheme", value)
+	}
+	if err := ValidateAddressChecksum(parts[0], false); err != nil {
+		return common.Address{}, SigningConfig{}, fmt.Errorf("invalid signing scheme %q: %w", value, err)
+	}
+
+	fields := strings.Split(parts[1], ":")
+	scheme, err := ParseSigningScheme(fields[0])
+	if err != nil {
+		return common.Address{}, SigningConfig{}, err
+	}
+	config := SigningConfig{Scheme: scheme}
+
+	switch {
+	case scheme == EIP712 && len(fields) == 4:
+		chainID, ok := new(big.Int).SetString(fields[3], 10)
+		if !ok {
+			return common.Address{}, SigningConfig{}, fmt.Errorf("invalid EIP-712 chain ID in %q", value)
+		}
+		config.Domain = EIP712Domain{Name: fields[1], Version: fields[2], ChainID: chainID}
+	case scheme == EIP712:
+		return common.Address{}, SigningConfig{}, fmt.Errorf("invalid signing scheme %q: expected address=eip712:name:version:chainID", value)
+	case len(fields) != 1:
+		return common.Address{}, SigningConfig{}, fmt.Errorf("invalid signing scheme %q: %s takes no domain", value, scheme)
+	}
+	return common.HexToAddress(parts[0]), config, nil
+}
+
+// SigningSchemes, if set, selects the signing scheme of each verifying contract. Otherwise every
+// claim is signed over PrefixMsg.
+var SigningSchemes *SigningSchemeRegistry
+
+// SigningSchemeRegistry maps verifying contracts to their SigningConfig, falling back to a
+// default for unregistered contracts. It is safe for concurrent use.
+type SigningSchemeRegistry struct {
+	mu        sync.RWMutex
+	fallback  SigningConfig
+	contracts map[common.Address]SigningConfig
+}
+
+// NewSigningSchemeRegistry initializes a new SigningSchemeRegistry using fallback for every
+// unregistered contract
+func NewSigningSchemeRegistry(fallback SigningConfig) *SigningSchemeRegistry {
+	return &SigningSchemeRegistry{fallback: fallback, contracts: make(map[common.Address]SigningConfig)}
+}
+
+// Register records contract's signing config
+func (r *SigningSchemeRegistry) Register(contract common.Address, config SigningConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.contracts[contract] = config
+}
+
+// SetEncoding sets the encoding contract's claim messages are hashed over, keeping the rest of
+// its signing config
+func (r *SigningSchemeRegistry) SetEncoding(contract common.Address, mode EncodingMode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config, ok := r.contracts[contract]
+	if !ok {
+		config = r.fallback
+	}
+	config.Encoding = mode
+	r.contracts[contract] = config
+}
+
+// Config returns contract's signing config
+func (r *SigningSchemeRegistry) Config(contract common.Address) SigningConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if config, ok := r.contracts[contract]; ok {
+		return config
+	}
+	return r.fallback
+}
+
+// claimSigningConfig returns the signing config of the first of target, if given, and that
+// contract, per SigningSchemes
+func claimSigningConfig(target []common.Address) (SigningConfig, common.Address) {
+	var contract common.Address
+	if len(target) > 0 {
+		contract = target[0]
+	}
+	if SigningSchemes == nil {
+		return SigningConfig{}, contract
+	}
+	return SigningSchemes.Config(contract), contract
+}
+
+// claimDigest returns the hash to sign over message for verification by the first of target, if
+// given, per SigningSchemes
+func claimDigest(message []byte, target []common.Address) ([]byte, error) {
+	config, contract := claimSigningConfig(target)
+	return config.Digest(message, contract)
+}
+
+// claimEncoding returns the encoding claim messages verified by the first of target, if given,
+// are hashed over, per SigningSchemes
+func claimEncoding(target []common.Address) EncodingMode {
+	config, _ := claimSigningConfig(target)
+	return config.Encoding
+}
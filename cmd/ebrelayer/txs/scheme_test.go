@@ -0,0 +1,142 @@
+package txs
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// contractDigest rebuilds, as a verifying contract would, the digest it recovers a claim's signer
+// from under each scheme
+func contractDigest(t *testing.T, config SigningConfig, message []byte, contract common.Address) []byte {
+	switch config.Scheme {
+	case EthSignedMessage:
+		return accounts.TextHash(message)
+	case Raw:
+		return message
+	case EIP712:
+		domain := crypto.Keccak256(
+			crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")),
+			crypto.Keccak256([]byte(config.Domain.Name)), crypto.Keccak256([]byte(config.Domain.Version)),
+			math.U256Bytes(new(big.Int).Set(config.Domain.ChainID)), common.LeftPadBytes(contract.Bytes(), 32))
+		claim := crypto.Keccak256(crypto.Keccak256([]byte("Claim(bytes32 message)")), message)
+		return crypto.Keccak256([]byte("\x19\x01"), domain, claim)
+	case IntendedValidator:
+		return crypto.Keccak256([]byte("\x19\x00"), contract.Bytes(), message)
+	}
+	t.Fatalf("no verification for %s", config.Scheme)
+	return nil
+}
+
+func TestSigningSchemesRecoverable(t *testing.T) {
+	key := testKey(t)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	message := common.Hex2Bytes(goldenClaim.message)
+	contract := common.HexToAddress(checksummedAddress)
+
+	configs := []SigningConfig{
+		{Scheme: EthSignedMessage},
+		{Scheme: Raw},
+		{Scheme: EIP712, Domain: EIP712Domain{Name: "Oracle", Version: "1", ChainID: big.NewInt(1666600000)}},
+		{Scheme: IntendedValidator},
+	}
+	digests := make(map[string]SigningScheme)
+	for _, config := range configs {
+		t.Run(config.Scheme.String(), func(t *testing.T) {
+			digest, err := config.Digest(message, contract)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := SignClaim(digest, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pub, err := crypto.SigToPub(contractDigest(t, config, message, contract), sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recovered := crypto.PubkeyToAddress(*pub); recovered != signer {
+				t.Fatalf("signature recovers to %s under %s verification, want %s", recovered.Hex(), config.Scheme, signer.Hex())
+			}
+			if other, ok := digests[string(digest)]; ok {
+				t.Fatalf("%s signs the same digest as %s", config.Scheme, other)
+			}
+			digests[string(digest)] = config.Scheme
+		})
+	}
+}
+
+func TestParseSigningConfig(t *testing.T) {
+	contract, config, err := ParseSigningConfig(checksummedAddress + "=eip712:Oracle:1:5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contract != common.HexToAddress(checksummedAddress) || config.Scheme != EIP712 ||
+		config.Domain.Name != "Oracle" || config.Domain.Version != "1" || config.Domain.ChainID.Int64() != 5 {
+		t.Fatalf("ParseSigningConfig = %s, %+v", contract.Hex(), config)
+	}
+	for _, name := range []string{"eth-signed-message", "raw", "intended-validator"} {
+		if _, config, err := ParseSigningConfig(checksummedAddress + "=" + name); err != nil || config.Scheme.String() != name {
+			t.Fatalf("ParseSigningConfig(%s) = %+v, %v", name, config, err)
+		}
+	}
+
+	for _, value := range []string{checksummedAddress, checksummedAddress + "=eip712", checksummedAddress + "=raw:extra",
+		checksummedAddress + "=unknown", corruptedAddress + "=raw"} {
+		if _, _, err := ParseSigningConfig(value); err == nil {
+			t.Fatalf("ParseSigningConfig(%q) succeeded", value)
+		}
+	}
+}
+
+func TestSigningSchemeRegistryFallback(t *testing.T) {
+	contract := common.HexToAddress(checksummedAddress)
+	registry := NewSigningSchemeRegistry(SigningConfig{Scheme: Raw})
+	registry.Register(contract, SigningConfig{Scheme: IntendedValidator})
+
+	if scheme := registry.Config(contract).Scheme; scheme != IntendedValidator {
+		t.Fatalf("registered contract's scheme = %s, want intended-validator", scheme)
+	}
+	if scheme := registry.Config(common.HexToAddress("0x1")).Scheme; scheme != Raw {
+		t.Fatalf("unregistered contract's scheme = %s, want the raw fallback", scheme)
+	}
+	if _, err := (SigningConfig{Scheme: EIP712}).Digest(bytes.Repeat([]byte{1}, 32), contract); err == nil {
+		t.Fatal("EIP712 digest without a chain ID succeeded")
+	}
+}
+
+func TestRawSchemeRecoversUnprefixed(t *testing.T) {
+	defer func(schemes *SigningSchemeRegistry) { SigningSchemes = schemes }(SigningSchemes)
+	key := testKey(t)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	contract := common.HexToAddress(checksummedAddress)
+	SigningSchemes = NewSigningSchemeRegistry(SigningConfig{Scheme: EthSignedMessage})
+	SigningSchemes.Register(contract, SigningConfig{Scheme: Raw})
+
+	events := testClaimEvents(3)
+	raw, err := SignClaimsBatch(NewKeySigner(key), events, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefixed, err := SignClaimsBatch(NewKeySigner(key), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, claim := range raw {
+		// As ecrecover(keccak256(claim), v, r, s) would, straight from the claim hash
+		if recovered, err := RecoverSigner(claim.Message[:], claim.Signature); err != nil || recovered != signer {
+			t.Fatalf("raw signature %d recovers to %s, %v over the unprefixed hash, want %s", i, recovered.Hex(), err, signer.Hex())
+		}
+		if recovered, _ := RecoverSigner(PrefixMsg(claim.Message[:]), claim.Signature); recovered == signer {
+			t.Fatalf("raw signature %d recovers over the prefixed hash", i)
+		}
+		if bytes.Equal(claim.Signature, prefixed[i].Signature) {
+			t.Fatalf("claim %d signed the same for a raw contract as by default", i)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrSelfTestFailed is returned by SelfTest when signing or claim hashing is broken
+var ErrSelfTestFailed = errors.New("self-test failed")
+
+// selfTestMessage is the fixed message SelfTest signs
+var selfTestMessage = Keccak256([]byte("ebrelayer self-test"))
+
+// selfTestClaim is a fixed claim, laid out as ClaimMessageLayout, whose hash must equal
+// selfTestClaimHash
+var selfTestClaim = []interface{}{
+	big.NewInt(1),
+	common.HexToAddress("0x1111111111111111111111111111111111111111"),
+	common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	common.HexToAddress("0x3333333333333333333333333333333333333333"),
+	big.NewInt(1000000000000000000),
+}
+
+// selfTestClaimHash is the golden keccak256(abi.encodePacked(...)) of selfTestClaim
+const selfTestClaimHash = "0xa1246b7d492c5bb2ae5e511aec5845ada237bfc1f30cc47c02ae129f2418a8f5"
+
+// SelfTest signs a fixed message with signer and checks the signature recovers to the signer's
+// address, then checks the claim message layout still hashes a fixed claim to its golden value.
+// Run at startup, it catches a misconfigured key or a broken build before any real event.
+func SelfTest(signer Signer) error {
+	signature, err := signer.Sign(PrefixMsg(selfTestMessage))
+	if err != nil {
+		return fmt.Errorf("%w: signing: %v", ErrSelfTestFailed, err)
+	}
+	recovered, err := RecoverSigner(PrefixMsg(selfTestMessage), signature)
+	if err != nil {
+		return fmt.Errorf("%w: recovering signer: %v", ErrSelfTestFailed, err)
+	}
+	if recovered != signer.Address() {
+		return fmt.Errorf("%w: signature recovers to %s, expected %s", ErrSelfTestFailed, recovered.Hex(),
+			signer.Address().Hex())
+	}
+
+	claimHash, err := SoliditySHA3Typed(ClaimMessageLayout, selfTestClaim)
+	if err != nil {
+		return fmt.Errorf("%w: hashing claim: %v", ErrSelfTestFailed, err)
+	}
+	if !bytes.Equal(claimHash, common.FromHex(selfTestClaimHash)) {
+		return fmt.Errorf("%w: claim hashes to %s, expected %s", ErrSelfTestFailed, hexutil.Encode(claimHash),
+			selfTestClaimHash)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package txs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mismatchedSigner signs with one key but claims another's address
+type mismatchedSigner struct {
+	Signer
+	address common.Address
+}
+
+func (s mismatchedSigner) Address() common.Address {
+	return s.address
+}
+
+func TestSelfTest(t *testing.T) {
+	signer := NewKeySigner(testKey(t))
+	if err := SelfTest(signer); err != nil {
+		t.Fatalf("SelfTest of a matching key = %v", err)
+	}
+
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mismatched := mismatchedSigner{Signer: signer, address: crypto.PubkeyToAddress(other.PublicKey)}
+	if err := SelfTest(mismatched); !errors.Is(err, ErrSelfTestFailed) {
+		t.Fatalf("SelfTest of a mismatched key and address = %v, want ErrSelfTestFailed", err)
+	}
+	if err := SelfTest(failingSigner{}); !errors.Is(err, ErrSelfTestFailed) {
+		t.Fatalf("SelfTest of a failing signer = %v, want ErrSelfTestFailed", err)
+	}
+}
@@ -0,0 +1,255 @@
+package txs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// SignatureCache, if set, holds the signature of each claim signed and not yet confirmed, so a
+// claim re-observed after a restart is given the signature it was first signed with rather than
+// being signed again
+var SignatureCache *ClaimSignatureCache
+
+// cachedSignature is a line of a ClaimSignatureCache file: signer's signature over the claim hash
+// for unlock ID UnlockID on Chain, or, if Evicted, the eviction of that claim's signatures
+type cachedSignature struct {
+	Chain     string         `json:"chain"`
+	UnlockID  *big.Int       `json:"unlockID"`
+	Signer    common.Address `json:"signer,omitempty"`
+	Hash      hexutil.Bytes  `json:"hash,omitempty"`
+	Signature hexutil.Bytes  `json:"signature,omitempty"`
+
+	Evicted bool `json:"evicted,omitempty"`
+}
+
+// ClaimSignatureCache maps each signer's claim hashes to the signatures it made over them, until
+// the claims are confirmed and their signatures evicted. Unless held in memory only, it appends
+// each change as a JSON line to a file, compacted to the cached signatures when reopened. It is
+// safe for concurrent use.
+type ClaimSignatureCache struct {
+	mu         sync.Mutex
+	file       *os.File
+	signatures map[string]cachedSignature
+	claims     map[string][]string
+}
+
+// NewClaimSignatureCache opens the ClaimSignatureCache at path, loading any previously cached
+// signatures, or initializes one held in memory only if path is empty
+func NewClaimSignatureCache(path string) (*ClaimSignatureCache, error) {
+	c := &ClaimSignatureCache{
+		signatures: make(map[string]cachedSignature),
+		claims:     make(map[string][]string),
+	}
+	if path == "" {
+		return c, nil
+	}
+
+	lines, err := c.load(path)
+	if err != nil {
+		return nil, err
+	}
+	if lines > len(c.signatures) {
+		if err := c.compact(path); err != nil {
+			return nil, err
+		}
+	}
+	if c.file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load replays the lines of the file at path, if it exists, returning how many it held
+func (c *ClaimSignatureCache) load(path string) (int, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		lines++
+		var entry cachedSignature
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return 0, fmt.Errorf("line %d: %w", lines, err)
+		}
+		if entry.UnlockID == nil {
+			return 0, fmt.Errorf("line %d: missing unlock ID", lines)
+		}
+		c.apply(entry)
+	}
+	return lines, scanner.Err()
+}
+
+// compact rewrites the file at path to hold only the cached signatures, in place of the evicted
+// and replaced ones. The file is replaced atomically.
+func (c *ClaimSignatureCache) compact(path string) error {
+	keys := make([]string, 0, len(c.signatures))
+	for key := range c.signatures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var data []byte
+	for _, key := range keys {
+		line, err := json.Marshal(c.signatures[key])
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	return types.WriteFileAtomic(path, data)
+}
+
+// Get returns signer's cached signature over the claim hash, if any
+func (c *ClaimSignatureCache) Get(signer common.Address, hash []byte) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.signatures[claimSignatureKey(signer, hash)]
+	return append([]byte(nil), entry.Signature...), ok
+}
+
+// Put caches signer's signature over the claim hash for unlockID on chain
+func (c *ClaimSignatureCache) Put(chain string, unlockID *big.Int, signer common.Address, hash, sig []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := cachedSignature{
+		Chain:     chain,
+		UnlockID:  new(big.Int).Set(unlockID),
+		Signer:    signer,
+		Hash:      append(hexutil.Bytes{}, hash...),
+		Signature: append(hexutil.Bytes{}, sig...),
+	}
+	if err := c.append(entry); err != nil {
+		return err
+	}
+	c.apply(entry)
+	return nil
+}
+
+// Evict discards the signatures cached for the claim for unlockID on chain, once it is confirmed
+func (c *ClaimSignatureCache) Evict(chain string, unlockID *big.Int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.claims[signedClaimKey(chain, unlockID)]) == 0 {
+		return nil
+	}
+	entry := cachedSignature{Chain: chain, UnlockID: new(big.Int).Set(unlockID), Evicted: true}
+	if err := c.append(entry); err != nil {
+		return err
+	}
+	c.apply(entry)
+	return nil
+}
+
+// Close closes the underlying file, if any
+func (c *ClaimSignatureCache) Close() error {
+	if c.file == nil {
+		return nil
+	}
+	return c.file.Close()
+}
+
+// append writes entry as a line to the file, if any. The caller must hold mu.
+func (c *ClaimSignatureCache) append(entry cachedSignature) error {
+	if c.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = c.file.Write(append(line, '\n'))
+	return err
+}
+
+// apply records entry in the in-memory maps
+func (c *ClaimSignatureCache) apply(entry cachedSignature) {
+	claim := signedClaimKey(entry.Chain, entry.UnlockID)
+	if entry.Evicted {
+		for _, key := range c.claims[claim] {
+			delete(c.signatures, key)
+		}
+		delete(c.claims, claim)
+		return
+	}
+
+	key := claimSignatureKey(entry.Signer, entry.Hash)
+	if _, ok := c.signatures[key]; !ok {
+		c.claims[claim] = append(c.claims[claim], key)
+	}
+	c.signatures[key] = entry
+}
+
+// claimSignatureKey returns the cache key of signer's signature over the claim hash
+func claimSignatureKey(signer common.Address, hash []byte) string {
+	return signer.Hex() + ":" + hexutil.Encode(hash)
+}
+
+// evictClaimSignature evicts an oracle claim's signatures from SignatureCache, if set, once the
+// claim is confirmed on-chain
+func evictClaimSignature(claim QueuedClaim) {
+	if SignatureCache == nil || claim.UnlockID == nil {
+		return
+	}
+	if err := SignatureCache.Evict(claim.Chain, claim.UnlockID); err != nil {
+		getLogger().Error("Evicting cached claim signature failed", "chain", claim.Chain, "unlockID", claim.UnlockID,
+			"err", err)
+	}
+}
+
+// signClaimDigest returns signer's signature over the claim digest for unlockID on chain. A
+// signature SignatureCache holds is returned as is, so a re-observed or retried claim is given the
+// signature it was first signed with. Otherwise an unlock ID ClaimTracker holds as signed is
+// rejected before anything is signed, and digest is signed with sign, checked by
+// SignatureMonitor, recorded with ClaimTracker and cached.
+func signClaimDigest(chain string, unlockID *big.Int, signer common.Address, digest []byte,
+	sign func(digest []byte) ([]byte, error)) ([]byte, error) {
+	if SignatureCache != nil {
+		if sig, ok := SignatureCache.Get(signer, digest); ok {
+			getLogger().Info("Reusing cached claim signature", "chain", chain, "unlockID", unlockID,
+				"signer", signer.Hex())
+			return sig, nil
+		}
+	}
+	if err := checkTracked(chain, unlockID); err != nil {
+		return nil, err
+	}
+
+	sig, err := sign(digest)
+	if err != nil {
+		return nil, err
+	}
+	if err := SignatureMonitor.Observe(chain, unlockID, sig); err != nil {
+		return nil, err
+	}
+	if err := trackSigned(chain, unlockID); err != nil {
+		return nil, err
+	}
+	if SignatureCache == nil {
+		return sig, nil
+	}
+	return sig, SignatureCache.Put(chain, unlockID, signer, digest, sig)
+}
@@ -0,0 +1,240 @@
+package txs
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+func TestSignatureCacheReusedAfterRestart(t *testing.T) {
+	defer func(cache *ClaimSignatureCache) { SignatureCache = cache }(SignatureCache)
+	_, restore := useRecordingLogger()
+	defer restore()
+	dir, err := ioutil.TempDir("", "sigcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signatures.json")
+	events := []types.EthLogNewUnlockClaimEvent{goldenEthEvent()}
+
+	if SignatureCache, err = NewClaimSignatureCache(path); err != nil {
+		t.Fatal(err)
+	}
+	first := &countingSigner{Signer: NewKeySigner(testKey(t))}
+	signed, err := SignClaimsBatch(first, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.signed != 1 {
+		t.Fatalf("signed %d claims, want 1", first.signed)
+	}
+
+	// After a restart, the re-observed claim is given the signature it was first signed with
+	if SignatureCache, err = NewClaimSignatureCache(path); err != nil {
+		t.Fatal(err)
+	}
+	restarted := &countingSigner{Signer: NewKeySigner(testKey(t))}
+	resigned, err := SignClaimsBatch(restarted, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted.signed != 0 {
+		t.Fatalf("signer invoked %d times for a cached claim", restarted.signed)
+	}
+	if !bytes.Equal(resigned[0].Signature, signed[0].Signature) {
+		t.Fatalf("cached claim signature = %x, want the first %x", resigned[0].Signature, signed[0].Signature)
+	}
+
+	// Another claim is still signed
+	other := goldenEthEvent()
+	other.UnlockID = big.NewInt(43)
+	if _, err := SignClaimsBatch(restarted, []types.EthLogNewUnlockClaimEvent{other}); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.signed != 1 {
+		t.Fatalf("signed %d claims, want the uncached claim signed", restarted.signed)
+	}
+}
+
+func TestClaimSignatureCacheKeyedBySigner(t *testing.T) {
+	cache, err := NewClaimSignatureCache("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := common.Hex2Bytes(goldenClaim.message)
+	if err := cache.Put(ethereumChainLabel, goldenClaim.unlockID, goldenClaim.sender, digest, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if sig, ok := cache.Get(goldenClaim.sender, digest); !ok || !bytes.Equal(sig, []byte{1}) {
+		t.Fatalf("Get = %x, %v, want the signature put", sig, ok)
+	}
+	if _, ok := cache.Get(goldenClaim.recipient, digest); ok {
+		t.Fatal("Get returned another signer's signature")
+	}
+}
+
+// readCacheLines returns the lines of the signature cache file at path
+func readCacheLines(t *testing.T, path string) []string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestClaimSignatureCacheAppends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sigcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signatures.json")
+
+	cache, err := NewClaimSignatureCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Put(ethereumChainLabel, big.NewInt(42), goldenClaim.sender, []byte{0x42}, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	first := readCacheLines(t, path)
+	if err := cache.Put(harmonyChainLabel, big.NewInt(43), goldenClaim.sender, []byte{0x43}, []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	// Each signature is appended, leaving the lines before it as they were
+	lines := readCacheLines(t, path)
+	if len(first) != 1 || len(lines) != 2 || lines[0] != first[0] {
+		t.Fatalf("cache file holds %q after %q, want one line appended", lines, first)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache, err = NewClaimSignatureCache(path); err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	for hash, want := range map[byte]byte{0x42: 1, 0x43: 2} {
+		if sig, ok := cache.Get(goldenClaim.sender, []byte{hash}); !ok || !bytes.Equal(sig, []byte{want}) {
+			t.Fatalf("reopened Get(%x) = %x, %v, want %x", hash, sig, ok, want)
+		}
+	}
+	if reopened := readCacheLines(t, path); len(reopened) != 2 {
+		t.Fatalf("reopening rewrote the cache file to %q", reopened)
+	}
+}
+
+func TestClaimSignatureCacheEvictsConfirmedClaims(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sigcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signatures.json")
+
+	cache, err := NewClaimSignatureCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, unlockID := range []int64{42, 43} {
+		if err := cache.Put(ethereumChainLabel, big.NewInt(unlockID), goldenClaim.sender, []byte{byte(unlockID)},
+			[]byte{1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The same unlock ID on the other chain is another claim
+	if err := cache.Evict(harmonyChainLabel, big.NewInt(42)); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Evict(ethereumChainLabel, big.NewInt(42)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(goldenClaim.sender, []byte{42}); ok {
+		t.Fatal("Get returned an evicted signature")
+	}
+	if _, ok := cache.Get(goldenClaim.sender, []byte{43}); !ok {
+		t.Fatal("Evict discarded another claim's signature")
+	}
+	if lines := readCacheLines(t, path); len(lines) != 3 {
+		t.Fatalf("cache file holds %d lines, want the eviction appended to both signatures", len(lines))
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening compacts the file to the signatures still cached
+	if cache, err = NewClaimSignatureCache(path); err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	if _, ok := cache.Get(goldenClaim.sender, []byte{42}); ok {
+		t.Fatal("evicted signature cached again after reopening")
+	}
+	if _, ok := cache.Get(goldenClaim.sender, []byte{43}); !ok {
+		t.Fatal("signature lost after reopening")
+	}
+	if lines := readCacheLines(t, path); len(lines) != 1 {
+		t.Fatalf("reopened cache file holds %d lines, want it compacted to 1", len(lines))
+	}
+}
+
+func TestMinedSubmissionEvictsSignature(t *testing.T) {
+	defer func(cache *ClaimSignatureCache) { SignatureCache = cache }(SignatureCache)
+	defer func(queue *ClaimSubmissionQueue) { SubmissionQueue = queue }(SubmissionQueue)
+	SubmissionQueue = nil
+	var err error
+	if SignatureCache, err = NewClaimSignatureCache(""); err != nil {
+		t.Fatal(err)
+	}
+	for _, unlockID := range []int64{42, 43} {
+		if err := SignatureCache.Put(ethereumChainLabel, big.NewInt(unlockID), goldenClaim.sender,
+			[]byte{byte(unlockID)}, []byte{1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A claim the contracts already processed is evicted at once
+	confirmSubmission(testOracleSubmission(43))
+	if _, ok := SignatureCache.Get(goldenClaim.sender, []byte{43}); ok {
+		t.Fatal("already processed claim's signature still cached")
+	}
+
+	// A submitted claim is evicted once its transaction is mined
+	mined := func(ctx context.Context, txHash common.Hash) (uint64, error) { return 1, nil }
+	watchSubmission(testOracleSubmission(42), common.HexToHash("0x42"), mined)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := SignatureCache.Get(goldenClaim.sender, []byte{42}); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("mined claim's signature still cached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// With a submission queue, the queue's confirmation evicts it
+	if SubmissionQueue, err = NewClaimSubmissionQueue(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignatureCache.Put(ethereumChainLabel, big.NewInt(44), goldenClaim.sender, []byte{44}, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SubmissionQueue.Confirm(testOracleSubmission(44)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := SignatureCache.Get(goldenClaim.sender, []byte{44}); ok {
+		t.Fatal("confirmed queued claim's signature still cached")
+	}
+}
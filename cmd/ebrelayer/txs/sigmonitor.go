@@ -0,0 +1,75 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrSignatureReused is returned when a signature made or collected for one claim was already
+// seen for a claim with another unlock ID, which only a claim hash collision or a claim layout
+// bug can cause
+var ErrSignatureReused = errors.New("signature already seen for another unlock ID")
+
+// SignatureMonitor, if set, checks every claim signature made or collected against those seen for
+// other claims
+var SignatureMonitor *SignatureSanityMonitor
+
+// SignatureSanityMonitor maps each claim signature seen to the unlock ID it was made for, raising
+// a critical alert, and if Halt is set halting claim signing, when one recurs for another unlock
+// ID. It is safe for concurrent use.
+type SignatureSanityMonitor struct {
+	Halt bool
+
+	mu   sync.Mutex
+	seen map[string]signatureClaim
+}
+
+// signatureClaim is the claim a signature was first seen for, and where it came from
+type signatureClaim struct {
+	source   string
+	unlockID string
+}
+
+// NewSignatureSanityMonitor initializes a new SignatureSanityMonitor, halting signing on a reused
+// signature if halt is set
+func NewSignatureSanityMonitor(halt bool) *SignatureSanityMonitor {
+	return &SignatureSanityMonitor{Halt: halt, seen: make(map[string]signatureClaim)}
+}
+
+// Observe records sig as made for the claim unlockID, returning ErrSignatureReused if it was
+// already seen for another unlock ID. The source, such as the chain a claim was signed for or the
+// validator a signature was collected from, is logged with a collision. Seeing a signature again
+// for the same claim, such as when a cached signature is reused, is not an error.
+func (m *SignatureSanityMonitor) Observe(source string, unlockID *big.Int, sig []byte) error {
+	if m == nil || unlockID == nil {
+		return nil
+	}
+	key := hexutil.Encode(sig)
+	claim := signatureClaim{source: source, unlockID: unlockID.String()}
+
+	m.mu.Lock()
+	first, ok := m.seen[key]
+	if !ok {
+		m.seen[key] = claim
+	}
+	m.mu.Unlock()
+	if !ok || first.unlockID == claim.unlockID {
+		return nil
+	}
+
+	getLogger().Error("CRITICAL: same signature seen for two unlock IDs, claim hashes may collide",
+		"unlockID", claim.unlockID, "source", source, "firstUnlockID", first.unlockID, "firstSource", first.source,
+		"signature", key)
+	err := fmt.Errorf("%w: signature for unlock ID %s (%s) was first seen for unlock ID %s (%s)",
+		ErrSignatureReused, claim.unlockID, source, first.unlockID, first.source)
+	if m.Halt {
+		if haltErr := HaltSigning(err.Error()); haltErr != nil {
+			getLogger().Error("Failed to persist signing halt", "err", haltErr)
+		}
+	}
+	return Permanent(err)
+}
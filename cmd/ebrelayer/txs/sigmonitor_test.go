@@ -0,0 +1,74 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fixedSigner signs every message with the same signature, as only colliding claim hashes could
+type fixedSigner struct {
+	Signer
+	sig []byte
+}
+
+func (s fixedSigner) Sign([]byte) ([]byte, error) {
+	return s.sig, nil
+}
+
+func TestSignatureMonitorAlertsOnReuse(t *testing.T) {
+	defer func(monitor *SignatureSanityMonitor) { SignatureMonitor = monitor }(SignatureMonitor)
+	defer func() { haltReason = "" }()
+	recorder, restore := useRecordingLogger()
+	defer restore()
+	_, disable := useTestMetrics(t)
+	defer disable()
+
+	SignatureMonitor = NewSignatureSanityMonitor(true)
+	signer := fixedSigner{Signer: NewKeySigner(testKey(t)), sig: bytes.Repeat([]byte{1}, crypto.SignatureLength)}
+	events := testClaimEvents(2)
+
+	_, err := SignClaimsBatch(signer, events)
+	if !errors.Is(err, ErrSignatureReused) || IsRetryableError(err) {
+		t.Fatalf("signing two claims with one signature = %v, want a permanent ErrSignatureReused", err)
+	}
+	alerted := false
+	for _, entry := range recorder.entries {
+		alerted = alerted || (entry.level == "ERROR" && strings.HasPrefix(entry.msg, "CRITICAL: same signature"))
+	}
+	if !alerted {
+		t.Fatal("reused signature wasn't alerted")
+	}
+	if _, halted := SigningHalted(); !halted {
+		t.Fatal("reused signature didn't halt signing")
+	}
+}
+
+func TestSignatureMonitorObserve(t *testing.T) {
+	_, restore := useRecordingLogger()
+	defer restore()
+	monitor := NewSignatureSanityMonitor(false)
+	sig := bytes.Repeat([]byte{2}, crypto.SignatureLength)
+
+	// Seeing a signature again for its own claim, as a cached signature is, isn't reuse
+	for i := 0; i < 2; i++ {
+		if err := monitor.Observe("ethereum", big.NewInt(1), sig); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := monitor.Observe("collected from 0x1", big.NewInt(2), sig); !errors.Is(err, ErrSignatureReused) {
+		t.Fatalf("Observe for another unlock ID = %v, want ErrSignatureReused", err)
+	}
+	if _, halted := SigningHalted(); halted {
+		t.Fatal("monitor without Halt halted signing")
+	}
+
+	var unset *SignatureSanityMonitor
+	if err := unset.Observe("ethereum", big.NewInt(2), sig); err != nil {
+		t.Fatal(err)
+	}
+}
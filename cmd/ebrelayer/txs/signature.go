@@ -3,9 +3,13 @@ package txs
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"encoding/binary"
 	"encoding/hex"
-	"log"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
 	"reflect"
@@ -14,156 +18,592 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/harmony-one/harmony/accounts/abi"
-	"github.com/joho/godotenv"
 
 	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
-	"golang.org/x/crypto/sha3"
 )
 
+const (
+	// EthereumPrivateKeyEnv is the default environment variable holding the validator's Ethereum private key
+	EthereumPrivateKeyEnv = "ETHEREUM_PRIVATE_KEY"
+	// HarmonyPrivateKeyEnv is the default environment variable holding the validator's Harmony private key
+	HarmonyPrivateKeyEnv = "HARMONY_PRIVATE_KEY"
+	// KeyFileEnvSuffix is appended to a key's environment variable to name a file containing the key
+	KeyFileEnvSuffix = "_FILE"
+	// KeyListEnvSuffix is appended to a key's environment variable to name a comma-separated list
+	// of keys, or of key files, held during key rotation
+	KeyListEnvSuffix = "S"
+)
+
+// ErrMissingPrivateKey is returned when a validator's private key is not configured
+var ErrMissingPrivateKey = errors.New("private key is not set")
+
+// ErrInvalidPrivateKey is returned when a private key is not a valid secp256k1 key
+var ErrInvalidPrivateKey = errors.New("invalid secp256k1 private key")
+
+// ErrInvalidMessageLength is returned when a message to sign or recover from is not a 32-byte hash
+var ErrInvalidMessageLength = errors.New("message is not a 32-byte hash")
+
 // LoadEthereumPrivateKey loads the validator's private key from environment variables
 func LoadEthereumPrivateKey() (key *ecdsa.PrivateKey, err error) {
+	return LoadPrivateKeyFromEnv(EthereumPrivateKeyEnv)
+}
+
+// LoadHarmonyPrivateKey loads the validator's private key from environment variables
+func LoadHarmonyPrivateKey() (key *ecdsa.PrivateKey, err error) {
+	return LoadPrivateKeyFromEnv(HarmonyPrivateKeyEnv)
+}
+
+// LoadPrivateKeyFromEnv loads a private key from the named environment variable. The key may
+// instead be kept in a file, referenced either by a [name]_FILE variable or by a path placed
+// directly in the named variable. A variable set in the process environment takes precedence
+// over the .env file, as LoadEnvOnce documents.
+func LoadPrivateKeyFromEnv(name string) (key *ecdsa.PrivateKey, err error) {
 	// Load config file containing environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file", err)
+	if err := LoadEnvOnce(); err != nil {
+		return nil, err
+	}
+
+	// Private key for validator's address must be set as an environment variable
+	rawPrivateKey := os.Getenv(name)
+	keyFile := os.Getenv(name + KeyFileEnvSuffix)
+	if keyFile == "" && strings.ContainsRune(rawPrivateKey, os.PathSeparator) {
+		keyFile = rawPrivateKey
+	}
+
+	if keyFile != "" {
+		rawPrivateKey, err = readKeyFile(keyFile)
+		if err != nil {
+			getLogger().Error("Error reading private key file", "env", name, "err", err)
+			return nil, err
+		}
 	}
 
-	// Private key for validator's Ethereum address must be set as an environment variable
-	rawPrivateKey := os.Getenv("ETHEREUM_PRIVATE_KEY")
 	if strings.TrimSpace(rawPrivateKey) == "" {
-		log.Fatal("Error loading ETHEREUM_PRIVATE_KEY from .env file")
+		getLogger().Error("Error loading private key from .env file", "env", name)
+		return nil, fmt.Errorf("%w: %s", ErrMissingPrivateKey, name)
 	}
 
 	// Parse private key
 	privateKey, err := crypto.HexToECDSA(rawPrivateKey)
 	if err != nil {
-		log.Fatal(err)
+		getLogger().Error("Error parsing private key", "env", name, "err", err)
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	if err := ValidatePrivateKey(privateKey); err != nil {
+		getLogger().Error("Error validating private key", "env", name, "err", err)
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
 	}
 
 	return privateKey, nil
 }
 
-// LoadHarmonyPrivateKey loads the validator's private key from environment variables
-func LoadHarmonyPrivateKey() (key *ecdsa.PrivateKey, err error) {
-	// Load config file containing environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file", err)
+// LoadPrivateKeysFromEnv loads the private keys listed, comma-separated, in the [name]S
+// environment variable, such as ETHEREUM_PRIVATE_KEYS. Each entry is a hex key or the path of a
+// key file. If the list is unset, the single key in name is loaded as by LoadPrivateKeyFromEnv.
+func LoadPrivateKeysFromEnv(name string) ([]*ecdsa.PrivateKey, error) {
+	if err := LoadEnvOnce(); err != nil {
+		return nil, err
 	}
 
-	// Private key for validator's Ethereum address must be set as an environment variable
-	rawPrivateKey := os.Getenv("HARMONY_PRIVATE_KEY")
-	if strings.TrimSpace(rawPrivateKey) == "" {
-		log.Fatal("Error loading HARMONY_PRIVATE_KEY from .env file")
+	list := os.Getenv(name + KeyListEnvSuffix)
+	if strings.TrimSpace(list) == "" {
+		key, err := LoadPrivateKeyFromEnv(name)
+		if err != nil {
+			return nil, err
+		}
+		return []*ecdsa.PrivateKey{key}, nil
 	}
 
-	// Parse private key
-	privateKey, err := crypto.HexToECDSA(rawPrivateKey)
-	if err != nil {
-		log.Fatal(err)
+	var keys []*ecdsa.PrivateKey
+	for i, entry := range strings.Split(list, ",") {
+		rawPrivateKey := strings.TrimSpace(entry)
+		if rawPrivateKey == "" {
+			continue
+		}
+		if strings.ContainsRune(rawPrivateKey, os.PathSeparator) {
+			var err error
+			if rawPrivateKey, err = readKeyFile(rawPrivateKey); err != nil {
+				return nil, fmt.Errorf("key %d of %s: %w", i, name+KeyListEnvSuffix, err)
+			}
+		}
+
+		privateKey, err := crypto.HexToECDSA(rawPrivateKey)
+		if err != nil {
+			getLogger().Error("Error parsing private key", "env", name+KeyListEnvSuffix, "index", i, "err", err)
+			return nil, fmt.Errorf("parsing key %d of %s: %w", i, name+KeyListEnvSuffix, err)
+		}
+		if err := ValidatePrivateKey(privateKey); err != nil {
+			getLogger().Error("Error validating private key", "env", name+KeyListEnvSuffix, "index", i, "err", err)
+			return nil, fmt.Errorf("parsing key %d of %s: %w", i, name+KeyListEnvSuffix, err)
+		}
+		keys = append(keys, privateKey)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingPrivateKey, name+KeyListEnvSuffix)
 	}
+	return keys, nil
+}
 
-	return privateKey, nil
+// ValidatePrivateKey checks that key is on the secp256k1 curve, that its scalar is in [1, n-1],
+// and that its public key is the scalar's point, so it produces signatures the contracts accept
+func ValidatePrivateKey(key *ecdsa.PrivateKey) error {
+	if key == nil || key.D == nil {
+		return ErrMissingPrivateKey
+	}
+
+	curve := crypto.S256()
+	if !isSecp256k1(key.Curve) {
+		name := "unknown"
+		if key.Curve != nil && key.Curve.Params().Name != "" {
+			name = key.Curve.Params().Name
+		}
+		return fmt.Errorf("%w: key is on curve %s", ErrInvalidPrivateKey, name)
+	}
+	if key.D.Sign() <= 0 || key.D.Cmp(curve.Params().N) >= 0 {
+		return fmt.Errorf("%w: scalar is outside [1, n-1]", ErrInvalidPrivateKey)
+	}
+
+	x, y := curve.ScalarBaseMult(math.PaddedBigBytes(key.D, 32))
+	if key.X == nil || key.Y == nil || key.X.Cmp(x) != 0 || key.Y.Cmp(y) != 0 {
+		return fmt.Errorf("%w: public key does not match scalar", ErrInvalidPrivateKey)
+	}
+	return nil
+}
+
+// isSecp256k1 reports whether curve has secp256k1's domain parameters
+func isSecp256k1(curve elliptic.Curve) bool {
+	if curve == nil {
+		return false
+	}
+	params, want := curve.Params(), crypto.S256().Params()
+	return params.P.Cmp(want.P) == 0 && params.N.Cmp(want.N) == 0 && params.B.Cmp(want.B) == 0 &&
+		params.Gx.Cmp(want.Gx) == 0 && params.Gy.Cmp(want.Gy) == 0
+}
+
+// readKeyFile reads a hex private key from a file, ignoring surrounding whitespace and newlines
+func readKeyFile(path string) (string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading private key file: %w", err)
+	}
+	return strings.TrimSpace(string(contents)), nil
 }
 
 // LoadSender uses the validator's private key to load the validator's address
 func LoadSender(privateKey *ecdsa.PrivateKey) (address common.Address, err error) {
+	if privateKey == nil {
+		return common.Address{}, ErrMissingPrivateKey
+	}
 
 	publicKey := privateKey.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
 	if !ok {
-		log.Fatal("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
+		return common.Address{}, errors.New("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
 	}
 
 	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
 	return fromAddress, nil
 }
 
-// EthGenerateClaimMessage Generates a hashed message containing a UnlockClaim event's data
-func EthGenerateClaimMessage(event types.EthLogNewUnlockClaimEvent) []byte {
-	unlockID := Int256(event.UnlockID)
-	sender := Int256(event.HarmonySender.Hex())
-	recipient := Int256(event.EthereumReceiver.Hex())
-	token := String(event.TokenAddress.Hex())
-	amount := Int256(event.Amount)
+// LoadHarmonySender uses the validator's private key to load the validator's Harmony address, in
+// its canonical bech32 form one1.... It encodes the same 20 bytes LoadSender returns, as Harmony
+// derives addresses from keys as Ethereum does, so FromBech32 of it is the validator's 0x address.
+func LoadHarmonySender(privateKey *ecdsa.PrivateKey) (string, error) {
+	address, err := LoadSender(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return types.ToBech32(address), nil
+}
+
+// ClaimMessageLayout is the ordered list of Solidity types packed into a claim message, matching
+// keccak256(abi.encodePacked(uint256 unlockID, address sender, address recipient, address token, uint256 amount))
+var ClaimMessageLayout = []string{"uint256", "address", "address", "address", "uint256"}
+
+// ClaimMessageValues returns a claim event's data in ClaimMessageLayout order
+func ClaimMessageValues(event types.ClaimEvent) []interface{} {
+	unlockID, sender, recipient, token, amount := event.ClaimFields()
+	return []interface{}{unlockID, sender, recipient, token, amount}
+}
+
+// ClaimMessageLayoutWithChainID extends ClaimMessageLayout with a trailing uint256 chain ID, binding
+// a claim signature to the chain it is submitted to
+var ClaimMessageLayoutWithChainID = append(append([]string{}, ClaimMessageLayout...), "uint256")
+
+// EthClaimChainID, if set, is mixed into the claim message of Ethereum UnlockClaims, which are
+// verified on Ethereum. Nil keeps the original layout used by existing deployments.
+var EthClaimChainID *big.Int
+
+// HmyClaimChainID, if set, is mixed into the claim message of Harmony UnlockClaims, which are
+// verified on Harmony. Nil keeps the original layout used by existing deployments.
+var HmyClaimChainID *big.Int
+
+// ClaimTxHashType is the Solidity type of the source transaction hash trailing a claim message
+// when EthClaimTxHash or HmyClaimTxHash is set
+const ClaimTxHashType = "bytes32"
+
+// EthClaimTxHash, if set, appends the hash of the transaction emitting an Ethereum UnlockClaim to
+// its claim message, after any chain ID, tracing each signature to its source transaction. Unset
+// keeps the layout used by existing deployments.
+var EthClaimTxHash bool
+
+// HmyClaimTxHash, if set, appends the hash of the transaction emitting a Harmony UnlockClaim to
+// its claim message, after any chain ID. Unset keeps the layout used by existing deployments.
+var HmyClaimTxHash bool
+
+// EthClaimTokenAmountHash, if set, packs the token and amount of an Ethereum UnlockClaim's claim
+// message as their TokenAmountField, keccak256(abi.encode(token, amount)), rather than inline.
+// Unset keeps the layout used by existing deployments.
+var EthClaimTokenAmountHash bool
+
+// HmyClaimTokenAmountHash, if set, packs the token and amount of a Harmony UnlockClaim's claim
+// message as their TokenAmountField. Unset keeps the layout used by existing deployments.
+var HmyClaimTokenAmountHash bool
+
+// ClaimMessage packs a claim event's data against ClaimMessageLayout and hashes it, appending the
+// chain ID configured for the event's chain if any and its source transaction hash if enabled,
+// rescaling the amount by Tokens if set, and hashing the token and amount together if enabled.
+// Addresses are packed as their raw 20 bytes, which are the same whether the address is displayed
+// as Ethereum hex or Harmony bech32. The data is ABI-encoded instead if SigningSchemes selects
+// ABIEncoding for the optional target verifying contract.
+func ClaimMessage(event types.ClaimEvent, target ...common.Address) ([]byte, error) {
+	return ClaimMessageForChain(event, claimChainID(event), WithEncoding(claimEncoding(target)))
+}
+
+// claimChainID returns the chain ID configured for a claim event's chain, or for its event version
+func claimChainID(event types.ClaimEvent) *big.Int {
+	if version, ok := claimEventVersion(event); ok && version.ClaimChainID != nil {
+		return version.ClaimChainID
+	}
+	if _, ok := event.(types.HmyLogNewUnlockClaimEvent); ok {
+		return HmyClaimChainID
+	}
+	return EthClaimChainID
+}
+
+// claimTxHash returns the hash of the transaction which emitted a claim event, if its chain, or
+// its event version, binds it into claim messages
+func claimTxHash(event types.ClaimEvent) *common.Hash {
+	enabled := EthClaimTxHash
+	if _, ok := event.(types.HmyLogNewUnlockClaimEvent); ok {
+		enabled = HmyClaimTxHash
+	}
+	if version, ok := claimEventVersion(event); ok && version.ClaimTxHash != nil {
+		enabled = *version.ClaimTxHash
+	}
+	traced, ok := event.(types.TracedClaimEvent)
+	if !enabled || !ok {
+		return nil
+	}
+	txHash := traced.SourceTxHash()
+	return &txHash
+}
+
+// ClaimMessageForChain hashes a claim event's data followed by chainID, as laid out by
+// ClaimMessageLayoutWithChainID. A nil chainID hashes against ClaimMessageLayout instead. The
+// event's source transaction hash trails them if its chain's EthClaimTxHash or HmyClaimTxHash is set,
+// and its token and amount are replaced by their TokenAmountField if EthClaimTokenAmountHash or
+// HmyClaimTokenAmountHash is.
+func ClaimMessageForChain(event types.ClaimEvent, chainID *big.Int, opts ...HashOption) ([]byte, error) {
+	unlockID, sender, recipient, token, amount := event.ClaimFields()
+
+	amount, err := normalizeAmount(token, amount)
+	if err != nil {
+		return nil, err
+	}
+	layout, values := claimMessageLayout([]interface{}{unlockID, sender, recipient, token, amount}, chainID,
+		claimTxHash(event))
+	if layout, values, err = claimTokenAmountLayout(event, layout, values); err != nil {
+		return nil, err
+	}
+	return SoliditySHA3Typed(layout, values, opts...)
+}
+
+// ErrMissingClaimField is returned when a claim's unlock ID or amount is not set
+var ErrMissingClaimField = errors.New("claim field is not set")
+
+// BuildClaimHash hashes a claim's fields as laid out by ClaimMessageLayout, matching the bridge
+// contracts' keccak256(abi.encodePacked(unlockID, sender, recipient, token, amount)). The fields
+// are hashed as given: the amount isn't rescaled and no chain ID is appended. A nil unlockID or
+// amount returns ErrMissingClaimField.
+func BuildClaimHash(unlockID *big.Int, sender, recipient, token common.Address, amount *big.Int) ([]byte, error) {
+	if unlockID == nil {
+		return nil, fmt.Errorf("%w: unlock ID", ErrMissingClaimField)
+	}
+	if amount == nil {
+		return nil, fmt.Errorf("%w: amount", ErrMissingClaimField)
+	}
+	return buildClaimHash(unlockID, sender, recipient, token, amount, nil, nil)
+}
+
+// buildClaimHash hashes a claim's fields followed by chainID and txHash, if set
+func buildClaimHash(unlockID *big.Int, sender, recipient, token common.Address, amount, chainID *big.Int,
+	txHash *common.Hash, opts ...HashOption) ([]byte, error) {
+	layout, values := claimMessageLayout([]interface{}{unlockID, sender, recipient, token, amount}, chainID, txHash)
+	return SoliditySHA3Typed(layout, values, opts...)
+}
+
+// claimMessageComponents returns the layout and values a claim message for chainID is packed from,
+// with the amount normalized to the decimals registered in Tokens and the source transaction hash
+// appended if enabled
+func claimMessageComponents(event types.ClaimEvent, chainID *big.Int) ([]string, []interface{}, error) {
+	unlockID, sender, recipient, token, amount := event.ClaimFields()
+	amount, err := normalizeAmount(token, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	layout, values := claimMessageLayout([]interface{}{unlockID, sender, recipient, token, amount}, chainID,
+		claimTxHash(event))
+	return claimTokenAmountLayout(event, layout, values)
+}
+
+// claimMessageLayout returns the layout of claim values in ClaimMessageLayout order, appending
+// chainID and then txHash to them if set
+func claimMessageLayout(values []interface{}, chainID *big.Int, txHash *common.Hash) ([]string, []interface{}) {
+	layout := ClaimMessageLayout
+	if chainID != nil {
+		layout, values = ClaimMessageLayoutWithChainID, append(values, chainID)
+	}
+	if txHash != nil {
+		layout = append(append([]string{}, layout...), ClaimTxHashType)
+		values = append(values, *txHash)
+	}
+	return layout, values
+}
+
+// ClaimMessagePreimage returns a claim event's message as ClaimMessage does, along with the packed
+// preimage it hashes, for debugging a message which doesn't match the contract's
+func ClaimMessagePreimage(event types.ClaimEvent) (message []byte, preimage []byte, err error) {
+	layout, values, err := claimMessageComponents(event, claimChainID(event))
+	if err != nil {
+		return nil, nil, err
+	}
+	return SoliditySHA3WithPreimage(layout, values...)
+}
+
+// GenerateClaimMessage Generates a hashed message containing a UnlockClaim event's data
+func GenerateClaimMessage(event types.ClaimEvent) ([]byte, error) {
+	return ClaimMessage(event)
+}
 
-	// Generate claim message using UnlockClaim data
-	return SoliditySHA3(unlockID, sender, recipient, token, amount)
+// EthGenerateClaimMessage Generates a hashed message containing a UnlockClaim event's data. An
+// optional chainID overrides EthClaimChainID.
+func EthGenerateClaimMessage(event types.EthLogNewUnlockClaimEvent, chainID ...*big.Int) ([]byte, error) {
+	return generateClaimMessageForChain(event, EthClaimChainID, chainID)
 }
 
-// HmyGenerateClaimMessage Generates a hashed message containing a UnlockClaim event's data
-func HmyGenerateClaimMessage(event types.HmyLogNewUnlockClaimEvent) []byte {
-	unlockID := Int256(event.UnlockID)
-	sender := Int256(event.EthereumSender.Hex())
-	recipient := Int256(event.HarmonyReceiver.Hex())
-	token := String(event.TokenAddress.Hex())
-	amount := Int256(event.Amount)
+// HmyGenerateClaimMessage Generates a hashed message containing a UnlockClaim event's data. An
+// optional chainID overrides HmyClaimChainID.
+func HmyGenerateClaimMessage(event types.HmyLogNewUnlockClaimEvent, chainID ...*big.Int) ([]byte, error) {
+	return generateClaimMessageForChain(event, HmyClaimChainID, chainID)
+}
 
-	// Generate claim message using UnlockClaim data
-	return SoliditySHA3(unlockID, sender, recipient, token, amount)
+// generateClaimMessageForChain hashes a claim message for the first of override, if given, or
+// the default chainID
+func generateClaimMessageForChain(event types.ClaimEvent, chainID *big.Int, override []*big.Int) ([]byte, error) {
+	if len(override) > 0 {
+		chainID = override[0]
+	}
+	return ClaimMessageForChain(event, chainID)
 }
 
+// signedMessagePrefix is prepended, along with the message length, by web3.eth.sign
+const signedMessagePrefix = "\x19Ethereum Signed Message:\n"
+
 // PrefixMsg prefixes a message for verification, mimics behavior of web3.eth.sign
 func PrefixMsg(msg []byte) []byte {
-	return SoliditySHA3(String("\x19Ethereum Signed Message:\n32"), msg)
+	return Keccak256([]byte(signedMessagePrefix+"32"), msg)
+}
+
+// PrefixMsgN prefixes a message of any length for verification, mimics behavior of web3.eth.sign
+func PrefixMsgN(msg []byte) []byte {
+	if len(msg) == 32 {
+		return PrefixMsg(msg)
+	}
+	return Keccak256([]byte(signedMessagePrefix+strconv.Itoa(len(msg))), msg)
 }
 
-// SignClaim Signs the prepared message with validator's private key
+// PrefixMsgIntendedValidator prefixes a message for verification by a specific contract, per
+// EIP-191 version 0x00 ("data with intended validator"): keccak256(0x19 0x00 validator msg)
+func PrefixMsgIntendedValidator(msg []byte, validator common.Address) []byte {
+	return Keccak256([]byte{0x19, 0x00}, validator.Bytes(), msg)
+}
+
+// SignClaim Signs the prepared message with validator's private key. Signing is deterministic: nonces
+// are derived per RFC-6979 by both of go-ethereum's secp256k1 backends, so the same message and key
+// always produce byte-identical signatures, which callers may rely on to key stored signatures.
 func SignClaim(msg []byte, key *ecdsa.PrivateKey) ([]byte, error) {
-	// Sign the message
+	if key == nil {
+		return nil, ErrMissingPrivateKey
+	}
+	if len(msg) != 32 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidMessageLength, len(msg))
+	}
+
+	// Sign the message. crypto.Sign already produces low-s signatures; normalizing guards against a
+	// backend which doesn't.
 	sig, err := crypto.Sign(msg, key)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	return NormalizeS(sig), nil
+}
+
+// RecoverSigner returns the address whose key produced sig over hash, the digest actually signed:
+// PrefixMsg(message) for EthSignedMessage claims, or the claim message itself for Raw ones. The
+// recovery ID may be given as 0/1 or, as web3 produces, 27/28.
+func RecoverSigner(hash, sig []byte) (common.Address, error) {
+	if len(hash) != 32 {
+		return common.Address{}, fmt.Errorf("%w: %d bytes", ErrInvalidMessageLength, len(hash))
 	}
-	return sig, nil
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature is %d bytes, expected %d", len(sig), crypto.SignatureLength)
+	}
+	normalized := append([]byte{}, sig...)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash, normalized)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+var (
+	// secp256k1N is the order of the secp256k1 curve
+	secp256k1N = crypto.S256().Params().N
+	// secp256k1HalfN is the largest s value of a low-s signature
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// ErrInvalidSignatureS is returned when a signature's s value is zero or not below the curve order
+var ErrInvalidSignatureS = errors.New("signature s value out of range")
+
+// IsLowS reports whether sig's s value is in the lower half of the curve order, as contracts
+// guarding against malleability, such as OpenZeppelin's ECDSA, require
+func IsLowS(sig []byte) bool {
+	if len(sig) < CompactSignatureLength {
+		return false
+	}
+	sValue := new(big.Int).SetBytes(sig[32:64])
+	return sValue.Sign() > 0 && sValue.Cmp(secp256k1HalfN) <= 0
+}
+
+// NormalizeS returns a copy of sig with a high s value replaced by n - s, and its recovery ID, if
+// present, flipped to match, so it still recovers to the same signer. A low-s or out of range
+// signature is copied unchanged.
+func NormalizeS(sig []byte) []byte {
+	normalized := append([]byte{}, sig...)
+	if len(sig) < CompactSignatureLength {
+		return normalized
+	}
+	sValue := new(big.Int).SetBytes(sig[32:64])
+	if sValue.Cmp(secp256k1HalfN) <= 0 || sValue.Cmp(secp256k1N) >= 0 {
+		return normalized
+	}
+
+	copy(normalized[32:64], common.LeftPadBytes(new(big.Int).Sub(secp256k1N, sValue).Bytes(), 32))
+	if len(normalized) == crypto.SignatureLength {
+		// 0 and 1 swap, as do web3's 27 and 28
+		if v := normalized[crypto.RecoveryIDOffset]; v >= 27 {
+			normalized[crypto.RecoveryIDOffset] = 27 + ((v - 27) ^ 1)
+		} else {
+			normalized[crypto.RecoveryIDOffset] = v ^ 1
+		}
+	}
+	return normalized
+}
+
+// CompactSignatureLength is the length of a signature without its recovery ID, r || s
+const CompactSignatureLength = crypto.SignatureLength - 1
+
+// ErrSignerMismatch is returned when no recovery ID recovers a compact signature to the expected signer
+var ErrSignerMismatch = errors.New("signature does not recover to the expected signer")
+
+// SignClaimCompact signs msg as SignClaim does, returning the 64-byte r || s without the trailing
+// recovery ID
+func SignClaimCompact(msg []byte, key *ecdsa.PrivateKey) ([]byte, error) {
+	sig, err := SignClaim(msg, key)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:CompactSignatureLength], nil
 }
 
-// Int256 int256
+// ExpandSignature restores the recovery ID of a 64-byte r || s signature over msg, trying both
+// recovery IDs and keeping the one recovering signer. The result has a 0/1 recovery ID, as
+// SignClaim returns.
+func ExpandSignature(sig64, msg []byte, signer common.Address) ([]byte, error) {
+	if len(sig64) != CompactSignatureLength {
+		return nil, fmt.Errorf("compact signature is %d bytes, expected %d", len(sig64), CompactSignatureLength)
+	}
+
+	for v := byte(0); v < 2; v++ {
+		sig := append(append(make([]byte, 0, crypto.SignatureLength), sig64...), v)
+		recovered, err := RecoverSigner(msg, sig)
+		if err != nil {
+			if errors.Is(err, ErrInvalidMessageLength) {
+				return nil, err
+			}
+			continue
+		}
+		if recovered == signer {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSignerMismatch, signer.Hex())
+}
+
+// Int256 int256, packed as 32 bytes of two's complement. A []byte is taken as an already encoded
+// big-endian word, as found in log data, and left-padded to 32 bytes. Panics if the value doesn't
+// fit an int256, or with ErrWordOverflow if a []byte is longer than 32 bytes.
 func Int256(input interface{}) []byte {
+	var bn *big.Int
 	switch v := input.(type) {
+	case []byte:
+		return wordBytes(v, "int256")
 	case *big.Int:
-		return common.LeftPadBytes(v.Bytes(), 32)
+		bn = v
+	case json.Number:
+		return Int256(v.String())
 	case string:
-		bn := new(big.Int)
+		bn = new(big.Int)
 		bn.SetString(v, 10)
-		return common.LeftPadBytes(bn.Bytes(), 32)
 	case uint64:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = new(big.Int).SetUint64(v)
 	case uint32:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(int64(v))
 	case uint16:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(int64(v))
 	case uint8:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(int64(v))
 	case uint:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = new(big.Int).SetUint64(uint64(v))
 	case int64:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(v)
 	case int32:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(int64(v))
 	case int16:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(int64(v))
 	case int8:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(int64(v))
 	case int:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 32)
+		bn = big.NewInt(int64(v))
 	}
 
-	if isArray(input) {
-		return Int256Array(input)
+	if bn == nil {
+		if isArray(input) {
+			return Int256Array(input)
+		}
+		bn = new(big.Int)
 	}
 
-	return common.LeftPadBytes([]byte{}, 32)
+	return twosComplement(bn, 32, "int256")
 }
 
 func isArray(value interface{}) bool {
@@ -173,23 +613,23 @@ func isArray(value interface{}) bool {
 
 // Int256Array int256 array
 func Int256Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Int256(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Int256, true)
 }
 
-// String string
+// String string. Hashes and addresses are packed as their raw bytes, never their hex text, while any
+// other fmt.Stringer (such as *big.Int) is packed as the UTF-8 text of its String method.
 func String(input interface{}) []byte {
 	switch v := input.(type) {
 	case []byte:
 		return v
 	case string:
 		return []byte(v)
+	case common.Hash:
+		return v.Bytes()
+	case common.Address:
+		return v.Bytes()
+	case fmt.Stringer:
+		return []byte(v.String())
 	}
 
 	if isArray(input) {
@@ -199,7 +639,10 @@ func String(input interface{}) []byte {
 	return []byte("")
 }
 
-// StringArray string
+// StringArray concatenates the bytes of each string, with no length prefixes, padding or
+// separators, as abi.encodePacked(a, b, c) lays out strings passed individually. Solidity rejects
+// abi.encodePacked of a string[] value itself, so this is the only packed layout of a string list,
+// and ["ab", "c"] packs as ["a", "bc"] does. Use StringArrayABI to match abi.encode(string[]).
 func StringArray(input interface{}) []byte {
 	var values []byte
 	s := reflect.ValueOf(input)
@@ -211,43 +654,217 @@ func StringArray(input interface{}) []byte {
 	return values
 }
 
-// SoliditySHA3 solidity sha3
+// SoliditySHA3 solidity sha3. Invalid input, such as a non-[]byte argument to the untyped form,
+// is logged and hashes to nil; use SoliditySHA3Checked to handle the error instead.
 func SoliditySHA3(data ...interface{}) []byte {
-	types, ok := data[0].([]string)
-	if len(data) > 1 && ok {
+	hash, err := SoliditySHA3Checked(data...)
+	if err != nil {
+		getLogger().Error("Invalid SoliditySHA3 input", "err", err)
+		return nil
+	}
+	return hash
+}
+
+// SoliditySHA3Checked solidity sha3, taking either a []string of types followed by their values
+// or a []interface{} of them, or only []byte values which are hashed as already packed. As many
+// values as types must be given. Any other argument returns an error naming its index and type,
+// and a type pack can't encode returns ErrUnsupportedABIType.
+func SoliditySHA3Checked(data ...interface{}) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("no values to hash")
+	}
+
+	// A leading []string is always a typed call, so a count mismatch is reported as such rather
+	// than as a []string among the packed values
+	if types, ok := data[0].([]string); ok {
 		rest := data[1:]
-		if len(rest) == len(types) {
-			return solsha3(types, data[1:]...)
-		}
-		iface, ok := data[1].([]interface{})
-		if ok {
-			return solsha3(types, iface...)
+		if len(rest) != len(types) && len(rest) == 1 {
+			if iface, ok := rest[0].([]interface{}); ok {
+				return SoliditySHA3Typed(types, iface)
+			}
 		}
+		return SoliditySHA3Typed(types, rest)
 	}
 
 	var v [][]byte
-	for _, item := range data {
-		v = append(v, item.([]byte))
+	for i, item := range data {
+		packed, ok := item.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("argument %d is %T, expected []byte or a leading []string of types", i, item)
+		}
+		v = append(v, packed)
+	}
+	return solsha3Legacy(v...), nil
+}
+
+// SoliditySHA3Typed solidity sha3 over values packed according to their Solidity types, or
+// ABI-encoded with WithEncoding(ABIEncoding). The values are hashed with legacy keccak256 unless
+// another hash is given with WithHash.
+func SoliditySHA3Typed(types []string, values []interface{}, opts ...HashOption) ([]byte, error) {
+	if newHashConfig(opts).encoding == ABIEncoding {
+		encoded, err := ABIEncode(types, values...)
+		if err != nil {
+			return nil, err
+		}
+		return hashWith(opts, encoded), nil
 	}
-	return solsha3Legacy(v...)
+	packed, err := SolidityPack(types, values...)
+	if err != nil {
+		return nil, err
+	}
+	return hashWith(opts, packed), nil
 }
 
-// solsha3 solidity sha3
-func solsha3(types []string, values ...interface{}) []byte {
+// SoliditySHA3WithPreimage solidity sha3 over values packed according to their Solidity types,
+// also returning the packed preimage hashed, to diff byte-for-byte against the contract's
+// abi.encodePacked result
+func SoliditySHA3WithPreimage(types []string, values ...interface{}) (hash []byte, preimage []byte, err error) {
+	preimage, err = SolidityPack(types, values...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Keccak256(preimage), preimage, nil
+}
 
-	var b [][]byte
+// SolidityPack packs values according to their Solidity types as abi.encodePacked and ethers'
+// solidityPack do, returning the bytes SoliditySHA3Typed hashes. A value which can't be packed as
+// its type returns an error instead of panicking.
+func SolidityPack(types []string, values ...interface{}) ([]byte, error) {
+	if err := checkTyped(types, values); err != nil {
+		return nil, err
+	}
+	packed := make([][]byte, len(types))
 	for i, typ := range types {
-		b = append(b, pack(typ, values[i], false))
+		var err error
+		if packed[i], err = safePack(typ, values[i], packValue); err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+	}
+	return bytes.Join(packed, nil), nil
+}
+
+// packValue packs a single value of typ as abi.encodePacked does
+func packValue(typ string, value interface{}) []byte {
+	return pack(typ, value, false)
+}
+
+// safePack encodes value as typ with encode, returning a panic as an error naming typ and
+// describing value, so that one malformed value can't crash the relayer. It guards the typed API
+// until pack and its helpers return errors rather than panicking.
+func safePack(typ string, value interface{}, encode func(typ string, value interface{}) []byte) (encoded []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("packing %s value %s: %w", typ, describeValue(value), e)
+			} else {
+				err = fmt.Errorf("packing %s value %s: %v", typ, describeValue(value), r)
+			}
+			encoded = nil
+		}
+	}()
+	return encode(typ, value), nil
+}
+
+// maxValueDescription is how many bytes of a value's text describeValue keeps
+const maxValueDescription = 64
+
+// describeValue describes value by its Go type and its text, truncated and quoted so that a
+// large or binary value can be logged safely
+func describeValue(value interface{}) string {
+	text := fmt.Sprintf("%v", value)
+	if len(text) > maxValueDescription {
+		text = text[:maxValueDescription] + "..."
+	}
+	return fmt.Sprintf("%T(%s)", value, strconv.QuoteToASCII(text))
+}
+
+// checkTyped checks there is a value for each type, and that pack can encode every type
+func checkTyped(types []string, values []interface{}) error {
+	if len(types) != len(values) {
+		return fmt.Errorf("%d types provided but %d values", len(types), len(values))
+	}
+	for _, typ := range types {
+		if err := checkABIType(typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrUnsupportedABIType is returned for a Solidity type pack can't encode
+var ErrUnsupportedABIType = errors.New("unsupported ABI type")
+
+// ErrBytesLength is returned for a bytesN value which isn't exactly N bytes long, rather than
+// truncating or zero-padding it
+var ErrBytesLength = errors.New("bytes value length doesn't match its type")
+
+// The type patterns are compiled once and shared: a compiled regexp is safe for concurrent use,
+// so pack and its helpers hold no other state and may be called from any number of goroutines
+var (
+	// numberTypePattern matches a Solidity integer type, capturing its signedness and optional width
+	numberTypePattern = regexp.MustCompile(`^(u?int)([0-9]*)$`)
+	// bytesTypePattern matches a fixed-size Solidity bytes type, capturing its size
+	bytesTypePattern = regexp.MustCompile(`^bytes([0-9]+)$`)
+)
+
+// checkABIType returns ErrUnsupportedABIType unless pack can encode typ: address, string, bool,
+// (u)int of 8, 16, 32, 64, 128 or 256 bits, bytes1 to bytes32, registered enums, and arrays and
+// tuples of those
+func checkABIType(typ string) error {
+	if enum, ok := lookupEnumType(typ); ok {
+		if enum == nil {
+			return fmt.Errorf("%w: unregistered %s", ErrUnsupportedABIType, typ)
+		}
+		return nil
+	}
+	if components, ok := tupleComponents(typ); ok {
+		if len(components) == 0 {
+			return fmt.Errorf("%w: empty tuple %s", ErrUnsupportedABIType, typ)
+		}
+		for _, component := range components {
+			if err := checkABIType(component); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	hash := sha3.NewLegacyKeccak256()
-	bs := concatByteSlices(b...)
+	switch typ {
+	case "address", "string", "bool":
+		return nil
+	}
 
-	hash.Write(bs)
-	return hash.Sum(nil)
+	if matches := numberTypePattern.FindStringSubmatch(typ); matches != nil {
+		switch matches[2] {
+		case "", "8", "16", "32", "64", "128", "256":
+			return nil
+		}
+	} else if matches := bytesTypePattern.FindStringSubmatch(typ); matches != nil {
+		if size, err := strconv.Atoi(matches[1]); err == nil && size > 0 && size <= 32 &&
+			strconv.Itoa(size) == matches[1] {
+			return nil
+		}
+	} else if matches := arrayTypePattern.FindStringSubmatch(typ); matches != nil {
+		return checkABIType(matches[1])
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedABIType, typ)
 }
 
 func pack(typ string, value interface{}, _isArray bool) []byte {
+	if components, ok := tupleComponents(typ); ok {
+		return packTuple(typ, components, value, _isArray)
+	}
+	if enum, ok := lookupEnumType(typ); ok {
+		if enum == nil {
+			panic(fmt.Errorf("%w: unregistered %s", ErrUnsupportedABIType, typ))
+		}
+		index, err := enum.Value(value)
+		if err != nil {
+			panic(err)
+		}
+		return pack("uint8", index, _isArray)
+	}
+
 	switch typ {
 	case "address":
 		if _isArray {
@@ -265,14 +882,14 @@ func pack(typ string, value interface{}, _isArray bool) []byte {
 		return Bool(value)
 	}
 
-	regexNumber := regexp.MustCompile(`^(u?int)([0-9]*)$`)
-	matches := regexNumber.FindAllStringSubmatch(typ, -1)
+	matches := numberTypePattern.FindAllStringSubmatch(typ, -1)
 	if len(matches) > 0 {
 		match := matches[0]
 		var err error
 		size := 256
+		signed := false
 		if len(match) > 1 {
-			//signed = match[1] == "int"
+			signed = match[1] == "int"
 		}
 		if len(match) > 2 {
 			size, err = strconv.Atoi(match[2])
@@ -286,41 +903,51 @@ func pack(typ string, value interface{}, _isArray bool) []byte {
 			panic("invalid number type " + typ)
 		}
 
+		bits := size
 		if _isArray {
 			size = 256
 		}
 
+		// Widths without their own encoder, such as uint24 or int160, pack from the 32-byte word
+		// cut down to the width, panicking if the value overflows it
 		var v []byte
-		if strings.HasPrefix(typ, "int8") {
+		switch {
+		case signed && bits == 8:
 			v = Int8(value)
-		} else if strings.HasPrefix(typ, "int16") {
+		case signed && bits == 16:
 			v = Int16(value)
-		} else if strings.HasPrefix(typ, "int32") {
+		case signed && bits == 32:
 			v = Int32(value)
-		} else if strings.HasPrefix(typ, "int64") {
+		case signed && bits == 64:
 			v = Int64(value)
-		} else if strings.HasPrefix(typ, "int128") {
+		case signed && bits == 128:
 			v = Int128(value)
-		} else if strings.HasPrefix(typ, "int256") {
+		case signed && bits == 256:
 			v = Int256(value)
-		} else if strings.HasPrefix(typ, "uint8") {
+		case signed:
+			v = bytesInteger(Int256(value), bits, true)
+		case bits == 8:
 			v = Uint8(value)
-		} else if strings.HasPrefix(typ, "uint16") {
+		case bits == 16:
 			v = Uint16(value)
-		} else if strings.HasPrefix(typ, "uint32") {
+		case bits == 32:
 			v = Uint32(value)
-		} else if strings.HasPrefix(typ, "uint128") {
-			v = Uint128(value)
-		} else if strings.HasPrefix(typ, "uint64") {
+		case bits == 64:
 			v = Uint64(value)
-		} else if strings.HasPrefix(typ, "uint256") {
+		case bits == 128:
+			v = Uint128(value)
+		case bits == 256:
 			v = Uint256(value)
+		default:
+			v = bytesInteger(Uint256(value), bits, false)
+		}
+		if signed {
+			return signExtend(v, size/8)
 		}
 		return padZeros(v, size/8)
 	}
 
-	regexBytes := regexp.MustCompile(`^bytes([0-9]+)$`)
-	matches = regexBytes.FindAllStringSubmatch(typ, -1)
+	matches = bytesTypePattern.FindAllStringSubmatch(typ, -1)
 	if len(matches) > 0 {
 		match := matches[0]
 
@@ -336,84 +963,257 @@ func pack(typ string, value interface{}, _isArray bool) []byte {
 			panic("invalid number type " + typ)
 		}
 
-		//if (bytes_1.arrayify(value).byteLength !== size) {
-		//throw new Error('invalid value for ' + type);
-		//}
+		b := fixedBytes(value)
+		if len(b) != size {
+			panic(fmt.Errorf("%w: %d bytes for %s", ErrBytesLength, len(b), typ))
+		}
 
+		// As an array element, a bytesN value is right-padded to a 32-byte word
 		if _isArray {
-			s := reflect.ValueOf(value)
-			v := s.Index(0).Bytes()
-			z := make([]byte, 64)
-			copy(z[:], v[:])
-			return z[:]
+			return common.RightPadBytes(b, 32)
 		}
+		return b
+	}
 
-		str, isString := value.(string)
-		if isString && isHex(str) {
-			s := strings.TrimPrefix(str, "0x")
-			if len(s)%2 == 1 {
-				s = "0" + s
+	matches = arrayTypePattern.FindAllStringSubmatch(typ, -1)
+	if len(matches) > 0 {
+		match := matches[0]
+
+		_ = match
+		if isArray(value) {
+			baseType := match[1]
+			k := reflect.ValueOf(value)
+			count := k.Len()
+			var err error
+			if len(match) > 1 && match[2] != "" {
+				count, err = strconv.Atoi(match[2])
+				if err != nil {
+					panic(err)
+				}
 			}
-			hexb, err := hex.DecodeString(s)
-			if err != nil {
-				panic(err)
+			if count != k.Len() {
+				panic("invalid value for " + typ)
+			}
+
+			var result [][]byte
+			for i := 0; i < k.Len(); i++ {
+				val := k.Index(i).Interface()
+
+				result = append(result, pack(baseType, val, true))
 			}
-			z := make([]byte, size)
-			copy(z[:], hexb)
-			return z
-		} else if isString {
-			s := reflect.ValueOf(value)
-			z := make([]byte, size)
-			copy(z[:], s.Bytes())
-			return z
+
+			return concatByteSlices(result...)
 		}
+		panic(fmt.Sprintf("invalid value for %s: %T is not an array", typ, value))
+	}
+	return nil
+}
+
+// arrayWords encodes each element of an array with encode, sign-extending signed elements or
+// zero-padding others to exactly one 32-byte word. An element wider than a word panics.
+func arrayWords(input interface{}, encode func(interface{}) []byte, signed bool) []byte {
+	var values []byte
+	s := reflect.ValueOf(input)
+	for i := 0; i < s.Len(); i++ {
+		element := encode(s.Index(i).Interface())
+		if len(element) > 32 {
+			panic(fmt.Sprintf("array element %d is %d bytes, overflowing a 32-byte word", i, len(element)))
+		}
+		if signed {
+			element = signExtend(element, 32)
+		} else {
+			element = padZeros(element, 32)
+		}
+		values = append(values, element...)
+	}
+	return values
+}
+
+// fixedBytes returns the bytes of a bytesN value: a 0x-prefixed hex string, or a byte slice or array
+func fixedBytes(value interface{}) []byte {
+	if str, ok := value.(string); ok {
+		if !isHex(str) {
+			panic("bytes value " + strconv.Quote(str) + " is not 0x-prefixed hex")
+		}
+		s := strings.TrimPrefix(str, "0x")
+		if len(s)%2 == 1 {
+			s = "0" + s
+		}
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			panic(err)
+		}
+		return b
+	}
+
+	v := reflect.ValueOf(value)
+	b := make([]byte, v.Len())
+	for i := range b {
+		b[i] = v.Index(i).Interface().(byte)
+	}
+	return b
+}
+
+func padZeros(value []byte, width int) []byte {
+	return common.LeftPadBytes(value, width)
+}
+
+// signExtend left-pads a two's complement value to width bytes, preserving its sign
+func signExtend(value []byte, width int) []byte {
+	if len(value) == 0 || value[0]&0x80 == 0 || len(value) >= width {
+		return padZeros(value, width)
+	}
+	extended := bytes.Repeat([]byte{0xff}, width)
+	copy(extended[width-len(value):], value)
+	return extended
+}
+
+// twosComplement encodes n as width bytes of two's complement, panicking if it overflows the
+// signed integer type named typ
+func twosComplement(n *big.Int, width int, typ string) []byte {
+	bits := uint(width * 8)
+	limit := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		panic(fmt.Sprintf("value %s overflows %s", n, typ))
+	}
+
+	v := n
+	if n.Sign() < 0 {
+		v = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), bits))
+	}
+	return common.LeftPadBytes(v.Bytes(), width)
+}
+
+// ErrWordOverflow is returned when a big-endian integer, given as bytes or packed as a uint256, is
+// longer than 32 bytes
+var ErrWordOverflow = errors.New("value longer than a 32-byte word")
+
+// WordBytesChecked left-pads a big-endian integer of at most 32 bytes to a 32-byte word,
+// returning ErrWordOverflow if it is longer
+func WordBytesChecked(b []byte) ([]byte, error) {
+	if len(b) > 32 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrWordOverflow, len(b))
+	}
+	return common.LeftPadBytes(b, 32), nil
+}
+
+// wordBytes left-pads b to a 32-byte word of typ, panicking with ErrWordOverflow if it is longer
+func wordBytes(b []byte, typ string) []byte {
+	word, err := WordBytesChecked(b)
+	if err != nil {
+		panic(fmt.Errorf("%s: %w", typ, err))
+	}
+	return word
+}
+
+// ErrIntegerOverflow is returned when an integer doesn't fit the range of its type
+var ErrIntegerOverflow = errors.New("integer overflows its type")
+
+// bytesInteger packs b, a big-endian integer as found in log data, as an integer of bits width:
+// two's complement of b's own length if signed, such as a sign-extended 32-byte word, or unsigned
+// otherwise. Panics with ErrIntegerOverflow if the value doesn't fit the width's range.
+func bytesInteger(b []byte, bits int, signed bool) []byte {
+	value := new(big.Int).SetBytes(b)
+	if signed && len(b) > 0 && b[0]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+
+	typ := "uint" + strconv.Itoa(bits)
+	fits := value.BitLen() <= bits
+	if signed {
+		typ = "int" + strconv.Itoa(bits)
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		fits = value.Cmp(limit) < 0 && value.Cmp(new(big.Int).Neg(limit)) >= 0
+	}
+	if !fits {
+		panic(fmt.Errorf("%w: 0x%x as %s", ErrIntegerOverflow, b, typ))
+	}
+
+	if value.Sign() < 0 {
+		value.Add(value, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	}
+	return common.LeftPadBytes(value.Bytes(), bits/8)
+}
 
-		s := reflect.ValueOf(value)
-		z := make([]byte, size)
-		b := make([]byte, s.Len())
-		for i := 0; i < s.Len(); i++ {
-			b[i] = s.Index(i).Interface().(byte)
-		}
-		copy(z[:], b[:])
-		return z
+// ErrAddressChecksum is returned when a mixed-case address fails EIP-55 checksum validation
+var ErrAddressChecksum = errors.New("invalid address checksum")
+
+// ErrInvalidAddress is returned when an address array element isn't a single address
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ValidateAddressChecksum verifies the EIP-55 checksum casing of a hex address. All-lowercase and
+// all-uppercase input carries no checksum and is only rejected when strict is set.
+func ValidateAddressChecksum(address string, strict bool) error {
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid hex address: %s", address)
 	}
 
-	regexArray := regexp.MustCompile(`^(.*)\[([0-9]*)\]$`)
-	matches = regexArray.FindAllStringSubmatch(typ, -1)
-	if len(matches) > 0 {
-		match := matches[0]
+	unprefixed := address
+	if len(unprefixed) == 2*common.AddressLength+2 {
+		unprefixed = unprefixed[2:]
+	}
 
-		_ = match
-		if isArray(value) {
-			baseType := match[1]
-			k := reflect.ValueOf(value)
-			count := k.Len()
-			var err error
-			if len(match) > 1 && match[2] != "" {
-				count, err = strconv.Atoi(match[2])
-				if err != nil {
-					panic(err)
-				}
-			}
-			if count != k.Len() {
-				panic("invalid value for " + typ)
-			}
+	if !strict && (unprefixed == strings.ToLower(unprefixed) || unprefixed == strings.ToUpper(unprefixed)) {
+		return nil
+	}
 
-			var result [][]byte
-			for i := 0; i < k.Len(); i++ {
-				val := k.Index(i).Interface()
+	if common.HexToAddress(unprefixed).Hex()[2:] != unprefixed {
+		return fmt.Errorf("%w: %s", ErrAddressChecksum, address)
+	}
+	return nil
+}
 
-				result = append(result, pack(baseType, val, true))
-			}
+// AddressChecked address, rejecting hex input which fails checksum validation
+func AddressChecked(input string, strict bool) ([]byte, error) {
+	if err := ValidateAddressChecksum(input, strict); err != nil {
+		return nil, err
+	}
+	return Address(input), nil
+}
 
-			return concatByteSlices(result...)
+// addressElement returns the 20-byte address an address array element represents: a
+// common.Address, a 20-byte []byte, or a hex string of at most 20 bytes. Nested slices and
+// byte slices of any other length are rejected rather than encoded as they stand.
+func addressElement(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Bytes(), nil
+	case []byte:
+		if len(v) != common.AddressLength {
+			return nil, fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidAddress, len(v), common.AddressLength)
+		}
+		return v, nil
+	case string:
+		unprefixed := v
+		if has0xPrefix(unprefixed) {
+			unprefixed = unprefixed[2:]
+		}
+		if len(unprefixed)%2 == 1 {
+			unprefixed = "0" + unprefixed
 		}
+		if decoded, err := hex.DecodeString(unprefixed); err != nil || len(decoded) > common.AddressLength {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, v)
+		}
+		return Address(v), nil
 	}
-	return nil
+	return nil, fmt.Errorf("%w: element is %T", ErrInvalidAddress, value)
 }
 
-func padZeros(value []byte, width int) []byte {
-	return common.LeftPadBytes(value, width)
+// addressElements returns the 20-byte address of each element of an address array
+func addressElements(input interface{}) ([][]byte, error) {
+	if input == nil || !isArray(input) {
+		return nil, fmt.Errorf("%w: %T is not an address array", ErrInvalidAddress, input)
+	}
+	s := reflect.ValueOf(input)
+	addresses := make([][]byte, s.Len())
+	for i := range addresses {
+		address, err := addressElement(s.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("address array element %d: %w", i, err)
+		}
+		addresses[i] = address
+	}
+	return addresses, nil
 }
 
 func Address(input interface{}) []byte {
@@ -421,11 +1221,9 @@ func Address(input interface{}) []byte {
 	case common.Address:
 		return v.Bytes()[:]
 	case string:
-		v = strings.TrimPrefix(v, "0x")
-		if v == "" || v == "0" {
-			return []byte{0}
+		if has0xPrefix(v) {
+			v = v[2:]
 		}
-
 		if len(v)%2 == 1 {
 			v = "0" + v
 		}
@@ -435,7 +1233,8 @@ func Address(input interface{}) []byte {
 			panic(err)
 		}
 
-		return decoded
+		// Short inputs, including "", "0" and "0x0", are left-padded to a full 20-byte address
+		return common.LeftPadBytes(decoded, common.AddressLength)
 	case []byte:
 		return v
 	}
@@ -474,16 +1273,21 @@ func concatByteSlices(arrays ...[]byte) []byte {
 	return result
 }
 
-// Int8 int8
+// Int8 int8. A []byte is taken as a big-endian two's complement integer, as found in log data.
+// Panics with ErrIntegerOverflow if it doesn't fit an int8.
 func Int8(input interface{}) []byte {
 	b := make([]byte, 1)
 	switch v := input.(type) {
+	case []byte:
+		return bytesInteger(v, 8, true)
 	case *big.Int:
-		b[0] = byte(int8(v.Uint64()))
+		b[0] = byte(int8(v.Int64()))
+	case json.Number:
+		return Int8(v.String())
 	case string:
 		bn := new(big.Int)
 		bn.SetString(v, 10)
-		b[0] = byte(int8(bn.Uint64()))
+		b[0] = byte(int8(bn.Int64()))
 	case uint64:
 		b[0] = byte(int8(v))
 	case uint32:
@@ -515,16 +1319,21 @@ func Int8(input interface{}) []byte {
 	return b
 }
 
-// Int16 int16
+// Int16 int16. A []byte is taken as a big-endian two's complement integer, as found in log data.
+// Panics with ErrIntegerOverflow if it doesn't fit an int16.
 func Int16(input interface{}) []byte {
 	b := make([]byte, 2)
 	switch v := input.(type) {
+	case []byte:
+		return bytesInteger(v, 16, true)
 	case *big.Int:
-		binary.BigEndian.PutUint16(b, uint16(v.Uint64()))
+		binary.BigEndian.PutUint16(b, uint16(v.Int64()))
+	case json.Number:
+		return Int16(v.String())
 	case string:
 		bn := new(big.Int)
 		bn.SetString(v, 10)
-		binary.BigEndian.PutUint16(b, uint16(bn.Uint64()))
+		binary.BigEndian.PutUint16(b, uint16(bn.Int64()))
 	case uint64:
 		binary.BigEndian.PutUint16(b, uint16(v))
 	case uint32:
@@ -556,16 +1365,21 @@ func Int16(input interface{}) []byte {
 	return b
 }
 
-// Int32 int32
+// Int32 int32. A []byte is taken as a big-endian two's complement integer, as found in log data.
+// Panics with ErrIntegerOverflow if it doesn't fit an int32.
 func Int32(input interface{}) []byte {
 	b := make([]byte, 4)
 	switch v := input.(type) {
+	case []byte:
+		return bytesInteger(v, 32, true)
 	case *big.Int:
-		binary.BigEndian.PutUint32(b, uint32(v.Uint64()))
+		binary.BigEndian.PutUint32(b, uint32(v.Int64()))
+	case json.Number:
+		return Int32(v.String())
 	case string:
 		bn := new(big.Int)
 		bn.SetString(v, 10)
-		binary.BigEndian.PutUint32(b, uint32(bn.Uint64()))
+		binary.BigEndian.PutUint32(b, uint32(bn.Int64()))
 	case uint64:
 		binary.BigEndian.PutUint32(b, uint32(v))
 	case uint32:
@@ -597,16 +1411,21 @@ func Int32(input interface{}) []byte {
 	return b
 }
 
-// Int64 int64
+// Int64 int64. A []byte is taken as a big-endian two's complement integer, as found in log data.
+// Panics with ErrIntegerOverflow if it doesn't fit an int64.
 func Int64(input interface{}) []byte {
 	b := make([]byte, 8)
 	switch v := input.(type) {
+	case []byte:
+		return bytesInteger(v, 64, true)
 	case *big.Int:
-		binary.BigEndian.PutUint64(b, v.Uint64())
+		binary.BigEndian.PutUint64(b, uint64(v.Int64()))
+	case json.Number:
+		return Int64(v.String())
 	case string:
 		bn := new(big.Int)
 		bn.SetString(v, 10)
-		binary.BigEndian.PutUint64(b, bn.Uint64())
+		binary.BigEndian.PutUint64(b, uint64(bn.Int64()))
 	case uint64:
 		binary.BigEndian.PutUint64(b, v)
 	case uint32:
@@ -638,419 +1457,280 @@ func Int64(input interface{}) []byte {
 	return b
 }
 
-// Int128 int128
+// Int128 int128, packed as 16 bytes of two's complement. A []byte is taken as a big-endian two's
+// complement integer, as found in log data. Panics if the value doesn't fit an int128.
 func Int128(input interface{}) []byte {
+	var bn *big.Int
 	switch v := input.(type) {
+	case []byte:
+		return bytesInteger(v, 128, true)
 	case *big.Int:
-		return common.LeftPadBytes(v.Bytes(), 16)
+		bn = v
+	case json.Number:
+		return Int128(v.String())
 	case string:
-		bn := new(big.Int)
+		bn = new(big.Int)
 		bn.SetString(v, 10)
-		return common.LeftPadBytes(bn.Bytes(), 16)
 	case uint64:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = new(big.Int).SetUint64(v)
 	case uint32:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(int64(v))
 	case uint16:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(int64(v))
 	case uint8:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(int64(v))
 	case uint:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = new(big.Int).SetUint64(uint64(v))
 	case int64:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(v)
 	case int32:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(int64(v))
 	case int16:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(int64(v))
 	case int8:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(int64(v))
 	case int:
-		bn := big.NewInt(int64(v))
-		return common.LeftPadBytes(bn.Bytes(), 16)
+		bn = big.NewInt(int64(v))
 	}
 
-	if isArray(input) {
-		return Int128Array(input)
+	if bn == nil {
+		if isArray(input) {
+			return Int128Array(input)
+		}
+		bn = new(big.Int)
 	}
 
-	return common.LeftPadBytes([]byte{}, 16)
+	return twosComplement(bn, 16, "int128")
 }
 
-// Uint8 uint8
-func Uint8(input interface{}) []byte {
-	b := new(bytes.Buffer)
-	switch v := input.(type) {
-	case *big.Int:
-		binary.Write(b, binary.BigEndian, uint8(v.Uint64()))
-	case string:
-		bn := new(big.Int)
-		bn.SetString(v, 10)
-		binary.Write(b, binary.BigEndian, uint8(bn.Uint64()))
-	case uint64:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case uint32:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case uint16:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case uint8:
-		binary.Write(b, binary.BigEndian, v)
-	case uint:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case int64:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case int32:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case int16:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case int8:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	case int:
-		binary.Write(b, binary.BigEndian, uint8(v))
-	default:
-		binary.Write(b, binary.BigEndian, uint8(0))
-	}
-
-	if isArray(input) {
-		return Uint8Array(input)
-	}
+// ErrNegativeUnsigned is returned for a negative value packed as an unsigned integer type, which
+// Solidity rejects rather than wrapping around to a large unsigned value
+var ErrNegativeUnsigned = errors.New("negative value for unsigned integer type")
 
-	return b.Bytes()
-}
+// ErrUnsupportedType is returned for a value of a Go type an ABI type can't be packed from, or a
+// string which isn't a decimal integer
+var ErrUnsupportedType = errors.New("unsupported value type")
 
-// Uint16 uint16
-func Uint16(input interface{}) []byte {
-	b := new(bytes.Buffer)
+// unsignedInteger returns input, a *big.Int, decimal string, json.Number or Go integer, as a
+// *big.Int, or false if it is of another type. Panics with ErrUnsupportedType if a string isn't a
+// decimal integer, and with ErrNegativeUnsigned if the value is negative.
+func unsignedInteger(input interface{}) (*big.Int, bool) {
+	var bn *big.Int
 	switch v := input.(type) {
 	case *big.Int:
-		binary.Write(b, binary.BigEndian, uint16(v.Uint64()))
+		if v == nil {
+			return nil, false
+		}
+		bn = v
+	case json.Number:
+		return unsignedInteger(v.String())
 	case string:
-		bn := new(big.Int)
-		bn.SetString(v, 10)
-		binary.Write(b, binary.BigEndian, uint16(bn.Uint64()))
+		var ok bool
+		if bn, ok = new(big.Int).SetString(v, 10); !ok {
+			panic(fmt.Errorf("%w: %q isn't a decimal integer", ErrUnsupportedType, v))
+		}
 	case uint64:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = new(big.Int).SetUint64(v)
 	case uint32:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = new(big.Int).SetUint64(uint64(v))
 	case uint16:
-		binary.Write(b, binary.BigEndian, v)
+		bn = new(big.Int).SetUint64(uint64(v))
 	case uint8:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = new(big.Int).SetUint64(uint64(v))
 	case uint:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = new(big.Int).SetUint64(uint64(v))
 	case int64:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = big.NewInt(v)
 	case int32:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = big.NewInt(int64(v))
 	case int16:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = big.NewInt(int64(v))
 	case int8:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = big.NewInt(int64(v))
 	case int:
-		binary.Write(b, binary.BigEndian, uint16(v))
+		bn = big.NewInt(int64(v))
 	default:
-		binary.Write(b, binary.BigEndian, uint16(0))
+		return nil, false
 	}
-
-	if isArray(input) {
-		return Uint16Array(input)
+	if bn.Sign() < 0 {
+		panic(fmt.Errorf("%w: %v", ErrNegativeUnsigned, input))
 	}
-
-	return b.Bytes()
+	return bn, true
 }
 
-// Uint32 uint32
-func Uint32(input interface{}) []byte {
-	b := new(bytes.Buffer)
-	switch v := input.(type) {
-	case *big.Int:
-		binary.Write(b, binary.BigEndian, uint32(v.Uint64()))
-	case string:
-		bn := new(big.Int)
-		bn.SetString(v, 10)
-		binary.Write(b, binary.BigEndian, uint32(bn.Uint64()))
-	case uint64:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case uint32:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case uint16:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case uint8:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case uint:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case int64:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case int32:
-		binary.Write(b, binary.BigEndian, v)
-	case int16:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case int8:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	case int:
-		binary.Write(b, binary.BigEndian, uint32(v))
-	default:
-		binary.Write(b, binary.BigEndian, uint32(0))
+// packUnsigned packs input as an unsigned integer of bits width, as Uint8 to Uint256 do. A []byte
+// is taken as a big-endian unsigned integer, and an array or slice is packed as an array of them
+// with packArray. Panics with ErrUnsupportedType for a value of any other type, and with
+// ErrIntegerOverflow, or ErrWordOverflow for a uint256, if the value doesn't fit.
+func packUnsigned(input interface{}, bits int, packArray func(input interface{}) []byte) []byte {
+	typ := "uint" + strconv.Itoa(bits)
+	if b, ok := input.([]byte); ok {
+		if bits == 256 {
+			return wordBytes(b, typ)
+		}
+		return bytesInteger(b, bits, false)
 	}
 
-	if isArray(input) {
-		return Uint32Array(input)
+	bn, ok := unsignedInteger(input)
+	if !ok {
+		if input != nil && isArray(input) {
+			return packArray(input)
+		}
+		panic(fmt.Errorf("%w: %T as %s", ErrUnsupportedType, input, typ))
+	}
+	if bn.BitLen() > bits {
+		overflow := ErrIntegerOverflow
+		if bits == 256 {
+			overflow = ErrWordOverflow
+		}
+		panic(fmt.Errorf("%w: %s as %s", overflow, bn, typ))
 	}
+	return common.LeftPadBytes(bn.Bytes(), bits/8)
+}
 
-	return b.Bytes()
+// Uint8 uint8, from a *big.Int, decimal string, json.Number or Go integer. A []byte is taken as a
+// big-endian unsigned integer, as found in log data. Panics with ErrNegativeUnsigned if the value
+// is negative, with ErrIntegerOverflow if it doesn't fit a uint8, and with ErrUnsupportedType for
+// a value of another type.
+func Uint8(input interface{}) []byte {
+	return packUnsigned(input, 8, Uint8Array)
 }
 
-// Uint64 uint64
-func Uint64(input interface{}) []byte {
-	b := new(bytes.Buffer)
-	switch v := input.(type) {
-	case *big.Int:
-		binary.Write(b, binary.BigEndian, v.Uint64())
-	case string:
-		bn := new(big.Int)
-		bn.SetString(v, 10)
-		binary.Write(b, binary.BigEndian, bn.Uint64())
-	case uint64:
-		binary.Write(b, binary.BigEndian, v)
-	case uint32:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case uint16:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case uint8:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case uint:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case int64:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case int32:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case int16:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case int8:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	case int:
-		binary.Write(b, binary.BigEndian, uint64(v))
-	default:
-		binary.Write(b, binary.BigEndian, uint64(0))
-	}
+// Uint16 uint16, from a *big.Int, decimal string, json.Number or Go integer. A []byte is taken as
+// a big-endian unsigned integer, as found in log data. Panics with ErrNegativeUnsigned if the
+// value is negative, with ErrIntegerOverflow if it doesn't fit a uint16, and with
+// ErrUnsupportedType for a value of another type.
+func Uint16(input interface{}) []byte {
+	return packUnsigned(input, 16, Uint16Array)
+}
 
-	if isArray(input) {
-		return Uint64Array(input)
-	}
+// Uint32 uint32, from a *big.Int, decimal string, json.Number or Go integer. A []byte is taken as
+// a big-endian unsigned integer, as found in log data. Panics with ErrNegativeUnsigned if the
+// value is negative, with ErrIntegerOverflow if it doesn't fit a uint32, and with
+// ErrUnsupportedType for a value of another type.
+func Uint32(input interface{}) []byte {
+	return packUnsigned(input, 32, Uint32Array)
+}
 
-	return b.Bytes()
+// Uint64 uint64, from a *big.Int, decimal string, json.Number or Go integer. A []byte is taken as
+// a big-endian unsigned integer, as found in log data. Panics with ErrNegativeUnsigned if the
+// value is negative, with ErrIntegerOverflow if it doesn't fit a uint64, and with
+// ErrUnsupportedType for a value of another type.
+func Uint64(input interface{}) []byte {
+	return packUnsigned(input, 64, Uint64Array)
 }
 
-// Uint128 uint128
+// Uint128 uint128, from a *big.Int, decimal string, json.Number or Go integer. A []byte is taken
+// as a big-endian unsigned integer, as found in log data. Panics with ErrNegativeUnsigned if the
+// value is negative, with ErrIntegerOverflow if it doesn't fit a uint128, and with
+// ErrUnsupportedType for a value of another type.
 func Uint128(input interface{}) []byte {
-	switch v := input.(type) {
-	case *big.Int:
-		return common.LeftPadBytes(v.Bytes(), 16)
-	case string:
-		bn := new(big.Int)
-		bn.SetString(v, 10)
-		return common.LeftPadBytes(bn.Bytes(), 16)
-	}
-
-	if isArray(input) {
-		return Uint128Array(input)
-	}
-
-	return common.LeftPadBytes([]byte(""), 16)
+	return packUnsigned(input, 128, Uint128Array)
 }
 
-// Uint256 uint256
+// Uint256 uint256, from a *big.Int, decimal string, json.Number or Go integer. A []byte is taken
+// as a big-endian unsigned integer, as found in log data, and left-padded to 32 bytes. Panics with
+// ErrNegativeUnsigned if the value is negative, with ErrWordOverflow if it is longer than 32
+// bytes, and with ErrUnsupportedType for a value of another type.
 func Uint256(input interface{}) []byte {
-	switch v := input.(type) {
-	case *big.Int:
-		return abi.U256(v)
-	case string:
-		bn := new(big.Int)
-		bn.SetString(v, 10)
-		return abi.U256(bn)
-	}
-
-	if isArray(input) {
-		return Uint256Array(input)
-	}
-
-	return common.RightPadBytes([]byte(""), 32)
+	return packUnsigned(input, 256, Uint256Array)
 }
 
 func isHex(str string) bool {
 	return strings.HasPrefix(str, "0x")
 }
 
+// has0xPrefix reports whether str begins with "0x" or "0X"
+func has0xPrefix(str string) bool {
+	return len(str) >= 2 && str[0] == '0' && (str[1] == 'x' || str[1] == 'X')
+}
+
 // solsha3Legacy solidity sha3
 func solsha3Legacy(data ...[]byte) []byte {
-	hash := sha3.NewLegacyKeccak256()
-	bs := concatByteSlices(data...)
-
-	hash.Write(bs)
-	return hash.Sum(nil)
+	return Keccak256(data...)
 }
 
-// AddressArray address
+// AddressArray address array, left-padding each address to 32 bytes as both ABI encoding and
+// abi.encodePacked do for an address[] value. See AddressArrayPacked for 20-byte elements.
 func AddressArray(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Address(val), 32)
-		values = append(values, result...)
+	values, err := AddressArrayChecked(input)
+	if err != nil {
+		panic(err)
 	}
 	return values
 }
 
-// BoolArray bool array
-func BoolArray(input interface{}) []byte {
+// AddressArrayChecked address array, returning ErrInvalidAddress for any element which isn't a
+// single address instead of panicking
+func AddressArrayChecked(input interface{}) ([]byte, error) {
+	addresses, err := addressElements(input)
+	if err != nil {
+		return nil, err
+	}
 	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Bool(val), 32)
-		values = append(values, result...)
+	for _, address := range addresses {
+		values = append(values, padZeros(address, 32)...)
 	}
-	return values
+	return values, nil
+}
+
+// BoolArray bool array, each element zero-padded to 32 bytes as abi.encode and abi.encodePacked
+// do for a bool[] value. See BoolArrayPacked for 1-byte elements.
+func BoolArray(input interface{}) []byte {
+	return arrayWords(input, Bool, false)
 }
 
 // Int8Array int8 array
 func Int8Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Int8(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Int8, true)
 }
 
 // Int16Array int16 array
 func Int16Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Int16(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Int16, true)
 }
 
 // Int32Array int32
 func Int32Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Int32(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Int32, true)
 }
 
 // Int64Array int64 array
 func Int64Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Int64(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Int64, true)
 }
 
 // Int128Array int128 array
 func Int128Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Int128(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Int128, true)
 }
 
 // Uint8Array uint8 array
 func Uint8Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Uint8(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Uint8, false)
 }
 
 // Uint16Array uint16 array
 func Uint16Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Uint16(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Uint16, false)
 }
 
 // Uint32Array uint32 array
 func Uint32Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Uint32(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Uint32, false)
 }
 
 // Uint64Array uint64 array
 func Uint64Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Uint64(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Uint64, false)
 }
 
 // Uint128Array uint128
 func Uint128Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Uint128(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Uint128, false)
 }
 
 // Uint256Array uint256 array
 func Uint256Array(input interface{}) []byte {
-	var values []byte
-	s := reflect.ValueOf(input)
-	for i := 0; i < s.Len(); i++ {
-		val := s.Index(i).Interface()
-		result := common.LeftPadBytes(Uint256(val), 32)
-		values = append(values, result...)
-	}
-	return values
+	return arrayWords(input, Uint256, false)
 }
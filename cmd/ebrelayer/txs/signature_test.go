@@ -0,0 +1,1071 @@
+package txs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	harmonybridge "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/harmonybridge"
+	ethereumbridge "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/harmony/bindings/ethereumbridge"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// EIP-55's own checksum vector, with the case of its last letter flipped to corrupt it
+const (
+	checksummedAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	corruptedAddress   = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"
+)
+
+func TestValidateAddressChecksum(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		strict  bool
+		wantErr error
+	}{
+		{"checksummed", checksummedAddress, false, nil},
+		{"checksummed strict", checksummedAddress, true, nil},
+		{"checksummed unprefixed", checksummedAddress[2:], false, nil},
+		{"lowercase", strings.ToLower(checksummedAddress), false, nil},
+		{"uppercase", "0x" + strings.ToUpper(checksummedAddress[2:]), false, nil},
+		{"lowercase strict", strings.ToLower(checksummedAddress), true, ErrAddressChecksum},
+		{"corrupted", corruptedAddress, false, ErrAddressChecksum},
+		{"corrupted strict", corruptedAddress, true, ErrAddressChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAddressChecksum(tt.address, tt.strict)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateAddressChecksum(%s, %v) = %v, want %v", tt.address, tt.strict, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := ValidateAddressChecksum("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", false); err == nil {
+		t.Fatal("a 19-byte address was accepted")
+	}
+}
+
+func TestAddressChecked(t *testing.T) {
+	address, err := AddressChecked(checksummedAddress, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(address) != 20 || address[0] != 0x5a || address[19] != 0xed {
+		t.Fatalf("AddressChecked = %x", address)
+	}
+	if _, err := AddressChecked(corruptedAddress, false); !errors.Is(err, ErrAddressChecksum) {
+		t.Fatalf("AddressChecked(corrupted) = %v, want ErrAddressChecksum", err)
+	}
+}
+
+func TestAddressZero(t *testing.T) {
+	zero := make([]byte, common.AddressLength)
+	for _, input := range []string{"", "0", "0x", "0x0", "0x" + strings.Repeat("0", 40)} {
+		if address := Address(input); !bytes.Equal(address, zero) {
+			t.Fatalf("Address(%q) = %x, want the 20-byte zero address", input, address)
+		}
+	}
+	if address := Address(common.Address{}); !bytes.Equal(address, zero) {
+		t.Fatalf("Address(common.Address{}) = %x, want the 20-byte zero address", address)
+	}
+	// Short, non-zero inputs are left-padded too
+	if address := Address("0xff"); len(address) != common.AddressLength || address[19] != 0xff {
+		t.Fatalf("Address(\"0xff\") = %x", address)
+	}
+}
+
+func TestParseTokenMappingChecksum(t *testing.T) {
+	lowercase := strings.ToLower(checksummedAddress)
+	if _, _, err := ParseTokenMapping(checksummedAddress + "=" + lowercase); err != nil {
+		t.Fatalf("ParseTokenMapping of valid addresses = %v", err)
+	}
+	if _, _, err := ParseTokenMapping(lowercase + "=" + corruptedAddress); !errors.Is(err, ErrAddressChecksum) {
+		t.Fatalf("ParseTokenMapping with a corrupted checksum = %v, want ErrAddressChecksum", err)
+	}
+}
+
+// testPrivateKeyHex is a throwaway secp256k1 key for tests
+const testPrivateKeyHex = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+// testKey returns the private key of testPrivateKeyHex
+func testKey(t testing.TB) *ecdsa.PrivateKey {
+	key, err := crypto.HexToECDSA(testPrivateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// skipEnvFile marks the .env file as loaded, so tests take keys from the process environment alone
+func skipEnvFile() {
+	envOnce.Do(func() {})
+}
+
+// setEnv sets an environment variable, returning the function restoring its previous value
+func setEnv(t *testing.T, key, value string) func() {
+	previous, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+// writeKeyFile writes contents to a key file in a temporary directory, returning its path and
+// the function removing it
+func writeKeyFile(t *testing.T, contents string) (string, func()) {
+	dir, err := ioutil.TempDir("", "keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadPrivateKeyFromEnvInlineHex(t *testing.T) {
+	skipEnvFile()
+	defer setEnv(t, "TEST_PRIVATE_KEY", testPrivateKeyHex)()
+
+	key, err := LoadPrivateKeyFromEnv("TEST_PRIVATE_KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(crypto.FromECDSA(key)); got != testPrivateKeyHex {
+		t.Fatalf("loaded key %s, want %s", got, testPrivateKeyHex)
+	}
+}
+
+func TestLoadPrivateKeyFromEnvFile(t *testing.T) {
+	skipEnvFile()
+	// Editors and secret mounts commonly leave a trailing newline
+	path, cleanup := writeKeyFile(t, testPrivateKeyHex+"\n")
+	defer cleanup()
+
+	for _, tt := range []struct {
+		name, env, value string
+	}{
+		{"_FILE variable", "TEST_PRIVATE_KEY" + KeyFileEnvSuffix, path},
+		{"path in the key variable", "TEST_PRIVATE_KEY", path},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setEnv(t, tt.env, tt.value)()
+
+			key, err := LoadPrivateKeyFromEnv("TEST_PRIVATE_KEY")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(crypto.FromECDSA(key)); got != testPrivateKeyHex {
+				t.Fatalf("loaded key %s, want %s", got, testPrivateKeyHex)
+			}
+		})
+	}
+}
+
+func TestLoadPrivateKeyFromEnvErrors(t *testing.T) {
+	skipEnvFile()
+	if _, err := LoadPrivateKeyFromEnv("TEST_UNSET_PRIVATE_KEY"); !errors.Is(err, ErrMissingPrivateKey) {
+		t.Fatalf("unset key = %v, want ErrMissingPrivateKey", err)
+	}
+
+	defer setEnv(t, "TEST_PRIVATE_KEY_FILE", filepath.Join(os.TempDir(), "no-such-key-file"))()
+	if _, err := LoadPrivateKeyFromEnv("TEST_PRIVATE_KEY"); err == nil || !os.IsNotExist(errors.Unwrap(err)) {
+		t.Fatalf("missing key file = %v, want the file's not-exist error", err)
+	}
+}
+
+func TestValidatePrivateKey(t *testing.T) {
+	key := testKey(t)
+	if err := ValidatePrivateKey(key); err != nil {
+		t.Fatalf("ValidatePrivateKey of a valid key = %v", err)
+	}
+
+	n := crypto.S256().Params().N
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mismatched := *key
+	mismatched.D = big.NewInt(2)
+	tests := []struct {
+		name string
+		key  *ecdsa.PrivateKey
+	}{
+		{"zero", &ecdsa.PrivateKey{PublicKey: key.PublicKey, D: big.NewInt(0)}},
+		{"curve order", &ecdsa.PrivateKey{PublicKey: key.PublicKey, D: new(big.Int).Set(n)}},
+		{"above curve order", &ecdsa.PrivateKey{PublicKey: key.PublicKey, D: new(big.Int).Add(n, big.NewInt(1))}},
+		{"negative", &ecdsa.PrivateKey{PublicKey: key.PublicKey, D: big.NewInt(-1)}},
+		{"other curve", p256},
+		{"mismatched public key", &mismatched},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePrivateKey(tt.key); !errors.Is(err, ErrInvalidPrivateKey) {
+				t.Fatalf("ValidatePrivateKey = %v, want ErrInvalidPrivateKey", err)
+			}
+		})
+	}
+
+	// Keys loaded from the environment are rejected before they can sign
+	skipEnvFile()
+	for _, value := range []string{strings.Repeat("0", 64), hex.EncodeToString(math.PaddedBigBytes(n, 32))} {
+		restore := setEnv(t, "TEST_PRIVATE_KEY", value)
+		_, err := LoadPrivateKeyFromEnv("TEST_PRIVATE_KEY")
+		restore()
+		if err == nil {
+			t.Fatalf("loaded the invalid key %s", value)
+		}
+	}
+}
+
+// Harmony's documented example address, in both its forms
+const (
+	testHarmonyAddress = "one1pdv9lrdwl0rg5vglh4xtyrv3wjk3wsqket7zxy"
+	testHarmonyHex     = "0x0B585F8DaEfBC68a311FbD4cB20d9174aD174016"
+)
+
+func TestLoadHarmonySender(t *testing.T) {
+	key := testKey(t)
+	sender, err := LoadSender(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	harmony, err := LoadHarmonySender(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender.Hex() != "0x71562b71999873DB5b286dF957af199Ec94617F7" || harmony != "one1w9tzkuvenpeakkegdhu40tcenmy5v9lhjselq6" {
+		t.Fatalf("derived %s and %s from the test key", sender.Hex(), harmony)
+	}
+	// Both forms are the same 20 bytes
+	if decoded, err := types.FromBech32(harmony); err != nil || decoded != sender {
+		t.Fatalf("FromBech32(%s) = %s, %v, want %s", harmony, decoded.Hex(), err, sender.Hex())
+	}
+
+	if _, err := LoadHarmonySender(nil); !errors.Is(err, ErrMissingPrivateKey) {
+		t.Fatalf("LoadHarmonySender(nil) = %v, want ErrMissingPrivateKey", err)
+	}
+}
+
+func TestHmyGenerateClaimMessageBech32RoundTrip(t *testing.T) {
+	receiver, err := types.FromBech32(testHarmonyAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receiver != common.HexToAddress(testHarmonyHex) {
+		t.Fatalf("FromBech32(%s) = %s, want %s", testHarmonyAddress, receiver.Hex(), testHarmonyHex)
+	}
+	event := types.HmyLogNewUnlockClaimEvent{
+		UnlockID:        big.NewInt(1),
+		EthereumSender:  common.HexToAddress(checksummedAddress),
+		HarmonyReceiver: receiver,
+		TokenAddress:    common.HexToAddress("0x4"),
+		Amount:          big.NewInt(100),
+	}
+
+	_, preimage, err := ClaimMessagePreimage(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The receiver follows the 32-byte unlock ID and the 20-byte sender
+	packed := common.BytesToAddress(preimage[52:72])
+	if got := types.ToBech32(packed); got != testHarmonyAddress {
+		t.Fatalf("receiver packed as %s (%s), want %s", got, packed.Hex(), testHarmonyAddress)
+	}
+	if !bytes.Equal(preimage[32:52], common.HexToAddress(checksummedAddress).Bytes()) {
+		t.Fatalf("sender packed as %x, want %s", preimage[32:52], checksummedAddress)
+	}
+	if message, err := HmyGenerateClaimMessage(event); err != nil || !bytes.Equal(message, crypto.Keccak256(preimage)) {
+		t.Fatalf("HmyGenerateClaimMessage = %x, want the hash of its preimage", message)
+	}
+}
+
+// goldenClaim is a claim of 1.5 USDT, with its message as the contracts compute it:
+// keccak256(abi.encodePacked(uint256 unlockID, address sender, address recipient, address token, uint256 amount))
+var goldenClaim = struct {
+	unlockID                 *big.Int
+	sender, recipient, token common.Address
+	amount                   *big.Int
+	message                  string
+}{
+	unlockID:  big.NewInt(42),
+	sender:    common.HexToAddress(testHarmonyHex),
+	recipient: common.HexToAddress(checksummedAddress),
+	token:     common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+	amount:    big.NewInt(1500000000000000000),
+	message:   "6b4b60580b5a8f3483953e1e4bd72b5ca7dfd36d3da1f5d69a22a14dc588f063",
+}
+
+func TestEthGenerateClaimMessageGolden(t *testing.T) {
+	// Lay out the contract's abi.encodePacked by hand: 32-byte words for the uint256s and
+	// the raw 20 bytes of each address
+	var preimage []byte
+	preimage = append(preimage, common.LeftPadBytes(goldenClaim.unlockID.Bytes(), 32)...)
+	preimage = append(preimage, goldenClaim.sender.Bytes()...)
+	preimage = append(preimage, goldenClaim.recipient.Bytes()...)
+	preimage = append(preimage, goldenClaim.token.Bytes()...)
+	preimage = append(preimage, common.LeftPadBytes(goldenClaim.amount.Bytes(), 32)...)
+	if want := hex.EncodeToString(crypto.Keccak256(preimage)); want != goldenClaim.message {
+		t.Fatalf("hand-packed claim hashes to %s, want the golden %s", want, goldenClaim.message)
+	}
+
+	if message := hex.EncodeToString(ethClaimMessage(t, goldenEthEvent())); message != goldenClaim.message {
+		t.Fatalf("EthGenerateClaimMessage = %s, want %s", message, goldenClaim.message)
+	}
+	hmyEvent := types.HmyLogNewUnlockClaimEvent{
+		UnlockID:        goldenClaim.unlockID,
+		EthereumSender:  goldenClaim.sender,
+		HarmonyReceiver: goldenClaim.recipient,
+		TokenAddress:    goldenClaim.token,
+		Amount:          goldenClaim.amount,
+	}
+	message, err := HmyGenerateClaimMessage(hmyEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(message) != goldenClaim.message {
+		t.Fatalf("HmyGenerateClaimMessage = %x, want %s", message, goldenClaim.message)
+	}
+}
+
+// TestClaimMessageLayoutMatchesContracts checks ClaimMessageLayout against the fields of the
+// bridges' unlock claim events, which carry the claim the contracts hash, less the validator
+func TestClaimMessageLayoutMatchesContracts(t *testing.T) {
+	for _, tt := range []struct {
+		abiJSON, event string
+	}{
+		{harmonybridge.HarmonyBridgeABI, "EthLogNewUnlockClaim"},
+		{ethereumbridge.EthereumBridgeABI, "HmyLogNewUnlockClaim"},
+	} {
+		t.Run(tt.event, func(t *testing.T) {
+			contractABI, err := abi.JSON(strings.NewReader(tt.abiJSON))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var layout []string
+			for _, input := range contractABI.Events[tt.event].Inputs {
+				if input.Name != "_validatorAddress" {
+					layout = append(layout, input.Type.String())
+				}
+			}
+			if strings.Join(layout, ",") != strings.Join(ClaimMessageLayout, ",") {
+				t.Fatalf("%s claim fields are (%s), ClaimMessageLayout is (%s)", tt.event,
+					strings.Join(layout, ","), strings.Join(ClaimMessageLayout, ","))
+			}
+		})
+	}
+}
+
+func TestClaimMessageValuesOrder(t *testing.T) {
+	event := types.EthLogNewUnlockClaimEvent{
+		UnlockID:         goldenClaim.unlockID,
+		HarmonySender:    goldenClaim.sender,
+		EthereumReceiver: goldenClaim.recipient,
+		TokenAddress:     goldenClaim.token,
+		Amount:           goldenClaim.amount,
+	}
+	values := ClaimMessageValues(event)
+	want := []interface{}{goldenClaim.unlockID, goldenClaim.sender, goldenClaim.recipient, goldenClaim.token,
+		goldenClaim.amount}
+	if len(values) != len(ClaimMessageLayout) {
+		t.Fatalf("%d claim values for a %d-field layout", len(values), len(ClaimMessageLayout))
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Fatalf("claim value %d = %v, want %v", i, values[i], want[i])
+		}
+	}
+}
+
+func TestStringRepresentations(t *testing.T) {
+	txHash := common.HexToHash("0xab")
+	address := common.HexToAddress(checksummedAddress)
+	tests := []struct {
+		name  string
+		input interface{}
+		want  []byte
+	}{
+		{"string", "bridge", []byte("bridge")},
+		{"bytes", []byte{0x01, 0x02}, []byte{0x01, 0x02}},
+		// Hashes and addresses pack as their raw bytes, never their hex text
+		{"hash", txHash, txHash.Bytes()},
+		{"address", address, address.Bytes()},
+		// Other Stringers pack as their text
+		{"big.Int", big.NewInt(-1500), []byte("-1500")},
+		{"array", []string{"a", "bc"}, []byte("abc")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := String(tt.input); !bytes.Equal(got, tt.want) {
+				t.Fatalf("String(%v) = %x, want %x", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	hash, err := SoliditySHA3Typed([]string{"string"}, []interface{}{txHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hash, crypto.Keccak256(txHash.Bytes())) {
+		t.Fatalf("hash of a packed common.Hash = %x, want the hash of its bytes", hash)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	key := testKey(t)
+	hash := common.Hex2Bytes(goldenClaim.message)
+	limits := NewAmountLimits(big.NewInt(1))
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"sign without a key", func() error { _, err := SignClaim(hash, nil); return err }, ErrMissingPrivateKey},
+		{"sender without a key", func() error { _, err := LoadSender(nil); return err }, ErrMissingPrivateKey},
+		{"sign a short message", func() error { _, err := SignClaim(hash[:31], key); return err }, ErrInvalidMessageLength},
+		{"recover from a long hash", func() error {
+			_, err := RecoverSigner(append(hash, 0), make([]byte, 65))
+			return err
+		}, ErrInvalidMessageLength},
+		{"hash an unsupported type", func() error {
+			_, err := SoliditySHA3Typed([]string{"uint24"}, []interface{}{big.NewInt(1)})
+			return err
+		}, ErrUnsupportedABIType},
+		{"pack an unsupported array", func() error { _, err := PackedArray("fixed128x18[]", []uint8{1}); return err }, ErrUnsupportedABIType},
+		{"corrupted checksum", func() error { return ValidateAddressChecksum(corruptedAddress, false) }, ErrAddressChecksum},
+		{"amount above the limit", func() error { return limits.Check(goldenClaim.token, big.NewInt(2)) }, ErrAmountExceedsLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSoliditySHA3TypeValueCountMismatch(t *testing.T) {
+	types := []string{"uint256", "address"}
+	tests := []struct {
+		name string
+		call func() ([]byte, error)
+		want string
+	}{
+		{"too few values", func() ([]byte, error) { return SoliditySHA3Checked(types, goldenClaim.unlockID) }, "2 types provided but 1 values"},
+		{"no values", func() ([]byte, error) { return SoliditySHA3Checked(types) }, "2 types provided but 0 values"},
+		{"too many values", func() ([]byte, error) {
+			return SoliditySHA3Checked(types, goldenClaim.unlockID, goldenClaim.token, goldenClaim.amount)
+		}, "2 types provided but 3 values"},
+		{"short value slice", func() ([]byte, error) {
+			return SoliditySHA3Checked(types, []interface{}{goldenClaim.unlockID})
+		}, "2 types provided but 1 values"},
+		{"typed", func() ([]byte, error) { return SoliditySHA3Typed(types, []interface{}{goldenClaim.unlockID}) }, "2 types provided but 1 values"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.call(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// Matching counts hash as before, whether the values are spread or given as a slice
+	want, err := SoliditySHA3Typed(types, []interface{}{goldenClaim.unlockID, goldenClaim.token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range [][]interface{}{
+		{types, goldenClaim.unlockID, goldenClaim.token},
+		{types, []interface{}{goldenClaim.unlockID, goldenClaim.token}},
+	} {
+		if hash, err := SoliditySHA3Checked(data...); err != nil || !bytes.Equal(hash, want) {
+			t.Fatalf("SoliditySHA3Checked = %x, %v, want %x", hash, err, want)
+		}
+	}
+}
+
+func TestSoliditySHA3LegacyInput(t *testing.T) {
+	hash, err := SoliditySHA3Checked([]byte("ab"), []byte("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hash, crypto.Keccak256([]byte("abc"))) {
+		t.Fatalf("legacy hash = %x, want the hash of the concatenated values", hash)
+	}
+
+	if _, err := SoliditySHA3Checked([]byte("ab"), "c"); err == nil || !strings.Contains(err.Error(), "argument 1 is string") {
+		t.Fatalf("SoliditySHA3Checked with a string argument = %v, want an error naming argument 1 and its type", err)
+	}
+	if _, err := SoliditySHA3Checked(); err == nil {
+		t.Fatal("SoliditySHA3Checked of nothing succeeded")
+	}
+
+	// The unchecked form logs the error rather than panicking
+	recorder, restore := useRecordingLogger()
+	defer restore()
+	if hash := SoliditySHA3(big.NewInt(1)); hash != nil {
+		t.Fatalf("SoliditySHA3 with a *big.Int argument = %x, want nil", hash)
+	}
+	if len(recorder.entries) != 1 || !strings.Contains(fmt.Sprint(recorder.entries[0].value("err")), "argument 0 is *big.Int") {
+		t.Fatalf("logged %+v, want the invalid argument", recorder.entries)
+	}
+}
+
+func TestSignClaimDeterministic(t *testing.T) {
+	key := testKey(t)
+	event := testClaimEvents(1)[0]
+	msg := PrefixMsg(ethClaimMessage(t, event))
+
+	first, err := SignClaim(msg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SignClaim(msg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("signing the same claim twice gave %x and %x", first, second)
+	}
+	viaSigner, err := NewKeySigner(key).Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, viaSigner) {
+		t.Fatalf("KeySigner signed the claim as %x, want %x", viaSigner, first)
+	}
+
+	signer, err := RecoverSigner(msg, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); signer != want {
+		t.Fatalf("RecoverSigner = %s, want %s", signer.Hex(), want.Hex())
+	}
+}
+
+func TestIntegerPackersAcceptJSONNumbers(t *testing.T) {
+	tests := []struct {
+		name   string
+		pack   func(interface{}) []byte
+		number json.Number
+		want   *big.Int
+	}{
+		{"int8", Int8, "-0012", big.NewInt(-12)},
+		{"int16", Int16, "0300", big.NewInt(300)},
+		{"int32", Int32, "-70000", big.NewInt(-70000)},
+		{"int64", Int64, "0000000042", big.NewInt(42)},
+		{"int128", Int128, "-1", big.NewInt(-1)},
+		{"int256", Int256, "-0007", big.NewInt(-7)},
+		{"uint8", Uint8, "0255", big.NewInt(255)},
+		{"uint16", Uint16, "00001", big.NewInt(1)},
+		{"uint32", Uint32, "4294967295", big.NewInt(4294967295)},
+		{"uint64", Uint64, "018", big.NewInt(18)},
+		{"uint128", Uint128, "0", big.NewInt(0)},
+		{"uint256", Uint256, "01500000000000000000", goldenClaim.amount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.pack(tt.want)
+			if got := tt.pack(tt.number); !bytes.Equal(got, want) {
+				t.Fatalf("packing json.Number %q = %x, want %x", tt.number, got, want)
+			}
+			// Leading zeros are kept by json.Number but not read as octal
+			if got := tt.pack(string(tt.number)); !bytes.Equal(got, want) {
+				t.Fatalf("packing %q = %x, want %x", tt.number, got, want)
+			}
+		})
+	}
+
+	// Numbers decoded from JSON with UseNumber hash as their integer values
+	decoder := json.NewDecoder(strings.NewReader(`{"unlockID": 42, "amount": 1500000000000000000}`))
+	decoder.UseNumber()
+	var claim map[string]interface{}
+	if err := decoder.Decode(&claim); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := SoliditySHA3Typed([]string{"uint256", "uint256"}, []interface{}{claim["unlockID"], claim["amount"]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := SoliditySHA3Typed([]string{"uint256", "uint256"}, []interface{}{goldenClaim.unlockID, goldenClaim.amount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hash, want) {
+		t.Fatalf("hash of decoded json.Numbers = %x, want %x", hash, want)
+	}
+}
+
+func TestPrefixMsgNRecovery(t *testing.T) {
+	key := testKey(t)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	claim := ethClaimMessage(t, testClaimEvents(1)[0])
+
+	tests := []struct {
+		name   string
+		digest []byte
+	}{
+		{"PrefixMsg", PrefixMsg(claim)},
+		{"PrefixMsgN 32 bytes", PrefixMsgN(claim)},
+		{"PrefixMsgN empty", PrefixMsgN(nil)},
+		{"PrefixMsgN 5 bytes", PrefixMsgN([]byte("claim"))},
+		{"PrefixMsgN 100 bytes", PrefixMsgN(bytes.Repeat([]byte{0xab}, 100))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := SignClaim(tt.digest, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signer, err := RecoverSigner(tt.digest, sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signer != address {
+				t.Fatalf("RecoverSigner = %s, want %s", signer.Hex(), address.Hex())
+			}
+		})
+	}
+
+	if !bytes.Equal(PrefixMsgN(claim), PrefixMsg(claim)) {
+		t.Fatal("PrefixMsgN of a 32-byte message differs from PrefixMsg")
+	}
+	// go-ethereum's own personal_sign hash covers the lengths PrefixMsg doesn't
+	for _, msg := range [][]byte{nil, []byte("claim"), claim, bytes.Repeat([]byte{0xab}, 100)} {
+		if got, want := PrefixMsgN(msg), accounts.TextHash(msg); !bytes.Equal(got, want) {
+			t.Fatalf("PrefixMsgN of %d bytes = %x, want %x", len(msg), got, want)
+		}
+	}
+}
+
+// goldenEthEvent returns goldenClaim as an Ethereum UnlockClaim event
+func goldenEthEvent() types.EthLogNewUnlockClaimEvent {
+	return types.EthLogNewUnlockClaimEvent{
+		UnlockID:         goldenClaim.unlockID,
+		HarmonySender:    goldenClaim.sender,
+		EthereumReceiver: goldenClaim.recipient,
+		TokenAddress:     goldenClaim.token,
+		Amount:           goldenClaim.amount,
+	}
+}
+
+// ethClaimMessage returns EthGenerateClaimMessage's message for event, failing t on an error
+func ethClaimMessage(t *testing.T, event types.EthLogNewUnlockClaimEvent, chainID ...*big.Int) []byte {
+	t.Helper()
+	message, err := EthGenerateClaimMessage(event, chainID...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+func TestGenerateClaimMessageErrors(t *testing.T) {
+	defer func(tokens *TokenRegistry) { Tokens = tokens }(Tokens)
+	Tokens = NewTokenRegistry()
+	Tokens.Register(goldenClaim.token, 18, 6)
+
+	// 1.5 tokens plus a wei can't be scaled down to 6 decimals
+	event := goldenEthEvent()
+	event.Amount = new(big.Int).Add(goldenClaim.amount, big.NewInt(1))
+	if _, err := EthGenerateClaimMessage(event); !errors.Is(err, ErrPrecisionLoss) {
+		t.Fatalf("EthGenerateClaimMessage of an inexact amount = %v, want ErrPrecisionLoss", err)
+	}
+	if _, err := GenerateClaimMessage(event); !errors.Is(err, ErrPrecisionLoss) {
+		t.Fatalf("GenerateClaimMessage of an inexact amount = %v, want ErrPrecisionLoss", err)
+	}
+	hmyEvent := types.HmyLogNewUnlockClaimEvent{UnlockID: event.UnlockID, EthereumSender: event.HarmonySender,
+		HarmonyReceiver: event.EthereumReceiver, TokenAddress: event.TokenAddress, Amount: event.Amount}
+	if _, err := HmyGenerateClaimMessage(hmyEvent); !errors.Is(err, ErrPrecisionLoss) {
+		t.Fatalf("HmyGenerateClaimMessage of an inexact amount = %v, want ErrPrecisionLoss", err)
+	}
+}
+
+func TestClaimMessageChainID(t *testing.T) {
+	event := goldenEthEvent()
+
+	// Without a chain ID the message is unchanged, as deployed contracts compute it
+	unbound, err := ClaimMessageForChain(event, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(unbound) != goldenClaim.message {
+		t.Fatalf("ClaimMessageForChain(nil) = %x, want the golden %s", unbound, goldenClaim.message)
+	}
+
+	messages := make(map[string]*big.Int)
+	for _, chainID := range []*big.Int{big.NewInt(1), big.NewInt(5), big.NewInt(1666600000), big.NewInt(1666700000)} {
+		message, err := ClaimMessageForChain(event, chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(message, unbound) {
+			t.Fatalf("message bound to chain %s equals the unbound message", chainID)
+		}
+		if other, ok := messages[string(message)]; ok {
+			t.Fatalf("chains %s and %s produce the same message %x", other, chainID, message)
+		}
+		messages[string(message)] = chainID
+
+		// The chain ID is appended to the packed claim as a uint256
+		_, preimage, err := ClaimMessagePreimage(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := crypto.Keccak256(preimage, common.LeftPadBytes(chainID.Bytes(), 32))
+		if !bytes.Equal(message, want) {
+			t.Fatalf("message bound to chain %s = %x, want %x", chainID, message, want)
+		}
+	}
+}
+
+func TestClaimMessagePreimage(t *testing.T) {
+	message, preimage, err := ClaimMessagePreimage(goldenEthEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// uint256 unlock ID, three 20-byte addresses, then the uint256 amount, back to back
+	if len(preimage) != 32+3*common.AddressLength+32 {
+		t.Fatalf("preimage is %d bytes, want 124", len(preimage))
+	}
+	want := strings.Join([]string{
+		"000000000000000000000000000000000000000000000000000000000000002a",
+		"0b585f8daefbc68a311fbd4cb20d9174ad174016",
+		"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"dac17f958d2ee523a2206206994597c13d831ec7",
+		"00000000000000000000000000000000000000000000000014d1120d7b160000",
+	}, "")
+	if got := hex.EncodeToString(preimage); got != want {
+		t.Fatalf("preimage = %s, want %s", got, want)
+	}
+	if hex.EncodeToString(message) != goldenClaim.message || !bytes.Equal(message, crypto.Keccak256(preimage)) {
+		t.Fatalf("message = %x, want the golden %s hashing the preimage", message, goldenClaim.message)
+	}
+
+	hash, typedPreimage, err := SoliditySHA3WithPreimage([]string{"uint8", "string"}, uint8(1), "ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(typedPreimage, []byte{0x01, 'a', 'b'}) || !bytes.Equal(hash, crypto.Keccak256(typedPreimage)) {
+		t.Fatalf("SoliditySHA3WithPreimage = %x, %x", hash, typedPreimage)
+	}
+}
+
+func TestEthClaimChainIDOptIn(t *testing.T) {
+	event := goldenEthEvent()
+	defer func(chainID *big.Int) { EthClaimChainID = chainID }(EthClaimChainID)
+
+	EthClaimChainID = nil
+	if message := hex.EncodeToString(ethClaimMessage(t, event)); message != goldenClaim.message {
+		t.Fatalf("EthGenerateClaimMessage without a chain ID = %s, want the golden %s", message, goldenClaim.message)
+	}
+
+	EthClaimChainID = big.NewInt(1)
+	bound, err := ClaimMessageForChain(event, EthClaimChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message := ethClaimMessage(t, event); !bytes.Equal(message, bound) {
+		t.Fatalf("EthGenerateClaimMessage = %x, want the message bound to EthClaimChainID %x", message, bound)
+	}
+	// An explicit chain ID takes precedence
+	override, err := ClaimMessageForChain(event, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message := ethClaimMessage(t, event, big.NewInt(5)); !bytes.Equal(message, override) {
+		t.Fatalf("EthGenerateClaimMessage(5) = %x, want %x", message, override)
+	}
+}
+
+func TestClaimTxHashOptIn(t *testing.T) {
+	defer func(eth, hmy bool) { EthClaimTxHash, HmyClaimTxHash = eth, hmy }(EthClaimTxHash, HmyClaimTxHash)
+	first, second := goldenEthEvent(), goldenEthEvent()
+	first.TxHash, second.TxHash = common.HexToHash("0x1"), common.HexToHash("0x2")
+
+	// Off by default, the tx hash leaves the deployed layout unchanged
+	EthClaimTxHash, HmyClaimTxHash = false, false
+	for _, event := range []types.EthLogNewUnlockClaimEvent{first, second} {
+		if message := hex.EncodeToString(ethClaimMessage(t, event)); message != goldenClaim.message {
+			t.Fatalf("message of tx %s = %s, want the golden %s", event.TxHash.Hex(), message, goldenClaim.message)
+		}
+	}
+
+	_, unbound, err := ClaimMessagePreimage(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	EthClaimTxHash = true
+	messages := make(map[string]bool)
+	for _, event := range []types.EthLogNewUnlockClaimEvent{first, second} {
+		message, preimage, err := ClaimMessagePreimage(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The hash trails the packed claim as a bytes32
+		if want := append(append([]byte{}, unbound...), event.TxHash[:]...); !bytes.Equal(preimage, want) {
+			t.Fatalf("preimage of tx %s = %x, want the claim followed by the hash", event.TxHash.Hex(), preimage)
+		}
+		if !bytes.Equal(message, ethClaimMessage(t, event)) || !bytes.Equal(message, crypto.Keccak256(preimage)) {
+			t.Fatalf("message of tx %s = %x, inconsistent with its preimage", event.TxHash.Hex(), message)
+		}
+		fields, err := EventClaimFields(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash, err := fields.Hash(); err != nil || !bytes.Equal(hash, message) {
+			t.Fatalf("EventClaimFields hash = %x, %v, want the claim message %x", hash, err, message)
+		}
+		messages[string(message)] = true
+	}
+	if len(messages) != 2 {
+		t.Fatal("claims differing only in their tx hash produce the same message")
+	}
+
+	// Harmony claims keep their layout while only Ethereum's binds the hash
+	hmyEvent := types.HmyLogNewUnlockClaimEvent{UnlockID: goldenClaim.unlockID, EthereumSender: goldenClaim.sender,
+		HarmonyReceiver: goldenClaim.recipient, TokenAddress: goldenClaim.token, Amount: goldenClaim.amount, TxHash: first.TxHash}
+	if message, err := ClaimMessage(hmyEvent); err != nil || hex.EncodeToString(message) != goldenClaim.message {
+		t.Fatalf("Harmony claim message = %x, %v, want the golden %s", message, err, goldenClaim.message)
+	}
+}
+
+func TestPrefixMsgIntendedValidatorRecovery(t *testing.T) {
+	key := testKey(t)
+	validator := common.HexToAddress(checksummedAddress)
+	message := ethClaimMessage(t, goldenEthEvent())
+
+	// EIP-191 version 0x00: 0x19, the version byte, the validating contract, then the data
+	preimage := append(append([]byte{0x19, 0x00}, validator.Bytes()...), message...)
+	digest := PrefixMsgIntendedValidator(message, validator)
+	if !bytes.Equal(digest, crypto.Keccak256(preimage)) {
+		t.Fatalf("PrefixMsgIntendedValidator = %x, want the hash of %x", digest, preimage)
+	}
+	if other := PrefixMsgIntendedValidator(message, common.HexToAddress(testHarmonyHex)); bytes.Equal(other, digest) {
+		t.Fatal("digests for different validators are equal")
+	}
+	if bytes.Equal(digest, PrefixMsg(message)) {
+		t.Fatal("intended validator digest equals the eth_sign digest")
+	}
+
+	sig, err := SignClaim(digest, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := RecoverSigner(digest, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); signer != want {
+		t.Fatalf("RecoverSigner = %s, want %s", signer.Hex(), want.Hex())
+	}
+}
+
+func TestSignClaimsBatchIntendedValidator(t *testing.T) {
+	contract := common.HexToAddress(checksummedAddress)
+	defer func(schemes *SigningSchemeRegistry) { SigningSchemes = schemes }(SigningSchemes)
+	SigningSchemes = NewSigningSchemeRegistry(SigningConfig{})
+	SigningSchemes.Register(contract, SigningConfig{Scheme: IntendedValidator})
+
+	key := testKey(t)
+	signed, err := SignClaimsBatch(NewKeySigner(key), []types.EthLogNewUnlockClaimEvent{goldenEthEvent()}, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The contract recovers the signer through the 0x00-prefixed hash of the claim message
+	signer, err := RecoverSigner(PrefixMsgIntendedValidator(signed[0].Message[:], contract), signed[0].Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); signer != want {
+		t.Fatalf("RecoverSigner = %s, want %s", signer.Hex(), want.Hex())
+	}
+}
+
+func TestCompactSignatureRoundTrip(t *testing.T) {
+	key := testKey(t)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	// Enough messages that both recovery IDs are exercised
+	recoveryIDs := make(map[byte]bool)
+	for _, event := range testClaimEvents(16) {
+		msg := PrefixMsg(ethClaimMessage(t, event))
+		expanded, err := SignClaim(msg, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compact, err := SignClaimCompact(msg, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(compact) != CompactSignatureLength || !bytes.Equal(compact, expanded[:CompactSignatureLength]) {
+			t.Fatalf("compact signature %x, want the r || s of %x", compact, expanded)
+		}
+
+		restored, err := ExpandSignature(compact, msg, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(restored, expanded) {
+			t.Fatalf("expanded signature %x, want %x", restored, expanded)
+		}
+		recoveryIDs[restored[crypto.RecoveryIDOffset]] = true
+	}
+	if len(recoveryIDs) != 2 {
+		t.Fatalf("recovery IDs %v, want both 0 and 1 restored", recoveryIDs)
+	}
+
+	msg := PrefixMsg(common.Hex2Bytes(goldenClaim.message))
+	compact, err := SignClaimCompact(msg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpandSignature(compact, msg, common.HexToAddress(checksummedAddress)); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("ExpandSignature for another signer = %v, want ErrSignerMismatch", err)
+	}
+	if _, err := ExpandSignature(compact[:63], msg, signer); err == nil {
+		t.Fatal("expanded a 63-byte signature")
+	}
+}
+
+// highS returns the malleated form of the low-s signature sig: s replaced by n - s and the
+// recovery ID flipped, which recovers to the same signer
+func highS(sig []byte) []byte {
+	malleated := append([]byte{}, sig...)
+	s := new(big.Int).SetBytes(sig[32:64])
+	copy(malleated[32:64], math.PaddedBigBytes(new(big.Int).Sub(secp256k1N, s), 32))
+	malleated[crypto.RecoveryIDOffset] ^= 1
+	return malleated
+}
+
+func TestNormalizeS(t *testing.T) {
+	signer, sig := testSignature(t)
+	malleated := highS(sig)
+	if !IsLowS(sig) || IsLowS(malleated) {
+		t.Fatalf("IsLowS = %v, %v for the signature and its high-s form", IsLowS(sig), IsLowS(malleated))
+	}
+
+	if normalized := NormalizeS(malleated); !bytes.Equal(normalized, sig) {
+		t.Fatalf("NormalizeS(high s) = %x, want the original %x", normalized, sig)
+	}
+	// Web3's 27/28 recovery IDs are flipped within their own form
+	web3 := append([]byte{}, malleated...)
+	web3[crypto.RecoveryIDOffset] += 27
+	normalized := NormalizeS(web3)
+	if want := sig[crypto.RecoveryIDOffset] + 27; normalized[crypto.RecoveryIDOffset] != want {
+		t.Fatalf("normalized recovery ID %d, want %d", normalized[crypto.RecoveryIDOffset], want)
+	}
+	recovered, err := RecoverSigner(common.Hex2Bytes(goldenClaim.message), normalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != signer {
+		t.Fatalf("normalized signature recovered %s, want %s", recovered.Hex(), signer.Hex())
+	}
+
+	// Low-s and out of range signatures are left as they are
+	if !bytes.Equal(NormalizeS(sig), sig) {
+		t.Fatal("NormalizeS changed a low-s signature")
+	}
+	outOfRange := append([]byte{}, sig...)
+	copy(outOfRange[32:64], bytes.Repeat([]byte{0xff}, 32))
+	if !bytes.Equal(NormalizeS(outOfRange), outOfRange) {
+		t.Fatal("NormalizeS changed a signature with s above the curve order")
+	}
+}
+
+func TestWordByteInputs(t *testing.T) {
+	word := math.U256Bytes(new(big.Int).Set(goldenClaim.amount))
+	short := goldenClaim.amount.Bytes()
+	for _, input := range [][]byte{word, short} {
+		if got := Uint256(input); !bytes.Equal(got, word) {
+			t.Fatalf("Uint256(%x) = %x, want %x", input, got, word)
+		}
+	}
+	if got := Uint256([]byte{}); !bytes.Equal(got, make([]byte, 32)) {
+		t.Fatalf("Uint256 of no bytes = %x, want a zero word", got)
+	}
+	// A 32-byte word is taken as already two's complement
+	minusOne := bytes.Repeat([]byte{0xff}, 32)
+	if got := Int256(minusOne); !bytes.Equal(got, Int256(big.NewInt(-1))) {
+		t.Fatalf("Int256(%x) = %x, want -1", minusOne, got)
+	}
+
+	packed, err := SolidityPack([]string{"uint256"}, short)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(packed, word) {
+		t.Fatalf("packed uint256 bytes = %x, want %x", packed, word)
+	}
+
+	long := make([]byte, 33)
+	if !panics(func() { Uint256(long) }) || !panics(func() { Int256(long) }) {
+		t.Fatal("a 33-byte word didn't panic")
+	}
+	if _, err := WordBytesChecked(long); !errors.Is(err, ErrWordOverflow) {
+		t.Fatalf("WordBytesChecked of 33 bytes = %v, want ErrWordOverflow", err)
+	}
+	if _, err := SolidityPack([]string{"uint256"}, long); !errors.Is(err, ErrWordOverflow) {
+		t.Fatalf("packing a 33-byte uint256 = %v, want ErrWordOverflow", err)
+	}
+}
+
+func TestNarrowIntegerByteInputs(t *testing.T) {
+	if got := Uint32([]byte{1, 2, 3, 4}); !bytes.Equal(got, []byte{1, 2, 3, 4}) {
+		t.Fatalf("Uint32(01020304) = %x, want 01020304", got)
+	}
+	if got := Uint8([]byte{0xfe}); !bytes.Equal(got, []byte{0xfe}) {
+		t.Fatalf("Uint8(fe) = %x, want fe", got)
+	}
+	// Shorter or longer slices are integers, not uint8 arrays, narrowed to the type's width
+	if got := Uint32([]byte{7}); !bytes.Equal(got, []byte{0, 0, 0, 7}) {
+		t.Fatalf("Uint32(07) = %x, want 00000007", got)
+	}
+	if got := Uint8(common.LeftPadBytes([]byte{7}, 32)); !bytes.Equal(got, []byte{7}) {
+		t.Fatalf("Uint8 of a word holding 7 = %x, want 07", got)
+	}
+	// A sign-extended word keeps its sign
+	if got := Int16(append(bytes.Repeat([]byte{0xff}, 31), 0x80)); !bytes.Equal(got, []byte{0xff, 0x80}) {
+		t.Fatalf("Int16 of the word -128 = %x, want ff80", got)
+	}
+
+	packed, err := SolidityPack([]string{"uint32", "uint8"}, []byte{1, 2, 3, 4}, []byte{0xfe})
+	if err != nil || !bytes.Equal(packed, []byte{1, 2, 3, 4, 0xfe}) {
+		t.Fatalf("SolidityPack(uint32, uint8) of bytes = %x, %v", packed, err)
+	}
+	for typ, input := range map[string][]byte{"uint8": {1, 0}, "uint32": {1, 0, 0, 0, 0}, "int8": {0x80, 0}} {
+		if _, err := SolidityPack([]string{typ}, input); !errors.Is(err, ErrIntegerOverflow) {
+			t.Fatalf("packing %x as %s = %v, want ErrIntegerOverflow", input, typ, err)
+		}
+	}
+}
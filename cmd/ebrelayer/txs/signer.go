@@ -0,0 +1,129 @@
+package txs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs prepared claim messages on behalf of a validator
+type Signer interface {
+	// Address returns the address of the validator's signing key
+	Address() common.Address
+	// Sign signs a prepared 32 byte message
+	Sign(msg []byte) ([]byte, error)
+}
+
+// ContextSigner is a Signer which can abandon a signing request, such as a remote KMS
+type ContextSigner interface {
+	Signer
+	// SignContext signs a prepared 32 byte message, giving up once ctx is done
+	SignContext(ctx context.Context, msg []byte) ([]byte, error)
+}
+
+// KeySigner is a Signer backed by an in-memory private key
+type KeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner initializes a new KeySigner
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// Address implements Signer
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// Sign implements Signer
+func (s *KeySigner) Sign(msg []byte) ([]byte, error) {
+	return SignClaim(msg, s.key)
+}
+
+// PrivateKey returns the signer's private key, for authorizing the transactions submitting its claims
+func (s *KeySigner) PrivateKey() *ecdsa.PrivateKey {
+	return s.key
+}
+
+// ErrNoMatchingSigner is returned when none of a SignerSet's signers is the requested validator
+var ErrNoMatchingSigner = errors.New("no signer matches validator")
+
+// SignerSet holds a validator's signers while it rotates keys, so claims can be signed with
+// whichever key the verifying contract currently has registered
+type SignerSet struct {
+	signers []Signer
+}
+
+// NewSignerSet initializes a new SignerSet
+func NewSignerSet(signers ...Signer) *SignerSet {
+	return &SignerSet{signers: signers}
+}
+
+// NewKeySignerSet initializes a new SignerSet of KeySigners
+func NewKeySignerSet(keys []*ecdsa.PrivateKey) *SignerSet {
+	signers := make([]Signer, len(keys))
+	for i, key := range keys {
+		signers[i] = NewKeySigner(key)
+	}
+	return NewSignerSet(signers...)
+}
+
+// Signers returns the set's signers, in the order given
+func (s *SignerSet) Signers() []Signer {
+	return s.signers
+}
+
+// Addresses returns the address of each of the set's signers, in the order given
+func (s *SignerSet) Addresses() []common.Address {
+	addresses := make([]common.Address, len(s.signers))
+	for i, signer := range s.signers {
+		addresses[i] = signer.Address()
+	}
+	return addresses
+}
+
+// SelectSigner returns the signer whose address is validator, or ErrNoMatchingSigner
+func (s *SignerSet) SelectSigner(validator common.Address) (Signer, error) {
+	for _, signer := range s.signers {
+		if signer.Address() == validator {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoMatchingSigner, validator.Hex())
+}
+
+// SignClaimContext signs the prepared message with the given signer, returning ctx.Err() if the
+// context is cancelled or its deadline passes before the signer responds
+func SignClaimContext(ctx context.Context, signer Signer, msg []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if contextSigner, ok := signer.(ContextSigner); ok {
+		return contextSigner.SignContext(ctx, msg)
+	}
+
+	type result struct {
+		sig []byte
+		err error
+	}
+
+	// Buffered so the signing goroutine can exit even if nobody is waiting on it
+	done := make(chan result, 1)
+	go func() {
+		sig, err := signer.Sign(msg)
+		done <- result{sig, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.sig, r.err
+	}
+}
@@ -0,0 +1,86 @@
+package txs
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// rotationKeys returns the old and new keys of a validator rotating keys
+func rotationKeys(t *testing.T) []*ecdsa.PrivateKey {
+	newKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*ecdsa.PrivateKey{testKey(t), newKey}
+}
+
+func TestSignerSetSelectSigner(t *testing.T) {
+	keys := rotationKeys(t)
+	set := NewKeySignerSet(keys)
+
+	for _, key := range keys {
+		validator := crypto.PubkeyToAddress(key.PublicKey)
+		signer, err := set.SelectSigner(validator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signer.Address() != validator {
+			t.Fatalf("selected %s, want the registered validator %s", signer.Address().Hex(), validator.Hex())
+		}
+		// The selected signer's signatures recover to the registered validator
+		sig, err := signer.Sign(PrefixMsg(common.Hex2Bytes(goldenClaim.message)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recovered, err := RecoverSigner(PrefixMsg(common.Hex2Bytes(goldenClaim.message)), sig); err != nil || recovered != validator {
+			t.Fatalf("signature recovers to %s, %v, want %s", recovered.Hex(), err, validator.Hex())
+		}
+	}
+	if addresses := set.Addresses(); len(addresses) != 2 || addresses[0] != crypto.PubkeyToAddress(keys[0].PublicKey) {
+		t.Fatalf("Addresses = %v, want the keys' addresses in order", addresses)
+	}
+}
+
+func TestSignerSetNoMatch(t *testing.T) {
+	set := NewKeySignerSet(rotationKeys(t))
+	if signer, err := set.SelectSigner(common.HexToAddress(checksummedAddress)); !errors.Is(err, ErrNoMatchingSigner) || signer != nil {
+		t.Fatalf("SelectSigner of an unknown validator = %v, %v, want ErrNoMatchingSigner", signer, err)
+	}
+	if _, err := NewSignerSet().SelectSigner(common.Address{}); !errors.Is(err, ErrNoMatchingSigner) {
+		t.Fatalf("SelectSigner of an empty set = %v, want ErrNoMatchingSigner", err)
+	}
+}
+
+func TestLoadPrivateKeysFromEnv(t *testing.T) {
+	skipEnvFile()
+	keys := rotationKeys(t)
+	newKeyHex := common.Bytes2Hex(crypto.FromECDSA(keys[1]))
+	path, cleanup := writeKeyFile(t, newKeyHex+"\n")
+	defer cleanup()
+
+	for _, list := range []string{testPrivateKeyHex + "," + newKeyHex, testPrivateKeyHex + ", " + path + ","} {
+		restore := setEnv(t, "TEST_PRIVATE_KEY"+KeyListEnvSuffix, list)
+		loaded, err := LoadPrivateKeysFromEnv("TEST_PRIVATE_KEY")
+		restore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) != 2 || loaded[0].D.Cmp(keys[0].D) != 0 || loaded[1].D.Cmp(keys[1].D) != 0 {
+			t.Fatalf("loaded %d keys from %q, want the old and new keys in order", len(loaded), list)
+		}
+	}
+
+	// Without a list, the single key is loaded
+	defer setEnv(t, "TEST_PRIVATE_KEY", testPrivateKeyHex)()
+	if loaded, err := LoadPrivateKeysFromEnv("TEST_PRIVATE_KEY"); err != nil || len(loaded) != 1 || loaded[0].D.Cmp(keys[0].D) != 0 {
+		t.Fatalf("LoadPrivateKeysFromEnv without a list = %d keys, %v, want the single key", len(loaded), err)
+	}
+	defer setEnv(t, "TEST_PRIVATE_KEY"+KeyListEnvSuffix, " , ")()
+	if _, err := LoadPrivateKeysFromEnv("TEST_PRIVATE_KEY"); !errors.Is(err, ErrMissingPrivateKey) {
+		t.Fatalf("LoadPrivateKeysFromEnv of an empty list = %v, want ErrMissingPrivateKey", err)
+	}
+}
@@ -0,0 +1,99 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrMissingSigner is returned when ordering a claim's signatures by validator while a validator
+// has no collected signature, and placeholders weren't asked for
+var ErrMissingSigner = errors.New("validator has no collected signature")
+
+// MissingSigners is how a ClaimSignatureSet ordered by validator handles a validator without a
+// collected signature
+type MissingSigners int
+
+const (
+	// RejectMissingSigners returns ErrMissingSigner, naming the validators without a signature
+	RejectMissingSigners MissingSigners = iota
+	// PlaceholderMissingSigners puts an all-zero signature in each missing validator's place
+	PlaceholderMissingSigners
+)
+
+// ClaimSignatureSet is the signatures collected for a claim, as a SignatureStore returns them, to
+// be submitted together to a contract verifying several validators' signatures
+type ClaimSignatureSet struct {
+	Signatures []SignerSig
+	// ValidatorOrder, if set, is the order of the validator indices the verifying contract was
+	// deployed with, which Ordered returns the signatures in. Signatures by other signers are
+	// left out.
+	ValidatorOrder []common.Address
+	// Missing is how Ordered handles a validator in ValidatorOrder without a signature
+	Missing MissingSigners
+}
+
+// NewClaimSignatureSet initializes a ClaimSignatureSet ordered by ascending signer address
+func NewClaimSignatureSet(signatures []SignerSig) ClaimSignatureSet {
+	return ClaimSignatureSet{Signatures: signatures}
+}
+
+// WithValidatorOrder returns the set ordered by validators, handling those without a signature
+// as missing says
+func (s ClaimSignatureSet) WithValidatorOrder(validators []common.Address, missing MissingSigners) ClaimSignatureSet {
+	s.ValidatorOrder = append([]common.Address(nil), validators...)
+	s.Missing = missing
+	return s
+}
+
+// Ordered returns the signatures in the order the verifying contract expects them: that of
+// ValidatorOrder if set, or otherwise ascending signer address
+func (s ClaimSignatureSet) Ordered() ([][]byte, error) {
+	bySigner := make(map[common.Address][]byte, len(s.Signatures))
+	for _, sig := range s.Signatures {
+		bySigner[sig.Signer] = sig.Signature
+	}
+
+	if len(s.ValidatorOrder) == 0 {
+		signers := make([]common.Address, 0, len(bySigner))
+		for signer := range bySigner {
+			signers = append(signers, signer)
+		}
+		sort.Slice(signers, func(i, j int) bool { return bytes.Compare(signers[i][:], signers[j][:]) < 0 })
+
+		ordered := make([][]byte, len(signers))
+		for i, signer := range signers {
+			ordered[i] = bySigner[signer]
+		}
+		return ordered, nil
+	}
+
+	ordered := make([][]byte, len(s.ValidatorOrder))
+	seen := make(map[common.Address]bool, len(s.ValidatorOrder))
+	var missing []string
+	for i, validator := range s.ValidatorOrder {
+		if seen[validator] {
+			return nil, fmt.Errorf("validator %s is listed twice in the validator order", validator.Hex())
+		}
+		seen[validator] = true
+
+		sig, ok := bySigner[validator]
+		switch {
+		case ok:
+			ordered[i] = sig
+		case s.Missing == PlaceholderMissingSigners:
+			ordered[i] = make([]byte, crypto.SignatureLength)
+		default:
+			missing = append(missing, validator.Hex())
+		}
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingSigner, strings.Join(missing, ", "))
+	}
+	return ordered, nil
+}
@@ -0,0 +1,81 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testValidatorSigs returns signatures by the validators 0x1, 0x2 and 0x3, each whose
+// signature is its address' last byte repeated
+func testValidatorSigs() ([]common.Address, []SignerSig) {
+	validators := []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")}
+	signatures := make([]SignerSig, len(validators))
+	for i, validator := range validators {
+		signatures[i] = SignerSig{Signer: validator, Signature: bytes.Repeat([]byte{validator[19]}, crypto.SignatureLength)}
+	}
+	return validators, signatures
+}
+
+// signedBy returns the validator whose testValidatorSigs signature sig is, or 0 for a placeholder
+func signedBy(sig []byte) byte {
+	return sig[0]
+}
+
+func TestClaimSignatureSetOrdered(t *testing.T) {
+	validators, signatures := testValidatorSigs()
+	// Collected out of order
+	set := NewClaimSignatureSet([]SignerSig{signatures[2], signatures[0], signatures[1]})
+
+	ordered, err := set.Ordered()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ordered) != 3 || signedBy(ordered[0]) != 1 || signedBy(ordered[1]) != 2 || signedBy(ordered[2]) != 3 {
+		t.Fatalf("Ordered = %x, want ascending signer address", ordered)
+	}
+
+	// The contract's validator indices needn't be ascending
+	ordered, err = set.WithValidatorOrder([]common.Address{validators[1], validators[2], validators[0]}, RejectMissingSigners).Ordered()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ordered) != 3 || signedBy(ordered[0]) != 2 || signedBy(ordered[1]) != 3 || signedBy(ordered[2]) != 1 {
+		t.Fatalf("Ordered by validator = %x, want validators 2, 3, 1", ordered)
+	}
+
+	// Signers the contract doesn't list are left out
+	ordered, err = set.WithValidatorOrder([]common.Address{validators[2], validators[0]}, RejectMissingSigners).Ordered()
+	if err != nil || len(ordered) != 2 || signedBy(ordered[0]) != 3 || signedBy(ordered[1]) != 1 {
+		t.Fatalf("Ordered by a subset = %x, %v, want validators 3, 1", ordered, err)
+	}
+}
+
+func TestClaimSignatureSetMissingSigner(t *testing.T) {
+	validators, signatures := testValidatorSigs()
+	absent := common.HexToAddress("0x4")
+	order := []common.Address{validators[2], absent, validators[0]}
+	set := NewClaimSignatureSet(signatures)
+
+	_, err := set.WithValidatorOrder(order, RejectMissingSigners).Ordered()
+	if !errors.Is(err, ErrMissingSigner) || !strings.Contains(err.Error(), absent.Hex()) {
+		t.Fatalf("Ordered with a missing signer = %v, want ErrMissingSigner naming %s", err, absent.Hex())
+	}
+
+	ordered, err := set.WithValidatorOrder(order, PlaceholderMissingSigners).Ordered()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ordered) != 3 || signedBy(ordered[0]) != 3 || signedBy(ordered[2]) != 1 ||
+		!bytes.Equal(ordered[1], make([]byte, crypto.SignatureLength)) {
+		t.Fatalf("Ordered with a placeholder = %x, want a zero signature in the missing validator's place", ordered)
+	}
+
+	if _, err := set.WithValidatorOrder([]common.Address{validators[0], validators[0]}, PlaceholderMissingSigners).Ordered(); err == nil {
+		t.Fatal("ordered by a validator listed twice")
+	}
+}
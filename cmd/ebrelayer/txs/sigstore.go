@@ -0,0 +1,188 @@
+package txs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// SignerSig is a signature collected for a claim, along with the validator which made it
+type SignerSig struct {
+	Signer    common.Address `json:"signer"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// SignatureStore holds the signatures collected for each claim until enough were gathered to
+// submit it
+type SignatureStore interface {
+	// Put records signer's signature over the claim unlockID, replacing any previous one by
+	// signer. A high-s signature is stored normalized to low s.
+	Put(unlockID *big.Int, signer common.Address, sig []byte) error
+	// Get returns the signatures collected for the claim unlockID, in the order first put
+	Get(unlockID *big.Int) ([]SignerSig, error)
+	// Delete discards the signatures collected for the claim unlockID, once it was submitted
+	Delete(unlockID *big.Int) error
+}
+
+// checkCollectedSignature validates a signature collected from another validator, returning it
+// with a high s value normalized, so that only signatures contracts accept are aggregated
+func checkCollectedSignature(signer common.Address, sig []byte) ([]byte, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("signature by %s is %d bytes, expected %d", signer.Hex(), len(sig), crypto.SignatureLength)
+	}
+	sValue := new(big.Int).SetBytes(sig[32:64])
+	if sValue.Sign() == 0 || sValue.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("%w: signature by %s", ErrInvalidSignatureS, signer.Hex())
+	}
+	if !IsLowS(sig) {
+		getLogger().Warn("Normalizing high-s signature", "signer", signer.Hex())
+	}
+	return NormalizeS(sig), nil
+}
+
+// signatureSet maps each claim's unlock ID to its collected signatures
+type signatureSet map[string][]SignerSig
+
+// put records signer's signature over unlockID, replacing any previous one by signer
+func (s signatureSet) put(unlockID *big.Int, signer common.Address, sig []byte) {
+	key := unlockID.String()
+	signature := append(hexutil.Bytes{}, sig...)
+	for i, existing := range s[key] {
+		if existing.Signer == signer {
+			s[key][i].Signature = signature
+			return
+		}
+	}
+	s[key] = append(s[key], SignerSig{Signer: signer, Signature: signature})
+}
+
+// get returns a copy of the signatures collected for unlockID
+func (s signatureSet) get(unlockID *big.Int) []SignerSig {
+	return append([]SignerSig(nil), s[unlockID.String()]...)
+}
+
+// MemorySignatureStore is a SignatureStore held in memory. It is safe for concurrent use.
+type MemorySignatureStore struct {
+	mu         sync.Mutex
+	signatures signatureSet
+}
+
+// NewMemorySignatureStore initializes a new, empty MemorySignatureStore
+func NewMemorySignatureStore() *MemorySignatureStore {
+	return &MemorySignatureStore{signatures: make(signatureSet)}
+}
+
+// Put implements SignatureStore
+func (s *MemorySignatureStore) Put(unlockID *big.Int, signer common.Address, sig []byte) error {
+	sig, err := checkCollectedSignature(signer, sig)
+	if err != nil {
+		return err
+	}
+	if err := SignatureMonitor.Observe("collected from "+signer.Hex(), unlockID, sig); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.signatures.put(unlockID, signer, sig)
+	return nil
+}
+
+// Get implements SignatureStore
+func (s *MemorySignatureStore) Get(unlockID *big.Int) ([]SignerSig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.signatures.get(unlockID), nil
+}
+
+// Delete implements SignatureStore
+func (s *MemorySignatureStore) Delete(unlockID *big.Int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.signatures, unlockID.String())
+	return nil
+}
+
+// FileSignatureStore is a SignatureStore persisting every claim's signatures in one JSON file,
+// replaced atomically on each change so collected signatures survive restarts. It is safe for
+// concurrent use.
+type FileSignatureStore struct {
+	path       string
+	mu         sync.Mutex
+	signatures signatureSet
+}
+
+// NewFileSignatureStore opens the FileSignatureStore at path, loading any previously stored
+// signatures. The file is created on the first Put.
+func NewFileSignatureStore(path string) (*FileSignatureStore, error) {
+	signatures := make(signatureSet)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &signatures); err != nil {
+			return nil, err
+		}
+	}
+	return &FileSignatureStore{path: path, signatures: signatures}, nil
+}
+
+// Put implements SignatureStore
+func (s *FileSignatureStore) Put(unlockID *big.Int, signer common.Address, sig []byte) error {
+	sig, err := checkCollectedSignature(signer, sig)
+	if err != nil {
+		return err
+	}
+	if err := SignatureMonitor.Observe("collected from "+signer.Hex(), unlockID, sig); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.signatures.put(unlockID, signer, sig)
+	return s.write()
+}
+
+// Get implements SignatureStore
+func (s *FileSignatureStore) Get(unlockID *big.Int) ([]SignerSig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.signatures.get(unlockID), nil
+}
+
+// Delete implements SignatureStore
+func (s *FileSignatureStore) Delete(unlockID *big.Int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.signatures[unlockID.String()]; !ok {
+		return nil
+	}
+	delete(s.signatures, unlockID.String())
+	return s.write()
+}
+
+// write replaces the file with the current signatures
+func (s *FileSignatureStore) write() error {
+	data, err := json.Marshal(s.signatures)
+	if err != nil {
+		return err
+	}
+	return types.WriteFileAtomic(s.path, data)
+}
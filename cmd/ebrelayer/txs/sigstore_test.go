@@ -0,0 +1,128 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testSignature returns testKey's signature over the golden claim message
+func testSignature(t *testing.T) (common.Address, []byte) {
+	key := testKey(t)
+	sig, err := crypto.Sign(common.Hex2Bytes(goldenClaim.message), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.PubkeyToAddress(key.PublicKey), sig
+}
+
+func TestFileSignatureStoreSurvivesReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sigstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signatures.json")
+	signer, sig := testSignature(t)
+
+	store, err := NewFileSignatureStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(goldenClaim.unlockID, signer, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileSignatureStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatures, err := reopened.Get(goldenClaim.unlockID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 1 || signatures[0].Signer != signer || !bytes.Equal(signatures[0].Signature, sig) {
+		t.Fatalf("reopened store holds %+v, want the signature by %s", signatures, signer.Hex())
+	}
+
+	if err := reopened.Delete(goldenClaim.unlockID); err != nil {
+		t.Fatal(err)
+	}
+	if signatures, _ := reopened.Get(goldenClaim.unlockID); len(signatures) != 0 {
+		t.Fatalf("store holds %+v after Delete", signatures)
+	}
+	reopened, err = NewFileSignatureStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signatures, _ := reopened.Get(goldenClaim.unlockID); len(signatures) != 0 {
+		t.Fatalf("store reopened after Delete holds %+v", signatures)
+	}
+}
+
+func TestSignatureStorePutReplacesSigner(t *testing.T) {
+	signer, sig := testSignature(t)
+	other := common.HexToAddress(checksummedAddress)
+	store := NewMemorySignatureStore()
+
+	for _, put := range []common.Address{signer, other, signer} {
+		if err := store.Put(goldenClaim.unlockID, put, sig); err != nil {
+			t.Fatal(err)
+		}
+	}
+	signatures, _ := store.Get(goldenClaim.unlockID)
+	if len(signatures) != 2 || signatures[0].Signer != signer || signatures[1].Signer != other {
+		t.Fatalf("store holds %+v, want one signature each, in the order first put", signatures)
+	}
+	if err := store.Put(goldenClaim.unlockID, signer, sig[:64]); err == nil {
+		t.Fatal("a 64-byte signature was stored")
+	}
+
+	if err := store.Delete(goldenClaim.unlockID); err != nil {
+		t.Fatal(err)
+	}
+	if signatures, _ := store.Get(goldenClaim.unlockID); len(signatures) != 0 {
+		t.Fatalf("store holds %+v after Delete", signatures)
+	}
+	if signatures, _ := store.Get(big.NewInt(1)); len(signatures) != 0 {
+		t.Fatalf("store holds %+v for an unknown claim", signatures)
+	}
+}
+
+func TestSignatureStoreRejectsMalleableSignatures(t *testing.T) {
+	recorder, restore := useRecordingLogger()
+	defer restore()
+	signer, sig := testSignature(t)
+	store := NewMemorySignatureStore()
+
+	// A high-s signature is stored as its low-s form
+	if err := store.Put(goldenClaim.unlockID, signer, highS(sig)); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := store.Get(goldenClaim.unlockID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || !bytes.Equal(stored[0].Signature, sig) {
+		t.Fatalf("stored %+v, want the normalized signature %x", stored, sig)
+	}
+	if len(recorder.entries) != 1 || recorder.entries[0].level != "WARN" {
+		t.Fatalf("logged %+v, want a warning about the high-s signature", recorder.entries)
+	}
+
+	zeroS := append([]byte{}, sig...)
+	copy(zeroS[32:64], make([]byte, 32))
+	if err := store.Put(goldenClaim.unlockID, signer, zeroS); !errors.Is(err, ErrInvalidSignatureS) {
+		t.Fatalf("Put of a zero s signature = %v, want ErrInvalidSignatureS", err)
+	}
+	if err := store.Put(goldenClaim.unlockID, signer, sig[:CompactSignatureLength]); err == nil {
+		t.Fatal("Put of a compact signature succeeded")
+	}
+}
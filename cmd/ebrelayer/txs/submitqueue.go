@@ -0,0 +1,507 @@
+package txs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+	"github.com/mochi-lab/eth-one-bridge/hmyclient"
+)
+
+// SubmissionQueue, if set, records each signed oracle claim, and each unlock claim relaying a lock,
+// before it is broadcast until its transaction is mined, so claims a crash interrupts are
+// re-submitted on restart
+var SubmissionQueue *ClaimSubmissionQueue
+
+// ErrClaimInFlight is returned in place of submitting a claim whose queued transaction is still
+// awaiting confirmation
+var ErrClaimInFlight = fmt.Errorf("%w: already broadcast", ErrClaimSkipped)
+
+// QueuedClaim is a claim awaiting confirmation on Chain, sent through the BridgeRegistry at
+// Registry: a signed oracle claim, or the unlock claim relaying a lock if Unlock is set
+type QueuedClaim struct {
+	Chain     string             `json:"chain"`
+	Registry  common.Address     `json:"registry"`
+	UnlockID  *big.Int           `json:"unlockID,omitempty"`
+	Message   common.Hash        `json:"message"`
+	Signature hexutil.Bytes      `json:"signature,omitempty"`
+	Unlock    *QueuedUnlockClaim `json:"unlock,omitempty"`
+	// TxHash is the claim's last broadcast transaction, if it got as far as being sent
+	TxHash *common.Hash `json:"txHash,omitempty"`
+}
+
+// QueuedUnlockClaim is the unlock claim relaying the lock in transaction LockTx on the source chain
+type QueuedUnlockClaim struct {
+	LockTx        common.Hash    `json:"lockTx"`
+	SourceChainID *big.Int       `json:"sourceChainID"`
+	Sender        common.Address `json:"sender"`
+	Receiver      common.Address `json:"receiver"`
+	Token         common.Address `json:"token"`
+	Amount        *big.Int       `json:"amount"`
+}
+
+// key returns the queue key of the claim, by unlock ID, or by lock transaction for an unlock claim
+func (c QueuedClaim) key() string {
+	if c.Unlock != nil {
+		return c.Chain + ":lock:" + c.Unlock.LockTx.Hex()
+	}
+	return signedClaimKey(c.Chain, c.UnlockID)
+}
+
+// ClaimRecovery is how Recover checks and re-submits the claims queued on a chain
+type ClaimRecovery struct {
+	// Processed reports whether an oracle claim was already accepted on-chain
+	Processed func(ctx context.Context, claim QueuedClaim) (bool, error)
+	// Status returns a transaction's receipt status, or ethereum.NotFound if it isn't mined
+	Status func(ctx context.Context, txHash common.Hash) (uint64, error)
+	// Pending reports whether a transaction is known to the node but not yet mined
+	Pending func(ctx context.Context, txHash common.Hash) (bool, error)
+	// Resubmit broadcasts the claim again
+	Resubmit func(claim QueuedClaim) error
+}
+
+// ClaimSubmissionQueue holds the claims submitted but not yet confirmed, persisting them to a file
+// if it has a path. It is safe for concurrent use.
+type ClaimSubmissionQueue struct {
+	path   string
+	mu     sync.Mutex
+	claims map[string]QueuedClaim
+}
+
+// NewClaimSubmissionQueue opens the ClaimSubmissionQueue persisted at path, loading the claims
+// still queued there, or initializes one in memory if path is empty
+func NewClaimSubmissionQueue(path string) (*ClaimSubmissionQueue, error) {
+	q := &ClaimSubmissionQueue{path: path, claims: make(map[string]QueuedClaim)}
+	if path == "" {
+		return q, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		var claims []QueuedClaim
+		if err := json.Unmarshal(data, &claims); err != nil {
+			return nil, err
+		}
+		for _, claim := range claims {
+			q.claims[claim.key()] = claim
+		}
+	}
+	return q, nil
+}
+
+// Enqueue records claim as about to be submitted, replacing any claim queued under its key
+func (q *ClaimSubmissionQueue) Enqueue(claim QueuedClaim) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.claims[claim.key()] = claim
+	return q.write()
+}
+
+// Queued returns the claim queued under claim's key, if any
+func (q *ClaimSubmissionQueue) Queued(claim QueuedClaim) (QueuedClaim, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	queued, ok := q.claims[claim.key()]
+	return queued, ok
+}
+
+// Sent records txHash as the transaction last broadcast for the claim queued under claim's key
+func (q *ClaimSubmissionQueue) Sent(claim QueuedClaim, txHash common.Hash) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := claim.key()
+	queued, ok := q.claims[key]
+	if !ok {
+		return nil
+	}
+	queued.TxHash = &txHash
+	q.claims[key] = queued
+	return q.write()
+}
+
+// Confirm removes the claim queued under claim's key, once it was mined or needn't be sent, and
+// evicts its signatures from SignatureCache
+func (q *ClaimSubmissionQueue) Confirm(claim QueuedClaim) error {
+	evictClaimSignature(claim)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := claim.key()
+	if _, ok := q.claims[key]; !ok {
+		return nil
+	}
+	delete(q.claims, key)
+	return q.write()
+}
+
+// Pending returns the claims queued on chain, oracle claims by unlock ID and then unlock claims
+// by lock transaction
+func (q *ClaimSubmissionQueue) Pending(chain string) []QueuedClaim {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var claims []QueuedClaim
+	for _, claim := range q.claims {
+		if claim.Chain == chain {
+			claims = append(claims, claim)
+		}
+	}
+	sortQueuedClaims(claims)
+	return claims
+}
+
+// Recover re-submits the claims queued on chain through recovery. A claim whose last broadcast
+// transaction was mined is only removed, and one whose transaction is still pending is watched
+// until it is mined, as is an oracle claim already accepted on-chain before the relayer saw it
+// confirmed. It returns how many claims were re-submitted, and the first error, after trying
+// every claim.
+func (q *ClaimSubmissionQueue) Recover(ctx context.Context, chain string, recovery ClaimRecovery) (int, error) {
+	var resubmitted int
+	var firstErr error
+	for _, claim := range q.Pending(chain) {
+		err := q.recoverClaim(ctx, claim, recovery)
+		if err == nil {
+			resubmitted++
+			continue
+		}
+		if errors.Is(err, ErrClaimSkipped) {
+			continue
+		}
+		getLogger().Error("Re-submitting queued claim failed", "chain", chain, "claim", claim.key(), "err", err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return resubmitted, firstErr
+}
+
+// recoverClaim re-submits claim unless its last broadcast transaction was mined, in which case it
+// is removed and ErrClaimAlreadyProcessed returned, or is still pending, in which case it is
+// watched and ErrClaimInFlight returned. An oracle claim recovery.Processed reports accepted
+// on-chain is likewise removed.
+func (q *ClaimSubmissionQueue) recoverClaim(ctx context.Context, claim QueuedClaim, recovery ClaimRecovery) error {
+	if claim.TxHash != nil {
+		receiptStatus, err := recovery.Status(ctx, *claim.TxHash)
+		switch {
+		case err == nil && receiptStatus == ctypes.ReceiptStatusSuccessful:
+			getLogger().Info("Queued claim transaction was already mined", "chain", claim.Chain, "claim", claim.key(),
+				"txHash", claim.TxHash.Hex())
+			if err := q.Confirm(claim); err != nil {
+				return err
+			}
+			return ErrClaimAlreadyProcessed
+		case err == nil:
+			// Reverted, so the claim is re-submitted unless the contracts already processed it
+		case err != ethereum.NotFound:
+			return err
+		default:
+			pending, err := recovery.Pending(ctx, *claim.TxHash)
+			if err != nil && err != ethereum.NotFound {
+				return err
+			}
+			if pending {
+				getLogger().Info("Queued claim transaction is still pending", "chain", claim.Chain, "claim", claim.key(),
+					"txHash", claim.TxHash.Hex())
+				q.watch(claim, *claim.TxHash, recovery.Status)
+				return ErrClaimInFlight
+			}
+		}
+	}
+
+	if claim.Unlock == nil {
+		done, err := recovery.Processed(ctx, claim)
+		if err != nil {
+			return err
+		}
+		if done {
+			getLogger().Info("Queued claim was already processed on-chain", "chain", claim.Chain, "claim", claim.key())
+			if err := q.Confirm(claim); err != nil {
+				return err
+			}
+			return ErrClaimAlreadyProcessed
+		}
+	}
+
+	keyvals := []interface{}{"chain", claim.Chain, "claim", claim.key()}
+	if claim.TxHash != nil {
+		keyvals = append(keyvals, "txHash", claim.TxHash.Hex())
+	}
+	getLogger().Info("Re-submitting queued claim", keyvals...)
+	// The last broadcast was dropped or reverted, so it mustn't hold back the new one
+	claim.TxHash = nil
+	if err := q.Enqueue(claim); err != nil {
+		return err
+	}
+	return recovery.Resubmit(claim)
+}
+
+// watch records txHash as claim's broadcast transaction, removing the claim once it is mined.
+// A claim whose transaction reverts, or isn't mined within DefaultBatchReceiptTimeout, stays
+// queued for recovery on restart.
+func (q *ClaimSubmissionQueue) watch(claim QueuedClaim, txHash common.Hash,
+	status func(ctx context.Context, txHash common.Hash) (uint64, error)) {
+	if err := q.Sent(claim, txHash); err != nil {
+		getLogger().Error("Recording queued claim transaction failed", "chain", claim.Chain, "claim", claim.key(), "err", err)
+	}
+
+	go func() {
+		if err := waitReceipt(context.Background(), txHash, status); err != nil {
+			getLogger().Warn("Queued claim not confirmed, keeping it for recovery", "chain", claim.Chain,
+				"claim", claim.key(), "txHash", txHash.Hex(), "err", err)
+			return
+		}
+		if err := q.Confirm(claim); err != nil {
+			getLogger().Error("Removing queued claim failed", "chain", claim.Chain, "claim", claim.key(), "err", err)
+		}
+	}()
+}
+
+// write persists the queued claims, if the queue has a path. The caller must hold mu.
+func (q *ClaimSubmissionQueue) write() error {
+	if q.path == "" {
+		return nil
+	}
+	claims := make([]QueuedClaim, 0, len(q.claims))
+	for _, claim := range q.claims {
+		claims = append(claims, claim)
+	}
+	sortQueuedClaims(claims)
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	return types.WriteFileAtomic(q.path, data)
+}
+
+// sortQueuedClaims orders claims by chain, then oracle claims by unlock ID before unlock claims by
+// lock transaction
+func sortQueuedClaims(claims []QueuedClaim) {
+	sort.Slice(claims, func(i, j int) bool {
+		a, b := claims[i], claims[j]
+		switch {
+		case a.Chain != b.Chain:
+			return a.Chain < b.Chain
+		case (a.Unlock == nil) != (b.Unlock == nil):
+			return a.Unlock == nil
+		case a.Unlock != nil:
+			return a.Unlock.LockTx.Hex() < b.Unlock.LockTx.Hex()
+		}
+		return a.UnlockID.Cmp(b.UnlockID) < 0
+	})
+}
+
+// oracleSubmission returns the queued form of a signed oracle claim for chain
+func oracleSubmission(chain string, registry common.Address, unlockID *big.Int, message [32]byte,
+	signature []byte) QueuedClaim {
+	return QueuedClaim{Chain: chain, Registry: registry, UnlockID: unlockID, Message: message, Signature: signature}
+}
+
+// ethUnlockSubmission returns the queued form of an Ethereum unlock claim
+func ethUnlockSubmission(registry common.Address, claim EthUnlockClaim) QueuedClaim {
+	return QueuedClaim{Chain: ethereumChainLabel, Registry: registry, Unlock: &QueuedUnlockClaim{
+		LockTx:        claim.LockTx,
+		SourceChainID: claim.HarmonyChainID,
+		Sender:        claim.HarmonySender,
+		Receiver:      claim.EthereumReceiver,
+		Token:         claim.Token,
+		Amount:        claim.Amount,
+	}}
+}
+
+// hmyUnlockSubmission returns the queued form of a Harmony unlock claim
+func hmyUnlockSubmission(registry common.Address, claim HmyUnlockClaim) QueuedClaim {
+	return QueuedClaim{Chain: harmonyChainLabel, Registry: registry, Unlock: &QueuedUnlockClaim{
+		LockTx:        claim.LockTx,
+		SourceChainID: claim.EthereumChainID,
+		Sender:        claim.EthereumSender,
+		Receiver:      claim.HarmonyReceiver,
+		Token:         claim.Token,
+		Amount:        claim.Amount,
+	}}
+}
+
+// enqueueSubmission records claim with SubmissionQueue, if set, before it is broadcast. It returns
+// ErrClaimInFlight for an unlock claim already broadcast for the same lock, such as one replayed
+// after recovery re-submitted it, since the contracts don't reject duplicate unlock claims.
+func enqueueSubmission(claim QueuedClaim) error {
+	if SubmissionQueue == nil {
+		return nil
+	}
+	if queued, ok := SubmissionQueue.Queued(claim); ok && claim.Unlock != nil && queued.TxHash != nil {
+		getLogger().Info("Unlock claim already broadcast, awaiting confirmation", "chain", claim.Chain,
+			"claim", claim.key(), "txHash", queued.TxHash.Hex())
+		return ErrClaimInFlight
+	}
+	return SubmissionQueue.Enqueue(claim)
+}
+
+// confirmSubmission removes a claim the contracts reported as already processed from
+// SubmissionQueue, if set, and evicts its signatures from SignatureCache
+func confirmSubmission(claim QueuedClaim) {
+	if SubmissionQueue == nil {
+		evictClaimSignature(claim)
+		return
+	}
+	if err := SubmissionQueue.Confirm(claim); err != nil {
+		getLogger().Error("Removing queued claim failed", "chain", claim.Chain, "claim", claim.key(), "err", err)
+	}
+}
+
+// watchSubmission records txHash as a queued claim's broadcast transaction with SubmissionQueue,
+// if set, removing the claim and evicting its signatures from SignatureCache once it is mined
+func watchSubmission(claim QueuedClaim, txHash common.Hash,
+	status func(ctx context.Context, txHash common.Hash) (uint64, error)) {
+	if SubmissionQueue != nil {
+		SubmissionQueue.watch(claim, txHash, status)
+		return
+	}
+	if SignatureCache == nil || claim.UnlockID == nil {
+		return
+	}
+	go func() {
+		if err := waitReceipt(context.Background(), txHash, status); err == nil {
+			evictClaimSignature(claim)
+		}
+	}()
+}
+
+// RecoverEthSubmissions re-submits, through provider, the Ethereum claims SubmissionQueue still
+// holds from before a restart, skipping those already mined or processed and watching those still
+// pending
+func RecoverEthSubmissions(ctx context.Context, provider string, privateKey *ecdsa.PrivateKey) (int, error) {
+	if SubmissionQueue == nil || len(SubmissionQueue.Pending(ethereumChainLabel)) == 0 {
+		return 0, nil
+	}
+	var client *ethclient.Client
+	err := DialEndpoint(ctx, provider, func(ctx context.Context, rawurl string) (err error) {
+		client, err = ethclient.DialContext(ctx, rawurl)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	validator, err := LoadSender(privateKey)
+	if err != nil {
+		return 0, err
+	}
+
+	checkers := make(map[common.Address]ClaimChecker)
+	processed := func(ctx context.Context, claim QueuedClaim) (bool, error) {
+		checker, ok := checkers[claim.Registry]
+		if !ok {
+			oracleAddress, err := EthGetAddressFromBridgeRegistry(privateKey, client, claim.Registry, Oracle)
+			if err != nil {
+				return false, err
+			}
+			if checker, err = NewEthClaimChecker(ctx, client, oracleAddress, validator); err != nil {
+				return false, err
+			}
+			checkers[claim.Registry] = checker
+		}
+		return checker.ClaimAlreadyProcessed(ctx, claim.UnlockID)
+	}
+	return SubmissionQueue.Recover(ctx, ethereumChainLabel, ClaimRecovery{
+		Processed: processed,
+		Status:    ethReceiptStatus(client),
+		Pending: func(ctx context.Context, txHash common.Hash) (bool, error) {
+			_, pending, err := client.TransactionByHash(ctx, txHash)
+			return pending, err
+		},
+		Resubmit: func(claim QueuedClaim) error {
+			if claim.Unlock != nil {
+				unlockClaim := EthUnlockClaim{
+					HarmonyChainID:   claim.Unlock.SourceChainID,
+					HarmonySender:    claim.Unlock.Sender,
+					EthereumReceiver: claim.Unlock.Receiver,
+					Token:            claim.Unlock.Token,
+					Amount:           claim.Unlock.Amount,
+					LockTx:           claim.Unlock.LockTx,
+				}
+				return RelayUnlockClaimToEthereum(provider, claim.Registry, types.HmyLogLock, unlockClaim, privateKey)
+			}
+			oracleClaim := EthOracleClaim{UnlockID: claim.UnlockID, Message: claim.Message, Signature: claim.Signature}
+			return RelayOracleClaimToEthereum(provider, claim.Registry, types.EthLogNewUnlockClaim, oracleClaim, privateKey)
+		},
+	})
+}
+
+// RecoverHmySubmissions re-submits, through provider, the Harmony claims SubmissionQueue still
+// holds from before a restart, skipping those already mined or processed and watching those still
+// pending
+func RecoverHmySubmissions(ctx context.Context, provider string, privateKey *ecdsa.PrivateKey) (int, error) {
+	if SubmissionQueue == nil || len(SubmissionQueue.Pending(harmonyChainLabel)) == 0 {
+		return 0, nil
+	}
+	var client *hmyclient.Client
+	err := DialEndpoint(ctx, provider, func(ctx context.Context, rawurl string) (err error) {
+		client, err = hmyclient.DialContext(ctx, rawurl)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	validator, err := LoadSender(privateKey)
+	if err != nil {
+		return 0, err
+	}
+
+	checkers := make(map[common.Address]ClaimChecker)
+	processed := func(ctx context.Context, claim QueuedClaim) (bool, error) {
+		checker, ok := checkers[claim.Registry]
+		if !ok {
+			oracleAddress, err := HmyGetAddressFromBridgeRegistry(privateKey, client, claim.Registry, Oracle)
+			if err != nil {
+				return false, err
+			}
+			if checker, err = NewHmyClaimChecker(ctx, client, oracleAddress, validator); err != nil {
+				return false, err
+			}
+			checkers[claim.Registry] = checker
+		}
+		return checker.ClaimAlreadyProcessed(ctx, claim.UnlockID)
+	}
+	return SubmissionQueue.Recover(ctx, harmonyChainLabel, ClaimRecovery{
+		Processed: processed,
+		Status:    hmyReceiptStatus(client),
+		Pending: func(ctx context.Context, txHash common.Hash) (bool, error) {
+			_, pending, err := client.TransactionByHash(ctx, txHash)
+			return pending, err
+		},
+		Resubmit: func(claim QueuedClaim) error {
+			if claim.Unlock != nil {
+				unlockClaim := HmyUnlockClaim{
+					EthereumChainID: claim.Unlock.SourceChainID,
+					EthereumSender:  claim.Unlock.Sender,
+					HarmonyReceiver: claim.Unlock.Receiver,
+					Token:           claim.Unlock.Token,
+					Amount:          claim.Unlock.Amount,
+					LockTx:          claim.Unlock.LockTx,
+				}
+				return RelayUnlockClaimToHarmony(provider, claim.Registry, types.EthLogLock, unlockClaim, privateKey)
+			}
+			oracleClaim := HmyOracleClaim{UnlockID: claim.UnlockID, Message: claim.Message, Signature: claim.Signature}
+			return RelayOracleClaimToHarmony(provider, claim.Registry, types.HmyLogNewUnlockClaim, oracleClaim, privateKey)
+		},
+	})
+}
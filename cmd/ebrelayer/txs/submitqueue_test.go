@@ -0,0 +1,283 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// testQueue opens a ClaimSubmissionQueue in a temporary directory, returning its path and the
+// function removing it
+func testQueue(t *testing.T) (*ClaimSubmissionQueue, string, func()) {
+	dir, err := ioutil.TempDir("", "submitqueue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "queue.json")
+	q, err := NewClaimSubmissionQueue(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return q, path, func() { os.RemoveAll(dir) }
+}
+
+func testOracleSubmission(unlockID int64) QueuedClaim {
+	return oracleSubmission(ethereumChainLabel, common.HexToAddress("0x1"), big.NewInt(unlockID),
+		[32]byte{byte(unlockID)}, []byte{0x01, 0x02})
+}
+
+func testUnlockSubmission(lockTx string) QueuedClaim {
+	return ethUnlockSubmission(common.HexToAddress("0x1"), EthUnlockClaim{
+		HarmonyChainID:   big.NewInt(1666600000),
+		HarmonySender:    common.HexToAddress("0x2"),
+		EthereumReceiver: common.HexToAddress("0x3"),
+		Token:            common.HexToAddress("0x4"),
+		Amount:           big.NewInt(1000),
+		LockTx:           common.HexToHash(lockTx),
+	})
+}
+
+// testRecovery is a ClaimRecovery over fixed chain state, recording the claims re-submitted
+type testRecovery struct {
+	mu          sync.Mutex
+	processed   bool
+	statuses    []error
+	status      uint64
+	pending     bool
+	resubmitted []QueuedClaim
+}
+
+func (r *testRecovery) recovery() ClaimRecovery {
+	return ClaimRecovery{
+		Processed: func(context.Context, QueuedClaim) (bool, error) { return r.processed, nil },
+		Status: func(context.Context, common.Hash) (uint64, error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			// Each lookup takes the next of statuses, the last repeating
+			err := r.statuses[0]
+			if len(r.statuses) > 1 {
+				r.statuses = r.statuses[1:]
+			}
+			return r.status, err
+		},
+		Pending: func(context.Context, common.Hash) (bool, error) {
+			if !r.pending {
+				return false, ethereum.NotFound
+			}
+			return true, nil
+		},
+		Resubmit: func(claim QueuedClaim) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			r.resubmitted = append(r.resubmitted, claim)
+			return nil
+		},
+	}
+}
+
+func TestClaimSubmissionQueueSurvivesRestart(t *testing.T) {
+	q, path, cleanup := testQueue(t)
+	defer cleanup()
+
+	for _, claim := range []QueuedClaim{testUnlockSubmission("0xaa"), testOracleSubmission(7), testOracleSubmission(3)} {
+		if err := q.Enqueue(claim); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Sent(testOracleSubmission(3), common.HexToHash("0xbb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Confirm(testOracleSubmission(7)); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewClaimSubmissionQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := restarted.Pending(ethereumChainLabel)
+	if len(pending) != 2 {
+		t.Fatalf("restarted queue holds %d claims, want 2", len(pending))
+	}
+	if pending[0].UnlockID.Int64() != 3 || pending[0].TxHash == nil || *pending[0].TxHash != common.HexToHash("0xbb") {
+		t.Fatalf("first queued claim = %+v, want unlock ID 3 sent in 0xbb", pending[0])
+	}
+	if pending[1].Unlock == nil || pending[1].Unlock.LockTx != common.HexToHash("0xaa") ||
+		pending[1].Unlock.Amount.Int64() != 1000 {
+		t.Fatalf("second queued claim = %+v, want the unlock claim for lock 0xaa", pending[1])
+	}
+	if len(restarted.Pending(harmonyChainLabel)) != 0 {
+		t.Fatal("Harmony claims queued")
+	}
+}
+
+func TestRecoverSkipsMinedClaim(t *testing.T) {
+	q, _, cleanup := testQueue(t)
+	defer cleanup()
+	for _, claim := range []QueuedClaim{testOracleSubmission(1), testUnlockSubmission("0xaa")} {
+		if err := q.Enqueue(claim); err != nil {
+			t.Fatal(err)
+		}
+		if err := q.Sent(claim, common.HexToHash("0xbb")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain := &testRecovery{statuses: []error{nil}, status: ctypes.ReceiptStatusSuccessful}
+	resubmitted, err := q.Recover(context.Background(), ethereumChainLabel, chain.recovery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resubmitted != 0 || len(chain.resubmitted) != 0 {
+		t.Fatalf("re-submitted %d claims whose transactions were already mined", resubmitted)
+	}
+	if len(q.Pending(ethereumChainLabel)) != 0 {
+		t.Fatal("mined claims still queued")
+	}
+}
+
+func TestRecoverSkipsProcessedOracleClaim(t *testing.T) {
+	q, _, cleanup := testQueue(t)
+	defer cleanup()
+	if err := q.Enqueue(testOracleSubmission(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &testRecovery{processed: true}
+	resubmitted, err := q.Recover(context.Background(), ethereumChainLabel, chain.recovery())
+	if err != nil || resubmitted != 0 {
+		t.Fatalf("Recover = %d, %v, want the processed claim skipped", resubmitted, err)
+	}
+	if len(q.Pending(ethereumChainLabel)) != 0 {
+		t.Fatal("processed claim still queued")
+	}
+}
+
+func TestRecoverWatchesPendingClaim(t *testing.T) {
+	q, _, cleanup := testQueue(t)
+	defer cleanup()
+	claim := testUnlockSubmission("0xaa")
+	if err := q.Enqueue(claim); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Sent(claim, common.HexToHash("0xbb")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not mined when recovery checks, then mined by the time it is watched
+	chain := &testRecovery{statuses: []error{ethereum.NotFound, nil}, status: ctypes.ReceiptStatusSuccessful, pending: true}
+	resubmitted, err := q.Recover(context.Background(), ethereumChainLabel, chain.recovery())
+	if err != nil || resubmitted != 0 {
+		t.Fatalf("Recover = %d, %v, want the pending claim left to confirm", resubmitted, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(q.Pending(ethereumChainLabel)) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("pending claim still queued after its transaction was mined")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRecoverResubmitsDroppedAndRevertedClaims(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		status error
+	}{
+		{"dropped", ethereum.NotFound},
+		{"reverted", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q, _, cleanup := testQueue(t)
+			defer cleanup()
+			claim := testUnlockSubmission("0xaa")
+			if err := q.Enqueue(claim); err != nil {
+				t.Fatal(err)
+			}
+			if err := q.Sent(claim, common.HexToHash("0xbb")); err != nil {
+				t.Fatal(err)
+			}
+
+			chain := &testRecovery{statuses: []error{tt.status}, status: ctypes.ReceiptStatusFailed}
+			resubmitted, err := q.Recover(context.Background(), ethereumChainLabel, chain.recovery())
+			if err != nil || resubmitted != 1 {
+				t.Fatalf("Recover = %d, %v, want the claim re-submitted", resubmitted, err)
+			}
+			if chain.resubmitted[0].TxHash != nil {
+				t.Fatal("re-submitted claim still holds its last transaction")
+			}
+			if queued, ok := q.Queued(claim); !ok || queued.TxHash != nil {
+				t.Fatalf("queued claim = %+v, %v, want it queued until the new transaction is sent", queued, ok)
+			}
+		})
+	}
+}
+
+func TestRecoverReturnsLookupErrors(t *testing.T) {
+	q, _, cleanup := testQueue(t)
+	defer cleanup()
+	claim := testOracleSubmission(1)
+	if err := q.Enqueue(claim); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Sent(claim, common.HexToHash("0xbb")); err != nil {
+		t.Fatal(err)
+	}
+
+	unreachable := errors.New("connection refused")
+	chain := &testRecovery{statuses: []error{unreachable}}
+	if _, err := q.Recover(context.Background(), ethereumChainLabel, chain.recovery()); err != unreachable {
+		t.Fatalf("Recover = %v, want %v", err, unreachable)
+	}
+	if len(chain.resubmitted) != 0 || len(q.Pending(ethereumChainLabel)) != 1 {
+		t.Fatal("claim re-submitted or dropped without knowing whether its transaction was mined")
+	}
+}
+
+func TestEnqueueSubmissionSkipsBroadcastUnlockClaim(t *testing.T) {
+	q, _, cleanup := testQueue(t)
+	defer cleanup()
+	defer func(queue *ClaimSubmissionQueue) { SubmissionQueue = queue }(SubmissionQueue)
+	SubmissionQueue = q
+
+	claim := testUnlockSubmission("0xaa")
+	if err := enqueueSubmission(claim); err != nil {
+		t.Fatal(err)
+	}
+	// Replaying the lock before its claim was sent queues it again
+	if err := enqueueSubmission(claim); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Sent(claim, common.HexToHash("0xbb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := enqueueSubmission(claim); !errors.Is(err, ErrClaimInFlight) || !errors.Is(err, ErrClaimSkipped) {
+		t.Fatalf("enqueueSubmission of a broadcast unlock claim = %v, want ErrClaimInFlight", err)
+	}
+
+	// Oracle claims are left to the contracts, which reject duplicates
+	oracleClaim := testOracleSubmission(1)
+	if err := enqueueSubmission(oracleClaim); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Sent(oracleClaim, common.HexToHash("0xcc")); err != nil {
+		t.Fatal(err)
+	}
+	if err := enqueueSubmission(oracleClaim); err != nil {
+		t.Fatalf("enqueueSubmission of a broadcast oracle claim = %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+var (
+	// ErrUnknownTokenSymbol is returned when resolving a symbol no token is registered under
+	ErrUnknownTokenSymbol = errors.New("unknown token symbol")
+	// ErrTokenSymbolCollision is returned when registering a symbol twice, or a token address
+	// already registered under another symbol on the same chain
+	ErrTokenSymbolCollision = errors.New("token symbol collision")
+)
+
+// TokenSymbols, if set, resolves the token symbols operators may give in place of addresses when
+// configuring token mappings
+var TokenSymbols *TokenSymbolRegistry
+
+// SymbolTokens is the pair of addresses a token symbol resolves to, one on each chain
+type SymbolTokens struct {
+	Ethereum common.Address
+	Harmony  common.Address
+}
+
+// TokenSymbolRegistry resolves token symbols such as USDC to the token's address on each chain,
+// each symbol to a distinct address per chain. It is safe for concurrent use.
+type TokenSymbolRegistry struct {
+	mu      sync.RWMutex
+	symbols map[string]SymbolTokens
+	// owners maps each chain's registered token addresses to the symbol they are registered under
+	owners map[string]map[common.Address]string
+}
+
+// NewTokenSymbolRegistry initializes a new, empty TokenSymbolRegistry
+func NewTokenSymbolRegistry() *TokenSymbolRegistry {
+	return &TokenSymbolRegistry{
+		symbols: make(map[string]SymbolTokens),
+		owners: map[string]map[common.Address]string{
+			ethereumChainLabel: make(map[common.Address]string),
+			harmonyChainLabel:  make(map[common.Address]string),
+		},
+	}
+}
+
+// Register registers symbol as resolving to tokens, returning ErrTokenSymbolCollision if the
+// symbol, or either address on its chain, is already registered. Symbols are case-insensitive.
+func (r *TokenSymbolRegistry) Register(symbol string, tokens SymbolTokens) error {
+	key := strings.ToUpper(symbol)
+	if key == "" {
+		return errors.New("empty token symbol")
+	}
+	if isZeroAddress(tokens.Ethereum) || isZeroAddress(tokens.Harmony) {
+		return fmt.Errorf("token symbol %s has a zero address", key)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.symbols[key]; ok {
+		return fmt.Errorf("%w: %s is registered twice", ErrTokenSymbolCollision, key)
+	}
+	for _, chain := range []string{ethereumChainLabel, harmonyChainLabel} {
+		address := tokens.address(chain)
+		if owner, ok := r.owners[chain][address]; ok {
+			return fmt.Errorf("%w: %s and %s both resolve to %s on %s", ErrTokenSymbolCollision, owner, key,
+				chainAddress(chain, address), chain)
+		}
+	}
+	r.symbols[key] = tokens
+	r.owners[ethereumChainLabel][tokens.Ethereum] = key
+	r.owners[harmonyChainLabel][tokens.Harmony] = key
+	return nil
+}
+
+// Resolve returns the addresses symbol resolves to, or ErrUnknownTokenSymbol
+func (r *TokenSymbolRegistry) Resolve(symbol string) (SymbolTokens, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens, ok := r.symbols[strings.ToUpper(symbol)]
+	if !ok {
+		return SymbolTokens{}, fmt.Errorf("%w: %s", ErrUnknownTokenSymbol, symbol)
+	}
+	return tokens, nil
+}
+
+// Symbols returns the registered symbols in order
+func (r *TokenSymbolRegistry) Symbols() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	symbols := make([]string, 0, len(r.symbols))
+	for symbol := range r.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// RegisterMapping maps the token symbol resolves to on one chain to its counterpart on the other:
+// its Ethereum token to its Harmony token if ethToHmy is set, or the reverse otherwise
+func (r *TokenSymbolRegistry) RegisterMapping(mapping *TokenMapping, symbol string, ethToHmy bool) error {
+	tokens, err := r.Resolve(symbol)
+	if err != nil {
+		return err
+	}
+	if ethToHmy {
+		mapping.Register(tokens.Ethereum, tokens.Harmony)
+	} else {
+		mapping.Register(tokens.Harmony, tokens.Ethereum)
+	}
+	return nil
+}
+
+// address returns the token's address on chain
+func (t SymbolTokens) address(chain string) common.Address {
+	if chain == harmonyChainLabel {
+		return t.Harmony
+	}
+	return t.Ethereum
+}
+
+// ParseTokenSymbol parses a token symbol given as "SYMBOL=ethToken:hmyToken". Hex addresses in
+// mixed case must carry a valid EIP-55 checksum, and the Harmony token may be given in bech32.
+func ParseTokenSymbol(value string) (string, SymbolTokens, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", SymbolTokens{}, fmt.Errorf("invalid token symbol %q: expected SYMBOL=ethToken:hmyToken", value)
+	}
+	addresses := strings.SplitN(parts[1], ":", 2)
+	if len(addresses) != 2 {
+		return "", SymbolTokens{}, fmt.Errorf("invalid token symbol %q: expected SYMBOL=ethToken:hmyToken", value)
+	}
+
+	if err := ValidateAddressChecksum(addresses[0], false); err != nil {
+		return "", SymbolTokens{}, fmt.Errorf("invalid token symbol %q: Ethereum token: %w", value, err)
+	}
+	tokens := SymbolTokens{Ethereum: common.HexToAddress(addresses[0])}
+	if strings.HasPrefix(strings.ToLower(addresses[1]), types.Bech32HRP+"1") {
+		harmony, err := types.FromBech32(addresses[1])
+		if err != nil {
+			return "", SymbolTokens{}, fmt.Errorf("invalid token symbol %q: Harmony token: %w", value, err)
+		}
+		tokens.Harmony = harmony
+	} else {
+		if err := ValidateAddressChecksum(addresses[1], false); err != nil {
+			return "", SymbolTokens{}, fmt.Errorf("invalid token symbol %q: Harmony token: %w", value, err)
+		}
+		tokens.Harmony = common.HexToAddress(addresses[1])
+	}
+	return strings.ToUpper(parts[0]), tokens, nil
+}
@@ -0,0 +1,110 @@
+package txs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+func TestTokenSymbolRegistryResolve(t *testing.T) {
+	registry := NewTokenSymbolRegistry()
+	usdt := SymbolTokens{Ethereum: goldenClaim.token, Harmony: common.HexToAddress("0x3c2b8be99c50593081eaa2a724f0b8285f5aba8f")}
+	if err := registry.Register("USDT", usdt); err != nil {
+		t.Fatal(err)
+	}
+
+	// Symbols are case-insensitive
+	for _, symbol := range []string{"USDT", "usdt"} {
+		tokens, err := registry.Resolve(symbol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tokens != usdt {
+			t.Fatalf("Resolve(%s) = %s:%s, want %s:%s", symbol, tokens.Ethereum.Hex(), tokens.Harmony.Hex(),
+				usdt.Ethereum.Hex(), usdt.Harmony.Hex())
+		}
+	}
+	if _, err := registry.Resolve("USDC"); !errors.Is(err, ErrUnknownTokenSymbol) {
+		t.Fatalf("Resolve of an unknown symbol = %v, want ErrUnknownTokenSymbol", err)
+	}
+
+	// Either direction maps the symbol's token on one chain to the other's
+	ethToHmy, hmyToEth := NewTokenMapping(), NewTokenMapping()
+	if err := registry.RegisterMapping(ethToHmy, "usdt", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterMapping(hmyToEth, "USDT", false); err != nil {
+		t.Fatal(err)
+	}
+	if dest, ok := ethToHmy.Dest(usdt.Ethereum); !ok || dest != usdt.Harmony {
+		t.Fatalf("Ethereum token mapped to %s, %v, want %s", dest.Hex(), ok, usdt.Harmony.Hex())
+	}
+	if dest, ok := hmyToEth.Dest(usdt.Harmony); !ok || dest != usdt.Ethereum {
+		t.Fatalf("Harmony token mapped to %s, %v, want %s", dest.Hex(), ok, usdt.Ethereum.Hex())
+	}
+	if err := registry.RegisterMapping(ethToHmy, "USDC", true); !errors.Is(err, ErrUnknownTokenSymbol) {
+		t.Fatalf("RegisterMapping of an unknown symbol = %v, want ErrUnknownTokenSymbol", err)
+	}
+}
+
+func TestTokenSymbolRegistryCollision(t *testing.T) {
+	registry := NewTokenSymbolRegistry()
+	usdt := SymbolTokens{Ethereum: goldenClaim.token, Harmony: common.HexToAddress("0x3c")}
+	if err := registry.Register("USDT", usdt); err != nil {
+		t.Fatal(err)
+	}
+	for name, register := range map[string]func() error{
+		"the symbol twice": func() error {
+			return registry.Register("usdt", SymbolTokens{Ethereum: common.HexToAddress("0x1"), Harmony: common.HexToAddress("0x2")})
+		},
+		"a taken Ethereum token": func() error {
+			return registry.Register("USDC", SymbolTokens{Ethereum: usdt.Ethereum, Harmony: common.HexToAddress("0x2")})
+		},
+		"a taken Harmony token": func() error {
+			return registry.Register("USDC", SymbolTokens{Ethereum: common.HexToAddress("0x1"), Harmony: usdt.Harmony})
+		},
+	} {
+		if err := register(); !errors.Is(err, ErrTokenSymbolCollision) {
+			t.Fatalf("registering %s = %v, want ErrTokenSymbolCollision", name, err)
+		}
+	}
+	// The same address on the other chain is no collision
+	if err := registry.Register("WETH", SymbolTokens{Ethereum: usdt.Harmony, Harmony: usdt.Ethereum}); err != nil {
+		t.Fatal(err)
+	}
+	if symbols := registry.Symbols(); len(symbols) != 2 || symbols[0] != "USDT" || symbols[1] != "WETH" {
+		t.Fatalf("Symbols = %v, want [USDT WETH]", symbols)
+	}
+}
+
+func TestParseTokenSymbol(t *testing.T) {
+	harmony := common.HexToAddress("0x3c")
+	for _, value := range []string{
+		"usdt=" + checksummedAddress + ":" + harmony.Hex(),
+		"USDT=" + checksummedAddress + ":" + types.ToBech32(harmony),
+	} {
+		symbol, tokens, err := ParseTokenSymbol(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if symbol != "USDT" || tokens.Ethereum != common.HexToAddress(checksummedAddress) || tokens.Harmony != harmony {
+			t.Fatalf("ParseTokenSymbol(%q) = %s, %s:%s", value, symbol, tokens.Ethereum.Hex(), tokens.Harmony.Hex())
+		}
+	}
+
+	for _, value := range []string{
+		"USDT",
+		"=" + checksummedAddress + ":" + harmony.Hex(),
+		"USDT=" + checksummedAddress,
+		"USDT=" + corruptedAddress + ":" + harmony.Hex(),
+		"USDT=" + harmony.Hex() + ":" + corruptedAddress,
+		"USDT=" + harmony.Hex() + ":one1invalid",
+	} {
+		if _, _, err := ParseTokenSymbol(value); err == nil {
+			t.Fatalf("ParseTokenSymbol(%q) succeeded", value)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrPrecisionLoss is returned when an amount can't be scaled down to fewer decimals exactly
+var ErrPrecisionLoss = errors.New("amount is not exactly representable at the destination decimals")
+
+// Tokens, if set, rescales the amount of each claim to the decimals of the token on the chain
+// verifying the claim
+var Tokens *TokenRegistry
+
+// TokenDecimals holds a token's decimals as emitted in its events and as expected by the chain
+// verifying its claims, and the symbol its amounts are logged with
+type TokenDecimals struct {
+	Source uint8
+	Dest   uint8
+	Symbol string
+}
+
+// TokenRegistry maps the tokens bridged between chains with different decimals to their decimals
+// on each side. It is safe for concurrent use.
+type TokenRegistry struct {
+	mu     sync.RWMutex
+	tokens map[common.Address]TokenDecimals
+}
+
+// NewTokenRegistry initializes a new, empty TokenRegistry
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{tokens: make(map[common.Address]TokenDecimals)}
+}
+
+// Register records token's decimals on its source and destination chains, and its optional symbol
+func (r *TokenRegistry) Register(token common.Address, sourceDecimals, destDecimals uint8, symbol ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	decimals := TokenDecimals{Source: sourceDecimals, Dest: destDecimals}
+	if len(symbol) > 0 {
+		decimals.Symbol = symbol[0]
+	}
+	r.tokens[token] = decimals
+}
+
+// Decimals returns token's registered decimals, if any
+func (r *TokenRegistry) Decimals(token common.Address) (TokenDecimals, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	decimals, ok := r.tokens[token]
+	return decimals, ok
+}
+
+// NormalizeAmount rescales amount from token's source decimals to its destination decimals. The
+// amount of an unregistered token is returned unchanged. Scaling down returns ErrPrecisionLoss
+// rather than truncating an amount which isn't a multiple of the scale.
+func (r *TokenRegistry) NormalizeAmount(token common.Address, amount *big.Int) (*big.Int, error) {
+	decimals, ok := r.Decimals(token)
+	if !ok || decimals.Source == decimals.Dest || amount == nil {
+		return amount, nil
+	}
+
+	if decimals.Dest > decimals.Source {
+		scale := decimalScale(decimals.Dest - decimals.Source)
+		return new(big.Int).Mul(amount, scale), nil
+	}
+
+	scale := decimalScale(decimals.Source - decimals.Dest)
+	quotient, remainder := new(big.Int).QuoRem(amount, scale, new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, fmt.Errorf("%w: %v of %s from %d to %d decimals", ErrPrecisionLoss, amount,
+			token.Hex(), decimals.Source, decimals.Dest)
+	}
+	return quotient, nil
+}
+
+// ParseTokenDecimals parses a token's decimals given as "address=source:dest", optionally
+// followed by ":symbol"
+func ParseTokenDecimals(value string) (common.Address, TokenDecimals, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return common.Address{}, TokenDecimals{}, fmt.Errorf("invalid token decimals %q: expected address=source:dest", value)
+	}
+	if err := ValidateAddressChecksum(parts[0], false); err != nil {
+		return common.Address{}, TokenDecimals{}, fmt.Errorf("invalid token decimals %q: %w", value, err)
+	}
+	sides := strings.SplitN(parts[1], ":", 3)
+	if len(sides) < 2 {
+		return common.Address{}, TokenDecimals{}, fmt.Errorf("invalid token decimals %q: expected address=source:dest", value)
+	}
+
+	source, err := strconv.ParseUint(sides[0], 10, 8)
+	if err != nil {
+		return common.Address{}, TokenDecimals{}, fmt.Errorf("invalid source decimals in %q: %w", value, err)
+	}
+	dest, err := strconv.ParseUint(sides[1], 10, 8)
+	if err != nil {
+		return common.Address{}, TokenDecimals{}, fmt.Errorf("invalid destination decimals in %q: %w", value, err)
+	}
+	decimals := TokenDecimals{Source: uint8(source), Dest: uint8(dest)}
+	if len(sides) == 3 {
+		decimals.Symbol = sides[2]
+	}
+	return common.HexToAddress(parts[0]), decimals, nil
+}
+
+// FormatAmount formats amount, held at token's source decimals, as a decimal number followed by
+// its symbol, such as "12.5 USDC". The amount of an unregistered token is formatted as the raw
+// integer, noting its decimals are unknown.
+func (r *TokenRegistry) FormatAmount(token common.Address, amount *big.Int) string {
+	if r != nil {
+		if decimals, ok := r.Decimals(token); ok {
+			return FormatAmount(amount, decimals.Source, decimals.Symbol)
+		}
+	}
+	return fmt.Sprintf("%v (raw, decimals unknown)", amount)
+}
+
+// FormatAmount formats amount as a decimal number with decimals fractional digits, trimming
+// trailing zeros, followed by symbol if given
+func FormatAmount(amount *big.Int, decimals uint8, symbol string) string {
+	if amount == nil {
+		return "<nil>"
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(new(big.Int).Abs(amount), decimalScale(decimals), new(big.Int))
+	formatted := quotient.String()
+	if remainder.Sign() != 0 {
+		fraction := fmt.Sprintf("%0*s", int(decimals), remainder.String())
+		formatted += "." + strings.TrimRight(fraction, "0")
+	}
+	if amount.Sign() < 0 {
+		formatted = "-" + formatted
+	}
+	if symbol != "" {
+		formatted += " " + symbol
+	}
+	return formatted
+}
+
+// ParseTokenAmount parses a decimal amount such as "12.5" into base units of a token with
+// decimals fractional digits, as FormatAmount formats them. An amount with more significant
+// fractional digits than decimals returns ErrPrecisionLoss.
+func ParseTokenAmount(s string, decimals int) (*big.Int, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("invalid decimals %d", decimals)
+	}
+	digits := strings.TrimPrefix(s, "-")
+	whole, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, fraction = digits[:i], digits[i+1:]
+	}
+	if len(whole)+len(fraction) == 0 || !isDecimalDigits(whole) || !isDecimalDigits(fraction) {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("%w: %s has more than %d decimals", ErrPrecisionLoss, s, decimals)
+	}
+	amount, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(digits) < len(s) {
+		amount.Neg(amount)
+	}
+	return amount, nil
+}
+
+// isDecimalDigits reports whether s is only the digits 0-9
+func isDecimalDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatTokenAmount formats amount, held at token's source decimals, with Tokens
+func FormatTokenAmount(token common.Address, amount *big.Int) string {
+	return Tokens.FormatAmount(token, amount)
+}
+
+// normalizeAmount rescales amount with Tokens, if set, and then scales it with AmountScales, if set
+func normalizeAmount(token common.Address, amount *big.Int) (*big.Int, error) {
+	if Tokens != nil {
+		var err error
+		if amount, err = Tokens.NormalizeAmount(token, amount); err != nil {
+			return nil, err
+		}
+	}
+	if AmountScales == nil {
+		return amount, nil
+	}
+	return AmountScales.ScaleAmount(token, amount)
+}
+
+// decimalScale returns 10^decimals
+func decimalScale(decimals uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
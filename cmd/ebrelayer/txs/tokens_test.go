@@ -0,0 +1,173 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNormalizeAmount(t *testing.T) {
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	registry := NewTokenRegistry()
+	registry.Register(usdc, 6, 18)
+	registry.Register(goldenClaim.token, 18, 6)
+
+	tests := []struct {
+		name   string
+		token  common.Address
+		amount *big.Int
+		want   *big.Int
+	}{
+		{"upscale", usdc, big.NewInt(1500000), goldenClaim.amount},
+		{"upscale zero", usdc, big.NewInt(0), big.NewInt(0)},
+		{"downscale", goldenClaim.token, goldenClaim.amount, big.NewInt(1500000)},
+		{"downscale smallest unit", goldenClaim.token, big.NewInt(1000000000000), big.NewInt(1)},
+		{"unregistered", common.HexToAddress("0x1"), big.NewInt(123), big.NewInt(123)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registry.NormalizeAmount(tt.token, tt.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Cmp(tt.want) != 0 {
+				t.Fatalf("NormalizeAmount(%s) = %s, want %s", tt.amount, got, tt.want)
+			}
+		})
+	}
+
+	// Downscaling never truncates
+	for _, amount := range []*big.Int{big.NewInt(1), big.NewInt(1500000000000000001), big.NewInt(999999999999)} {
+		if got, err := registry.NormalizeAmount(goldenClaim.token, amount); !errors.Is(err, ErrPrecisionLoss) {
+			t.Fatalf("NormalizeAmount(%s) = %v, %v, want ErrPrecisionLoss", amount, got, err)
+		}
+	}
+}
+
+func TestNormalizedAmountIsSigned(t *testing.T) {
+	defer func(tokens *TokenRegistry) { Tokens = tokens }(Tokens)
+	Tokens = NewTokenRegistry()
+	Tokens.Register(goldenClaim.token, 6, 18)
+
+	// 1.5 tokens at 6 decimals are claimed as the golden 1.5 at 18
+	event := goldenEthEvent()
+	event.Amount = big.NewInt(1500000)
+	message, err := ClaimMessageForChain(event, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.Bytes2Hex(message) != goldenClaim.message {
+		t.Fatalf("claim message = %x, want the golden %s over the rescaled amount", message, goldenClaim.message)
+	}
+
+	event.Amount = big.NewInt(1)
+	Tokens.Register(goldenClaim.token, 18, 6)
+	if _, err := ClaimMessageForChain(event, nil); !errors.Is(err, ErrPrecisionLoss) {
+		t.Fatalf("claim message of an inexact amount = %v, want ErrPrecisionLoss", err)
+	}
+}
+
+func TestParseTokenDecimals(t *testing.T) {
+	token, decimals, err := ParseTokenDecimals(checksummedAddress + "=6:18:USDC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != common.HexToAddress(checksummedAddress) || decimals != (TokenDecimals{Source: 6, Dest: 18, Symbol: "USDC"}) {
+		t.Fatalf("ParseTokenDecimals = %s, %+v", token.Hex(), decimals)
+	}
+
+	for _, value := range []string{checksummedAddress, checksummedAddress + "=6", checksummedAddress + "=6:256",
+		corruptedAddress + "=6:18", "0x1=6:18"} {
+		if _, _, err := ParseTokenDecimals(value); err == nil {
+			t.Fatalf("ParseTokenDecimals(%q) succeeded", value)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   *big.Int
+		decimals uint8
+		symbol   string
+		want     string
+	}{
+		{goldenClaim.amount, 18, "USDT", "1.5 USDT"},
+		{big.NewInt(1), 18, "", "0.000000000000000001"},
+		{new(big.Int).Mul(goldenClaim.amount, big.NewInt(2)), 18, "ONE", "3 ONE"},
+		{big.NewInt(12500000), 6, "USDC", "12.5 USDC"},
+		{big.NewInt(1000001), 6, "USDC", "1.000001 USDC"},
+		{big.NewInt(-250000), 6, "", "-0.25"},
+		{big.NewInt(0), 6, "USDC", "0 USDC"},
+		{nil, 6, "USDC", "<nil>"},
+	}
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.decimals, tt.symbol); got != tt.want {
+			t.Fatalf("FormatAmount(%s, %d) = %q, want %q", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+
+	registry := NewTokenRegistry()
+	registry.Register(goldenClaim.token, 6, 18, "USDT")
+	if got := registry.FormatAmount(goldenClaim.token, big.NewInt(1500000)); got != "1.5 USDT" {
+		t.Fatalf("FormatAmount of a registered token = %q, want it at its source decimals", got)
+	}
+	if got := registry.FormatAmount(common.HexToAddress("0x1"), big.NewInt(1500000)); got != "1500000 (raw, decimals unknown)" {
+		t.Fatalf("FormatAmount of an unregistered token = %q", got)
+	}
+}
+
+func TestParseTokenAmount(t *testing.T) {
+	tests := []struct {
+		s        string
+		decimals int
+		want     *big.Int
+	}{
+		// Whole numbers
+		{"3", 18, new(big.Int).Mul(goldenClaim.amount, big.NewInt(2))},
+		{"1500000", 0, big.NewInt(1500000)},
+		{"0", 6, big.NewInt(0)},
+		// Fractional values
+		{"1.5", 18, goldenClaim.amount},
+		{"12.5", 6, big.NewInt(12500000)},
+		{".25", 6, big.NewInt(250000)},
+		{"7.", 2, big.NewInt(700)},
+		{"-0.25", 6, big.NewInt(-250000)},
+		{"0.000000000000000001", 18, big.NewInt(1)},
+		// Zeros beyond the decimals lose nothing
+		{"1.000001000", 6, big.NewInt(1000001)},
+		{"2.00", 0, big.NewInt(2)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTokenAmount(tt.s, tt.decimals)
+		if err != nil {
+			t.Fatalf("ParseTokenAmount(%q, %d) = %v", tt.s, tt.decimals, err)
+		}
+		if got.Cmp(tt.want) != 0 {
+			t.Fatalf("ParseTokenAmount(%q, %d) = %s, want %s", tt.s, tt.decimals, got, tt.want)
+		}
+	}
+
+	// Over-precise inputs can't be expressed in base units
+	for s, decimals := range map[string]int{"1.0000001": 6, "0.5": 0, "1.0000000000000000001": 18} {
+		if _, err := ParseTokenAmount(s, decimals); !errors.Is(err, ErrPrecisionLoss) {
+			t.Fatalf("ParseTokenAmount(%q, %d) = %v, want ErrPrecisionLoss", s, decimals, err)
+		}
+	}
+	for _, s := range []string{"", ".", "-", "1.2.3", "1e6", "+1", "1,5", " 1", "0x10"} {
+		if _, err := ParseTokenAmount(s, 6); err == nil || errors.Is(err, ErrPrecisionLoss) {
+			t.Fatalf("ParseTokenAmount(%q) = %v, want it rejected as invalid", s, err)
+		}
+	}
+	if _, err := ParseTokenAmount("1", -1); err == nil {
+		t.Fatal("parsed an amount with negative decimals")
+	}
+
+	// ParseTokenAmount is the inverse of FormatAmount
+	for _, amount := range []*big.Int{goldenClaim.amount, big.NewInt(1000001), big.NewInt(-250000)} {
+		if got, err := ParseTokenAmount(FormatAmount(amount, 6, ""), 6); err != nil || got.Cmp(amount) != 0 {
+			t.Fatalf("ParseTokenAmount(FormatAmount(%s)) = %s, %v", amount, got, err)
+		}
+	}
+}
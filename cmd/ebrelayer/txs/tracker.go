@@ -0,0 +1,91 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// ErrAlreadySigned is returned when asked to sign a claim for an unlock ID which was already signed
+var ErrAlreadySigned = errors.New("unlock ID already signed")
+
+// ClaimTracker, if set, rejects signing any unlock ID twice per chain
+var ClaimTracker *SignedClaimTracker
+
+// SignedClaimTracker records the unlock IDs signed on each chain, so the relayer never signs one
+// twice and wastes gas on a reverting submission. A claim retried after a failed submission is
+// given its first signature from SignatureCache rather than being signed again. It is safe for
+// concurrent use.
+type SignedClaimTracker struct {
+	store types.KeyStore
+	mu    sync.Mutex
+}
+
+// NewSignedClaimTracker initializes a new SignedClaimTracker recording into store, or into memory
+// if store is nil
+func NewSignedClaimTracker(store types.KeyStore) *SignedClaimTracker {
+	if store == nil {
+		store = types.NewMemoryKeyStore()
+	}
+	return &SignedClaimTracker{store: store}
+}
+
+// HasSigned reports whether unlockID was signed on chain
+func (t *SignedClaimTracker) HasSigned(chain string, unlockID *big.Int) (bool, error) {
+	return t.store.Has(signedClaimKey(chain, unlockID))
+}
+
+// CheckSigned returns ErrAlreadySigned if unlockID was signed on chain
+func (t *SignedClaimTracker) CheckSigned(chain string, unlockID *big.Int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.checkSigned(chain, unlockID)
+}
+
+// MarkSigned records unlockID as signed on chain, returning ErrAlreadySigned if it already was
+func (t *SignedClaimTracker) MarkSigned(chain string, unlockID *big.Int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.checkSigned(chain, unlockID); err != nil {
+		return err
+	}
+	return t.store.Add(signedClaimKey(chain, unlockID))
+}
+
+// checkSigned implements CheckSigned, with t.mu held
+func (t *SignedClaimTracker) checkSigned(chain string, unlockID *big.Int) error {
+	signed, err := t.store.Has(signedClaimKey(chain, unlockID))
+	if err != nil {
+		return err
+	}
+	if signed {
+		return fmt.Errorf("%w: %v on %s", ErrAlreadySigned, unlockID, chain)
+	}
+	return nil
+}
+
+// signedClaimKey returns the store key of a chain's unlock ID
+func signedClaimKey(chain string, unlockID *big.Int) string {
+	return chain + ":" + unlockID.String()
+}
+
+// checkTracked returns ErrAlreadySigned if ClaimTracker, if set, holds unlockID as signed on chain
+func checkTracked(chain string, unlockID *big.Int) error {
+	if ClaimTracker == nil {
+		return nil
+	}
+	return ClaimTracker.CheckSigned(chain, unlockID)
+}
+
+// trackSigned records a signed claim with ClaimTracker, if set
+func trackSigned(chain string, unlockID *big.Int) error {
+	if ClaimTracker == nil {
+		return nil
+	}
+	return ClaimTracker.MarkSigned(chain, unlockID)
+}
@@ -0,0 +1,103 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// useClaimTracker installs a ClaimTracker over store, returning the function removing it
+func useClaimTracker(store types.KeyStore) func() {
+	ClaimTracker = NewSignedClaimTracker(store)
+	return func() { ClaimTracker = nil }
+}
+
+func TestSigningUnlockIDTwiceReturnsErrAlreadySigned(t *testing.T) {
+	defer useClaimTracker(nil)()
+	signer := NewKeySigner(testKey(t))
+	event := testClaimEvents(1)[0]
+	if _, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{event}); err != nil {
+		t.Fatal(err)
+	}
+
+	conflicting := event
+	conflicting.Amount = new(big.Int).Add(event.Amount, big.NewInt(1))
+	for name, again := range map[string]types.EthLogNewUnlockClaimEvent{"the same": event, "another": conflicting} {
+		_, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{again})
+		if !errors.Is(err, ErrAlreadySigned) {
+			t.Fatalf("signing unlock ID %s again over %s claim = %v, want ErrAlreadySigned", event.UnlockID, name, err)
+		}
+	}
+	// Unlock IDs are tracked per chain
+	hmyEvent := types.HmyLogNewUnlockClaimEvent{
+		UnlockID:        event.UnlockID,
+		EthereumSender:  event.HarmonySender,
+		HarmonyReceiver: event.EthereumReceiver,
+		TokenAddress:    event.TokenAddress,
+		Amount:          conflicting.Amount,
+	}
+	if _, err := HmySignClaimsBatch(signer, []types.HmyLogNewUnlockClaimEvent{hmyEvent}); err != nil {
+		t.Fatalf("signing the unlock ID on Harmony = %v", err)
+	}
+}
+
+func TestSignedClaimTrackerSurvivesRestart(t *testing.T) {
+	store := types.NewMemoryKeyStore()
+	unlockID := big.NewInt(7)
+	if err := NewSignedClaimTracker(store).MarkSigned(ethereumChainLabel, unlockID); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := NewSignedClaimTracker(store)
+	if signed, err := restarted.HasSigned(ethereumChainLabel, unlockID); err != nil || !signed {
+		t.Fatalf("HasSigned after a restart = %v, %v, want true", signed, err)
+	}
+	if signed, err := restarted.HasSigned(harmonyChainLabel, unlockID); err != nil || signed {
+		t.Fatalf("HasSigned on another chain = %v, %v, want false", signed, err)
+	}
+	if err := restarted.CheckSigned(ethereumChainLabel, unlockID); !errors.Is(err, ErrAlreadySigned) {
+		t.Fatalf("CheckSigned after a restart = %v, want ErrAlreadySigned", err)
+	}
+	if err := restarted.CheckSigned(harmonyChainLabel, unlockID); err != nil {
+		t.Fatalf("CheckSigned on another chain = %v", err)
+	}
+	if err := restarted.MarkSigned(ethereumChainLabel, unlockID); !errors.Is(err, ErrAlreadySigned) {
+		t.Fatalf("MarkSigned twice = %v, want ErrAlreadySigned", err)
+	}
+}
+
+func TestRetriedClaimReusesCachedSignature(t *testing.T) {
+	defer useClaimTracker(nil)()
+	defer func(cache *ClaimSignatureCache) { SignatureCache = cache }(SignatureCache)
+	_, restore := useRecordingLogger()
+	defer restore()
+	var err error
+	if SignatureCache, err = NewClaimSignatureCache(""); err != nil {
+		t.Fatal(err)
+	}
+	signer := &countingSigner{Signer: NewKeySigner(testKey(t))}
+	event := testClaimEvents(1)[0]
+	first, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{event})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Retrying the claim returns its first signature without signing the unlock ID again
+	retried, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{event})
+	if err != nil {
+		t.Fatalf("retrying a cached claim = %v", err)
+	}
+	if signer.signed != 1 || !bytes.Equal(retried[0].Signature, first[0].Signature) {
+		t.Fatalf("retried claim signed %d times as %x, want the first signature %x", signer.signed,
+			retried[0].Signature, first[0].Signature)
+	}
+
+	conflicting := event
+	conflicting.Amount = new(big.Int).Add(event.Amount, big.NewInt(1))
+	if _, err := SignClaimsBatch(signer, []types.EthLogNewUnlockClaimEvent{conflicting}); !errors.Is(err, ErrAlreadySigned) {
+		t.Fatalf("signing unlock ID %s over another claim = %v, want ErrAlreadySigned", event.UnlockID, err)
+	}
+}
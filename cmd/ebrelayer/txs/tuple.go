@@ -0,0 +1,100 @@
+package txs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tupleComponents splits a tuple type such as "(address,uint256)" into the types of its
+// components in declaration order, dropping any component names as in "(address token,uint256
+// amount)". It reports false if typ is not a tuple type.
+func tupleComponents(typ string) ([]string, bool) {
+	if len(typ) < 2 || typ[0] != '(' || typ[len(typ)-1] != ')' {
+		return nil, false
+	}
+
+	inner := typ[1 : len(typ)-1]
+	var components []string
+	depth, start := 0, 0
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			switch inner[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth != 0 || inner[i] != ',' {
+				continue
+			}
+		}
+		if component := componentType(inner[start:i]); component != "" {
+			components = append(components, component)
+		}
+		start = i + 1
+	}
+	return components, true
+}
+
+// componentType strips the name from a tuple component such as "uint256 amount"
+func componentType(component string) string {
+	component = strings.TrimSpace(component)
+	depth := 0
+	for i := len(component) - 1; i >= 0; i-- {
+		switch component[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+		case ' ':
+			// An enum type such as "enum ClaimKind" has a space of its own
+			if depth == 0 && strings.TrimSpace(component[:i]) != strings.TrimSpace(enumTypePrefix) {
+				return strings.TrimSpace(component[:i])
+			}
+		}
+	}
+	return component
+}
+
+// tupleValues returns the n component values of a tuple given as a []interface{}, or as a
+// struct, or pointer to one, whose exported fields are the components in declaration order
+func tupleValues(typ string, value interface{}, n int) []interface{} {
+	if values, ok := value.([]interface{}); ok {
+		if len(values) != n {
+			panic(fmt.Sprintf("invalid value for %s: %d components", typ, len(values)))
+		}
+		return values
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("invalid value for %s: %T is not a struct or []interface{}", typ, value))
+	}
+
+	var values []interface{}
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath == "" {
+			values = append(values, v.Field(i).Interface())
+		}
+	}
+	if len(values) != n {
+		panic(fmt.Sprintf("invalid value for %s: %d exported fields", typ, len(values)))
+	}
+	return values
+}
+
+// packTuple packs each component of a tuple in declaration order. Packed mode concatenates the
+// components' packed encodings, while ABI mode, as used for array elements, pads each component
+// to 32 bytes as abi.encode does for a tuple of static types.
+func packTuple(typ string, components []string, value interface{}, abiMode bool) []byte {
+	values := tupleValues(typ, value, len(components))
+	var result [][]byte
+	for i, component := range components {
+		result = append(result, pack(component, values[i], abiMode))
+	}
+	return concatByteSlices(result...)
+}
@@ -0,0 +1,88 @@
+package txs
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// tokenAmount is a simple two-field tuple, as a contract's struct Transfer { address token; uint256 amount; }
+type tokenAmount struct {
+	Token  common.Address
+	Amount *big.Int
+}
+
+// tokenAmountType returns go-ethereum's type for a tokenAmount tuple
+func tokenAmountType(t *testing.T, typ string) abi.Type {
+	abiType, err := abi.NewType(typ, "", []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abiType
+}
+
+func TestTupleMatchesGoEthereum(t *testing.T) {
+	value := tokenAmount{Token: goldenClaim.token, Amount: goldenClaim.amount}
+
+	// Each element of an array is a static tuple, encoded as go-ethereum does
+	want, err := abi.Arguments{{Type: tokenAmountType(t, "tuple")}}.Pack(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pack("(address token,uint256 amount)", value, true); !bytes.Equal(got, want) {
+		t.Fatalf("ABI packed tuple = %x, want %x", got, want)
+	}
+	values := []tokenAmount{value, {Token: common.HexToAddress(checksummedAddress), Amount: big.NewInt(1)}}
+	wantArray, err := abiPacked(tokenAmountType(t, "tuple[]"), values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pack("(address,uint256)[]", values, false); !bytes.Equal(got, wantArray) {
+		t.Fatalf("packed tuple array = %x, want %x", got, wantArray)
+	}
+
+	// Standalone, the components are packed back to back, as abi.encodePacked does
+	packed := append(goldenClaim.token.Bytes(), common.LeftPadBytes(goldenClaim.amount.Bytes(), 32)...)
+	for _, input := range []interface{}{value, &value, []interface{}{value.Token, value.Amount}} {
+		if got := pack("(address,uint256)", input, false); !bytes.Equal(got, packed) {
+			t.Fatalf("packed tuple %v = %x, want %x", input, got, packed)
+		}
+	}
+	hash, err := SoliditySHA3Typed([]string{"(address,uint256)"}, []interface{}{value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hash, Keccak256(packed)) {
+		t.Fatalf("tuple hash = %x, want the hash of %x", hash, packed)
+	}
+}
+
+func TestTupleComponents(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want []string
+	}{
+		{"(address,uint256)", []string{"address", "uint256"}},
+		{"(address token, uint256 amount)", []string{"address", "uint256"}},
+		{"(bytes32,(address,bool) inner)", []string{"bytes32", "(address,bool)"}},
+		{"()", nil},
+	}
+	for _, tt := range tests {
+		if got, ok := tupleComponents(tt.typ); !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("tupleComponents(%q) = %q, %v, want %q", tt.typ, got, ok, tt.want)
+		}
+	}
+	if _, ok := tupleComponents("uint256"); ok {
+		t.Fatal("uint256 split as a tuple")
+	}
+	if _, err := SoliditySHA3Typed([]string{"(address,uint24)"}, []interface{}{[]interface{}{common.Address{}, 1}}); err == nil {
+		t.Fatal("hashed a tuple with an unsupported component")
+	}
+}
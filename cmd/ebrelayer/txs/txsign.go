@@ -0,0 +1,67 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EthChainID, if set, is the chain ID Ethereum claim transactions are signed for. Nil signs for
+// the chain ID the node reports.
+var EthChainID *big.Int
+
+// ErrNotAuthorized is returned when asked to sign a transaction for an account other than the signer's
+var ErrNotAuthorized = errors.New("not authorized to sign this account")
+
+// TransactionSigner returns the signer of transactions for chainID: EIP-155, which binds the
+// signature to the chain so the transaction can't be replayed on another, or Homestead if
+// chainID is nil. The go-ethereum version this relayer builds against predates London, whose
+// signer only adds typed transactions, which it submits as legacy transactions.
+func TransactionSigner(chainID *big.Int) ctypes.Signer {
+	if chainID == nil {
+		return ctypes.HomesteadSigner{}
+	}
+	return ctypes.NewEIP155Signer(chainID)
+}
+
+// SignTransaction signs tx with signer for chainID, per TransactionSigner
+func SignTransaction(tx *ctypes.Transaction, chainID *big.Int, signer Signer) (*ctypes.Transaction, error) {
+	txSigner := TransactionSigner(chainID)
+	sig, err := signer.Sign(txSigner.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) == crypto.SignatureLength && sig[crypto.RecoveryIDOffset] >= 27 {
+		// WithSignature expects the recovery ID as 0/1, not web3's 27/28
+		sig = append([]byte(nil), sig...)
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	return tx.WithSignature(txSigner, sig)
+}
+
+// NewTransactOpts returns TransactOpts from signer's address, signing with SignTransaction for chainID
+func NewTransactOpts(signer Signer, chainID *big.Int) *bind.TransactOpts {
+	from := signer.Address()
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(_ ctypes.Signer, address common.Address, tx *ctypes.Transaction) (*ctypes.Transaction, error) {
+			if address != from {
+				return nil, ErrNotAuthorized
+			}
+			return SignTransaction(tx, chainID, signer)
+		},
+	}
+}
+
+// ethTransactionChainID returns EthChainID, or the chain ID client's node reports if unset
+func ethTransactionChainID(ctx context.Context, client *EthClient) (*big.Int, error) {
+	if EthChainID != nil {
+		return EthChainID, nil
+	}
+	return client.networkChainID(ctx)
+}
@@ -0,0 +1,84 @@
+package txs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// web3Signer is a Signer returning recovery IDs as web3's 27/28, as remote signers may
+type web3Signer struct {
+	Signer
+}
+
+func (s web3Signer) Sign(msg []byte) ([]byte, error) {
+	sig, err := s.Signer.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+func TestSignTransactionRecoversSender(t *testing.T) {
+	signer := NewKeySigner(testKey(t))
+	tx := ctypes.NewTransaction(9, goldenClaim.recipient, big.NewInt(0), 200000, big.NewInt(1e9), common.Hex2Bytes(goldenClaim.message))
+
+	for _, chainID := range []*big.Int{big.NewInt(1), big.NewInt(5), big.NewInt(1666600000)} {
+		for _, s := range []Signer{signer, web3Signer{signer}} {
+			signed, err := SignTransaction(tx, chainID, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !signed.Protected() || signed.ChainId().Cmp(chainID) != 0 {
+				t.Fatalf("signed for chain %s, protected %v, want EIP-155 chain %s", signed.ChainId(), signed.Protected(), chainID)
+			}
+			sender, err := ctypes.Sender(ctypes.NewEIP155Signer(chainID), signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sender != signer.Address() {
+				t.Fatalf("chain %s transaction recovered %s, want %s", chainID, sender.Hex(), signer.Address().Hex())
+			}
+
+			// The signature doesn't carry over to another chain
+			if _, err := ctypes.Sender(ctypes.NewEIP155Signer(new(big.Int).Add(chainID, big.NewInt(1))), signed); err == nil {
+				t.Fatalf("chain %s transaction recovered a sender on another chain", chainID)
+			}
+		}
+	}
+
+	// Without a chain ID, transactions are signed as Homestead ones
+	signed, err := SignTransaction(tx, nil, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := ctypes.Sender(ctypes.HomesteadSigner{}, signed); err != nil || signed.Protected() || sender != signer.Address() {
+		t.Fatalf("Homestead transaction recovered %s, %v, protected %v", sender.Hex(), err, signed.Protected())
+	}
+}
+
+func TestNewTransactOpts(t *testing.T) {
+	signer := NewKeySigner(testKey(t))
+	opts := NewTransactOpts(signer, big.NewInt(5))
+	if opts.From != signer.Address() {
+		t.Fatalf("From = %s, want %s", opts.From.Hex(), signer.Address().Hex())
+	}
+
+	tx := ctypes.NewTransaction(0, goldenClaim.recipient, big.NewInt(0), 21000, big.NewInt(1e9), nil)
+	// The bindings pass their own Homestead signer, which is ignored for the chain's
+	signed, err := opts.Signer(ctypes.HomesteadSigner{}, opts.From, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.ChainId().Int64() != 5 {
+		t.Fatalf("signed for chain %s, want 5", signed.ChainId())
+	}
+	if _, err := opts.Signer(ctypes.HomesteadSigner{}, goldenClaim.sender, tx); !errors.Is(err, ErrNotAuthorized) {
+		t.Fatalf("signing for another account = %v, want ErrNotAuthorized", err)
+	}
+}
@@ -27,6 +27,8 @@ type EthUnlockClaim struct {
 	EthereumReceiver common.Address
 	Token            common.Address
 	Amount           *big.Int
+	// LockTx is the Harmony transaction of the lock the claim relays
+	LockTx common.Hash
 }
 
 // HmyUnlockClaim contains data required to make an Harmony UnlockClaim
@@ -36,4 +38,6 @@ type HmyUnlockClaim struct {
 	HarmonyReceiver common.Address
 	Token           common.Address
 	Amount          *big.Int
+	// LockTx is the Ethereum transaction of the lock the claim relays
+	LockTx common.Hash
 }
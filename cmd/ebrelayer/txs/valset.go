@@ -0,0 +1,145 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	hbind "github.com/harmony-one/harmony/accounts/abi/bind"
+
+	ethvalset "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/ethereum/bindings/valset"
+	hmyvalset "github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/contract/generated/harmony/bindings/valset"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+	"github.com/mochi-lab/eth-one-bridge/hmyclient"
+)
+
+// ErrNotRegisteredValidator is returned when a relayer's key isn't a validator its chain's Valset
+// contract has registered, so the Oracle would reject every claim it signs
+var ErrNotRegisteredValidator = errors.New("key is not a registered validator")
+
+// ValidatorSetCaller queries whether a Valset contract has registered a validator
+type ValidatorSetCaller interface {
+	IsActiveValidator(opts *bind.CallOpts, validator common.Address) (bool, error)
+}
+
+// ValidatorCheck checks that a relayer's key is a validator Chain's Valset contract has
+// registered. The Valset only answers membership queries, so the validators it has registered
+// are listed by checking each address Candidates returns, such as those its logs ever added.
+type ValidatorCheck struct {
+	Chain      string
+	Valset     ValidatorSetCaller
+	Candidates func(ctx context.Context) ([]common.Address, error)
+}
+
+// Check returns ErrNotRegisteredValidator, listing the registered validators, if validator
+// isn't one
+func (c ValidatorCheck) Check(ctx context.Context, validator common.Address) error {
+	active, err := c.Valset.IsActiveValidator(&bind.CallOpts{Context: ctx}, validator)
+	if err != nil {
+		return fmt.Errorf("checking %s validator set: %w", c.Chain, err)
+	}
+	if active {
+		return nil
+	}
+
+	registered, err := c.RegisteredValidators(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s on %s, and listing the registered validators failed: %v",
+			ErrNotRegisteredValidator, c.format(validator), c.Chain, err)
+	}
+	names := make([]string, len(registered))
+	for i, address := range registered {
+		names[i] = c.format(address)
+	}
+	return fmt.Errorf("%w: %s on %s, registered validators are [%s]", ErrNotRegisteredValidator,
+		c.format(validator), c.Chain, strings.Join(names, ", "))
+}
+
+// RegisteredValidators returns the Candidates the Valset currently registers as validators
+func (c ValidatorCheck) RegisteredValidators(ctx context.Context) ([]common.Address, error) {
+	if c.Candidates == nil {
+		return nil, errors.New("no validator candidates to check")
+	}
+	candidates, err := c.Candidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered []common.Address
+	seen := make(map[common.Address]bool, len(candidates))
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		active, err := c.Valset.IsActiveValidator(&bind.CallOpts{Context: ctx}, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if active {
+			registered = append(registered, candidate)
+		}
+	}
+	return registered, nil
+}
+
+// format returns address as the chain's explorers display it
+func (c ValidatorCheck) format(address common.Address) string {
+	return chainAddress(c.Chain, address)
+}
+
+// chainAddress returns address as the explorers of chain display it
+func chainAddress(chain string, address common.Address) string {
+	if chain == harmonyChainLabel {
+		return types.ToBech32(address)
+	}
+	return address.Hex()
+}
+
+// NewEthValidatorCheck initializes a ValidatorCheck against the Ethereum Valset at valsetAddress
+func NewEthValidatorCheck(client *ethclient.Client, valsetAddress common.Address) (ValidatorCheck, error) {
+	valset, err := ethvalset.NewValset(valsetAddress, client)
+	if err != nil {
+		return ValidatorCheck{}, err
+	}
+	candidates := func(ctx context.Context) ([]common.Address, error) {
+		logs, err := valset.FilterEthLogValidatorAdded(&bind.FilterOpts{Context: ctx})
+		if err != nil {
+			return nil, err
+		}
+		defer logs.Close()
+
+		var added []common.Address
+		for logs.Next() {
+			added = append(added, logs.Event.Validator)
+		}
+		return added, logs.Error()
+	}
+	return ValidatorCheck{Chain: ethereumChainLabel, Valset: valset, Candidates: candidates}, nil
+}
+
+// NewHmyValidatorCheck initializes a ValidatorCheck against the Harmony Valset at valsetAddress
+func NewHmyValidatorCheck(client *hmyclient.Client, valsetAddress common.Address) (ValidatorCheck, error) {
+	valset, err := hmyvalset.NewValset(valsetAddress, client)
+	if err != nil {
+		return ValidatorCheck{}, err
+	}
+	candidates := func(ctx context.Context) ([]common.Address, error) {
+		logs, err := valset.FilterEthLogValidatorAdded(&hbind.FilterOpts{Context: ctx})
+		if err != nil {
+			return nil, err
+		}
+		defer logs.Close()
+
+		var added []common.Address
+		for logs.Next() {
+			added = append(added, logs.Event.Validator)
+		}
+		return added, logs.Error()
+	}
+	return ValidatorCheck{Chain: harmonyChainLabel, Valset: valset, Candidates: candidates}, nil
+}
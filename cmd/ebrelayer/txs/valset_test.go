@@ -0,0 +1,75 @@
+package txs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// testValset is a ValidatorSetCaller registering the validators it maps to true
+type testValset map[common.Address]bool
+
+func (v testValset) IsActiveValidator(_ *bind.CallOpts, validator common.Address) (bool, error) {
+	return v[validator], nil
+}
+
+// testValidatorCheck returns a ValidatorCheck on chain of valset, whose logs added added
+func testValidatorCheck(chain string, valset testValset, added ...common.Address) ValidatorCheck {
+	return ValidatorCheck{
+		Chain:      chain,
+		Valset:     valset,
+		Candidates: func(context.Context) ([]common.Address, error) { return added, nil },
+	}
+}
+
+func TestValidatorCheck(t *testing.T) {
+	relayer := crypto.PubkeyToAddress(testKey(t).PublicKey)
+	other := goldenClaim.sender
+	// removed was added by the Valset's logs, then removed
+	removed := goldenClaim.recipient
+
+	registered := testValidatorCheck(ethereumChainLabel, testValset{relayer: true, other: true}, relayer, other)
+	if err := registered.Check(context.Background(), relayer); err != nil {
+		t.Fatalf("Check of a registered key = %v", err)
+	}
+
+	unregistered := testValidatorCheck(ethereumChainLabel, testValset{other: true}, other, removed, other)
+	err := unregistered.Check(context.Background(), relayer)
+	if !errors.Is(err, ErrNotRegisteredValidator) {
+		t.Fatalf("Check of an unregistered key = %v, want ErrNotRegisteredValidator", err)
+	}
+	// Only the still registered candidates are listed, once each
+	if want := "registered validators are [" + other.Hex() + "]"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("Check = %v, want it to list %s", err, other.Hex())
+	}
+
+	// Harmony addresses are listed in their one1 form
+	harmony := testValidatorCheck(harmonyChainLabel, testValset{other: true}, other)
+	err = harmony.Check(context.Background(), relayer)
+	if !errors.Is(err, ErrNotRegisteredValidator) || !strings.Contains(err.Error(), types.ToBech32(other)) ||
+		!strings.Contains(err.Error(), types.ToBech32(relayer)) {
+		t.Fatalf("Harmony Check = %v, want one1 addresses", err)
+	}
+}
+
+func TestValidatorCheckCandidatesFailure(t *testing.T) {
+	check := ValidatorCheck{
+		Chain:  ethereumChainLabel,
+		Valset: testValset{},
+		Candidates: func(context.Context) ([]common.Address, error) {
+			return nil, errors.New("logs unavailable")
+		},
+	}
+	// The key is still reported unregistered when the registered validators can't be listed
+	err := check.Check(context.Background(), goldenClaim.sender)
+	if !errors.Is(err, ErrNotRegisteredValidator) || !strings.Contains(err.Error(), "logs unavailable") {
+		t.Fatalf("Check = %v, want ErrNotRegisteredValidator with the listing error", err)
+	}
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Bech32HRP is the human-readable prefix of Harmony's bech32 addresses
+const Bech32HRP = "one"
+
+// ErrInvalidBech32 is returned when decoding a string which isn't a valid Harmony bech32 address
+var ErrInvalidBech32 = errors.New("invalid bech32 address")
+
+const (
+	bech32Charset        = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32ChecksumLength = 6
+	bech32MaxLength      = 90
+)
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// ToBech32 returns addr as a Harmony bech32 address, one1..., as Harmony explorers display it
+func ToBech32(addr common.Address) string {
+	data, _ := convertBits(addr.Bytes(), 8, 5, true)
+	checksum := bech32Checksum(Bech32HRP, data)
+
+	var b strings.Builder
+	b.WriteString(Bech32HRP + "1")
+	for _, value := range append(data, checksum...) {
+		b.WriteByte(bech32Charset[value])
+	}
+	return b.String()
+}
+
+// FromBech32 decodes a Harmony bech32 address, returning ErrInvalidBech32 if s isn't one: if its
+// prefix isn't one1, it mixes cases, it has characters outside the bech32 charset, its checksum
+// doesn't match or it doesn't encode exactly 20 bytes
+func FromBech32(s string) (common.Address, error) {
+	if len(s) > bech32MaxLength {
+		return common.Address{}, fmt.Errorf("%w: %d characters, at most %d", ErrInvalidBech32, len(s), bech32MaxLength)
+	}
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return common.Address{}, fmt.Errorf("%w: mixed case", ErrInvalidBech32)
+	}
+
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 0 || lower[:separator] != Bech32HRP {
+		return common.Address{}, fmt.Errorf("%w: expected %s1 prefix", ErrInvalidBech32, Bech32HRP)
+	}
+	if len(lower)-separator-1 < bech32ChecksumLength {
+		return common.Address{}, fmt.Errorf("%w: too short", ErrInvalidBech32)
+	}
+
+	data := make([]byte, 0, len(lower)-separator-1)
+	for _, c := range lower[separator+1:] {
+		value := strings.IndexRune(bech32Charset, c)
+		if value < 0 {
+			return common.Address{}, fmt.Errorf("%w: invalid character %q", ErrInvalidBech32, c)
+		}
+		data = append(data, byte(value))
+	}
+	if bech32Polymod(append(bech32HRPExpand(Bech32HRP), data...)) != 1 {
+		return common.Address{}, fmt.Errorf("%w: checksum mismatch", ErrInvalidBech32)
+	}
+
+	decoded, err := convertBits(data[:len(data)-bech32ChecksumLength], 5, 8, false)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidBech32, err)
+	}
+	if len(decoded) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidBech32, len(decoded), common.AddressLength)
+	}
+	return common.BytesToAddress(decoded), nil
+}
+
+// bech32Polymod computes the BCH checksum of bech32 values
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, value := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(value)
+		for i, generator := range bech32Generator {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands a human-readable prefix into the values its checksum covers
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// bech32Checksum returns the checksum values of data under hrp
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, make([]byte, bech32ChecksumLength)...)
+	mod := bech32Polymod(values) ^ 1
+
+	checksum := make([]byte, bech32ChecksumLength)
+	for i := range checksum {
+		checksum[i] = byte(mod>>uint(5*(5-i))) & 31
+	}
+	return checksum
+}
+
+// convertBits regroups data from fromBits-bit to toBits-bit values, padding the last value with
+// zeros if pad is set, and otherwise rejecting leftover bits which aren't zero padding
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+
+	var converted []byte
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, fmt.Errorf("value %d exceeds %d bits", value, fromBits)
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			converted = append(converted, byte(acc>>bits&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			converted = append(converted, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return converted, nil
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// bech32Pairs are addresses with their Harmony bech32 forms, the first Harmony's documented example
+var bech32Pairs = []struct {
+	hex, bech32 string
+}{
+	{"0x0B585F8DaEfBC68a311FbD4cB20d9174aD174016", "one1pdv9lrdwl0rg5vglh4xtyrv3wjk3wsqket7zxy"},
+	{"0x0000000000000000000000000000000000000000", "one1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqquzw7vz"},
+	{"0xdAC17F958D2ee523a2206206994597C13D831ec7", "one1mtqhl9vd9mjj8g3qvgrfj3vhcy7cx8k89xwxnh"},
+	{"0xFFfFfFffFFfffFFfFFfFFFFFffFFFffffFfFFFfF", "one1llllllllllllllllllllllllllllllllsn03tg"},
+}
+
+func TestBech32KnownPairs(t *testing.T) {
+	for _, pair := range bech32Pairs {
+		addr := common.HexToAddress(pair.hex)
+		if got := ToBech32(addr); got != pair.bech32 {
+			t.Fatalf("ToBech32(%s) = %s, want %s", pair.hex, got, pair.bech32)
+		}
+		for _, s := range []string{pair.bech32, strings.ToUpper(pair.bech32)} {
+			decoded, err := FromBech32(s)
+			if err != nil {
+				t.Fatalf("FromBech32(%s) = %v", s, err)
+			}
+			if decoded != addr {
+				t.Fatalf("FromBech32(%s) = %s, want %s", s, decoded.Hex(), pair.hex)
+			}
+		}
+	}
+}
+
+func TestFromBech32Malformed(t *testing.T) {
+	valid := bech32Pairs[0].bech32
+	for name, s := range map[string]string{
+		"empty":           "",
+		"other prefix":    "bc1" + valid[4:],
+		"no separator":    "one" + valid[4:],
+		"mixed case":      "One1" + valid[4:],
+		"invalid char":    valid[:10] + "b" + valid[11:],
+		"bad checksum":    valid[:len(valid)-1] + "q",
+		"truncated":       valid[:len(valid)-5],
+		"10-byte payload": "one1qqqqqqqqqqqqqqqq3xnzln",
+		"too long":        "one1" + strings.Repeat("q", 90),
+	} {
+		if _, err := FromBech32(s); !errors.Is(err, ErrInvalidBech32) {
+			t.Fatalf("FromBech32 of %s %q = %v, want ErrInvalidBech32", name, s, err)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
-import "log"
+import (
+	"log"
+	"sync"
+)
 
 // TODO: This should be moved to new 'events' directory and expanded so that it can
 // serve as a local store of witnessed events and allow for re-trying failed relays.
@@ -11,30 +14,44 @@ var EventRecords = make(map[string]EthLogLockEvent)
 // HarmonyEventRecords map of transaction hashes to EthereumEvent structs
 var HarmonyEventRecords = make(map[string]HmyLogLockEvent)
 
+// eventRecordsMu guards EventRecords and HarmonyEventRecords, which are written by concurrently
+// processed events
+var eventRecordsMu sync.RWMutex
+
 // EthNewEventWrite add a validator's address to the official claims list
 func EthNewEventWrite(txHash string, event EthLogLockEvent) {
+	eventRecordsMu.Lock()
+	defer eventRecordsMu.Unlock()
 	EventRecords[txHash] = event
 }
 
 // HmyNewEventWrite add a validator's address to the official claims list
 func HmyNewEventWrite(txHash string, event HmyLogLockEvent) {
+	eventRecordsMu.Lock()
+	defer eventRecordsMu.Unlock()
 	HarmonyEventRecords[txHash] = event
 }
 
 // EthIsEventRecorded checks the sessions stored events for this transaction hash
 func EthIsEventRecorded(txHash string) bool {
+	eventRecordsMu.RLock()
+	defer eventRecordsMu.RUnlock()
 	return EventRecords[txHash].Nonce != nil
 }
 
 // HmyIsEventRecorded checks the sessions stored events for this transaction hash
 func HmyIsEventRecorded(txHash string) bool {
+	eventRecordsMu.RLock()
+	defer eventRecordsMu.RUnlock()
 	return HarmonyEventRecords[txHash].Nonce != nil
 }
 
 // EthPrintEventByTx prints any witnessed events associated with a given transaction hash
 func EthPrintEventByTx(txHash string) {
-	if EthIsEventRecorded(txHash) {
-		log.Println(EventRecords[txHash].String())
+	eventRecordsMu.RLock()
+	defer eventRecordsMu.RUnlock()
+	if event := EventRecords[txHash]; event.Nonce != nil {
+		log.Println(event.String())
 	} else {
 		log.Printf("\nNo records from this session for tx: %v\n", txHash)
 	}
@@ -42,8 +59,10 @@ func EthPrintEventByTx(txHash string) {
 
 // HmyPrintEventByTx prints any witnessed events associated with a given transaction hash
 func HmyPrintEventByTx(txHash string) {
-	if HmyIsEventRecorded(txHash) {
-		log.Println(HarmonyEventRecords[txHash].String())
+	eventRecordsMu.RLock()
+	defer eventRecordsMu.RUnlock()
+	if event := HarmonyEventRecords[txHash]; event.Nonce != nil {
+		log.Println(event.String())
 	} else {
 		log.Printf("\nNo records from this session for tx: %v\n", txHash)
 	}
@@ -51,6 +70,8 @@ func HmyPrintEventByTx(txHash string) {
 
 // EthPrintEvents prints all the claims made on this event
 func EthPrintEvents() {
+	eventRecordsMu.RLock()
+	defer eventRecordsMu.RUnlock()
 	// For each claim, print the validator which submitted the claim
 	for txHash, event := range EventRecords {
 		log.Printf("\nTransaction: %v\n", txHash)
@@ -60,6 +81,8 @@ func EthPrintEvents() {
 
 // HmyPrintEvents prints all the claims made on this event
 func HmyPrintEvents() {
+	eventRecordsMu.RLock()
+	defer eventRecordsMu.RUnlock()
 	// For each claim, print the validator which submitted the claim
 	for txHash, event := range HarmonyEventRecords {
 		log.Printf("\nTransaction: %v\n", txHash)
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// KeyStore records a set of string keys, such as the events relayed or the claims signed
+type KeyStore interface {
+	// Has reports whether key was added
+	Has(key string) (bool, error)
+	// Add records key
+	Add(key string) error
+}
+
+// MemoryKeyStore is a KeyStore held in memory. It is safe for concurrent use.
+type MemoryKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+// NewMemoryKeyStore initializes a new, empty MemoryKeyStore
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{keys: make(map[string]struct{})}
+}
+
+// Has implements KeyStore
+func (s *MemoryKeyStore) Has(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.keys[key]
+	return ok, nil
+}
+
+// Add implements KeyStore
+func (s *MemoryKeyStore) Add(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key] = struct{}{}
+	return nil
+}
+
+// FileKeyStore is a KeyStore appending each key as a line to a file, so keys survive restarts.
+// It is safe for concurrent use.
+type FileKeyStore struct {
+	mu   sync.Mutex
+	file *os.File
+	keys map[string]struct{}
+}
+
+// NewFileKeyStore opens the FileKeyStore at path, loading any previously recorded keys
+func NewFileKeyStore(path string) (*FileKeyStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if key := strings.TrimSpace(scanner.Text()); len(key) != 0 {
+			keys[key] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileKeyStore{file: file, keys: keys}, nil
+}
+
+// Has implements KeyStore
+func (s *FileKeyStore) Has(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.keys[key]
+	return ok, nil
+}
+
+// Add implements KeyStore
+func (s *FileKeyStore) Add(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key]; ok {
+		return nil
+	}
+	if _, err := s.file.WriteString(key + "\n"); err != nil {
+		return err
+	}
+	s.keys[key] = struct{}{}
+	return nil
+}
+
+// Close closes the underlying file
+func (s *FileKeyStore) Close() error {
+	return s.file.Close()
+}
+
+// WriteFileAtomic writes data to a temporary file beside path, then renames it over path, so a
+// crash mid-write leaves the previous contents intact
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
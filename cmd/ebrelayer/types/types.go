@@ -40,13 +40,29 @@ type EthLogLockEvent struct {
 	EthereumTokenAmount *big.Int
 	HarmonyTokenAmount  *big.Int
 	Nonce               *big.Int
+	// TxHash is the hash of the transaction which emitted the event
+	TxHash common.Hash
 }
 
 // String implements fmt.Stringer
 func (e EthLogLockEvent) String() string {
-	return fmt.Sprintf("\nChain ID: %v\nBridge contract address: %v\nEthereum Token: %v\nHarmony Token: %v\nEthereum Sender: %v\nHarmony Recipient: %v\nEthereum Token Amount: %v\nHarmony Token Amount: %v\nNonce: %v\n",
-		e.EthereumChainID, e.BridgeBankAddress.Hex(), e.EthereumToken.Hex(), e.HarmonyToken.Hex(), e.EthereumSender.Hex(),
-		e.HarmonyReceiver.Hex(), e.EthereumTokenAmount, e.HarmonyTokenAmount, e.Nonce)
+	return fmt.Sprintf("\nChain ID: %v\nBridge contract address: %v\nEthereum Token: %v\nHarmony Token: %v\nEthereum Sender: %v\nHarmony Recipient: %v\nEthereum Token Amount: %v\nHarmony Token Amount: %v\nNonce: %v\nTx Hash: %v\n",
+		e.EthereumChainID, e.BridgeBankAddress.Hex(), e.EthereumToken.Hex(), ToBech32(e.HarmonyToken), e.EthereumSender.Hex(),
+		ToBech32(e.HarmonyReceiver), e.EthereumTokenAmount, e.HarmonyTokenAmount, e.Nonce, e.TxHash.Hex())
+}
+
+// ClaimEvent is implemented by unlock claim events of either bridge direction
+type ClaimEvent interface {
+	// ClaimFields returns the event's data in the order it is packed into a claim message
+	ClaimFields() (unlockID *big.Int, sender, recipient, token common.Address, amount *big.Int)
+}
+
+// TracedClaimEvent is implemented by claim events carrying the hash of the transaction which
+// emitted them, which may be bound into their claim message for traceability
+type TracedClaimEvent interface {
+	ClaimEvent
+	// SourceTxHash returns the hash of the transaction which emitted the event
+	SourceTxHash() common.Hash
 }
 
 // EthLogNewUnlockClaimEvent struct which represents a EthLogNewUnlockClaim event
@@ -57,14 +73,29 @@ type EthLogNewUnlockClaimEvent struct {
 	ValidatorAddress common.Address
 	TokenAddress     common.Address
 	Amount           *big.Int
+	// TxHash is the hash of the transaction which emitted the event
+	TxHash common.Hash
+	// Version names the registered event version the event was decoded with, empty for the
+	// bundled ABI's
+	Version string
 }
 
 // String implements fmt.Stringer
 func (p EthLogNewUnlockClaimEvent) String() string {
 	return fmt.Sprintf("\nUnlocl ID: %v\nHarmony Sender: %v\n"+
-		"Ethereum Receiver: %v\nEthereum Token: %v\nAmount: %v\nValidator Address: %v\n\n",
-		p.UnlockID, p.HarmonySender.Hex(), p.EthereumReceiver.Hex(),
-		p.TokenAddress.Hex(), p.Amount, p.ValidatorAddress.Hex())
+		"Ethereum Receiver: %v\nEthereum Token: %v\nAmount: %v\nValidator Address: %v\nTx Hash: %v\n\n",
+		p.UnlockID, ToBech32(p.HarmonySender), p.EthereumReceiver.Hex(),
+		p.TokenAddress.Hex(), p.Amount, p.ValidatorAddress.Hex(), p.TxHash.Hex())
+}
+
+// ClaimFields implements ClaimEvent
+func (p EthLogNewUnlockClaimEvent) ClaimFields() (*big.Int, common.Address, common.Address, common.Address, *big.Int) {
+	return p.UnlockID, p.HarmonySender, p.EthereumReceiver, p.TokenAddress, p.Amount
+}
+
+// SourceTxHash implements TracedClaimEvent
+func (p EthLogNewUnlockClaimEvent) SourceTxHash() common.Hash {
+	return p.TxHash
 }
 
 // HmyLogLockEvent struct is used by HmyLogLock
@@ -79,13 +110,15 @@ type HmyLogLockEvent struct {
 	HarmonyTokenAmount  *big.Int
 	EthereumTokenAmount *big.Int
 	Nonce               *big.Int
+	// TxHash is the hash of the transaction which emitted the event
+	TxHash common.Hash
 }
 
 // String implements fmt.Stringer
 func (e HmyLogLockEvent) String() string {
-	return fmt.Sprintf("\nChain ID: %v\nBridge contract address: %v\nHarmony Token: %v\nEthereum Token: %v\nHarmony Sender: %v\nEthereum Recipient: %v\nHarmony Token Amount: %v\nEthereum Token Amount: %v\nNonce: %v\n",
-		e.HarmonyChainID, e.BridgeBankAddress.Hex(), e.HarmonyToken.Hex(), e.EthereumToken.Hex(), e.HarmonySender.Hex(),
-		e.EthereumReceiver.Hex(), e.HarmonyTokenAmount, e.EthereumTokenAmount, e.Nonce)
+	return fmt.Sprintf("\nChain ID: %v\nBridge contract address: %v\nHarmony Token: %v\nEthereum Token: %v\nHarmony Sender: %v\nEthereum Recipient: %v\nHarmony Token Amount: %v\nEthereum Token Amount: %v\nNonce: %v\nTx Hash: %v\n",
+		e.HarmonyChainID, ToBech32(e.BridgeBankAddress), ToBech32(e.HarmonyToken), e.EthereumToken.Hex(), ToBech32(e.HarmonySender),
+		e.EthereumReceiver.Hex(), e.HarmonyTokenAmount, e.EthereumTokenAmount, e.Nonce, e.TxHash.Hex())
 }
 
 // HmyLogNewUnlockClaimEvent struct which represents a HmyLogNewUnlockClaim event
@@ -96,12 +129,27 @@ type HmyLogNewUnlockClaimEvent struct {
 	ValidatorAddress common.Address
 	TokenAddress     common.Address
 	Amount           *big.Int
+	// TxHash is the hash of the transaction which emitted the event
+	TxHash common.Hash
+	// Version names the registered event version the event was decoded with, empty for the
+	// bundled ABI's
+	Version string
 }
 
 // String implements fmt.Stringer
 func (p HmyLogNewUnlockClaimEvent) String() string {
 	return fmt.Sprintf("\nUnlocl ID: %v\nEthereum Sender: %v\n"+
-		"Harmony Receiver: %v\nHarmony Token: %v\nAmount: %v\nValidator Address: %v\n\n",
-		p.UnlockID, p.EthereumSender.Hex(), p.HarmonyReceiver.Hex(),
-		p.TokenAddress.Hex(), p.Amount, p.ValidatorAddress.Hex())
+		"Harmony Receiver: %v\nHarmony Token: %v\nAmount: %v\nValidator Address: %v\nTx Hash: %v\n\n",
+		p.UnlockID, p.EthereumSender.Hex(), ToBech32(p.HarmonyReceiver),
+		ToBech32(p.TokenAddress), p.Amount, ToBech32(p.ValidatorAddress), p.TxHash.Hex())
+}
+
+// ClaimFields implements ClaimEvent
+func (p HmyLogNewUnlockClaimEvent) ClaimFields() (*big.Int, common.Address, common.Address, common.Address, *big.Int) {
+	return p.UnlockID, p.EthereumSender, p.HarmonyReceiver, p.TokenAddress, p.Amount
+}
+
+// SourceTxHash implements TracedClaimEvent
+func (p HmyLogNewUnlockClaimEvent) SourceTxHash() common.Hash {
+	return p.TxHash
 }
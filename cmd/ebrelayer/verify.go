@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+const (
+	// FlagVerifyChain is the chain verifying the claim: ethereum or harmony
+	FlagVerifyChain = "chain"
+	// FlagVerifyUnlockID is the claim's unlock ID
+	FlagVerifyUnlockID = "unlock-id"
+	// FlagVerifySender is the claim's sender on the source chain
+	FlagVerifySender = "sender"
+	// FlagVerifyRecipient is the claim's recipient on the verifying chain
+	FlagVerifyRecipient = "recipient"
+	// FlagVerifyToken is the claim's token address
+	FlagVerifyToken = "token"
+	// FlagVerifyAmount is the claim's amount
+	FlagVerifyAmount = "amount"
+	// FlagVerifyDecimals is the token's decimals the amount is given in whole units of
+	FlagVerifyDecimals = "decimals"
+	// FlagVerifySignature is the hex signature to verify
+	FlagVerifySignature = "signature"
+	// FlagVerifyClaimChainID is the chain ID bound into the claim, if any
+	FlagVerifyClaimChainID = "claim-chain-id"
+	// FlagVerifyTxHash is the source transaction hash bound into the claim, if any
+	FlagVerifyTxHash = "tx-hash"
+	// FlagVerifyScheme is the signing scheme of the verifying contract
+	FlagVerifyScheme = "scheme"
+	// FlagVerifyContract is the verifying contract, for schemes bound to it
+	FlagVerifyContract = "verifying-contract"
+	// FlagVerifyValidators is the validator set the signer is checked against
+	FlagVerifyValidators = "validators"
+)
+
+// verifyCmd
+func verifyCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Recover the validator which signed a claim, from the claim's fields and signature",
+		Args:  cobra.ExactArgs(0),
+		Example: "ebrelayer verify --chain ethereum --unlock-id 1 --sender 0x... --recipient 0x... " +
+			"--token 0x... --amount 100 --signature 0x... --validators 0x...,0x...",
+		RunE: RunVerifyCmd,
+	}
+
+	verifyCmd.Flags().String(FlagVerifyChain, "ethereum", "chain verifying the claim: ethereum or harmony")
+	verifyCmd.Flags().String(FlagVerifyUnlockID, "", "the claim's unlock ID")
+	verifyCmd.Flags().String(FlagVerifySender, "", "the claim's sender address on the source chain")
+	verifyCmd.Flags().String(FlagVerifyRecipient, "", "the claim's recipient address on the verifying chain")
+	verifyCmd.Flags().String(FlagVerifyToken, "", "the claim's token address")
+	verifyCmd.Flags().String(FlagVerifyAmount, "", "the claim's amount, in whole tokens such as 12.5 if --decimals is set")
+	verifyCmd.Flags().Int(FlagVerifyDecimals, 0, "the token's decimals, to give --amount in whole tokens; 0 for base units")
+	verifyCmd.Flags().String(FlagVerifySignature, "", "hex signature over the claim")
+	verifyCmd.Flags().Uint64(FlagVerifyClaimChainID, 0, "chain ID bound into the claim; 0 for the legacy claim layout")
+	verifyCmd.Flags().String(FlagVerifyTxHash, "", "source transaction hash bound into the claim; empty for the legacy claim layout")
+	verifyCmd.Flags().String(FlagVerifyScheme, txs.EthSignedMessage.String(),
+		"signing scheme of the verifying contract: eth-signed-message, raw, intended-validator or eip712:name:version:chainID")
+	verifyCmd.Flags().String(FlagVerifyContract, "", "the verifying contract, for the eip712 and intended-validator schemes")
+	verifyCmd.Flags().StringSlice(FlagVerifyValidators, nil, "validator addresses the signer is checked against")
+
+	return verifyCmd
+}
+
+// RunVerifyCmd : executes the verifyCmd
+func RunVerifyCmd(cmd *cobra.Command, args []string) error {
+	flags := make(map[string]string)
+	for _, name := range []string{FlagVerifyChain, FlagVerifyUnlockID, FlagVerifySender, FlagVerifyRecipient,
+		FlagVerifyToken, FlagVerifyAmount, FlagVerifySignature} {
+		value, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return err
+		}
+		if len(value) == 0 {
+			return errors.Errorf("--%s is required", name)
+		}
+		flags[name] = value
+	}
+
+	unlockID, ok := new(big.Int).SetString(flags[FlagVerifyUnlockID], 10)
+	if !ok {
+		return errors.Errorf("invalid [%s]: %s", FlagVerifyUnlockID, flags[FlagVerifyUnlockID])
+	}
+	decimals, err := cmd.Flags().GetInt(FlagVerifyDecimals)
+	if err != nil {
+		return err
+	}
+	amount, err := txs.ParseTokenAmount(flags[FlagVerifyAmount], decimals)
+	if err != nil {
+		return errors.Errorf("invalid [%s]: %v", FlagVerifyAmount, err)
+	}
+	addresses := make(map[string]common.Address)
+	for _, name := range []string{FlagVerifySender, FlagVerifyRecipient, FlagVerifyToken} {
+		if err := txs.ValidateAddressChecksum(flags[name], false); err != nil {
+			return errors.Errorf("invalid [%s]: %v", name, err)
+		}
+		addresses[name] = common.HexToAddress(flags[name])
+	}
+	signature, err := hexutil.Decode(flags[FlagVerifySignature])
+	if err != nil {
+		return errors.Errorf("invalid [%s]: %v", FlagVerifySignature, err)
+	}
+
+	var chainID *big.Int
+	claimChainID, err := cmd.Flags().GetUint64(FlagVerifyClaimChainID)
+	if err != nil {
+		return err
+	}
+	if claimChainID != 0 {
+		chainID = new(big.Int).SetUint64(claimChainID)
+	}
+
+	txHashFlag, err := cmd.Flags().GetString(FlagVerifyTxHash)
+	if err != nil {
+		return err
+	}
+	var txHash common.Hash
+	if len(txHashFlag) != 0 {
+		decoded, err := hexutil.Decode(txHashFlag)
+		if err != nil || len(decoded) != common.HashLength {
+			return errors.Errorf("invalid [%s]: %s", FlagVerifyTxHash, txHashFlag)
+		}
+		txHash = common.BytesToHash(decoded)
+	}
+
+	var event types.ClaimEvent
+	switch flags[FlagVerifyChain] {
+	case "ethereum":
+		event = types.EthLogNewUnlockClaimEvent{UnlockID: unlockID, HarmonySender: addresses[FlagVerifySender],
+			EthereumReceiver: addresses[FlagVerifyRecipient], TokenAddress: addresses[FlagVerifyToken], Amount: amount,
+			TxHash: txHash}
+		txs.EthClaimTxHash = len(txHashFlag) != 0
+	case "harmony":
+		event = types.HmyLogNewUnlockClaimEvent{UnlockID: unlockID, EthereumSender: addresses[FlagVerifySender],
+			HarmonyReceiver: addresses[FlagVerifyRecipient], TokenAddress: addresses[FlagVerifyToken], Amount: amount,
+			TxHash: txHash}
+		txs.HmyClaimTxHash = len(txHashFlag) != 0
+	default:
+		return errors.Errorf("invalid [%s]: %s", FlagVerifyChain, flags[FlagVerifyChain])
+	}
+
+	scheme, err := cmd.Flags().GetString(FlagVerifyScheme)
+	if err != nil {
+		return err
+	}
+	contractFlag, err := cmd.Flags().GetString(FlagVerifyContract)
+	if err != nil {
+		return err
+	}
+	var contract common.Address
+	if len(contractFlag) != 0 {
+		if err := txs.ValidateAddressChecksum(contractFlag, false); err != nil {
+			return errors.Errorf("invalid [%s]: %v", FlagVerifyContract, err)
+		}
+		contract = common.HexToAddress(contractFlag)
+	}
+	_, signingConfig, err := txs.ParseSigningConfig(contract.Hex() + "=" + scheme)
+	if err != nil {
+		return errors.Errorf("invalid [%s]: %v", FlagVerifyScheme, err)
+	}
+
+	message, err := txs.ClaimMessageForChain(event, chainID)
+	if err != nil {
+		return err
+	}
+	digest, err := signingConfig.Digest(message, contract)
+	if err != nil {
+		return err
+	}
+	signer, err := txs.RecoverSigner(digest, signature)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out, "Claim message:", hexutil.Encode(message))
+	fmt.Fprintln(out, "Signer:", signer.Hex())
+
+	validators, err := cmd.Flags().GetStringSlice(FlagVerifyValidators)
+	if err != nil {
+		return err
+	}
+	if len(validators) != 0 {
+		inSet := false
+		for _, validator := range validators {
+			if common.IsHexAddress(validator) && common.HexToAddress(validator) == signer {
+				inSet = true
+				break
+			}
+		}
+		fmt.Fprintln(out, "In validator set:", inSet)
+	}
+	return nil
+}
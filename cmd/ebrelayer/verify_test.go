@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/txs"
+	"github.com/mochi-lab/eth-one-bridge/cmd/ebrelayer/types"
+)
+
+// runVerify runs the verify command with args, returning its output
+func runVerify(t *testing.T, args ...string) (string, error) {
+	defer func(txHash bool) { txs.EthClaimTxHash = txHash }(txs.EthClaimTxHash)
+
+	var out bytes.Buffer
+	cmd := verifyCmd()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestVerifyCmdRecoversSignClaimSigner(t *testing.T) {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := crypto.PubkeyToAddress(key.PublicKey)
+	event := types.EthLogNewUnlockClaimEvent{
+		UnlockID:         big.NewInt(42),
+		HarmonySender:    common.HexToAddress("0x0B585F8DaEfBC68a311FbD4cB20d9174aD174016"),
+		EthereumReceiver: common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		TokenAddress:     common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+		Amount:           big.NewInt(1500000),
+	}
+	message, err := txs.EthGenerateClaimMessage(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := txs.SignClaim(txs.PrefixMsg(message), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"--chain", "ethereum", "--unlock-id", "42",
+		"--sender", event.HarmonySender.Hex(), "--recipient", event.EthereumReceiver.Hex(),
+		"--token", event.TokenAddress.Hex(), "--amount", "1.5", "--decimals", "6",
+		"--signature", hexutil.Encode(signature)}
+	out, err := runVerify(t, append(args, "--validators", common.HexToAddress("0x1").Hex()+","+validator.Hex())...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Claim message: " + hexutil.Encode(message), "Signer: " + validator.Hex(), "In validator set: true"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("verify output %q, want %q", out, want)
+		}
+	}
+
+	// A signature over any other claim recovers some other signer
+	args[3] = "43"
+	out, err = runVerify(t, append(args, "--validators", validator.Hex())...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "Signer: "+validator.Hex()) || !strings.Contains(out, "In validator set: false") {
+		t.Fatalf("verify output of another claim %q, want another signer outside the set", out)
+	}
+}
+
+func TestVerifyCmdRequiresFlags(t *testing.T) {
+	if _, err := runVerify(t, "--chain", "ethereum", "--unlock-id", "1"); err == nil || !strings.Contains(err.Error(), "--sender is required") {
+		t.Fatalf("verify without a sender = %v, want --sender is required", err)
+	}
+}
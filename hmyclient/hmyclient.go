@@ -172,6 +172,54 @@ func toBlockNumArg(number *big.Int) string {
 	return hexutil.EncodeBig(number)
 }
 
+// BlockNumber returns the number of the most recent block
+func (ec *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var result hexutil.Uint64
+	err := ec.c.CallContext(ctx, &result, "hmy_blockNumber")
+	return uint64(result), err
+}
+
+// ChainID returns the chain ID of the network the node serves, which transactions are signed for
+func (ec *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	var result hexutil.Big
+	if err := ec.c.CallContext(ctx, &result, "hmy_chainId"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// BlockHashByNumber returns the hash of the canonical block with the given number.
+// The block number can be nil, in which case the latest known block is used.
+func (ec *Client) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	var block *struct {
+		Hash common.Hash `json:"hash"`
+	}
+	err := ec.c.CallContext(ctx, &block, "hmy_getBlockByNumber", toBlockNumArg(number), false)
+	if err == nil && block == nil {
+		err = ethereum.NotFound
+	}
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return block.Hash, nil
+}
+
+// BlockTimestampByNumber returns the timestamp, in seconds since the epoch, of the canonical block
+// with the given number. The block number can be nil, in which case the latest known block is used.
+func (ec *Client) BlockTimestampByNumber(ctx context.Context, number *big.Int) (uint64, error) {
+	var block *struct {
+		Timestamp hexutil.Uint64 `json:"timestamp"`
+	}
+	err := ec.c.CallContext(ctx, &block, "hmy_getBlockByNumber", toBlockNumArg(number), false)
+	if err == nil && block == nil {
+		err = ethereum.NotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return uint64(block.Timestamp), nil
+}
+
 type rpcProgress struct {
 	StartingBlock hexutil.Uint64
 	CurrentBlock  hexutil.Uint64
@@ -404,6 +452,12 @@ func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) er
 	return ec.c.CallContext(ctx, nil, "hmy_sendRawTransaction", common.ToHex(data))
 }
 
+// SendRawTransaction injects an already signed and RLP-encoded transaction into the pending pool,
+// such as a Harmony transaction carrying shard IDs.
+func (ec *Client) SendRawTransaction(ctx context.Context, data []byte) error {
+	return ec.c.CallContext(ctx, nil, "hmy_sendRawTransaction", common.ToHex(data))
+}
+
 func toCallArg(msg ethereum.CallMsg) interface{} {
 	arg := map[string]interface{}{
 		"from": msg.From,